@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	crawler "crawler/internal/filecrawler"
-	"crawler/internal/fs"
+	"crawler/internal/osfs"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -41,7 +41,7 @@ func main() {
 	fmt.Println(root)
 
 	c := crawler.New[TestType, TestAccumulator]()
-	result, err := c.Collect(ctx, fs.NewOsFileSystem(), root, crawler.Configuration{
+	result, err := c.Collect(ctx, osfs.New(), root, crawler.Configuration{
 		SearchWorkers:      10,
 		FileWorkers:        10,
 		AccumulatorWorkers: 10,
@@ -0,0 +1,296 @@
+package archivefs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+// ErrInvalidArchive is returned when an archive cannot be read or contains an entry whose
+// name escapes the archive.
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// FileSystem is an implementation of the fs.FileSystem interface which treats zip and tar.gz
+// archives of an underlying file system as directories. Files whose names end with .zip,
+// .tar.gz or .tgz are listed by ReadDir as directories, and the paths inside them, such as
+// "data/set.zip/inner/1.json", are resolved against the contents of the archive, so Collect
+// descends into archives transparently. Archives nested into archives are descended as well.
+// Since a path is resolved by its name, a directory named as an archive cannot be read.
+//
+// An archive is read into memory entirely the first time a path inside it is accessed, and
+// it is kept in memory for the lifetime of the FileSystem. FileSystem is safe for concurrent
+// use as long as the underlying file system is.
+type FileSystem struct {
+	base fs.FileSystem
+
+	mu       sync.Mutex
+	archives map[string]*archive
+}
+
+// archive is an archive loaded into memory once
+type archive struct {
+	once       sync.Once
+	fileSystem fs.FileSystem
+	err        error
+}
+
+// New creates a FileSystem descending into the archives of the base file system.
+func New(base fs.FileSystem) *FileSystem {
+	return &FileSystem{
+		base:     base,
+		archives: make(map[string]*archive),
+	}
+}
+
+// Open opens the named file, which can be either a file of the underlying file system or
+// a file inside an archive.
+func (a *FileSystem) Open(name string) (fs.File, error) {
+	archivePath, inner, ok := splitArchivePath(name)
+	if !ok {
+		return a.base.Open(name)
+	}
+	fileSystem, err := a.load(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	return fileSystem.Open(inner)
+}
+
+// ReadDir reads the named directory, which can be a directory of the underlying file system,
+// an archive or a directory inside an archive. Archives are listed as directories.
+func (a *FileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	if archivePath, inner, ok := splitArchivePath(name); ok {
+		fileSystem, err := a.load(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		// the file system of the archive lists the nested archives as directories itself
+		return fileSystem.ReadDir(inner)
+	}
+
+	entries, err := a.base.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		if !entry.IsDir() && isArchive(entry.Name()) {
+			entries[i] = archiveEntry{entry}
+		}
+	}
+	return entries, nil
+}
+
+// Join joins path elements using the underlying file system.
+func (a *FileSystem) Join(elem ...string) string {
+	return a.base.Join(elem...)
+}
+
+// load returns the file system of the contents of the archive, reading the archive if it has
+// not been read yet
+func (a *FileSystem) load(name string) (fs.FileSystem, error) {
+	a.mu.Lock()
+	arch, exists := a.archives[name]
+	if !exists {
+		arch = new(archive)
+		a.archives[name] = arch
+	}
+	a.mu.Unlock()
+
+	// concurrent readers of the same archive wait for the first one to read it
+	arch.once.Do(func() {
+		var contents *memfs.FileSystem
+		contents, arch.err = a.read(name)
+		if arch.err == nil {
+			arch.fileSystem = New(contents)
+		}
+	})
+	return arch.fileSystem, arch.err
+}
+
+// read reads the archive into an in-memory file system
+func (a *FileSystem) read(name string) (fileSystem *memfs.FileSystem, err error) {
+	f, err := a.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// the builder panics if a file and a directory of the archive have the same name
+	defer func() {
+		if r := recover(); r != nil {
+			fileSystem, err = nil, fmt.Errorf("%w %s: %v", ErrInvalidArchive, name, r)
+		}
+	}()
+
+	builder := memfs.NewBuilder()
+	if strings.HasSuffix(name, ".zip") {
+		err = readZip(builder, data)
+	} else {
+		err = readTarGz(builder, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidArchive, name, err)
+	}
+	return builder.Build(), nil
+}
+
+// readZip adds the contents of the zip archive to the builder
+func readZip(builder *memfs.Builder, data []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		name, err := entryName(f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			builder.AddDir(name)
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+		builder.AddFile(name, string(content))
+	}
+	return nil
+}
+
+// readTarGz adds the contents of the gzip-compressed tar archive to the builder, only
+// directories and regular files are added
+func readTarGz(builder *memfs.Builder, data []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name, err := entryName(header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			builder.AddDir(name)
+		case tar.TypeReg:
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			builder.AddFile(name, string(content))
+		}
+	}
+}
+
+// entryName cleans the name of an archive entry and rejects names escaping the archive
+func entryName(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "/"))
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("entry %q escapes the archive", name)
+	}
+	return cleaned, nil
+}
+
+// isArchive reports whether the file name has an extension of a supported archive
+func isArchive(name string) bool {
+	return strings.HasSuffix(name, ".zip") ||
+		strings.HasSuffix(name, ".tar.gz") ||
+		strings.HasSuffix(name, ".tgz")
+}
+
+// splitArchivePath splits the name into the path of the outermost archive and the path
+// inside it, ok is false if the name has no archive component
+func splitArchivePath(name string) (archivePath string, inner string, ok bool) {
+	start := 0
+	for i := 0; i <= len(name); i++ {
+		if i < len(name) && !isSeparator(name[i]) {
+			continue
+		}
+		if i > start && isArchive(name[start:i]) {
+			inner = filepath.ToSlash(strings.TrimLeft(name[i:], separators))
+			if inner == "" {
+				inner = "."
+			}
+			return name[:i], inner, true
+		}
+		start = i + 1
+	}
+	return "", "", false
+}
+
+// separators lists the bytes separating path elements
+const separators = "/" + string(os.PathSeparator)
+
+// isSeparator reports whether the byte separates path elements
+func isSeparator(c byte) bool {
+	return strings.IndexByte(separators, c) >= 0
+}
+
+// archiveEntry is a directory entry of an archive which is listed as a directory
+type archiveEntry struct {
+	os.DirEntry
+}
+
+func (e archiveEntry) IsDir() bool {
+	return true
+}
+
+func (e archiveEntry) Type() iofs.FileMode {
+	return iofs.ModeDir
+}
+
+func (e archiveEntry) Info() (iofs.FileInfo, error) {
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return archiveInfo{info}, nil
+}
+
+// archiveInfo describes an archive as a directory
+type archiveInfo struct {
+	iofs.FileInfo
+}
+
+func (i archiveInfo) Mode() iofs.FileMode {
+	return i.FileInfo.Mode() | iofs.ModeDir
+}
+
+func (i archiveInfo) IsDir() bool {
+	return true
+}
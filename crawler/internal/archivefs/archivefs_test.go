@@ -0,0 +1,173 @@
+package archivefs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	crawler "crawler/internal/filecrawler"
+	"crawler/internal/memfs"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem(t *testing.T) {
+	nested := zipArchive(t, map[string]string{
+		"3.json": `{"data": 3}`,
+	})
+	base := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/set.zip", zipArchive(t, map[string]string{
+			"inner/2.json": `{"data": 2}`,
+			"nested.zip":   nested,
+		})).
+		AddFile("root/set.tar.gz", tarGzArchive(t, map[string]string{
+			"4.json":       `{"data": 4}`,
+			"inner/5.json": `{"data": 5}`,
+		})).
+		Build()
+	fileSystem := New(base)
+
+	entries, err := fileSystem.ReadDir("root")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, "1.json", entries[0].Name())
+	require.False(t, entries[0].IsDir())
+	require.Equal(t, "set.tar.gz", entries[1].Name())
+	require.True(t, entries[1].IsDir())
+	info, err := entries[1].Info()
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	require.Equal(t, "set.zip", entries[2].Name())
+	require.True(t, entries[2].IsDir())
+
+	entries, err = fileSystem.ReadDir(fileSystem.Join("root", "set.zip"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "inner", entries[0].Name())
+	require.True(t, entries[0].IsDir())
+	require.Equal(t, "nested.zip", entries[1].Name())
+	require.True(t, entries[1].IsDir())
+
+	require.Equal(t, `{"data": 1}`, readFile(t, fileSystem, "root/1.json"))
+	require.Equal(t, `{"data": 2}`, readFile(t, fileSystem, "root/set.zip/inner/2.json"))
+	require.Equal(t, `{"data": 3}`, readFile(t, fileSystem, "root/set.zip/nested.zip/3.json"))
+	require.Equal(t, `{"data": 4}`, readFile(t, fileSystem, "root/set.tar.gz/4.json"))
+	require.Equal(t, `{"data": 5}`, readFile(t, fileSystem, "root/set.tar.gz/inner/5.json"))
+
+	_, err = fileSystem.Open("root/set.zip/missing.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCollect(t *testing.T) {
+	type data struct {
+		Data int64 `json:"data"`
+	}
+
+	fileSystem := New(memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/dir/set.tgz", tarGzArchive(t, map[string]string{
+			"2.json":       `{"data": 2}`,
+			"inner/3.json": `{"data": 3}`,
+			"set.zip":      zipArchive(t, map[string]string{"4.json": `{"data": 4}`}),
+		})).
+		Build())
+
+	c := crawler.New[data, int64]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", crawler.Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+	}, func(current data, accum int64) int64 {
+		return accum + current.Data
+	}, func(current int64, accum int64) int64 {
+		return accum + current
+	})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 10, result)
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	fileSystem := New(memfs.NewBuilder().
+		AddFile("set.zip", zipArchive(t, map[string]string{"1.json": `{"data": 1}`})).
+		Build())
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries, err := fileSystem.ReadDir("set.zip")
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestInvalidArchive(t *testing.T) {
+	fileSystem := New(memfs.NewBuilder().
+		AddFile("broken.zip", "not a zip").
+		AddFile("escaping.tgz", tarGzArchive(t, map[string]string{"../1.json": "{}"})).
+		AddFile("conflict.zip", zipArchive(t, map[string]string{"a": "", "a/b": ""})).
+		Build())
+
+	for _, name := range []string{"broken.zip", "escaping.tgz", "conflict.zip"} {
+		_, err := fileSystem.ReadDir(name)
+		require.ErrorIs(t, err, ErrInvalidArchive)
+		// the error is remembered
+		_, err = fileSystem.Open(filepath.Join(name, "1.json"))
+		require.ErrorIs(t, err, ErrInvalidArchive)
+	}
+}
+
+func readFile(t *testing.T, fileSystem *FileSystem, name string) string {
+	f, err := fileSystem.Open(name)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func zipArchive(t *testing.T, files map[string]string) string {
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.String()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) string {
+	buffer := new(bytes.Buffer)
+	gz := gzip.NewWriter(buffer)
+	writer := tar.NewWriter(gz)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		})
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, gz.Close())
+	return buffer.String()
+}
@@ -0,0 +1,173 @@
+package decode
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeCSV decodes the records of a CSV file into the slice v points to, appending them to the
+// slice. The first record is the header naming the columns. The elements of the slice can be:
+//   - []string, holding the fields of a record, the header is not decoded as a record,
+//   - map[string]string, mapping the names of the columns to the fields of a record,
+//   - a struct, whose fields are filled with the fields of the columns of the same names.
+//
+// A column is matched with a struct field by the name in its `csv` tag, or else in its `json`
+// tag, or else by the name of the field compared case-insensitively. Fields tagged "-" and
+// columns without a field are skipped. String, boolean, integer and floating point fields are
+// supported, an empty CSV field leaves the struct field zero.
+func DecodeCSV(r io.Reader, v any) error {
+	slice, err := slicePointer(v)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	elementType := slice.Type().Elem()
+	decodeRecord, err := recordDecoder(elementType, header)
+	if err != nil {
+		return err
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		element := reflect.New(elementType).Elem()
+		if err := decodeRecord(record, element); err != nil {
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		slice.Set(reflect.Append(slice, element))
+	}
+}
+
+// recordDecoder returns the function decoding a record into an element of the type
+func recordDecoder(elementType reflect.Type, header []string) (func([]string, reflect.Value) error, error) {
+	switch {
+	case elementType.Kind() == reflect.Slice && elementType.Elem().Kind() == reflect.String:
+		return func(record []string, element reflect.Value) error {
+			element.Set(reflect.ValueOf(record).Convert(elementType))
+			return nil
+		}, nil
+	case elementType.Kind() == reflect.Map && elementType.Key().Kind() == reflect.String &&
+		elementType.Elem().Kind() == reflect.String:
+		return func(record []string, element reflect.Value) error {
+			element.Set(reflect.MakeMapWithSize(elementType, len(header)))
+			for i, field := range record {
+				if i < len(header) {
+					element.SetMapIndex(reflect.ValueOf(header[i]).Convert(elementType.Key()),
+						reflect.ValueOf(field).Convert(elementType.Elem()))
+				}
+			}
+			return nil
+		}, nil
+	case elementType.Kind() == reflect.Struct:
+		fields := columnFields(elementType, header)
+		return func(record []string, element reflect.Value) error {
+			for i, field := range record {
+				if i >= len(fields) || fields[i] == nil || field == "" {
+					continue
+				}
+				if err := setField(element.FieldByIndex(fields[i]), field); err != nil {
+					return fmt.Errorf("column %q: %w", header[i], err)
+				}
+			}
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("%w []%s: CSV records decode into []string, map[string]string or a struct",
+		ErrUnsupportedTarget, elementType)
+}
+
+// columnFields returns the indexes of the struct fields of the columns, nil for the columns
+// without a field
+func columnFields(structType reflect.Type, header []string) [][]int {
+	byName := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, tagged := tagName(field, "csv")
+		if !tagged {
+			name, tagged = tagName(field, "json")
+		}
+		if name == "-" {
+			continue
+		}
+		if !tagged {
+			name = strings.ToLower(field.Name)
+		}
+		byName[name] = field.Index
+	}
+
+	fields := make([][]int, len(header))
+	for i, column := range header {
+		if index, exists := byName[column]; exists {
+			fields[i] = index
+		} else if index, exists := byName[strings.ToLower(column)]; exists {
+			fields[i] = index
+		}
+	}
+	return fields
+}
+
+// tagName returns the name in the tag of the field
+func tagName(field reflect.StructField, key string) (string, bool) {
+	tag, exists := field.Tag.Lookup(key)
+	if !exists {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, name != ""
+}
+
+// setField parses the CSV field into the struct field
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: field of type %s", ErrUnsupportedTarget, field.Type())
+	}
+	return nil
+}
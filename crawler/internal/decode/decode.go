@@ -0,0 +1,163 @@
+package decode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedTarget is returned when a decoder cannot decode into the given value.
+var ErrUnsupportedTarget = errors.New("unsupported decoding target")
+
+// Decoder decodes the contents of a file into the value v points to.
+// Decoder must be thread-safe, since files are decoded concurrently.
+type Decoder interface {
+	Decode(r io.Reader, v any) error
+}
+
+// DecoderFunc is an adapter allowing an ordinary function to be used as a Decoder.
+type DecoderFunc func(r io.Reader, v any) error
+
+// Decode calls f(r, v).
+func (f DecoderFunc) Decode(r io.Reader, v any) error {
+	return f(r, v)
+}
+
+var (
+	// JSON decodes a single JSON value.
+	JSON Decoder = DecoderFunc(func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	})
+
+	// YAML decodes a single YAML document.
+	YAML Decoder = DecoderFunc(func(r io.Reader, v any) error {
+		return yaml.NewDecoder(r).Decode(v)
+	})
+
+	// XML decodes a single XML element.
+	XML Decoder = DecoderFunc(func(r io.Reader, v any) error {
+		return xml.NewDecoder(r).Decode(v)
+	})
+
+	// JSONLines decodes a JSON value per line into the slice v points to, appending the values
+	// to the slice. Blank lines are skipped.
+	JSONLines Decoder = DecoderFunc(decodeJSONLines)
+
+	// CSV decodes the records of a CSV file with a header into the slice v points to, see
+	// DecodeCSV for the supported element types.
+	CSV Decoder = DecoderFunc(DecodeCSV)
+)
+
+// sniffSize is the number of bytes peeked to sniff the format of a file
+const sniffSize = 512
+
+// Registry selects a decoder for a file by the extension of its name. Files with unknown
+// extensions are sniffed: contents starting with '<' are decoded as XML, contents starting
+// with '{' or '[' as JSON, and the others with the fallback decoder. Registry is safe for
+// concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	extensions map[string]Decoder
+	fallback   Decoder
+}
+
+// NewRegistry creates a registry without extensions, decoding every file by sniffing its
+// contents, with JSON as the fallback decoder.
+func NewRegistry() *Registry {
+	return &Registry{
+		extensions: make(map[string]Decoder),
+		fallback:   JSON,
+	}
+}
+
+// DefaultRegistry creates a registry of the built-in decoders: JSON for .json, YAML for .yaml
+// and .yml, XML for .xml, CSV for .csv and JSONLines for .jsonl and .ndjson.
+func DefaultRegistry() *Registry {
+	return NewRegistry().
+		Register(".json", JSON).
+		Register(".yaml", YAML).
+		Register(".yml", YAML).
+		Register(".xml", XML).
+		Register(".csv", CSV).
+		Register(".jsonl", JSONLines).
+		Register(".ndjson", JSONLines)
+}
+
+// Register sets the decoder of the files with the extension, such as ".json". Extensions are
+// compared case-insensitively. Register returns the registry, so that calls can be chained.
+func (r *Registry) Register(ext string, decoder Decoder) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extensions[strings.ToLower(ext)] = decoder
+	return r
+}
+
+// SetFallback sets the decoder of the files which neither extension nor sniffing tells the
+// format of.
+func (r *Registry) SetFallback(decoder Decoder) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = decoder
+	return r
+}
+
+// Lookup returns the decoder registered for the extension of the name.
+func (r *Registry) Lookup(name string) (Decoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	decoder, exists := r.extensions[strings.ToLower(path.Ext(name))]
+	return decoder, exists
+}
+
+// Decode decodes the contents of the named file into the value v points to, selecting the
+// decoder by the name or by the contents.
+func (r *Registry) Decode(name string, reader io.Reader, v any) error {
+	if decoder, exists := r.Lookup(name); exists {
+		return decoder.Decode(reader, v)
+	}
+
+	buffered := bufio.NewReaderSize(reader, sniffSize)
+	// a short or failed peek leaves what has been read, the error is met again by the decoder
+	head, _ := buffered.Peek(sniffSize)
+	return r.sniff(head).Decode(buffered, v)
+}
+
+// sniff selects the decoder by the beginning of the contents
+func (r *Registry) sniff(head []byte) Decoder {
+	head = bytes.TrimLeft(head, " \t\r\n\ufeff")
+	switch {
+	case bytes.HasPrefix(head, []byte("<")):
+		return XML
+	case bytes.HasPrefix(head, []byte("{")), bytes.HasPrefix(head, []byte("[")):
+		return JSON
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fallback
+}
+
+// Func adapts a function returning the decoded value, such as a protobuf or a domain-specific
+// parser, to a Decoder. The Decoder can decode into *T only.
+func Func[T any](fn func(r io.Reader) (T, error)) Decoder {
+	return DecoderFunc(func(r io.Reader, v any) error {
+		target, ok := v.(*T)
+		if !ok {
+			return fmt.Errorf("%w %T: the decoder returns %T", ErrUnsupportedTarget, v, *new(T))
+		}
+		value, err := fn(r)
+		if err != nil {
+			return err
+		}
+		*target = value
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+package decode
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Name  string  `json:"name" xml:"name" yaml:"name"`
+	Count int     `json:"count" xml:"count" yaml:"count"`
+	Ratio float64 `json:"ratio" xml:"ratio" yaml:"ratio"`
+}
+
+func TestBuiltinDecoders(t *testing.T) {
+	expected := record{Name: "a", Count: 2, Ratio: 0.5}
+
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "record.json", content: `{"name": "a", "count": 2, "ratio": 0.5}`},
+		{name: "record.yaml", content: "name: a\ncount: 2\nratio: 0.5\n"},
+		{name: "record.YML", content: "name: a\ncount: 2\nratio: 0.5\n"},
+		{name: "record.xml", content: `<record><name>a</name><count>2</count><ratio>0.5</ratio></record>`},
+		// unknown extensions are sniffed
+		{name: "record", content: "\n  <record><name>a</name><count>2</count><ratio>0.5</ratio></record>"},
+		{name: "record.txt", content: ` {"name": "a", "count": 2, "ratio": 0.5}`},
+	}
+
+	registry := DefaultRegistry()
+	for _, tt := range testCases {
+		var actual record
+		require.NoError(t, registry.Decode(tt.name, strings.NewReader(tt.content), &actual), tt.name)
+		require.Equal(t, expected, actual, tt.name)
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	content := "{\"name\": \"a\", \"count\": 1}\n\n{\"name\": \"b\", \"count\": 2}\n"
+
+	var records []record
+	require.NoError(t, DefaultRegistry().Decode("records.jsonl", strings.NewReader(content), &records))
+	require.Equal(t, []record{{Name: "a", Count: 1}, {Name: "b", Count: 2}}, records)
+
+	var single record
+	err := JSONLines.Decode(strings.NewReader(content), &single)
+	require.ErrorIs(t, err, ErrUnsupportedTarget)
+
+	err = JSONLines.Decode(strings.NewReader("{}\n{"), &records)
+	require.ErrorContains(t, err, "line 2")
+}
+
+func TestCSV(t *testing.T) {
+	content := "name,Count,ratio,ignored\na,1,0.5,x\nb,2,,y\n"
+
+	var records []record
+	require.NoError(t, DefaultRegistry().Decode("records.csv", strings.NewReader(content), &records))
+	require.Equal(t, []record{{Name: "a", Count: 1, Ratio: 0.5}, {Name: "b", Count: 2}}, records)
+
+	var rows [][]string
+	require.NoError(t, CSV.Decode(strings.NewReader(content), &rows))
+	require.Equal(t, [][]string{{"a", "1", "0.5", "x"}, {"b", "2", "", "y"}}, rows)
+
+	var maps []map[string]string
+	require.NoError(t, CSV.Decode(strings.NewReader(content), &maps))
+	require.Equal(t, "y", maps[1]["ignored"])
+
+	type tagged struct {
+		Title   string `csv:"name"`
+		Skipped string `csv:"-" json:"ignored"`
+		Valid   bool   `csv:"valid"`
+	}
+	var taggedRecords []tagged
+	require.NoError(t, CSV.Decode(strings.NewReader("name,ignored,valid\nt,s,true\n"), &taggedRecords))
+	require.Equal(t, []tagged{{Title: "t", Valid: true}}, taggedRecords)
+
+	err := CSV.Decode(strings.NewReader("name,count\na,x\n"), &records)
+	require.ErrorContains(t, err, `line 2: column "count"`)
+
+	err = CSV.Decode(strings.NewReader(content), &[]int{})
+	require.ErrorIs(t, err, ErrUnsupportedTarget)
+}
+
+func TestRegistry(t *testing.T) {
+	errCustom := errors.New("custom")
+	custom := DecoderFunc(func(r io.Reader, v any) error {
+		return errCustom
+	})
+
+	registry := NewRegistry().Register(".custom", custom)
+	var v record
+	require.ErrorIs(t, registry.Decode("a.CUSTOM", strings.NewReader("{}"), &v), errCustom)
+	_, exists := registry.Lookup("a.json")
+	require.False(t, exists)
+
+	// neither extension nor sniffing tells the format
+	require.Error(t, registry.Decode("a", strings.NewReader("name: a"), &v))
+	registry.SetFallback(YAML)
+	require.NoError(t, registry.Decode("a", strings.NewReader("name: a"), &v))
+	require.Equal(t, "a", v.Name)
+}
+
+func TestFunc(t *testing.T) {
+	decoder := Func(func(r io.Reader) (int, error) {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(strings.TrimSpace(string(content)))
+	})
+
+	var v int
+	require.NoError(t, decoder.Decode(strings.NewReader("42\n"), &v))
+	require.Equal(t, 42, v)
+
+	require.Error(t, decoder.Decode(strings.NewReader("x"), &v))
+	require.Equal(t, 42, v)
+
+	var s string
+	require.ErrorIs(t, decoder.Decode(strings.NewReader("1"), &s), ErrUnsupportedTarget)
+}
@@ -0,0 +1,45 @@
+package decode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// maxLineSize is the size of the longest line of a JSON-Lines file
+const maxLineSize = 64 << 20
+
+// decodeJSONLines decodes a JSON value per line appending them to the slice v points to
+func decodeJSONLines(r io.Reader, v any) error {
+	slice, err := slicePointer(v)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		element := reflect.New(slice.Type().Elem())
+		if err := json.Unmarshal(content, element.Interface()); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		slice.Set(reflect.Append(slice, element.Elem()))
+	}
+	return scanner.Err()
+}
+
+// slicePointer returns the slice v points to
+func slicePointer(v any) (reflect.Value, error) {
+	pointer := reflect.ValueOf(v)
+	if pointer.Kind() != reflect.Pointer || pointer.IsNil() || pointer.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("%w %T: a pointer to a slice is required", ErrUnsupportedTarget, v)
+	}
+	return pointer.Elem(), nil
+}
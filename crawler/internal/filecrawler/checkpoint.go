@@ -0,0 +1,209 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCheckpointInterval is the interval of checkpoints if none is configured.
+const DefaultCheckpointInterval = time.Second
+
+// Checkpoint configures the persistence of the state of a crawl, so that a crawl interrupted
+// by a crash or a cancellation can be resumed. The state is the set of the files accumulated
+// along with the intermediate results of the accumulator workers, which are encoded as JSON,
+// so the result type must support it.
+//
+// The state is saved every Interval, DefaultCheckpointInterval if it is not positive, and
+// once more before Collect returns. A crawl completed without errors removes the file.
+type Checkpoint struct {
+	Path     string        // Path of the checkpoint file.
+	Interval time.Duration // Interval of the checkpoints.
+	// Resume continues the crawl saved to the file, if any, skipping the files accumulated
+	// before and combining their results with the results of the rest of the files.
+	Resume bool
+}
+
+// checkpointFile is the contents of a checkpoint file
+type checkpointFile struct {
+	Processed []string          `json:"processed"`
+	Partials  []json.RawMessage `json:"partials"`
+}
+
+// partial is the intermediate result of an accumulator worker along with the files it is
+// made of
+type partial[R any] struct {
+	value R
+	paths []string
+	// files is the number of files the value is made of, if it is streamed
+	files int
+	mu    sync.Mutex
+}
+
+// checkpointer keeps track of the intermediate results of a crawl and saves them periodically
+type checkpointer[R any] struct {
+	conf *Checkpoint
+	// resumed is the state of the crawl resumed, which is saved along with the new results
+	resumed   checkpointFile
+	processed map[string]struct{}
+	values    []R
+
+	partials []*partial[R]
+	err      error
+	mu       sync.Mutex
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newCheckpointer creates the checkpointer of the configuration, loading the state of the
+// crawl resumed
+func newCheckpointer[R any](conf *Checkpoint) (*checkpointer[R], error) {
+	c := &checkpointer[R]{conf: conf, done: make(chan struct{})}
+	if conf == nil || !conf.Resume {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(conf.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// there is nothing to resume
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.resumed); err != nil {
+		return nil, err
+	}
+
+	c.processed = make(map[string]struct{}, len(c.resumed.Processed))
+	for _, path := range c.resumed.Processed {
+		c.processed[path] = struct{}{}
+	}
+	c.values = make([]R, len(c.resumed.Partials))
+	for i, raw := range c.resumed.Partials {
+		if err := json.Unmarshal(raw, &c.values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// tracks reports whether the files accumulated are tracked
+func (c *checkpointer[R]) tracks() bool {
+	return c.conf != nil
+}
+
+// accumulated reports whether the file has been accumulated before the crawl was resumed
+func (c *checkpointer[R]) accumulated(path string) bool {
+	_, ok := c.processed[path]
+	return ok
+}
+
+// newPartial creates the intermediate result of an accumulator worker
+func (c *checkpointer[R]) newPartial() *partial[R] {
+	p := new(partial[R])
+	c.mu.Lock()
+	c.partials = append(c.partials, p)
+	c.mu.Unlock()
+	return p
+}
+
+// start starts saving the state periodically, if it is configured
+func (c *checkpointer[R]) start() {
+	if c.conf == nil {
+		return
+	}
+	interval := c.conf.Interval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+				c.save()
+			}
+		}
+	}()
+}
+
+// stop stops the periodic checkpoints and saves the final state, unless the crawl is
+// completed, the error of the last checkpoint is returned
+func (c *checkpointer[R]) stop(completed bool) error {
+	if c.conf == nil {
+		return nil
+	}
+	close(c.done)
+	c.wg.Wait()
+
+	if !completed {
+		c.save()
+		return c.err
+	}
+	if err := os.Remove(c.conf.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// save writes the state of the crawl to the checkpoint file, the file is replaced at once so
+// that a crash leaves either the previous state or the new one
+func (c *checkpointer[R]) save() {
+	c.mu.Lock()
+	partials := c.partials
+	c.mu.Unlock()
+
+	state := checkpointFile{
+		Processed: append([]string(nil), c.resumed.Processed...),
+		Partials:  append([]json.RawMessage(nil), c.resumed.Partials...),
+	}
+	for _, p := range partials {
+		// the result and the files it is made of are taken together
+		p.mu.Lock()
+		raw, err := json.Marshal(p.value)
+		state.Processed = append(state.Processed, p.paths...)
+		p.mu.Unlock()
+		if err != nil {
+			c.err = err
+			return
+		}
+		state.Partials = append(state.Partials, raw)
+	}
+
+	c.err = writeFileAtomically(c.conf.Path, state)
+}
+
+// writeFileAtomically writes the value as JSON to a temporary file renamed to the path
+func writeFileAtomically(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkpointFileSystem(files int, broken string) *memfs.FileSystem {
+	builder := memfs.NewBuilder()
+	for i := range files {
+		name := fmt.Sprintf("root/%d/%d.json", i%3, i)
+		if name == broken {
+			builder.AddFile(name, `{"data": `)
+		} else {
+			builder.AddFile(name, fmt.Sprintf(`{"data": %d}`, i))
+		}
+	}
+	return builder.Build()
+}
+
+func collectWithCheckpoint(ctx context.Context, fileSystem fs.FileSystem, checkpoint *Checkpoint, accumulator func(TestType, TestAccumulator) TestAccumulator) (TestAccumulator, error) {
+	c := New[TestType, TestAccumulator]()
+	return c.Collect(ctx, fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		ErrorPolicy:        SkipAndCollect,
+		Checkpoint:         checkpoint,
+	}, accumulator, combiner)
+}
+
+func TestCheckpointResume(t *testing.T) {
+	checkpoint := &Checkpoint{Path: filepath.Join(t.TempDir(), "crawl.json"), Resume: true}
+
+	_, err := collectWithCheckpoint(context.Background(), checkpointFileSystem(20, "root/1/4.json"), checkpoint, sum)
+	require.ErrorContains(t, err, "root/1/4.json")
+	require.FileExists(t, checkpoint.Path)
+
+	// the broken file is fixed, and it is the only one read again
+	fileSystem := &recordingFileSystem{FileSystem: checkpointFileSystem(20, "")}
+	result, err := collectWithCheckpoint(context.Background(), fileSystem, checkpoint, sum)
+	require.NoError(t, err)
+	require.EqualValues(t, 19*20/2, result.Sum)
+	require.Equal(t, []string{"root/1/4.json"}, fileSystem.opened)
+	// a completed crawl leaves no checkpoint
+	require.NoFileExists(t, checkpoint.Path)
+}
+
+func TestCheckpointInterrupted(t *testing.T) {
+	checkpoint := &Checkpoint{
+		Path:     filepath.Join(t.TempDir(), "crawl.json"),
+		Interval: 5 * time.Millisecond,
+		Resume:   true,
+	}
+	fileSystem := checkpointFileSystem(50, "")
+
+	// the crawl is interrupted once some of the files are accumulated
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var accumulated atomic.Int64
+	_, err := collectWithCheckpoint(ctx, fileSystem, checkpoint, func(current TestType, accum TestAccumulator) TestAccumulator {
+		if accumulated.Add(1) == 10 {
+			cancel()
+		}
+		time.Sleep(time.Millisecond)
+		return sum(current, accum)
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.FileExists(t, checkpoint.Path)
+
+	accumulated.Store(0)
+	result, err := collectWithCheckpoint(context.Background(), fileSystem, checkpoint, func(current TestType, accum TestAccumulator) TestAccumulator {
+		accumulated.Add(1)
+		return sum(current, accum)
+	})
+	require.NoError(t, err)
+	// every file is accumulated exactly once over the two crawls
+	require.EqualValues(t, 49*50/2, result.Sum)
+	require.Less(t, accumulated.Load(), int64(50))
+}
+
+func TestCheckpointWithoutResume(t *testing.T) {
+	checkpoint := &Checkpoint{Path: filepath.Join(t.TempDir(), "crawl.json")}
+	require.NoError(t, os.WriteFile(checkpoint.Path, []byte("not a checkpoint"), 0o644))
+
+	result, err := collectWithCheckpoint(context.Background(), checkpointFileSystem(5, ""), checkpoint, sum)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, result.Sum)
+	require.NoFileExists(t, checkpoint.Path)
+
+	// there is nothing to resume
+	checkpoint.Resume = true
+	result, err = collectWithCheckpoint(context.Background(), checkpointFileSystem(5, ""), checkpoint, sum)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, result.Sum)
+}
+
+func TestCheckpointCorrupted(t *testing.T) {
+	checkpoint := &Checkpoint{Path: filepath.Join(t.TempDir(), "crawl.json"), Resume: true}
+	require.NoError(t, os.WriteFile(checkpoint.Path, []byte("not a checkpoint"), 0o644))
+
+	_, err := collectWithCheckpoint(context.Background(), checkpointFileSystem(5, ""), checkpoint, sum)
+	require.Error(t, err)
+}
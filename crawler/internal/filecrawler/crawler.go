@@ -2,20 +2,89 @@ package crawler
 
 import (
 	"context"
+	"crawler/internal/decode"
 	"crawler/internal/fs"
 	"crawler/internal/workerpool"
-	"encoding/json"
+	"errors"
+	"io"
 	"sync"
+	"time"
 )
 
 // Configuration holds the configuration for the crawler, specifying the number of workers for
 // file searching, processing, and accumulating tasks. The values for SearchWorkers, FileWorkers,
 // and AccumulatorWorkers are critical to efficient performance and must be defined in
 // every configuration.
+//
+// The search stage can be narrowed down with Include and Exclude patterns, so that the
+// irrelevant parts of large trees are neither read nor decoded. Excluded directories are not
+// descended into, excluded files are not read. If any Include pattern is set, only the files
+// matching one of them are read, while directories are descended into unless excluded.
 type Configuration struct {
 	SearchWorkers      int // Number of workers responsible for searching files.
 	FileWorkers        int // Number of workers for processing individual files.
 	AccumulatorWorkers int // Number of workers for accumulating results.
+
+	// The stages are connected by channels without buffers by default, so every stage waits
+	// for the next one to take its item, and the items held by the crawl are bounded by the
+	// number of the workers. The queues let a stage run ahead of the next one by up to the
+	// given number of items, smoothing out the bursts at the cost of the memory the items hold.
+	// A full queue blocks the stage feeding it, so the backpressure is kept.
+	FileQueueSize int // Number of file paths found and waiting to be read.
+	TypeQueueSize int // Number of decoded values waiting to be accumulated.
+
+	Include []Pattern // Patterns of files to read, all files are read if empty.
+	Exclude []Pattern // Patterns of files and directories to skip.
+
+	// MaxFileSize is the size in bytes of the largest file to read, larger files are skipped
+	// without being opened. The size is taken from the directory entry, and files whose size is
+	// not reported correctly are read up to the limit only, failing with ErrFileTooLarge.
+	// Zero means no limit.
+	MaxFileSize int64
+	// OnSkip, if set, is called with the path and the size of every file skipped for
+	// exceeding MaxFileSize. It is called concurrently by the search workers.
+	OnSkip func(path string, size int64)
+	// Statistics, if set, is updated with the counters of the crawl.
+	Statistics *Statistics
+
+	// OnProgress, if set, is called with the progress of the crawl every ProgressInterval,
+	// DefaultProgressInterval if it is not positive, and once more with the final progress
+	// before Collect returns. The calls are sequential.
+	OnProgress       func(Progress)
+	ProgressInterval time.Duration
+
+	// OnMetrics, if set, is called with the metrics of the crawl before Collect returns.
+	OnMetrics func(Metrics)
+
+	// MaxFilesPerSecond and MaxBytesPerSecond, if positive, limit the rate the files are
+	// opened at and the rate their contents are read at across all the workers, which keeps
+	// the load of shared file systems and remote APIs bounded. Bursts of up to a second worth
+	// of files or bytes are allowed.
+	MaxFilesPerSecond float64
+	MaxBytesPerSecond float64
+
+	// Deduplicate selects how the duplicates of files are detected, they are read as any
+	// other file by default.
+	Deduplicate Dedup
+
+	// Checkpoint, if set, saves the state of the crawl periodically, so that an interrupted
+	// crawl can be resumed.
+	Checkpoint *Checkpoint
+
+	// Retry controls how the directories and files failing with transient errors are read
+	// again, they are not by default.
+	Retry RetryPolicy
+
+	// ErrorPolicy controls whether the failures of individual files and directories abort
+	// the crawl, FailFast by default.
+	ErrorPolicy ErrorPolicy
+
+	// Decoder, if set, decodes every file, which allows formats such as protobuf or gob and
+	// domain-specific parsers to be used, see decode.Func. The decoder must be thread-safe.
+	Decoder decode.Decoder
+	// Decoders, if set and Decoder is not, selects the decoder of every file by its name or
+	// contents. If neither is set, the files are decoded as JSON.
+	Decoders *decode.Registry
 }
 
 // Combiner is a function type that defines how to combine two values of type R into a single
@@ -48,8 +117,9 @@ type Crawler[T, R any] interface {
 	//    it should return that modified value rather than creating a new one,
 	//    or alternatively, it can create and return a new combined result.
 	// 5. Context cancellation is respected across workers.
-	// 6. Type T is derived by json-deserializing the file contents, and any issues in deserialization
-	//    must be handled within the worker.
+	// 6. Type T is derived by json-deserializing the file contents, or by the decoders of the
+	//    Configuration, and any issues in deserialization must be handled within the worker.
+	//    The contents are decoded as a stream, so files of any size are supported.
 	// 7. The combiner function will wait for all workers to complete, ensuring no goroutine leaks
 	//    occur during the process.
 	Collect(
@@ -60,6 +130,42 @@ type Crawler[T, R any] interface {
 		accumulator workerpool.Accumulator[T, R],
 		combiner Combiner[R],
 	) (R, error)
+
+	// CollectStream performs the crawling operation as Collect does, but rather than
+	// combining the results it sends the intermediate results of the accumulator workers as
+	// soon as every one of them is made of batchSize files, so that the results can be
+	// processed as they arrive and memory stays bounded. A worker starts accumulating from
+	// the neutral element after every result sent, and the results made of fewer files are
+	// sent once there are no more files. The results channel is closed once the crawl is over,
+	// and then the error of the crawl, as Collect would return it, is sent to the error
+	// channel. Checkpoint of the Configuration is ignored.
+	// CollectStream panics if batchSize is less than one.
+	CollectStream(
+		ctx context.Context,
+		fileSystem fs.FileSystem,
+		root string,
+		conf Configuration,
+		batchSize int,
+		accumulator workerpool.Accumulator[T, R],
+	) (<-chan R, <-chan error)
+
+	// Watch collects the tree as Collect does and watches it afterwards, sending an update
+	// of the result whenever the watcher tells the files passing the filters of the
+	// Configuration have been added, changed or removed. The added files are crawled alone
+	// and combined with the previous result, while any other change makes the tree crawled
+	// from scratch, since R has no inverse of the combiner. The files failed to be crawled
+	// are crawled again once they change, and Checkpoint of the Configuration is ignored.
+	// The results sent may be passed to the combiner again, so they must not be modified.
+	// The channel is closed once the context is done.
+	Watch(
+		ctx context.Context,
+		fileSystem fs.FileSystem,
+		root string,
+		conf Configuration,
+		watcher Watcher,
+		accumulator workerpool.Accumulator[T, R],
+		combiner Combiner[R],
+	) (<-chan Update[R], error)
 }
 
 // crawlerImpl represents Crawler implementation
@@ -85,36 +191,14 @@ func newFileStorage() *fileStorage {
 	}
 }
 
-// protect wraps given function to recover from panics while saving an error
-func protect[T any](aE *atomicErr, fn func(string) T) func(string) T {
-	return func(arg string) (result T) {
-		defer func() {
-			if err := recover(); err != nil {
-				// here it is expected that err is a standard error
-				if e, ok := err.(error); ok {
-					aE.addError(e)
-				}
-			}
-		}()
-
-		// call a function before we will recover from panic
-		return fn(arg)
-	}
-}
-
-// atomicErr serves to protect error from concurrent access from multiple goroutines
-type atomicErr struct {
-	err error
-	mu  *sync.Mutex
-}
-
-// addError saves error to atomicErr if it hasn't been written
-func (a *atomicErr) addError(e error) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.err == nil {
-		a.err = e
-	}
+// item is a decoded file passed from transform stage to accumulate stage, files failed to be
+// decoded are passed as well, but they are not accumulated
+type item[T any] struct {
+	path  string
+	value T
+	ok    bool
+	// sent is the time the value is passed to accumulate stage at, if metrics are collected
+	sent time.Time
 }
 
 // Collect represents crawlerImpl implementation of function with the same name
@@ -126,156 +210,222 @@ func (c *crawlerImpl[T, R]) Collect(
 	accumulator workerpool.Accumulator[T, R],
 	combiner Combiner[R],
 ) (R, error) {
-	// channel required to start pipeline by sending names of searched files to it
-	fileChan := make(chan string)
-
-	// Each worker pool serves to work with a certain stage of file system processing
-	searchWp := workerpool.New[string, string]()
-	transformWp := workerpool.New[string, T]()
-	resultWp := workerpool.New[T, R]()
+	return c.collect(ctx, fileSystem, root, conf, accumulator, combiner, nil)
+}
 
-	fStorage := newFileStorage()
+// collect performs the crawling operation, the intermediate results are either sent to the
+// stream, if any, or combined
+func (c *crawlerImpl[T, R]) collect(
+	ctx context.Context,
+	fileSystem fs.FileSystem,
+	root string,
+	conf Configuration,
+	accumulator workerpool.Accumulator[T, R],
+	combiner Combiner[R],
+	results *stream[R],
+) (R, error) {
+	var result R
 
-	// wait group to ensure no additional work is needed to write to file channel
-	listWg := sync.WaitGroup{}
+	entryFilter, err := newFilter(conf)
+	if err != nil {
+		return result, err
+	}
 
-	aE := &atomicErr{
-		mu: new(sync.Mutex),
+	checkpoints, err := newCheckpointer[R](conf.Checkpoint)
+	if err != nil {
+		return result, err
 	}
 
-	listWg.Add(1)
-	go func() {
-		defer listWg.Done()
-		searchWp.List(ctx, conf.SearchWorkers, root, protect(aE, func(parent string) []string {
-			listWg.Add(1)
-			defer listWg.Done()
-
-			// get dir entries
-			dirEntries, err := fileSystem.ReadDir(parent)
-			if err != nil {
-				aE.addError(err)
-				return nil
-			}
+	// the pipeline is cancelled either by the caller or by an abort of the crawl
+	parentCtx := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	agg := newAggregator(conf.ErrorPolicy, cancel)
+
+	prog := newProgress(conf)
+	prog.start()
+	defer prog.stop()
+
+	m := newMetrics(conf, root)
+	defer m.report(conf)
+
+	files := newFileReader(ctx, fileSystem, conf, prog)
+	dups := newDedup(conf.Deduplicate)
 
-			// directories traversal
-			var dirs []string
-			for _, entry := range dirEntries {
-				join := fileSystem.Join(parent, entry.Name())
-				// check dir entry type
-				if entry.IsDir() {
+	checkpoints.start()
+
+	// the failures are recorded by the aggregator along with their paths and stages, so that
+	// the error policy is applied as soon as they happen, and the stages return no errors
+	pipeline := workerpool.NewPipeline[string, item[T], *partial[R]]()
+
+	// at this stage directories are searched, and the files found are sent to transform stage
+	pipeline.List(conf.SearchWorkers, root, func(parent string, emit func(string) bool) ([]string, error) {
+		begin := m.now()
+		m.scanned(parent, begin)
+		defer m.handled(StageSearch, begin)
+
+		// get dir entries
+		dirEntries, err := readDir(ctx, fileSystem, conf, parent)
+		if err != nil {
+			agg.addError(parent, StageSearch, err)
+			return nil, nil
+		}
+		prog.dirsScanned.Add(1)
+
+		// directories traversal
+		var dirs []string
+		for _, entry := range dirEntries {
+			name := entry.Name()
+			join := fileSystem.Join(parent, name)
+			// check dir entry type
+			if entry.IsDir() {
+				if !entryFilter.skipDir(name, join) {
+					m.discover(join)
 					dirs = append(dirs, join)
-				} else {
-					select {
-					// ensure cancelling context is taken into account
-					case <-ctx.Done():
-						return nil
-					case fileChan <- join:
-					}
 				}
+			} else if !entryFilter.skipFile(name, join) && !checkpoints.accumulated(join) {
+				// large files are skipped rather than read
+				tooLarge, err := skipLargeFile(conf, entry, join)
+				if err != nil {
+					agg.addError(join, StageSearch, err)
+					continue
+				}
+				if tooLarge {
+					continue
+				}
+				duplicate, err := dups.skipEntry(entry, conf.Statistics)
+				if err != nil {
+					agg.addError(join, StageSearch, err)
+					continue
+				}
+				if duplicate {
+					continue
+				}
+
+				// the file waits until a read worker takes it
+				sent := m.now()
+				if !emit(join) {
+					return nil, nil
+				}
+				prog.filesDiscovered.Add(1)
+				m.queued(StageRead, sent)
 			}
-			return dirs
-		}))
-	}()
-
-	// wait group to ensure goroutine closing file channel is finished
-	fWg := sync.WaitGroup{}
-
-	// fWg.Done guarantees that file channel is closed and working goroutines are finished
-	fWg.Add(1)
-	go func() {
-		// closing channel to stop pipeline
-		defer close(fileChan)
-		listWg.Wait()
-		fWg.Done()
-	}()
-
-	// at this stage files are read, deserialized and their results are sent to type channel
-	typeCh := transformWp.Transform(ctx, conf.FileWorkers, fileChan, protect(aE, func(current string) T {
-		f, err := fileSystem.Open(current)
+		}
+		return dirs, nil
+	}, workerpool.OnPanic(func(parent string, err error) {
+		agg.addError(parent, StageSearch, err)
+	}))
 
+	// at this stage files are read, deserialized and their results are sent to accumulate stage
+	pipeline.Transform(conf.FileWorkers, func(current string) (result item[T], _ error) {
+		result.path = current
+		defer prog.filesProcessed.Add(1)
+
+		begin := m.now()
 		defer func() {
-			_ = f.Close()
+			m.handled(StageRead, begin)
+			// the value waits for an accumulator worker from now on
+			result.sent = m.now()
 		}()
 
-		var result T
-
+		duplicate, err := dups.skipContent(files, current, conf.Statistics)
 		if err != nil {
-			aE.addError(err)
-			return result
+			agg.addError(current, StageRead, err)
+			return result, nil
+		}
+		if duplicate {
+			return result, nil
 		}
 
-		// such a buffer size is enough to make one read
-		const bufferSize = 512
-		var content []byte
-		buffer := make([]byte, bufferSize)
-
-		fStorage.mu.RLock()
-		// allow readers to read file content
-		fMu, exists := fStorage.fileMu[current]
-		fStorage.mu.RUnlock()
-
-		// if there is no data yet then one reader should become a writer
-		if !exists {
-			fStorage.mu.Lock()
-			fMu, exists = fStorage.fileMu[current]
-			// the mutex could have already been created during the waiting time
-			if !exists {
-				fMu = new(sync.Mutex)
-				fStorage.fileMu[current] = fMu
-			}
-			fStorage.mu.Unlock()
+		// deserialize file content, the decoder reads as much of the file as the value takes
+		err, decodeErr := files.read(current, func(r io.Reader) error {
+			var zero T
+			result.value = zero
+			return decodeFile(conf, current, r, &result.value)
+		})
+		if err != nil {
+			agg.addError(current, StageRead, err)
+			return result, nil
+		}
+		if decodeErr != nil {
+			agg.addError(current, StageDecode, decodeErr)
+			return result, nil
 		}
-		// everyone who wants to read a file will read it
-		fMu.Lock()
-		defer fMu.Unlock()
 
-		// one read to buffer is enough in this implementation
-		n, readErr := f.Read(buffer)
-		content = buffer[:n]
+		result.ok = true
+		return result, nil
+	}, workerpool.WithQueue[string](conf.FileQueueSize), workerpool.OnPanic(func(current string, err error) {
+		agg.addError(current, StageRead, err)
+	}))
 
-		if readErr != nil {
-			aE.addError(readErr)
-			return result
+	// apply accumulator function to deserialized values from files, skipping the failed ones
+	pipeline.Accumulate(conf.AccumulatorWorkers, func(current item[T], state *partial[R]) (*partial[R], error) {
+		// every worker accumulates to its own intermediate result
+		if state == nil {
+			state = checkpoints.newPartial()
 		}
-
-		// deserialize file content
-		er := json.Unmarshal(content, &result)
-		if er != nil {
-			aE.addError(er)
-			return result
+		if !current.ok {
+			return state, nil
 		}
+		m.queued(StageAccumulate, current.sent)
+		begin := m.now()
+		defer m.handled(StageAccumulate, begin)
+
+		// the intermediate result may be saved by a checkpoint meanwhile
+		state.mu.Lock()
+		defer state.mu.Unlock()
 
-		return result
+		state.value = accumulator(current.value, state.value)
+		if checkpoints.tracks() {
+			state.paths = append(state.paths, current.path)
+		}
+		results.accumulated(ctx, state)
+		return state, nil
+	}, workerpool.WithQueue[item[T]](conf.TypeQueueSize), workerpool.OnPanic(func(current item[T], err error) {
+		// a panicking accumulator fails the file being accumulated
+		agg.addError(current.path, StageAccumulate, err)
 	}))
 
-	// apply accumulator function to deserialized values from files
-	resultCh := resultWp.Accumulate(ctx, conf.AccumulatorWorkers, typeCh, accumulator)
+	// the pipeline is finished once Run returns, after that there will be no simultaneous
+	// writing and reading of agg.errs
+	resultValues, _ := pipeline.Run(ctx)
 
-	var result R
+	// an aborted crawl has no result
+	if agg.aborted {
+		err := agg.err(nil)
+		if cerr := checkpoints.stop(false); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+		return result, err
+	}
 
-	// this slice serves to collect values from result channel allowing combiner to wait
-	// for pipeline completion
-	var resultValues []R
-
-	for {
-		res, ok := <-resultCh
-		if !ok {
-			// at the moment when the channel is closed there will be no
-			// simultaneous writing and reading of aE.err
-			if aE.err != nil {
-				return result, aE.err
-			}
+	// the intermediate results are saved before the combiner may modify them
+	err = agg.err(parentCtx.Err())
+	if cerr := checkpoints.stop(err == nil); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
 
-			// wait for file channel to close
-			fWg.Wait()
-			// at this stage the combiner waited for the pipeline to finish working
-			for _, rv := range resultValues {
-				result = combiner(rv, result)
+	// the rest of the streamed results are sent rather than combined
+	if results != nil {
+		for _, rv := range resultValues {
+			if rv != nil {
+				results.send(ctx, rv)
 			}
-			return result, ctx.Err()
 		}
+		return result, err
+	}
 
-		// while the channel with the results is open they are not processed
-		resultValues = append(resultValues, res)
+	// at this stage the combiner waited for the pipeline to finish working
+	for _, rv := range resultValues {
+		// a worker which has not accumulated anything has no result
+		if rv != nil {
+			result = combiner(rv.value, result)
+		}
+	}
+	// the results of the crawl resumed
+	for _, rv := range checkpoints.values {
+		result = combiner(rv, result)
 	}
+	return result, err
 }
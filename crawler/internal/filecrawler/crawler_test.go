@@ -3,8 +3,10 @@ package crawler
 import (
 	"context"
 	"crawler/internal/fs"
+	"crawler/internal/memfs"
 	"crawler/pkg/mocks"
 	"errors"
+	"fmt"
 	"math/rand/v2"
 	"os"
 	"path/filepath"
@@ -97,15 +99,76 @@ func TestWithOsFileSystem(t *testing.T) {
 
 	c := New[TestType, TestAccumulator]()
 	result, err := c.Collect(ctx, fs.NewOsFileSystem(), rootDir, Configuration{
-		10,
-		10,
-		10,
+		SearchWorkers:      10,
+		FileWorkers:        10,
+		AccumulatorWorkers: 10,
 	}, accum, combiner)
 
 	require.NoError(t, err)
 	require.EqualValues(t, 100, result.Sum)
 }
 
+func TestWithMemFileSystem(t *testing.T) {
+	ctx := context.Background()
+
+	builder := memfs.NewBuilder().AddDir("root/empty")
+	for i := 0; i < 10; i++ {
+		for j := 0; j <= i; j++ {
+			builder.AddFile(fmt.Sprintf("root/%d/%d/%d.json", i, j, j), fmt.Sprintf(`{"data": %d}`, j))
+		}
+	}
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(ctx, builder.Build(), "root", Configuration{
+		SearchWorkers:      3,
+		FileWorkers:        3,
+		AccumulatorWorkers: 3,
+	}, func(current TestType, accum TestAccumulator) TestAccumulator {
+		accum.Sum += current.Data
+		return accum
+	}, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 165, result.Sum)
+}
+
+func TestQueueSizes(t *testing.T) {
+	builder := memfs.NewBuilder()
+	for i := 0; i < 50; i++ {
+		builder.AddFile(fmt.Sprintf("root/%d/%d.json", i%5, i), fmt.Sprintf(`{"data": %d}`, i))
+	}
+
+	for _, conf := range []Configuration{
+		{FileQueueSize: 8},
+		{TypeQueueSize: 8},
+		{FileQueueSize: 1, TypeQueueSize: 100},
+	} {
+		conf.SearchWorkers, conf.FileWorkers, conf.AccumulatorWorkers = 2, 2, 2
+		result, err := New[TestType, TestAccumulator]().Collect(context.Background(), builder.Build(), "root", conf, sum, combiner)
+		require.NoError(t, err)
+		require.EqualValues(t, 49*50/2, result.Sum)
+		require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+	}
+}
+
+func TestQueueSizesAborted(t *testing.T) {
+	builder := memfs.NewBuilder().AddFile("root/broken.json", `{"data": `)
+	for i := 0; i < 50; i++ {
+		builder.AddFile(fmt.Sprintf("root/%d/%d.json", i%5, i), fmt.Sprintf(`{"data": %d}`, i))
+	}
+
+	result, err := New[TestType, TestAccumulator]().Collect(context.Background(), builder.Build(), "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		FileQueueSize:      4,
+		TypeQueueSize:      4,
+	}, sum, combiner)
+	require.Error(t, err)
+	require.Zero(t, result)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
 func TestWorkers(t *testing.T) {
 	ctx := context.Background()
 
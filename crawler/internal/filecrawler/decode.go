@@ -0,0 +1,35 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// decodeFile decodes the contents of the named file into the value v points to with the
+// decoder the configuration selects
+func decodeFile[T any](conf Configuration, name string, r io.Reader, v *T) error {
+	if conf.Decoder != nil {
+		return conf.Decoder.Decode(r, v)
+	}
+	if conf.Decoders != nil {
+		return conf.Decoders.Decode(name, r, v)
+	}
+	return json.NewDecoder(r).Decode(v)
+}
+
+// trackingReader remembers the error of reading, other than io.EOF, and counts the bytes read
+type trackingReader struct {
+	r   io.Reader
+	err error
+	n   int64
+}
+
+func (t *trackingReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.n += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		t.err = err
+	}
+	return n, err
+}
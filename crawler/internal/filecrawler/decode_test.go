@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"crawler/internal/decode"
+	"crawler/internal/memfs"
+	"encoding/gob"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoders(t *testing.T) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/2.yaml", "data: 2\n").
+		AddFile("root/3.xml", "<value><data>3</data></value>").
+		AddFile("root/4", `{"data": 4}`).
+		Build()
+
+	type value struct {
+		Data int64 `json:"data" xml:"data"`
+	}
+	c := New[value, int64]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Decoders:           decode.DefaultRegistry(),
+	}, func(current value, accum int64) int64 {
+		return accum + current.Data
+	}, func(current int64, accum int64) int64 {
+		return accum + current
+	})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 10, result)
+}
+
+func TestRecordsDecoders(t *testing.T) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.jsonl", "{\"data\": 1}\n{\"data\": 2}\n").
+		AddFile("root/2.csv", "data\n3\n4\n").
+		Build()
+
+	c := New[[]TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Decoders:           decode.DefaultRegistry(),
+	}, func(current []TestType, accum TestAccumulator) TestAccumulator {
+		for _, record := range current {
+			accum.Sum += record.Data
+		}
+		return accum
+	}, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 10, result.Sum)
+}
+
+func TestCustomDecoder(t *testing.T) {
+	encode := func(v TestType) string {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, gob.NewEncoder(buffer).Encode(v))
+		return buffer.String()
+	}
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.gob", encode(TestType{Data: 1})).
+		AddFile("root/inner/2.gob", encode(TestType{Data: 2})).
+		Build()
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Decoder: decode.Func(func(r io.Reader) (TestType, error) {
+			var v TestType
+			err := gob.NewDecoder(r).Decode(&v)
+			return v, err
+		}),
+		// the decoder takes precedence over the registry
+		Decoders: decode.DefaultRegistry(),
+	}, sum, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 3, result.Sum)
+}
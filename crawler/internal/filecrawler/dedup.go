@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"sync"
+)
+
+// Dedup selects how the duplicates of files are detected, so that a document reachable by
+// multiple paths is decoded and accumulated only once. The first of the duplicates to be
+// read is the one accumulated, the rest of them are counted in Statistics.
+type Dedup int
+
+const (
+	// DedupNone reads every file.
+	DedupNone Dedup = iota
+	// DedupContent detects the files of the same contents by their SHA-256 hashes. Every file
+	// is read twice, once to be hashed and once more to be decoded if it is not a duplicate.
+	DedupContent
+	// DedupSizeAndModTime detects the files of the same size and modification time, as they
+	// are reported by the directory entries, without opening them. It is cheap, but different
+	// files of the same size modified at the same time are taken for duplicates.
+	DedupSizeAndModTime
+)
+
+// sizeAndModTime identifies a file by its size and modification time
+type sizeAndModTime struct {
+	size    int64
+	modTime int64
+}
+
+// dedup remembers the files read to detect their duplicates
+type dedup struct {
+	mode Dedup
+	seen sync.Map
+}
+
+// newDedup creates the detector of duplicates of the given mode
+func newDedup(mode Dedup) *dedup {
+	return &dedup{mode: mode}
+}
+
+// claim reports whether the key is claimed for the first time, the duplicates are counted
+func (d *dedup) claim(key any, stats *Statistics) bool {
+	if _, loaded := d.seen.LoadOrStore(key, struct{}{}); loaded {
+		stats.duplicate()
+		return false
+	}
+	return true
+}
+
+// skipEntry reports whether the file is a duplicate by its size and modification time
+func (d *dedup) skipEntry(entry os.DirEntry, stats *Statistics) (bool, error) {
+	if d.mode != DedupSizeAndModTime {
+		return false, nil
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return false, err
+	}
+	return !d.claim(sizeAndModTime{size: info.Size(), modTime: info.ModTime().UnixNano()}, stats), nil
+}
+
+// skipContent reports whether the file is a duplicate by its contents, the error of reading the
+// file is returned
+func (d *dedup) skipContent(r *fileReader, name string, stats *Statistics) (bool, error) {
+	if d.mode != DedupContent {
+		return false, nil
+	}
+	h := sha256.New()
+	// the errors of reading are returned by the file reader
+	err, _ := r.read(name, func(content io.Reader) error {
+		h.Reset()
+		_, _ = io.Copy(h, content)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return !d.claim([sha256.Size]byte(h.Sum(nil)), stats), nil
+}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"crawler/internal/osfs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectDeduplicated(fileSystem fs.FileSystem, root string, mode Dedup, stats *Statistics) (TestAccumulator, error) {
+	c := New[TestType, TestAccumulator]()
+	return c.Collect(context.Background(), fileSystem, root, Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Deduplicate:        mode,
+		Statistics:         stats,
+	}, sum, combiner)
+}
+
+func TestDedupContent(t *testing.T) {
+	fileSystem := &recordingFileSystem{FileSystem: memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/copy/1.json", `{"data": 1}`).
+		AddFile("root/copy/renamed.json", `{"data": 1}`).
+		AddFile("root/2.json", `{"data": 2}`).
+		Build()}
+	stats := &Statistics{}
+
+	result, err := collectDeduplicated(fileSystem, "root", DedupContent, stats)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 3, result.Sum)
+	require.EqualValues(t, 2, stats.DuplicateFiles)
+	// the files are opened to be hashed, and the originals once more to be decoded
+	require.Len(t, fileSystem.opened, 4+2)
+}
+
+func TestDedupNone(t *testing.T) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/copy/1.json", `{"data": 1}`).
+		Build()
+	stats := &Statistics{}
+
+	result, err := collectDeduplicated(fileSystem, "root", DedupNone, stats)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 2, result.Sum)
+	require.Zero(t, stats.DuplicateFiles)
+}
+
+func TestDedupSizeAndModTime(t *testing.T) {
+	root := t.TempDir()
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, content := range map[string]string{
+		"1.json":         `{"data": 1}`,
+		"link/1.json":    `{"data": 1}`,
+		"other/2.json":   `{"data":  2}`,
+		"touched/1.json": `{"data": 1}`,
+	} {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	touched := filepath.Join(root, "touched/1.json")
+	require.NoError(t, os.Chtimes(touched, modTime, modTime.Add(time.Second)))
+	stats := &Statistics{}
+
+	result, err := collectDeduplicated(osfs.New(), root, DedupSizeAndModTime, stats)
+
+	require.NoError(t, err)
+	// the files of the same size modified at the same time are taken for duplicates
+	require.EqualValues(t, 4, result.Sum)
+	require.EqualValues(t, 1, stats.DuplicateFiles)
+}
@@ -0,0 +1,118 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Stage is a stage of the crawl a failure happens at.
+type Stage int
+
+const (
+	StageSearch     Stage = iota // Listing directories and inspecting their entries.
+	StageRead                    // Opening and reading files.
+	StageDecode                  // Decoding the contents of files.
+	StageAccumulate              // Accumulating decoded values.
+)
+
+// String returns the name of the stage.
+func (s Stage) String() string {
+	switch s {
+	case StageSearch:
+		return "search"
+	case StageRead:
+		return "read"
+	case StageDecode:
+		return "decode"
+	case StageAccumulate:
+		return "accumulate"
+	}
+	return fmt.Sprintf("Stage(%d)", int(s))
+}
+
+// CrawlError is a failure of a file or a directory at a stage of the crawl.
+type CrawlError struct {
+	Path  string // Path of the file or the directory.
+	Stage Stage  // Stage the failure happened at.
+	Err   error  // Underlying error.
+}
+
+func (e *CrawlError) Error() string {
+	return e.Stage.String() + " " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *CrawlError) Unwrap() error {
+	return e.Err
+}
+
+// CrawlErrors are the failures of a crawl in the order they were recorded. Collect returns
+// them as an error, which errors.As extracts, while errors.Is matches any of the underlying
+// errors.
+type CrawlErrors []*CrawlError
+
+func (e CrawlErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e CrawlErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// aggregator records the failures of a crawl from multiple goroutines and aborts the crawl
+// when the error policy tells so
+type aggregator struct {
+	errs    CrawlErrors
+	aborted bool
+	policy  ErrorPolicy
+	// abort cancels the pipeline
+	abort context.CancelFunc
+	mu    *sync.Mutex
+}
+
+// newAggregator creates an aggregator cancelling the pipeline on abort
+func newAggregator(policy ErrorPolicy, abort context.CancelFunc) *aggregator {
+	return &aggregator{
+		policy: policy,
+		abort:  abort,
+		mu:     new(sync.Mutex),
+	}
+}
+
+// addError records the failure unless the crawl has been aborted, the failures after
+// the abort are consequences of the abort
+func (a *aggregator) addError(path string, stage Stage, e error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.aborted {
+		return
+	}
+	a.errs = append(a.errs, &CrawlError{Path: path, Stage: stage, Err: e})
+	if a.policy.aborts(len(a.errs)) {
+		a.aborted = true
+		a.abort()
+	}
+}
+
+// err returns the failures recorded joined with the error of the context, if any
+func (a *aggregator) err(ctxErr error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch {
+	case len(a.errs) == 0:
+		return ctxErr
+	case ctxErr == nil:
+		return a.errs
+	}
+	return errors.Join(a.errs, ctxErr)
+}
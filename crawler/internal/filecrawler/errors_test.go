@@ -0,0 +1,112 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"crawler/internal/workerpool"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawlErrors(t *testing.T) {
+	errAccumulate := errors.New("accumulate")
+	fileSystem := sizelessFileSystem{memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/broken.json", `{"data": `).
+		AddFile("root/large.json", `{"data": 2, "padding": "`+strings.Repeat("x", 100)+`"}`).
+		AddFile("root/panic.json", `{"data": 13}`).
+		AddFile("root/inner/3.json", `{"data": 3}`).
+		Build()}
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		MaxFileSize:        64,
+		ErrorPolicy:        SkipAndCollect,
+	}, func(current TestType, accum TestAccumulator) TestAccumulator {
+		if current.Data == 13 {
+			panic(errAccumulate)
+		}
+		return sum(current, accum)
+	}, combiner)
+
+	require.EqualValues(t, 4, result.Sum)
+
+	var crawlErrs CrawlErrors
+	require.ErrorAs(t, err, &crawlErrs)
+	stages := make(map[string]Stage)
+	for _, crawlErr := range crawlErrs {
+		stages[crawlErr.Path] = crawlErr.Stage
+	}
+	require.Equal(t, map[string]Stage{
+		"root/broken.json": StageDecode,
+		"root/large.json":  StageRead,
+		"root/panic.json":  StageAccumulate,
+	}, stages)
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorIs(t, err, ErrFileTooLarge)
+	require.ErrorIs(t, err, errAccumulate)
+	require.ErrorContains(t, err, "decode root/broken.json: unexpected EOF")
+}
+
+func TestCrawlErrorsNonErrorPanic(t *testing.T) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/panic.json", `{"data": 13}`).
+		Build()
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        SkipAndCollect,
+	}, func(current TestType, accum TestAccumulator) TestAccumulator {
+		if current.Data == 13 {
+			panic("unlucky")
+		}
+		return sum(current, accum)
+	}, combiner)
+
+	require.EqualValues(t, 1, result.Sum)
+
+	// the panic is recorded even though it is not an error
+	var panicErr *workerpool.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "unlucky", panicErr.Value)
+	require.ErrorContains(t, err, "accumulate root/panic.json: panic: unlucky")
+}
+
+func TestCrawlErrorsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := New[TestType, TestAccumulator]()
+	_, err := c.Collect(ctx, memfs.NewBuilder().Build(), "missing", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        SkipAndCollect,
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, context.Canceled)
+	var crawlErrs CrawlErrors
+	if errors.As(err, &crawlErrs) {
+		require.Equal(t, StageSearch, crawlErrs[0].Stage)
+		require.ErrorIs(t, crawlErrs[0], os.ErrNotExist)
+	}
+}
+
+func TestStage(t *testing.T) {
+	require.Equal(t, "search", StageSearch.String())
+	require.Equal(t, "accumulate", StageAccumulate.String())
+	require.Equal(t, "Stage(10)", Stage(10).String())
+}
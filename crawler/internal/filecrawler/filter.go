@@ -0,0 +1,97 @@
+package crawler
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// Pattern matches the entries found during the search stage. A pattern is created either by
+// Glob, matching the name of an entry, or by Regexp, matching its path.
+type Pattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+// Glob creates a pattern matching the name of an entry, which is the last element of its path,
+// with the shell pattern syntax of filepath.Match, such as "*.json" or "node_modules".
+func Glob(pattern string) Pattern {
+	return Pattern{glob: pattern}
+}
+
+// Regexp creates a pattern matching the path of an entry, as joined by the file system, with
+// the regular expression, such as `/testdata/` or `\.jsonl?$`.
+func Regexp(re *regexp.Regexp) Pattern {
+	return Pattern{re: re}
+}
+
+// String returns the source of the pattern.
+func (p Pattern) String() string {
+	if p.re != nil {
+		return p.re.String()
+	}
+	return p.glob
+}
+
+// match reports whether the entry with the name and the path matches the pattern
+func (p Pattern) match(name, path string) bool {
+	if p.re != nil {
+		return p.re.MatchString(path)
+	}
+	// the pattern is validated before the crawl, so the error is not possible
+	matched, _ := filepath.Match(p.glob, name)
+	return matched
+}
+
+// validate checks the syntax of a glob pattern
+func (p Pattern) validate() error {
+	if p.re != nil {
+		return nil
+	}
+	if _, err := filepath.Match(p.glob, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", p.glob, err)
+	}
+	return nil
+}
+
+// filter decides which entries are traversed according to the Include and Exclude patterns
+// of the configuration
+type filter struct {
+	include []Pattern
+	exclude []Pattern
+}
+
+// newFilter validates the patterns of the configuration
+func newFilter(conf Configuration) (filter, error) {
+	for _, patterns := range [][]Pattern{conf.Include, conf.Exclude} {
+		for _, p := range patterns {
+			if err := p.validate(); err != nil {
+				return filter{}, err
+			}
+		}
+	}
+	return filter{include: conf.Include, exclude: conf.Exclude}, nil
+}
+
+// skipDir reports whether the directory is excluded, so it is not descended into
+func (f filter) skipDir(name, path string) bool {
+	return matchAny(f.exclude, name, path)
+}
+
+// skipFile reports whether the file is excluded or not included, so it is not read
+func (f filter) skipFile(name, path string) bool {
+	if matchAny(f.exclude, name, path) {
+		return true
+	}
+	return len(f.include) > 0 && !matchAny(f.include, name, path)
+}
+
+// matchAny reports whether the entry matches any of the patterns
+func matchAny(patterns []Pattern, name, path string) bool {
+	for _, p := range patterns {
+		if p.match(name, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingFileSystem records the directories read and the files opened
+type recordingFileSystem struct {
+	fs.FileSystem
+
+	mu     sync.Mutex
+	read   []string
+	opened []string
+}
+
+func (r *recordingFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	r.mu.Lock()
+	r.read = append(r.read, name)
+	r.mu.Unlock()
+	return r.FileSystem.ReadDir(name)
+}
+
+func (r *recordingFileSystem) Open(name string) (fs.File, error) {
+	r.mu.Lock()
+	r.opened = append(r.opened, name)
+	r.mu.Unlock()
+	return r.FileSystem.Open(name)
+}
+
+func sum(current TestType, accum TestAccumulator) TestAccumulator {
+	accum.Sum += current.Data
+	return accum
+}
+
+func TestFilters(t *testing.T) {
+	fileSystem := &recordingFileSystem{FileSystem: memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/notes.txt", "not a json").
+		AddFile("root/node_modules/2.json", `{"data": 2}`).
+		AddFile("root/src/3.json", `{"data": 3}`).
+		AddFile("root/src/testdata/4.json", `{"data": 4}`).
+		AddFile("root/src/5.json.bak", "not a json").
+		Build()}
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Include:            []Pattern{Glob("*.json")},
+		Exclude:            []Pattern{Glob("node_modules"), Regexp(regexp.MustCompile(`/testdata(/|$)`))},
+	}, sum, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 4, result.Sum)
+	require.ElementsMatch(t, []string{"root", "root/src"}, fileSystem.read)
+	require.ElementsMatch(t, []string{"root/1.json", "root/src/3.json"}, fileSystem.opened)
+}
+
+func TestInvalidPattern(t *testing.T) {
+	c := New[TestType, TestAccumulator]()
+	_, err := c.Collect(context.Background(), memfs.NewBuilder().Build(), ".", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		Exclude:            []Pattern{Glob("[")},
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, filepath.ErrBadPattern)
+	require.Equal(t, "[", Glob("[").String())
+}
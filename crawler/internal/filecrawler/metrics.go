@@ -0,0 +1,139 @@
+package crawler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StageMetrics holds the metrics of a stage of the crawl.
+type StageMetrics struct {
+	Workers int   // Number of workers of the stage.
+	Items   int64 // Number of directories or files handled by the stage.
+	// Busy is the total time the workers spent handling items.
+	Busy time.Duration
+	// QueueWait is the total time the items waited to be picked up by a worker after they had
+	// been produced by the previous stage.
+	QueueWait time.Duration
+	// Utilization is the share of the time of the crawl the workers were busy, from 0 to 1.
+	Utilization float64
+}
+
+// MeanBusy returns the mean time an item was handled for.
+func (s StageMetrics) MeanBusy() time.Duration {
+	if s.Items == 0 {
+		return 0
+	}
+	return s.Busy / time.Duration(s.Items)
+}
+
+// MeanQueueWait returns the mean time an item waited for a worker.
+func (s StageMetrics) MeanQueueWait() time.Duration {
+	if s.Items == 0 {
+		return 0
+	}
+	return s.QueueWait / time.Duration(s.Items)
+}
+
+// Metrics holds the metrics of a crawl, which help to choose the numbers of workers of the
+// stages: a stage with a high utilization and long queue waits of its items lacks workers,
+// while a stage with a low utilization has too many of them.
+type Metrics struct {
+	Elapsed    time.Duration // Duration of the crawl.
+	Search     StageMetrics  // Listing directories, the items are directories.
+	Read       StageMetrics  // Reading and decoding files, the items are files.
+	Accumulate StageMetrics  // Accumulating decoded values, the items are files.
+}
+
+// stageMetrics collects the metrics of a stage
+type stageMetrics struct {
+	items atomic.Int64
+	busy  atomic.Int64
+	wait  atomic.Int64
+}
+
+// metrics collects the metrics of a crawl, a nil metrics collects nothing
+type metrics struct {
+	start      time.Time
+	stages     [StageAccumulate + 1]stageMetrics
+	discovered sync.Map
+}
+
+// newMetrics creates the metrics of a crawl if there is anyone to report them to
+func newMetrics(conf Configuration, root string) *metrics {
+	if conf.OnMetrics == nil {
+		return nil
+	}
+	m := &metrics{start: time.Now()}
+	m.discover(root)
+	return m
+}
+
+// now returns the current time if the metrics are collected
+func (m *metrics) now() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// discover records the time the directory was found at
+func (m *metrics) discover(dir string) {
+	if m == nil {
+		return
+	}
+	m.discovered.Store(dir, time.Now())
+}
+
+// scanned records the wait of the directory for a search worker
+func (m *metrics) scanned(dir string, begin time.Time) {
+	if m == nil {
+		return
+	}
+	if found, ok := m.discovered.LoadAndDelete(dir); ok {
+		m.stages[StageSearch].wait.Add(int64(begin.Sub(found.(time.Time))))
+	}
+}
+
+// queued records the wait of an item for a worker of the stage
+func (m *metrics) queued(stage Stage, since time.Time) {
+	if m == nil {
+		return
+	}
+	m.stages[stage].wait.Add(int64(time.Since(since)))
+}
+
+// handled records an item handled by a worker of the stage from begin till now
+func (m *metrics) handled(stage Stage, begin time.Time) {
+	if m == nil {
+		return
+	}
+	m.stages[stage].items.Add(1)
+	m.stages[stage].busy.Add(int64(time.Since(begin)))
+}
+
+// report reports the metrics collected to OnMetrics of the configuration
+func (m *metrics) report(conf Configuration) {
+	if m == nil {
+		return
+	}
+	elapsed := time.Since(m.start)
+	stage := func(s Stage, workers int) StageMetrics {
+		result := StageMetrics{
+			Workers:   workers,
+			Items:     m.stages[s].items.Load(),
+			Busy:      time.Duration(m.stages[s].busy.Load()),
+			QueueWait: time.Duration(m.stages[s].wait.Load()),
+		}
+		if workers > 0 && elapsed > 0 {
+			result.Utilization = min(float64(result.Busy)/(float64(workers)*float64(elapsed)), 1)
+		}
+		return result
+	}
+	conf.OnMetrics(Metrics{
+		Elapsed:    elapsed,
+		Search:     stage(StageSearch, conf.SearchWorkers),
+		Read:       stage(StageRead, conf.FileWorkers),
+		Accumulate: stage(StageAccumulate, conf.AccumulatorWorkers),
+	})
+}
@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	const latency = 5 * time.Millisecond
+	builder := memfs.NewBuilder().WithLatency(latency)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			builder.AddFile(fmt.Sprintf("root/%d/%d.json", i, j), fmt.Sprintf(`{"data": %d}`, j))
+		}
+	}
+
+	var (
+		metrics Metrics
+		calls   int
+	)
+	c := New[TestType, TestAccumulator]()
+	_, err := c.Collect(context.Background(), builder.Build(), "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        1,
+		AccumulatorWorkers: 3,
+		OnMetrics: func(m Metrics) {
+			metrics = m
+			calls++
+		},
+	}, func(current TestType, accum TestAccumulator) TestAccumulator {
+		time.Sleep(time.Millisecond)
+		return sum(current, accum)
+	}, combiner)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, calls)
+	require.Equal(t, 2, metrics.Search.Workers)
+	require.Equal(t, 1, metrics.Read.Workers)
+	require.Equal(t, 3, metrics.Accumulate.Workers)
+	require.EqualValues(t, 5, metrics.Search.Items)
+	require.EqualValues(t, 20, metrics.Read.Items)
+	require.EqualValues(t, 20, metrics.Accumulate.Items)
+
+	require.GreaterOrEqual(t, metrics.Search.MeanBusy(), latency)
+	require.GreaterOrEqual(t, metrics.Read.MeanBusy(), latency)
+	require.GreaterOrEqual(t, metrics.Accumulate.MeanBusy(), time.Millisecond)
+	// a single read worker keeps the files of the search stage waiting
+	require.Positive(t, metrics.Read.QueueWait)
+	require.Positive(t, metrics.Search.MeanQueueWait())
+
+	for _, stage := range []StageMetrics{metrics.Search, metrics.Read, metrics.Accumulate} {
+		require.Greater(t, stage.Utilization, 0.0)
+		require.LessOrEqual(t, stage.Utilization, 1.0)
+	}
+	require.GreaterOrEqual(t, metrics.Elapsed, 20*latency)
+	require.Zero(t, StageMetrics{}.MeanQueueWait())
+}
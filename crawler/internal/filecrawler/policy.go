@@ -0,0 +1,33 @@
+package crawler
+
+// ErrorPolicy controls whether the failures of individual files and directories abort the
+// crawl. The zero value is FailFast.
+type ErrorPolicy struct {
+	// tolerated is the number of failures which do not abort the crawl, negative for any number
+	tolerated int
+}
+
+var (
+	// FailFast aborts the crawl at the first failure, which Collect returns without a result.
+	FailFast = ErrorPolicy{}
+
+	// SkipAndCollect skips the failed files and directories, Collect returns the result of the
+	// rest of them along with the failures as CrawlErrors.
+	SkipAndCollect = ErrorPolicy{tolerated: -1}
+)
+
+// Threshold skips up to n failed files and directories, returning the failures along with the
+// result as SkipAndCollect does, and aborts the crawl at the failure number n+1 as FailFast
+// does. Threshold(0) is FailFast.
+// Threshold panics if n is negative.
+func Threshold(n int) ErrorPolicy {
+	if n < 0 {
+		panic("Invalid threshold")
+	}
+	return ErrorPolicy{tolerated: n}
+}
+
+// aborts reports whether the number of failures aborts the crawl
+func (p ErrorPolicy) aborts(failures int) bool {
+	return p.tolerated >= 0 && failures > p.tolerated
+}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func runWithPolicy(t *testing.T, policy ErrorPolicy) (TestAccumulator, error) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/broken.json", `{"data": `).
+		AddFile("root/inner/2.json", `{"data": 2}`).
+		AddFile("root/inner/invalid.json", `{"data": "x"}`).
+		AddFile("root/other/3.json", `{"data": 3}`).
+		Build()
+
+	c := New[TestType, TestAccumulator]()
+	return c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        policy,
+	}, sum, combiner)
+}
+
+func TestFailFast(t *testing.T) {
+	for _, policy := range []ErrorPolicy{FailFast, Threshold(0), {}} {
+		result, err := runWithPolicy(t, policy)
+		require.Error(t, err)
+		require.Zero(t, result)
+		var crawlErrs CrawlErrors
+		require.ErrorAs(t, err, &crawlErrs)
+		require.Len(t, crawlErrs, 1)
+	}
+}
+
+func TestSkipAndCollect(t *testing.T) {
+	result, err := runWithPolicy(t, SkipAndCollect)
+	require.EqualValues(t, 6, result.Sum)
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	var typeErr *json.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeErr)
+	require.ErrorContains(t, err, "root/broken.json")
+	require.ErrorContains(t, err, "root/inner/invalid.json")
+}
+
+func TestThreshold(t *testing.T) {
+	result, err := runWithPolicy(t, Threshold(2))
+	require.EqualValues(t, 6, result.Sum)
+	require.Error(t, err)
+
+	result, err = runWithPolicy(t, Threshold(1))
+	require.Zero(t, result)
+	var crawlErrs CrawlErrors
+	require.ErrorAs(t, err, &crawlErrs)
+	require.Len(t, crawlErrs, 2)
+
+	require.Panics(t, func() { Threshold(-1) })
+}
+
+func TestSkipMissingDirectory(t *testing.T) {
+	fileSystem := &recordingFileSystem{FileSystem: memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		Build()}
+
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "missing", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        SkipAndCollect,
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Zero(t, result)
+}
@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultProgressInterval is the interval of progress reports if none is configured.
+const DefaultProgressInterval = 100 * time.Millisecond
+
+// Progress is a snapshot of the progress of a crawl.
+type Progress struct {
+	DirectoriesScanned int64 // Number of directories listed.
+	FilesDiscovered    int64 // Number of files passed to be read.
+	FilesProcessed     int64 // Number of files read and decoded, including the failed ones.
+	BytesRead          int64 // Number of bytes read from files.
+}
+
+// progress counts the progress of a crawl and reports it periodically
+type progress struct {
+	dirsScanned     atomic.Int64
+	filesDiscovered atomic.Int64
+	filesProcessed  atomic.Int64
+	bytesRead       atomic.Int64
+
+	report   func(Progress)
+	interval time.Duration
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newProgress creates the progress of a crawl reporting to OnProgress of the configuration
+func newProgress(conf Configuration) *progress {
+	interval := conf.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	return &progress{
+		report:   conf.OnProgress,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+// snapshot returns the current values of the counters
+func (p *progress) snapshot() Progress {
+	return Progress{
+		DirectoriesScanned: p.dirsScanned.Load(),
+		FilesDiscovered:    p.filesDiscovered.Load(),
+		FilesProcessed:     p.filesProcessed.Load(),
+		BytesRead:          p.bytesRead.Load(),
+	}
+}
+
+// start starts reporting the progress periodically, if there is anyone to report to
+func (p *progress) start() {
+	if p.report == nil {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.report(p.snapshot())
+			}
+		}
+	}()
+}
+
+// stop stops the periodic reports and reports the final progress
+func (p *progress) stop() {
+	if p.report == nil {
+		return
+	}
+	close(p.done)
+	p.wg.Wait()
+	p.report(p.snapshot())
+}
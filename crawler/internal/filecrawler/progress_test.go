@@ -0,0 +1,51 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	builder := memfs.NewBuilder().WithLatency(5 * time.Millisecond)
+	var bytes int64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			content := fmt.Sprintf(`{"data": %d}`, j)
+			bytes += int64(len(content))
+			builder.AddFile(fmt.Sprintf("root/%d/%d.json", i, j), content)
+		}
+	}
+
+	// the calls are sequential, so no synchronization is needed
+	var reports []Progress
+	c := New[TestType, TestAccumulator]()
+	_, err := c.Collect(context.Background(), builder.Build(), "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		OnProgress: func(p Progress) {
+			reports = append(reports, p)
+		},
+		ProgressInterval: 10 * time.Millisecond,
+	}, sum, combiner)
+	require.NoError(t, err)
+
+	require.Greater(t, len(reports), 1)
+	for i := 1; i < len(reports); i++ {
+		require.GreaterOrEqual(t, reports[i].DirectoriesScanned, reports[i-1].DirectoriesScanned)
+		require.GreaterOrEqual(t, reports[i].FilesDiscovered, reports[i-1].FilesDiscovered)
+		require.GreaterOrEqual(t, reports[i].FilesProcessed, reports[i-1].FilesProcessed)
+		require.GreaterOrEqual(t, reports[i].BytesRead, reports[i-1].BytesRead)
+	}
+	require.Equal(t, Progress{
+		DirectoriesScanned: 5,
+		FilesDiscovered:    20,
+		FilesProcessed:     20,
+		BytesRead:          bytes,
+	}, reports[len(reports)-1])
+}
@@ -0,0 +1,96 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// tokenBucket limits the rate of an operation shared by the workers, the bucket holds up to
+// a second worth of tokens, so that short bursts are allowed
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+// newTokenBucket creates a full bucket of the given number of tokens per second, there is no
+// bucket and no limit if the rate is not positive
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	burst := max(rate, 1)
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// reserve takes the tokens from the bucket and returns the time to wait for them, the tokens
+// may be borrowed from the future so that the operations larger than the bucket do not starve
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait blocks until the tokens are available or the context is cancelled
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	if b == nil {
+		return nil
+	}
+	delay := b.reserve(n)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	// ensure cancelling context is taken into account
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttledReader takes a token from the bucket for every byte read
+type throttledReader struct {
+	ctx    context.Context
+	r      io.Reader
+	bucket *tokenBucket
+}
+
+// newThrottledReader limits the rate the reader is read at, the reader is returned as is if
+// there is no bucket
+func newThrottledReader(ctx context.Context, r io.Reader, bucket *tokenBucket) io.Reader {
+	if bucket == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, bucket: bucket}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	// reading in chunks of a bucket at most keeps the rate even
+	if len(p) > int(t.bucket.burst) {
+		p = p[:int(t.bucket.burst)]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.bucket.wait(t.ctx, float64(n)); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFilesPerSecond(t *testing.T) {
+	builder := memfs.NewBuilder()
+	for i := range 30 {
+		builder.AddFile(fmt.Sprintf("root/%d.json", i), `{"data": 1}`)
+	}
+
+	start := time.Now()
+	result, err := New[TestType, TestAccumulator]().Collect(context.Background(), builder.Build(), "root", Configuration{
+		SearchWorkers:      4,
+		FileWorkers:        8,
+		AccumulatorWorkers: 4,
+		MaxFilesPerSecond:  20,
+	}, sum, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 30, result.Sum)
+	// 20 files are opened at once, the rest of them at 20 files per second
+	require.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+}
+
+func TestMaxBytesPerSecond(t *testing.T) {
+	// a document of 3000 bytes
+	content := `{"data": 1, "padding": "` + strings.Repeat("x", 3000-26) + `"}`
+	builder := memfs.NewBuilder()
+	for i := range 2 {
+		builder.AddFile(fmt.Sprintf("root/%d.json", i), content)
+	}
+	progress := Progress{}
+
+	start := time.Now()
+	result, err := New[TestType, TestAccumulator]().Collect(context.Background(), builder.Build(), "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        2,
+		AccumulatorWorkers: 1,
+		MaxBytesPerSecond:  4000,
+		OnProgress:         func(p Progress) { progress = p },
+	}, sum, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 2, result.Sum)
+	require.EqualValues(t, 6000, progress.BytesRead)
+	// 4000 bytes are read at once, the rest of them at 4000 bytes per second
+	require.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+}
+
+func TestRateLimitCancelled(t *testing.T) {
+	builder := memfs.NewBuilder()
+	for i := range 10 {
+		builder.AddFile(fmt.Sprintf("root/%d.json", i), `{"data": 1}`)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := New[TestType, TestAccumulator]().Collect(ctx, builder.Build(), "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        2,
+		AccumulatorWorkers: 1,
+		MaxFilesPerSecond:  0.1,
+		ErrorPolicy:        SkipAndCollect,
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestTokenBucket(t *testing.T) {
+	require.Nil(t, newTokenBucket(0))
+
+	bucket := newTokenBucket(10)
+	require.Zero(t, bucket.reserve(10))
+	// the tokens are borrowed from the future
+	delay := bucket.reserve(5)
+	require.Greater(t, delay, 400*time.Millisecond)
+	require.LessOrEqual(t, delay, 500*time.Millisecond)
+}
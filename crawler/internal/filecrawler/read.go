@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"io"
+	"os"
+	"sync"
+)
+
+// fileReader reads the files of a crawl one reader of a file at a time, within the limits of
+// the rates of the configuration and retrying the failures of reading
+type fileReader struct {
+	ctx        context.Context
+	fileSystem fs.FileSystem
+	conf       Configuration
+	storage    *fileStorage
+	opens      *tokenBucket
+	reads      *tokenBucket
+	prog       *progress
+}
+
+// newFileReader creates the reader of the files of a crawl
+func newFileReader(ctx context.Context, fileSystem fs.FileSystem, conf Configuration, prog *progress) *fileReader {
+	return &fileReader{
+		ctx:        ctx,
+		fileSystem: fileSystem,
+		conf:       conf,
+		storage:    newFileStorage(),
+		// the limits of the rates are shared by the workers
+		opens: newTokenBucket(conf.MaxFilesPerSecond),
+		reads: newTokenBucket(conf.MaxBytesPerSecond),
+		prog:  prog,
+	}
+}
+
+// lock locks the file, so that it is read by one reader at a time
+func (r *fileReader) lock(name string) *sync.Mutex {
+	r.storage.mu.RLock()
+	// allow readers to read file content
+	fMu, exists := r.storage.fileMu[name]
+	r.storage.mu.RUnlock()
+
+	// if there is no data yet then one reader should become a writer
+	if !exists {
+		r.storage.mu.Lock()
+		fMu, exists = r.storage.fileMu[name]
+		// the mutex could have already been created during the waiting time
+		if !exists {
+			fMu = new(sync.Mutex)
+			r.storage.fileMu[name] = fMu
+		}
+		r.storage.mu.Unlock()
+	}
+	// everyone who wants to read a file will read it
+	fMu.Lock()
+	return fMu
+}
+
+// read opens the file and passes its contents to fn, a file failed to be read is read again
+// from its beginning, the error of reading is returned along with the error of fn, which is
+// not retried
+func (r *fileReader) read(name string, fn func(io.Reader) error) (readErr, fnErr error) {
+	readErr = r.conf.Retry.do(r.ctx, r.conf.Statistics, func() error {
+		if err := r.opens.wait(r.ctx, 1); err != nil {
+			return err
+		}
+		f, err := r.fileSystem.Open(name)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		defer r.lock(name).Unlock()
+
+		reader := newThrottledReader(r.ctx, f, r.reads)
+		if r.conf.MaxFileSize > 0 {
+			// the size reported by the file system may be missing or wrong
+			reader = newLimitedReader(reader, r.conf.MaxFileSize)
+		}
+		// the errors of reading are told from the errors of fn
+		tracked := &trackingReader{r: reader}
+
+		fnErr = fn(tracked)
+		r.prog.bytesRead.Add(tracked.n)
+		return tracked.err
+	})
+	return readErr, fnErr
+}
+
+// readDir reads the directory, a directory failed to be read is read again
+func readDir(ctx context.Context, fileSystem fs.FileSystem, conf Configuration, name string) ([]os.DirEntry, error) {
+	var dirEntries []os.DirEntry
+	err := conf.Retry.do(ctx, conf.Statistics, func() (err error) {
+		dirEntries, err = fileSystem.ReadDir(name)
+		return err
+	})
+	return dirEntries, err
+}
@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// RetryPolicy controls how the directories and files failing with transient errors are read
+// again, so that flaky network file systems or rate-limited object stores do not fail the
+// crawl. The zero value does not retry.
+//
+// Reading a directory is retried as a whole, and so is opening and reading a file, which is
+// reopened and decoded from its beginning. Decoding errors are never retried.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts to read a directory or a file, the
+	// operations are not retried if it is less than two.
+	Attempts int
+	// Backoff, if set, returns the delay before the given retry, counted from one, see
+	// ExponentialBackoff. The operations are retried without a delay if it is not set.
+	Backoff func(retry int) time.Duration
+	// Retryable, if set, reports whether the error is transient. IsTransient is used if it is
+	// not set.
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff returns a Backoff doubling the delay with every retry, starting with base
+// and never exceeding max.
+// ExponentialBackoff panics if base is not positive or max is less than base.
+func ExponentialBackoff(base, max time.Duration) func(retry int) time.Duration {
+	if base <= 0 || max < base {
+		panic("Invalid backoff")
+	}
+	return func(retry int) time.Duration {
+		delay := base
+		for i := 1; i < retry && delay < max; i++ {
+			delay *= 2
+		}
+		return min(delay, max)
+	}
+}
+
+// IsTransient reports whether the error may disappear if the operation is retried. Missing
+// files, denied permissions, invalid arguments, files exceeding MaxFileSize and cancelled
+// contexts are not transient, while the rest of the errors are.
+func IsTransient(err error) bool {
+	for _, permanent := range []error{
+		fs.ErrNotExist,
+		fs.ErrPermission,
+		fs.ErrInvalid,
+		ErrFileTooLarge,
+		context.Canceled,
+		context.DeadlineExceeded,
+	} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryable reports whether the failed operation should be retried
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransient(err)
+}
+
+// do calls op until it succeeds, fails with an error which is not retryable or runs out of
+// attempts, the last error is returned
+func (p RetryPolicy) do(ctx context.Context, stats *Statistics, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= p.Attempts || !p.retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		if p.Backoff != nil {
+			timer := time.NewTimer(p.Backoff(attempt))
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+		stats.retry()
+	}
+}
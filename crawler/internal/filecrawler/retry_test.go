@@ -0,0 +1,190 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"errors"
+	iofs "io/fs"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errFlaky = errors.New("connection reset")
+
+// flakyFileSystem fails the given number of times before every directory read and every file
+// opening and reading succeeds
+type flakyFileSystem struct {
+	fs.FileSystem
+	failures int
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newFlakyFileSystem(fileSystem fs.FileSystem, failures int) *flakyFileSystem {
+	return &flakyFileSystem{FileSystem: fileSystem, failures: failures, attempts: make(map[string]int)}
+}
+
+// fail reports whether the operation fails
+func (f *flakyFileSystem) fail(op string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts[op]++
+	return f.attempts[op] <= f.failures
+}
+
+func (f *flakyFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	if f.fail("readdir " + name) {
+		return nil, errFlaky
+	}
+	return f.FileSystem.ReadDir(name)
+}
+
+func (f *flakyFileSystem) Open(name string) (fs.File, error) {
+	if f.fail("open " + name) {
+		return nil, errFlaky
+	}
+	file, err := f.FileSystem.Open(name)
+	if err != nil || !f.fail("read "+name) {
+		return file, err
+	}
+	return flakyFile{file}, nil
+}
+
+// flakyFile fails after reading the first byte
+type flakyFile struct {
+	fs.File
+}
+
+func (f flakyFile) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, _ := f.File.Read(p[:1])
+	return n, errFlaky
+}
+
+func retryFileSystem() fs.FileSystem {
+	return memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/inner/2.json", `{"data": 2}`).
+		AddFile("root/inner/3.json", `{"data": 3}`).
+		Build()
+}
+
+func collectWithRetry(fileSystem fs.FileSystem, root string, policy RetryPolicy, stats *Statistics) (TestAccumulator, error) {
+	c := New[TestType, TestAccumulator]()
+	return c.Collect(context.Background(), fileSystem, root, Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Retry:              policy,
+		Statistics:         stats,
+		ErrorPolicy:        SkipAndCollect,
+	}, sum, combiner)
+}
+
+func TestRetry(t *testing.T) {
+	fileSystem := newFlakyFileSystem(retryFileSystem(), 2)
+	stats := &Statistics{}
+
+	result, err := collectWithRetry(fileSystem, "root", RetryPolicy{
+		Attempts: 5,
+		Backoff:  ExponentialBackoff(time.Millisecond, 4*time.Millisecond),
+	}, stats)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 6, result.Sum)
+	// every directory read and every file opening and reading failed twice
+	require.EqualValues(t, 2*(2+3+3), stats.Retries)
+}
+
+func TestRetryAttemptsExhausted(t *testing.T) {
+	result, err := collectWithRetry(newFlakyFileSystem(retryFileSystem(), 2), "root", RetryPolicy{Attempts: 2}, nil)
+
+	require.ErrorIs(t, err, errFlaky)
+	require.Zero(t, result)
+
+	var crawlErrs CrawlErrors
+	require.ErrorAs(t, err, &crawlErrs)
+	require.Len(t, crawlErrs, 1)
+	require.Equal(t, StageSearch, crawlErrs[0].Stage)
+}
+
+func TestNoRetry(t *testing.T) {
+	fileSystem := newFlakyFileSystem(retryFileSystem(), 1)
+
+	_, err := collectWithRetry(fileSystem, "root", RetryPolicy{}, nil)
+
+	require.ErrorIs(t, err, errFlaky)
+	require.Equal(t, 1, fileSystem.attempts["readdir root"])
+}
+
+func TestRetryPermanentErrors(t *testing.T) {
+	fileSystem := newFlakyFileSystem(memfs.NewBuilder().
+		AddFile("root/broken.json", `{"data": `).
+		Build(), 0)
+	stats := &Statistics{}
+
+	_, err := collectWithRetry(fileSystem, "root", RetryPolicy{Attempts: 3}, stats)
+	require.Error(t, err)
+	// decoding errors are not retried
+	require.Equal(t, 1, fileSystem.attempts["open root/broken.json"])
+
+	_, err = collectWithRetry(fileSystem, "missing", RetryPolicy{Attempts: 3}, stats)
+	require.ErrorIs(t, err, iofs.ErrNotExist)
+	require.Equal(t, 1, fileSystem.attempts["readdir missing"])
+	require.Zero(t, stats.Retries)
+}
+
+func TestRetryClassifier(t *testing.T) {
+	fileSystem := newFlakyFileSystem(retryFileSystem(), 1)
+
+	_, err := collectWithRetry(fileSystem, "root", RetryPolicy{
+		Attempts:  3,
+		Retryable: func(err error) bool { return !errors.Is(err, errFlaky) },
+	}, nil)
+
+	require.ErrorIs(t, err, errFlaky)
+	require.Equal(t, 1, fileSystem.attempts["readdir root"])
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := New[TestType, TestAccumulator]().Collect(ctx, newFlakyFileSystem(retryFileSystem(), 1), "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		Retry:              RetryPolicy{Attempts: 2, Backoff: func(int) time.Duration { return time.Hour }},
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, errFlaky)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	require.Equal(t, 10*time.Millisecond, backoff(1))
+	require.Equal(t, 20*time.Millisecond, backoff(2))
+	require.Equal(t, 40*time.Millisecond, backoff(3))
+	require.Equal(t, 50*time.Millisecond, backoff(4))
+	require.Equal(t, 50*time.Millisecond, backoff(100))
+
+	require.Panics(t, func() { ExponentialBackoff(0, time.Second) })
+	require.Panics(t, func() { ExponentialBackoff(time.Second, time.Millisecond) })
+}
+
+func TestIsTransient(t *testing.T) {
+	require.True(t, IsTransient(errFlaky))
+	require.False(t, IsTransient(&iofs.PathError{Op: "open", Path: "x", Err: iofs.ErrNotExist}))
+	require.False(t, IsTransient(ErrFileTooLarge))
+	require.False(t, IsTransient(context.Canceled))
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrFileTooLarge is returned when a file being read turns out to exceed MaxFileSize.
+var ErrFileTooLarge = errors.New("file too large")
+
+// skipLargeFile reports whether the file exceeds MaxFileSize of the configuration, a skipped
+// file is counted and reported to OnSkip
+func skipLargeFile(conf Configuration, entry os.DirEntry, path string) (bool, error) {
+	if conf.MaxFileSize <= 0 {
+		return false, nil
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return false, err
+	}
+	size := info.Size()
+	if size <= conf.MaxFileSize {
+		return false, nil
+	}
+	conf.Statistics.skipFile(size)
+	if conf.OnSkip != nil {
+		conf.OnSkip(path, size)
+	}
+	return true, nil
+}
+
+// limitedReader reads at most limit bytes of a file and fails with ErrFileTooLarge if the file
+// has more of them
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// newLimitedReader limits the reader to the given number of bytes
+func newLimitedReader(r io.Reader, limit int64) *limitedReader {
+	return &limitedReader{r: r, remaining: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrFileTooLarge
+	}
+	// one byte more than the limit is read to tell whether the file exceeds it
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrFileTooLarge
+	}
+	return n, err
+}
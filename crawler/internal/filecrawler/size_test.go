@@ -0,0 +1,144 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"encoding/json"
+	"io"
+	iofs "io/fs"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFileSize(t *testing.T) {
+	large := `{"data": 100, "padding": "` + strings.Repeat("x", 100) + `"}`
+	fileSystem := &recordingFileSystem{FileSystem: memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/large.json", large).
+		AddFile("root/inner/2.json", `{"data": 2}`).
+		AddFile("root/inner/large.json", large).
+		Build()}
+
+	var (
+		mu      sync.Mutex
+		skipped = make(map[string]int64)
+		stats   Statistics
+	)
+	c := New[TestType, TestAccumulator]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		MaxFileSize:        64,
+		OnSkip: func(path string, size int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			skipped[path] = size
+		},
+		Statistics: &stats,
+	}, sum, combiner)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 3, result.Sum)
+	require.ElementsMatch(t, []string{"root/1.json", "root/inner/2.json"}, fileSystem.opened)
+	require.Equal(t, map[string]int64{
+		"root/large.json":       int64(len(large)),
+		"root/inner/large.json": int64(len(large)),
+	}, skipped)
+	require.Equal(t, Statistics{SkippedFiles: 2, SkippedBytes: 2 * int64(len(large))}, stats)
+}
+
+func TestLargeFile(t *testing.T) {
+	type document struct {
+		Data    int64    `json:"data"`
+		Padding []string `json:"padding"`
+	}
+	padding := make([]string, 1000)
+	for i := range padding {
+		padding[i] = strings.Repeat("x", 10)
+	}
+	content, err := json.Marshal(document{Data: 7, Padding: padding})
+	require.NoError(t, err)
+
+	fileSystem := memfs.NewBuilder().AddFile("root/large.json", string(content)).Build()
+	c := New[document, int64]()
+	result, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+	}, func(current document, accum int64) int64 {
+		require.Len(t, current.Padding, 1000)
+		return accum + current.Data
+	}, func(current int64, accum int64) int64 {
+		return accum + current
+	})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 7, result)
+}
+
+// sizelessFileSystem reports zero sizes of the files
+type sizelessFileSystem struct {
+	fs.FileSystem
+}
+
+func (s sizelessFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	entries, err := s.FileSystem.ReadDir(name)
+	for i, entry := range entries {
+		entries[i] = sizelessEntry{entry}
+	}
+	return entries, err
+}
+
+type sizelessEntry struct {
+	os.DirEntry
+}
+
+func (s sizelessEntry) Info() (iofs.FileInfo, error) {
+	info, err := s.DirEntry.Info()
+	return sizelessInfo{info}, err
+}
+
+type sizelessInfo struct {
+	iofs.FileInfo
+}
+
+func (s sizelessInfo) Size() int64 {
+	return 0
+}
+
+func TestMaxFileSizeWhileReading(t *testing.T) {
+	fileSystem := sizelessFileSystem{memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/large.json", `{"data": 100, "padding": "`+strings.Repeat("x", 100)+`"}`).
+		Build()}
+
+	var stats Statistics
+	c := New[TestType, TestAccumulator]()
+	_, err := c.Collect(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		MaxFileSize:        64,
+		Statistics:         &stats,
+	}, sum, combiner)
+
+	require.ErrorIs(t, err, ErrFileTooLarge)
+	require.ErrorContains(t, err, "root/large.json")
+	require.Zero(t, stats.SkippedFiles)
+}
+
+func TestLimitedReader(t *testing.T) {
+	content, err := io.ReadAll(newLimitedReader(strings.NewReader("12345"), 5))
+	require.NoError(t, err)
+	require.Equal(t, "12345", string(content))
+
+	content, err = io.ReadAll(newLimitedReader(strings.NewReader("123456"), 5))
+	require.ErrorIs(t, err, ErrFileTooLarge)
+	require.Equal(t, "12345", string(content))
+}
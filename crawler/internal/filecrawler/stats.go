@@ -0,0 +1,39 @@
+package crawler
+
+import "sync/atomic"
+
+// Statistics holds the counters of a crawl. When the Configuration points to Statistics,
+// Collect adds its counters to them, so they should be read after Collect returns or with the
+// functions of the sync/atomic package.
+type Statistics struct {
+	SkippedFiles int64 // Number of files skipped for exceeding MaxFileSize.
+	SkippedBytes int64 // Total size of the files skipped for exceeding MaxFileSize.
+	Retries      int64 // Number of directory and file reads retried after transient errors.
+
+	DuplicateFiles int64 // Number of files skipped as duplicates of the files read.
+}
+
+// skipFile records a file skipped for its size
+func (s *Statistics) skipFile(size int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.SkippedFiles, 1)
+	atomic.AddInt64(&s.SkippedBytes, size)
+}
+
+// retry records a retried read
+func (s *Statistics) retry() {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.Retries, 1)
+}
+
+// duplicate records a file skipped as a duplicate
+func (s *Statistics) duplicate() {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.DuplicateFiles, 1)
+}
@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/workerpool"
+)
+
+// stream sends the intermediate results of the accumulator workers once they are made of
+// a batch of files
+type stream[R any] struct {
+	results chan R
+	batch   int
+}
+
+// accumulated counts the file accumulated to the intermediate result, which is sent once the
+// batch is complete
+func (s *stream[R]) accumulated(ctx context.Context, p *partial[R]) {
+	if s == nil {
+		return
+	}
+	p.files++
+	if p.files >= s.batch {
+		s.send(ctx, p)
+	}
+}
+
+// send sends the intermediate result, if it is made of any file, and starts a new one
+func (s *stream[R]) send(ctx context.Context, p *partial[R]) {
+	if p.files == 0 {
+		return
+	}
+	select {
+	// ensure cancelling context is taken into account
+	case <-ctx.Done():
+		return
+	case s.results <- p.value:
+	}
+	var zero R
+	p.value = zero
+	p.files = 0
+}
+
+// CollectStream represents crawlerImpl implementation of function with the same name
+func (c *crawlerImpl[T, R]) CollectStream(
+	ctx context.Context,
+	fileSystem fs.FileSystem,
+	root string,
+	conf Configuration,
+	batchSize int,
+	accumulator workerpool.Accumulator[T, R],
+) (<-chan R, <-chan error) {
+	if batchSize < 1 {
+		panic("Invalid batch size")
+	}
+	// the results sent are not saved by checkpoints
+	conf.Checkpoint = nil
+
+	s := &stream[R]{results: make(chan R), batch: batchSize}
+	// the error is kept until it is received
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(s.results)
+		_, err := c.collect(ctx, fileSystem, root, conf, accumulator, nil, s)
+		errCh <- err
+	}()
+	return s.results, errCh
+}
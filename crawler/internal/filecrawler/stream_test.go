@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/memfs"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func streamFileSystem(files int) *memfs.FileSystem {
+	builder := memfs.NewBuilder()
+	for i := 1; i <= files; i++ {
+		builder.AddFile(fmt.Sprintf("root/%d/%d.json", i%2, i), fmt.Sprintf(`{"data": %d}`, i))
+	}
+	return builder.Build()
+}
+
+func TestCollectStream(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		results, errCh := New[TestType, TestAccumulator]().CollectStream(context.Background(), streamFileSystem(10), "root", Configuration{
+			SearchWorkers:      2,
+			FileWorkers:        2,
+			AccumulatorWorkers: workers,
+		}, 3, sum)
+
+		var total int64
+		var count int
+		for result := range results {
+			require.Positive(t, result.Sum)
+			total += result.Sum
+			count++
+		}
+		require.NoError(t, <-errCh)
+		require.EqualValues(t, 55, total)
+		if workers == 1 {
+			// three full batches and the rest of the files
+			require.Equal(t, 4, count)
+		} else {
+			require.GreaterOrEqual(t, count, 4)
+		}
+	}
+}
+
+func TestCollectStreamErrors(t *testing.T) {
+	fileSystem := memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		AddFile("root/broken.json", `{"data": `).
+		Build()
+
+	results, errCh := New[TestType, TestAccumulator]().CollectStream(context.Background(), fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        SkipAndCollect,
+	}, 1, sum)
+
+	var sums []int64
+	for result := range results {
+		sums = append(sums, result.Sum)
+	}
+	require.Equal(t, []int64{1}, sums)
+	require.ErrorContains(t, <-errCh, "root/broken.json")
+}
+
+func TestCollectStreamCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results, errCh := New[TestType, TestAccumulator]().CollectStream(ctx, streamFileSystem(20), "root", Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+	}, 1, sum)
+
+	// the results stop being received
+	<-results
+	cancel()
+	for range results {
+	}
+	require.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestCollectStreamInvalidBatchSize(t *testing.T) {
+	require.Panics(t, func() {
+		New[TestType, TestAccumulator]().CollectStream(context.Background(), streamFileSystem(1), "root", Configuration{
+			SearchWorkers:      1,
+			FileWorkers:        1,
+			AccumulatorWorkers: 1,
+		}, 0, sum)
+	})
+}
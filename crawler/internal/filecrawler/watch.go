@@ -0,0 +1,306 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/workerpool"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the interval of the polls of Poll if none is configured.
+const DefaultPollInterval = time.Second
+
+// Watcher tells Watch when the tree may have changed. The tree is compared with its previous
+// state after every wake-up, so a spurious wake-up costs a scan of the tree and nothing else.
+// A Watcher based on the notifications of the operating system, such as osfs.Watch, makes the
+// changes noticed sooner than Poll does.
+type Watcher interface {
+	// Wait blocks until the tree may have changed or the context is done, in which case it
+	// returns the error of the context.
+	Wait(ctx context.Context) error
+}
+
+// pollWatcher wakes up periodically
+type pollWatcher struct {
+	interval time.Duration
+}
+
+// Poll returns a Watcher waking up every interval, DefaultPollInterval if it is not positive.
+func Poll(interval time.Duration) Watcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return pollWatcher{interval: interval}
+}
+
+func (p pollWatcher) Wait(ctx context.Context) error {
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+	select {
+	// ensure cancelling context is taken into account
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Update is a result of a crawl watched.
+type Update[R any] struct {
+	Result R
+	// Err is the error of the crawl producing the result, see Collect.
+	Err error
+
+	Added   int // Number of files added since the previous update or failed in it.
+	Changed int // Number of files changed since the previous update.
+	Removed int // Number of files removed since the previous update.
+}
+
+// fileState identifies the version of a file
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// treeState is the state of every file of a tree
+type treeState map[string]fileState
+
+// Watch represents crawlerImpl implementation of function with the same name
+func (c *crawlerImpl[T, R]) Watch(
+	ctx context.Context,
+	fileSystem fs.FileSystem,
+	root string,
+	conf Configuration,
+	watcher Watcher,
+	accumulator workerpool.Accumulator[T, R],
+	combiner Combiner[R],
+) (<-chan Update[R], error) {
+	entryFilter, err := newFilter(conf)
+	if err != nil {
+		return nil, err
+	}
+	if watcher == nil {
+		watcher = Poll(0)
+	}
+	// the crawls are not resumed, every update starts where the previous one stopped
+	conf.Checkpoint = nil
+
+	// crawl crawls the files of the tree given
+	crawl := func(files treeState) (R, error) {
+		return c.Collect(ctx, newTreeView(fileSystem, root, files), root, conf, accumulator, combiner)
+	}
+
+	updates := make(chan Update[R])
+	go func() {
+		defer close(updates)
+
+		var (
+			result R
+			state  treeState
+		)
+		for {
+			current, err := scanTree(ctx, fileSystem, root, conf, entryFilter)
+			if ctx.Err() != nil {
+				return
+			}
+
+			var update Update[R]
+			send := true
+			switch {
+			case err != nil:
+				// the tree is scanned again next time
+				update = Update[R]{Result: result, Err: err}
+			case state == nil:
+				result, err = crawl(current)
+				update = Update[R]{Result: result, Err: err, Added: len(current)}
+				state = current.crawled(err, conf.ErrorPolicy)
+			default:
+				// the files failed to be crawled are missing from the state, so they are
+				// crawled again as if they were added
+				added, changed, removed := state.diff(current)
+				update = Update[R]{Added: len(added), Changed: len(changed), Removed: len(removed)}
+				switch {
+				case len(changed)+len(removed) > 0:
+					// the contributions of the old versions of files cannot be taken back
+					// from the result, so the tree is crawled from scratch
+					result, update.Err = crawl(current)
+					state = current.crawled(update.Err, conf.ErrorPolicy)
+				case len(added) > 0:
+					var delta R
+					delta, update.Err = crawl(added)
+					result = combiner(delta, result)
+					for name, file := range added.crawled(update.Err, conf.ErrorPolicy) {
+						state[name] = file
+					}
+				default:
+					send = false
+				}
+				update.Result = result
+			}
+
+			if send {
+				select {
+				// ensure cancelling context is taken into account
+				case <-ctx.Done():
+					return
+				case updates <- update:
+				}
+			}
+
+			if watcher.Wait(ctx) != nil {
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// diff returns the files added to the new state, changed and removed from it
+func (s treeState) diff(current treeState) (added, changed treeState, removed []string) {
+	added, changed = make(treeState), make(treeState)
+	for name, file := range current {
+		old, ok := s[name]
+		switch {
+		case !ok:
+			added[name] = file
+		case old.size != file.size || !old.modTime.Equal(file.modTime):
+			changed[name] = file
+		}
+	}
+	for name := range s {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	return added, changed, removed
+}
+
+// crawled returns the files of the state which have contributed to the result of their crawl
+// failed with err, that is all of them unless err reports the failures of some files and
+// directories, and none of them if the crawl has been aborted or failed as a whole
+func (s treeState) crawled(err error, policy ErrorPolicy) treeState {
+	if err == nil {
+		return s
+	}
+	// CrawlErrors joined with other errors mean the crawl has failed as a whole
+	crawlErrs, ok := err.(CrawlErrors)
+	if !ok || policy.aborts(len(crawlErrs)) {
+		return make(treeState)
+	}
+	failed := make(map[string]struct{}, len(crawlErrs))
+	for _, crawlErr := range crawlErrs {
+		failed[crawlErr.Path] = struct{}{}
+	}
+	crawled := make(treeState, len(s))
+	for name, file := range s {
+		if !isFailed(name, failed) {
+			crawled[name] = file
+		}
+	}
+	return crawled
+}
+
+// isFailed reports whether the file or any of its directories has failed
+func isFailed(name string, failed map[string]struct{}) bool {
+	for ; name != ""; name = parentDir(name) {
+		if _, ok := failed[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scanTree returns the state of every file of the tree, which is crawled, that is files
+// passing the filters of the configuration
+func scanTree(ctx context.Context, fileSystem fs.FileSystem, root string, conf Configuration, entryFilter filter) (treeState, error) {
+	var mu sync.Mutex
+	state := make(treeState)
+
+	agg := newAggregator(FailFast, func() {})
+	workerpool.New[string, string]().List(ctx, conf.SearchWorkers, root, func(parent string) []string {
+		dirEntries, err := readDir(ctx, fileSystem, conf, parent)
+		if err != nil {
+			agg.addError(parent, StageSearch, err)
+			return nil
+		}
+
+		var dirs []string
+		for _, entry := range dirEntries {
+			name := entry.Name()
+			join := fileSystem.Join(parent, name)
+			if entry.IsDir() {
+				if !entryFilter.skipDir(name, join) {
+					dirs = append(dirs, join)
+				}
+				continue
+			}
+			if entryFilter.skipFile(name, join) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				agg.addError(join, StageSearch, err)
+				continue
+			}
+			mu.Lock()
+			state[join] = fileState{size: info.Size(), modTime: info.ModTime()}
+			mu.Unlock()
+		}
+		return dirs
+	}, workerpool.OnPanic(func(parent string, err error) {
+		agg.addError(parent, StageSearch, err)
+	}))
+	return state, agg.err(nil)
+}
+
+// treeView is a file system showing only the given files of a tree and their directories
+type treeView struct {
+	fs.FileSystem
+	files treeState
+	dirs  map[string]struct{}
+}
+
+// newTreeView creates the view of the files of the tree
+func newTreeView(fileSystem fs.FileSystem, root string, files treeState) *treeView {
+	view := &treeView{FileSystem: fileSystem, files: files, dirs: make(map[string]struct{})}
+	for name := range files {
+		// the paths are joined by the file system, so they are split by their slashes and
+		// the separators of the operating system alike
+		for dir := parentDir(name); dir != "" && dir != root; dir = parentDir(dir) {
+			if _, ok := view.dirs[dir]; ok {
+				break
+			}
+			view.dirs[dir] = struct{}{}
+		}
+	}
+	return view
+}
+
+// parentDir returns the parent directory of the path, or an empty string if there is none
+func parentDir(name string) string {
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] == '/' || name[i] == os.PathSeparator {
+			return name[:i]
+		}
+	}
+	return ""
+}
+
+func (v *treeView) ReadDir(name string) ([]os.DirEntry, error) {
+	dirEntries, err := v.FileSystem.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	visible := dirEntries[:0:0]
+	for _, entry := range dirEntries {
+		join := v.FileSystem.Join(name, entry.Name())
+		_, isFile := v.files[join]
+		_, isDir := v.dirs[join]
+		if isFile && !entry.IsDir() || isDir && entry.IsDir() {
+			visible = append(visible, entry)
+		}
+	}
+	return visible, nil
+}
@@ -0,0 +1,239 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"crawler/internal/memfs"
+	"crawler/internal/osfs"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signalWatcher wakes up whenever it is signalled
+type signalWatcher chan struct{}
+
+func (s signalWatcher) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s:
+		return nil
+	}
+}
+
+func nextUpdate(t *testing.T, updates <-chan Update[TestAccumulator]) Update[TestAccumulator] {
+	select {
+	case update, ok := <-updates:
+		require.True(t, ok)
+		return update
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no update")
+	}
+	return Update[TestAccumulator]{}
+}
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("1.json", `{"data": 1}`)
+	write("inner/2.json", `{"data": 2}`)
+	write("notes.txt", "not a json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fileSystem := &recordingFileSystem{FileSystem: osfs.New()}
+	watcher := make(signalWatcher)
+
+	updates, err := New[TestType, TestAccumulator]().Watch(ctx, fileSystem, root, Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+		Include:            []Pattern{Glob("*.json")},
+	}, watcher, sum, combiner)
+	require.NoError(t, err)
+
+	update := nextUpdate(t, updates)
+	require.NoError(t, update.Err)
+	require.EqualValues(t, 3, update.Result.Sum)
+	require.Equal(t, 2, update.Added)
+
+	// the added files are crawled alone
+	write("inner/deeper/3.json", `{"data": 3}`)
+	fileSystem.opened = nil
+	watcher <- struct{}{}
+	update = nextUpdate(t, updates)
+	require.NoError(t, update.Err)
+	require.EqualValues(t, 6, update.Result.Sum)
+	require.Equal(t, Update[TestAccumulator]{Result: TestAccumulator{Sum: 6}, Added: 1}, update)
+	require.Equal(t, []string{filepath.Join(root, "inner/deeper/3.json")}, fileSystem.opened)
+
+	// a change makes the tree crawled from scratch
+	write("1.json", `{"data": 10}`)
+	watcher <- struct{}{}
+	update = nextUpdate(t, updates)
+	require.Equal(t, Update[TestAccumulator]{Result: TestAccumulator{Sum: 15}, Changed: 1}, update)
+
+	require.NoError(t, os.Remove(filepath.Join(root, "inner/2.json")))
+	watcher <- struct{}{}
+	update = nextUpdate(t, updates)
+	require.Equal(t, Update[TestAccumulator]{Result: TestAccumulator{Sum: 13}, Removed: 1}, update)
+
+	// the files which are not crawled are not watched
+	write("notes.txt", "other notes")
+	write("4.json", `{"data": 4}`)
+	watcher <- struct{}{}
+	update = nextUpdate(t, updates)
+	require.Equal(t, Update[TestAccumulator]{Result: TestAccumulator{Sum: 17}, Added: 1}, update)
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestWatchPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := New[TestType, TestAccumulator]().Watch(ctx, memfs.NewBuilder().
+		AddFile("root/1.json", `{"data": 1}`).
+		Build(), "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+	}, Poll(time.Millisecond), sum, combiner)
+	require.NoError(t, err)
+
+	update := nextUpdate(t, updates)
+	require.EqualValues(t, 1, update.Result.Sum)
+
+	// the tree does not change, so there are no more updates
+	select {
+	case <-updates:
+		require.FailNow(t, "unexpected update")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	_, ok := <-updates
+	require.False(t, ok)
+}
+
+// brokenFileSystem fails opening the files which are broken
+type brokenFileSystem struct {
+	fs.FileSystem
+
+	mu     sync.Mutex
+	broken map[string]bool
+}
+
+func (b *brokenFileSystem) Open(name string) (fs.File, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.broken[name] {
+		return nil, errors.New("broken file")
+	}
+	return b.FileSystem.Open(name)
+}
+
+func (b *brokenFileSystem) fix(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.broken, name)
+}
+
+func TestWatchRetriesFailedFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fileSystem := &brokenFileSystem{
+		FileSystem: memfs.NewBuilder().
+			AddFile("root/1.json", `{"data": 1}`).
+			AddFile("root/inner/2.json", `{"data": 2}`).
+			Build(),
+		broken: map[string]bool{"root/inner/2.json": true},
+	}
+	watcher := make(signalWatcher)
+
+	updates, err := New[TestType, TestAccumulator]().Watch(ctx, fileSystem, "root", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		ErrorPolicy:        SkipAndCollect,
+	}, watcher, sum, combiner)
+	require.NoError(t, err)
+
+	update := nextUpdate(t, updates)
+	var crawlErrs CrawlErrors
+	require.ErrorAs(t, update.Err, &crawlErrs)
+	require.Len(t, crawlErrs, 1)
+	require.EqualValues(t, 1, update.Result.Sum)
+
+	// the failed file has not changed, but it is crawled again
+	fileSystem.fix("root/inner/2.json")
+	watcher <- struct{}{}
+	update = nextUpdate(t, updates)
+	require.Equal(t, Update[TestAccumulator]{Result: TestAccumulator{Sum: 3}, Added: 1}, update)
+
+	// every file has been crawled, so there is nothing to update
+	watcher <- struct{}{}
+	select {
+	case <-updates:
+		require.FailNow(t, "unexpected update")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestWatchNotifications(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "1.json"), []byte(`{"data": 1}`), 0o644))
+
+	watcher, err := osfs.Watch(root)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := New[TestType, TestAccumulator]().Watch(ctx, osfs.New(), root, Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+	}, watcher, sum, combiner)
+	require.NoError(t, err)
+
+	update := nextUpdate(t, updates)
+	require.EqualValues(t, 1, update.Result.Sum)
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, "inner"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "inner", "2.json"), []byte(`{"data": 2}`), 0o644))
+	// the file may be noticed before it is written completely
+	for update.Err != nil || update.Result.Sum != 3 {
+		update = nextUpdate(t, updates)
+	}
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestWatchInvalidPattern(t *testing.T) {
+	_, err := New[TestType, TestAccumulator]().Watch(context.Background(), memfs.NewBuilder().Build(), ".", Configuration{
+		SearchWorkers:      1,
+		FileWorkers:        1,
+		AccumulatorWorkers: 1,
+		Exclude:            []Pattern{Glob("[")},
+	}, nil, sum, combiner)
+	require.Error(t, err)
+}
@@ -0,0 +1,310 @@
+package httpfs
+
+import (
+	"bytes"
+	"crawler/internal/fs"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+// FileSystem is an implementation of the fs.FileSystem interface which turns the crawler into
+// a basic web crawler. Paths are absolute URLs: ReadDir fetches an index page and returns the
+// links found on it, Open fetches a document and Join resolves a link against the URL of the
+// page it was found on.
+//
+// An index page is either an HTML page, whose links are the href attributes of its <a>
+// elements, or a JSON document holding either an array of links or an object with the "links"
+// array. The links matched by the document matcher, which by default matches the paths ending
+// with ".json", are listed as files, the other links are listed as directories, that is as
+// index pages to follow. The name of an entry is the absolute URL of its link.
+//
+// Every URL is listed once: ReadDir skips the links listed before, so cycles of links do not
+// make the crawl endless. Only the links to the allowed hosts are listed, by default the host
+// of the page the link is found on. The requests to the same host can be spaced out with
+// WithDelay. FileSystem is safe for concurrent use.
+type FileSystem struct {
+	client       *http.Client
+	allowedHosts map[string]bool
+	isDocument   func(*url.URL) bool
+	delay        time.Duration
+
+	mu sync.Mutex
+	// seen holds the URLs listed or read
+	seen map[string]bool
+	// next holds the time the next request to a host can be sent at
+	next map[string]time.Time
+}
+
+// Option configures a FileSystem.
+type Option func(*FileSystem)
+
+// WithHTTPClient sets the HTTP client the requests are sent with, http.DefaultClient is used
+// by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *FileSystem) {
+		f.client = client
+	}
+}
+
+// WithAllowedHosts restricts the links listed to the links to the given hosts, the host of the
+// page the link is found on is no longer allowed implicitly. A host is compared with the host
+// of a URL including its port, if any.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(f *FileSystem) {
+		f.allowedHosts = make(map[string]bool, len(hosts))
+		for _, host := range hosts {
+			f.allowedHosts[strings.ToLower(host)] = true
+		}
+	}
+}
+
+// WithDelay makes the file system wait for the given delay between two requests to the
+// same host.
+func WithDelay(delay time.Duration) Option {
+	return func(f *FileSystem) {
+		f.delay = delay
+	}
+}
+
+// WithDocumentMatcher sets the function telling the links to documents from the links to
+// index pages.
+func WithDocumentMatcher(isDocument func(*url.URL) bool) Option {
+	return func(f *FileSystem) {
+		f.isDocument = isDocument
+	}
+}
+
+// New creates a FileSystem.
+func New(opts ...Option) *FileSystem {
+	f := &FileSystem{
+		client: http.DefaultClient,
+		isDocument: func(u *url.URL) bool {
+			return path.Ext(u.Path) == ".json"
+		},
+		seen: make(map[string]bool),
+		next: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Open fetches the document at the URL.
+func (f *FileSystem) Open(name string) (fs.File, error) {
+	response, err := f.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// ReadDir fetches the index page at the URL and returns the links found on it which have not
+// been listed before and lead to allowed hosts.
+func (f *FileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	page, err := url.Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("httpfs: %w", err)
+	}
+	f.markSeen(page)
+
+	response, err := f.get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("httpfs: read %s: %w", name, err)
+	}
+
+	links, err := extractLinks(response.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, fmt.Errorf("httpfs: parse index %s: %w", name, err)
+	}
+
+	var entries []os.DirEntry
+	for _, link := range links {
+		ref, err := url.Parse(link)
+		if err != nil {
+			// a malformed link does not spoil the rest of the page
+			continue
+		}
+		u := page.ResolveReference(ref)
+		u.Fragment = ""
+		u.RawFragment = ""
+		if (u.Scheme != "http" && u.Scheme != "https") || !f.allowed(page, u) || !f.markSeen(u) {
+			continue
+		}
+		entries = append(entries, &entry{name: u.String(), isDir: !f.isDocument(u)})
+	}
+	return entries, nil
+}
+
+// Join resolves the last element against the URL made of the preceding ones, so that
+// joining a page with the link found on it gives the URL of the link.
+func (f *FileSystem) Join(elem ...string) string {
+	if len(elem) == 0 {
+		return ""
+	}
+	result := elem[0]
+	for _, e := range elem[1:] {
+		base, err := url.Parse(result)
+		if err != nil {
+			return e
+		}
+		ref, err := url.Parse(e)
+		if err != nil {
+			return e
+		}
+		result = base.ResolveReference(ref).String()
+	}
+	return result
+}
+
+// get sends a GET request waiting for the politeness delay, responses other than 200 OK are
+// converted to errors
+func (f *FileSystem) get(name string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("httpfs: %w", err)
+	}
+	f.wait(request.URL.Host)
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("httpfs: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		err := fmt.Errorf("unexpected status %s", response.Status)
+		if response.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %s", iofs.ErrNotExist, response.Status)
+		}
+		return nil, fmt.Errorf("httpfs: get %s: %w", name, err)
+	}
+	return response, nil
+}
+
+// wait sleeps until a request to the host can be sent, requests waiting for the same host are
+// spaced out by the delay
+func (f *FileSystem) wait(host string) {
+	if f.delay <= 0 {
+		return
+	}
+	f.mu.Lock()
+	now := time.Now()
+	at := f.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	f.next[host] = at.Add(f.delay)
+	f.mu.Unlock()
+
+	time.Sleep(at.Sub(now))
+}
+
+// allowed reports whether the link found on the page can be listed
+func (f *FileSystem) allowed(page *url.URL, link *url.URL) bool {
+	host := strings.ToLower(link.Host)
+	if f.allowedHosts == nil {
+		return host == strings.ToLower(page.Host)
+	}
+	return f.allowedHosts[host]
+}
+
+// markSeen marks the URL as seen and reports whether it has not been seen before
+func (f *FileSystem) markSeen(u *url.URL) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := u.String()
+	if f.seen[key] {
+		return false
+	}
+	f.seen[key] = true
+	return true
+}
+
+// hrefPattern matches the href attributes of <a> elements
+var hrefPattern = regexp.MustCompile(`(?is)<a\s[^>]*?\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+// extractLinks extracts links from an index page, which is either a JSON or an HTML document
+func extractLinks(contentType string, body []byte) ([]string, error) {
+	trimmed := bytes.TrimSpace(body)
+	if strings.Contains(contentType, "json") || bytes.HasPrefix(trimmed, []byte("[")) ||
+		bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			var links []string
+			err := json.Unmarshal(trimmed, &links)
+			return links, err
+		}
+		var index struct {
+			Links []string `json:"links"`
+		}
+		err := json.Unmarshal(trimmed, &index)
+		return index.Links, err
+	}
+
+	var links []string
+	for _, match := range hrefPattern.FindAllSubmatch(body, -1) {
+		link := string(match[1]) + string(match[2]) + string(match[3])
+		links = append(links, html.UnescapeString(strings.TrimSpace(link)))
+	}
+	return links, nil
+}
+
+// entry is a link listed by ReadDir
+type entry struct {
+	name  string
+	isDir bool
+}
+
+func (e *entry) Name() string {
+	return e.name
+}
+
+func (e *entry) IsDir() bool {
+	return e.isDir
+}
+
+func (e *entry) Type() iofs.FileMode {
+	return e.Mode().Type()
+}
+
+func (e *entry) Info() (iofs.FileInfo, error) {
+	return e, nil
+}
+
+func (e *entry) Size() int64 {
+	return 0
+}
+
+func (e *entry) Mode() iofs.FileMode {
+	if e.isDir {
+		return iofs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (e *entry) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (e *entry) Sys() any {
+	return nil
+}
@@ -0,0 +1,150 @@
+package httpfs
+
+import (
+	"context"
+	crawler "crawler/internal/filecrawler"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSite(t *testing.T, pages map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, exists := pages[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, ".json") || strings.HasPrefix(content, "[") {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "text/html")
+		}
+		_, _ = io.WriteString(w, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReadDir(t *testing.T) {
+	other := newSite(t, map[string]string{"/4.json": `{"data": 4}`})
+	site := newSite(t, map[string]string{
+		"/": `<html><body>
+			<a href="/1.json">one</a>
+			<A class="link" HREF='sub/'>sub</A>
+			<a href=2.json#section>two</a>
+			<a href="` + other.URL + `/4.json">other</a>
+			<a href="mailto:someone@example.com">mail</a>
+			<a href="/1.json">one again</a>
+		</body></html>`,
+		"/sub/":       `["../", "3.json", "index.json"]`,
+		"/sub/3.json": `{"data": 3}`,
+	})
+	fileSystem := New(WithDocumentMatcher(func(u *url.URL) bool {
+		return strings.HasSuffix(u.Path, ".json") && !strings.HasSuffix(u.Path, "index.json")
+	}))
+
+	entries, err := fileSystem.ReadDir(site.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, []string{site.URL + "/1.json", site.URL + "/sub/", site.URL + "/2.json"}, names(entries))
+	require.False(t, entries[0].IsDir())
+	require.True(t, entries[1].IsDir())
+
+	// the link back to the root has been listed
+	entries, err = fileSystem.ReadDir(fileSystem.Join(site.URL+"/", "sub/"))
+	require.NoError(t, err)
+	require.Equal(t, []string{site.URL + "/sub/3.json", site.URL + "/sub/index.json"}, names(entries))
+	require.False(t, entries[0].IsDir())
+	require.True(t, entries[1].IsDir())
+
+	_, err = fileSystem.ReadDir(site.URL + "/sub/index.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	f, err := fileSystem.Open(site.URL + "/sub/3.json")
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, `{"data": 3}`, string(content))
+	require.NoError(t, f.Close())
+
+	_, err = fileSystem.Open(site.URL + "/2.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestAllowedHosts(t *testing.T) {
+	other := newSite(t, map[string]string{"/2.json": `{"data": 2}`})
+	site := newSite(t, map[string]string{
+		"/": `{"links": ["/1.json", "` + other.URL + `/2.json"]}`,
+	})
+	otherURL, err := url.Parse(other.URL)
+	require.NoError(t, err)
+
+	entries, err := New(WithAllowedHosts(otherURL.Host)).ReadDir(site.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, []string{other.URL + "/2.json"}, names(entries))
+}
+
+func TestCollect(t *testing.T) {
+	site := newSite(t, map[string]string{
+		"/":         `<a href="a/">a</a><a href="b/">b</a><a href="1.json">1</a>`,
+		"/a/":       `<a href="/">home</a><a href="/b/">b</a><a href="2.json">2</a>`,
+		"/b/":       `<a href="/a/">a</a><a href="3.json">3</a><a href="/a/2.json">2</a>`,
+		"/1.json":   `{"data": 1}`,
+		"/a/2.json": `{"data": 2}`,
+		"/b/3.json": `{"data": 3}`,
+	})
+
+	type data struct {
+		Data int64 `json:"data"`
+	}
+	c := crawler.New[data, int64]()
+	result, err := c.Collect(context.Background(), New(), site.URL+"/", crawler.Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        2,
+		AccumulatorWorkers: 2,
+	}, func(current data, accum int64) int64 {
+		return accum + current.Data
+	}, func(current int64, accum int64) int64 {
+		return accum + current
+	})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 6, result)
+}
+
+func TestDelay(t *testing.T) {
+	site := newSite(t, map[string]string{"/1.json": `{"data": 1}`})
+	const delay = 20 * time.Millisecond
+	fileSystem := New(WithDelay(delay))
+
+	start := time.Now()
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := fileSystem.Open(site.URL + "/1.json")
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+		}()
+	}
+	wg.Wait()
+
+	require.GreaterOrEqual(t, time.Since(start), 4*delay)
+}
+
+func names(entries []os.DirEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package memfs
+
+import (
+	"bytes"
+	"crawler/internal/fs"
+	"errors"
+	iofs "io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+var (
+	// ErrNotDir is returned by ReadDir when the named file is not a directory.
+	ErrNotDir = errors.New("not a directory")
+	// ErrIsDir is returned by Open when the named file is a directory.
+	ErrIsDir = errors.New("is a directory")
+)
+
+// FileSystem is an in-memory implementation of the fs.FileSystem interface intended for tests.
+// It is created by a Builder and is immutable afterwards, so its methods are safe for concurrent
+// use. Paths are slash-separated and cleaned with path.Clean, so "a/b", "a//b" and "a/./b" name
+// the same file. Directory entries are returned sorted by name, which makes the traversal
+// order deterministic.
+type FileSystem struct {
+	// dirs maps a directory to its entries sorted by name
+	dirs map[string][]os.DirEntry
+	// files maps a file to its content
+	files map[string][]byte
+	// latency is the time Open and ReadDir sleep for before returning
+	latency time.Duration
+}
+
+// Builder builds a FileSystem. Its methods return the builder itself, so that calls can be
+// chained. Builder is not thread-safe.
+type Builder struct {
+	dirs    map[string]map[string]*node
+	files   map[string][]byte
+	latency time.Duration
+}
+
+// node describes a directory entry while the file system is being built
+type node struct {
+	name  string
+	isDir bool
+	size  int64
+}
+
+// NewBuilder creates a builder of an empty file system.
+func NewBuilder() *Builder {
+	return &Builder{
+		dirs:  make(map[string]map[string]*node),
+		files: make(map[string][]byte),
+	}
+}
+
+// AddDir adds the named directory together with its missing parents.
+// AddDir panics if the name or one of its parents is already added as a file.
+func (b *Builder) AddDir(name string) *Builder {
+	b.addDir(path.Clean(name))
+	return b
+}
+
+// AddFile adds the named file with the given content together with its missing parent
+// directories. If the file is already added, its content is replaced.
+// AddFile panics if the name is already added as a directory or one of its parents is
+// already added as a file.
+func (b *Builder) AddFile(name string, content string) *Builder {
+	name = path.Clean(name)
+	if _, exists := b.dirs[name]; exists || isRoot(name) {
+		panic("memfs: " + name + " is a directory")
+	}
+	parent, base := path.Split(name)
+	entries := b.addDir(path.Clean(parent))
+	entries[base] = &node{name: base, size: int64(len(content))}
+	b.files[name] = []byte(content)
+	return b
+}
+
+// WithLatency makes Open and ReadDir of the file system sleep for the given duration, which
+// simulates a slow storage.
+func (b *Builder) WithLatency(latency time.Duration) *Builder {
+	b.latency = latency
+	return b
+}
+
+// Build returns the file system built. The builder can be used further, it does not affect
+// the file systems already built.
+func (b *Builder) Build() *FileSystem {
+	fileSystem := &FileSystem{
+		dirs:    make(map[string][]os.DirEntry, len(b.dirs)),
+		files:   make(map[string][]byte, len(b.files)),
+		latency: b.latency,
+	}
+	for name, nodes := range b.dirs {
+		entries := make([]os.DirEntry, 0, len(nodes))
+		for _, n := range nodes {
+			entries = append(entries, iofs.FileInfoToDirEntry(fileInfo{*n}))
+		}
+		slices.SortFunc(entries, func(a, b os.DirEntry) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+		fileSystem.dirs[name] = entries
+	}
+	for name, content := range b.files {
+		fileSystem.files[name] = slices.Clone(content)
+	}
+	return fileSystem
+}
+
+// addDir adds the cleaned name as a directory along with its parents and returns its entries
+func (b *Builder) addDir(name string) map[string]*node {
+	if entries, exists := b.dirs[name]; exists {
+		return entries
+	}
+	if _, exists := b.files[name]; exists {
+		panic("memfs: " + name + " is a file")
+	}
+	entries := make(map[string]*node)
+	b.dirs[name] = entries
+	if !isRoot(name) {
+		parent, base := path.Split(name)
+		b.addDir(path.Clean(parent))[base] = &node{name: base, isDir: true}
+	}
+	return entries
+}
+
+// isRoot reports whether the cleaned name is a root of the file system
+func isRoot(name string) bool {
+	return name == "." || name == "/"
+}
+
+// Open opens the named file for reading.
+func (m *FileSystem) Open(name string) (fs.File, error) {
+	m.sleep()
+	name = path.Clean(name)
+	content, exists := m.files[name]
+	if !exists {
+		if _, isDir := m.dirs[name]; isDir {
+			return nil, &iofs.PathError{Op: "open", Path: name, Err: ErrIsDir}
+		}
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: iofs.ErrNotExist}
+	}
+	return &file{Reader: bytes.NewReader(content)}, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (m *FileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	m.sleep()
+	name = path.Clean(name)
+	entries, exists := m.dirs[name]
+	if !exists {
+		if _, isFile := m.files[name]; isFile {
+			return nil, &iofs.PathError{Op: "readdir", Path: name, Err: ErrNotDir}
+		}
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: iofs.ErrNotExist}
+	}
+	return slices.Clone(entries), nil
+}
+
+// Join joins any number of path elements into a single slash-separated path using path.Join.
+func (m *FileSystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// sleep simulates the latency of the storage
+func (m *FileSystem) sleep() {
+	if m.latency > 0 {
+		time.Sleep(m.latency)
+	}
+}
+
+// file is an opened file of the in-memory file system
+type file struct {
+	*bytes.Reader
+}
+
+// Close does nothing, the content of the file stays in memory.
+func (f *file) Close() error {
+	return nil
+}
+
+// fileInfo describes a file of the in-memory file system
+type fileInfo struct {
+	n node
+}
+
+func (i fileInfo) Name() string {
+	return i.n.name
+}
+
+func (i fileInfo) Size() int64 {
+	return i.n.size
+}
+
+func (i fileInfo) Mode() iofs.FileMode {
+	if i.n.isDir {
+		return iofs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (i fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i fileInfo) IsDir() bool {
+	return i.n.isDir
+}
+
+func (i fileInfo) Sys() any {
+	return nil
+}
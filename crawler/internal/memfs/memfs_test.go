@@ -0,0 +1,95 @@
+package memfs
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem(t *testing.T) {
+	fileSystem := NewBuilder().
+		AddFile("root/b.json", `{"data": 2}`).
+		AddDir("root/empty").
+		AddFile("root/inner/c.json", `{"data": 3}`).
+		AddFile("root/a.json", `{"data": 1}`).
+		Build()
+
+	entries, err := fileSystem.ReadDir("root")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.json", "b.json", "empty", "inner"}, names(entries))
+	require.False(t, entries[0].IsDir())
+	require.True(t, entries[2].IsDir())
+	info, err := entries[1].Info()
+	require.NoError(t, err)
+	require.EqualValues(t, len(`{"data": 2}`), info.Size())
+
+	entries, err = fileSystem.ReadDir(".")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root"}, names(entries))
+
+	entries, err = fileSystem.ReadDir("root/empty")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	f, err := fileSystem.Open(fileSystem.Join("root", "inner", "c.json"))
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, `{"data": 3}`, string(content))
+	require.NoError(t, f.Close())
+}
+
+func TestFileSystemErrors(t *testing.T) {
+	fileSystem := NewBuilder().AddFile("root/a.json", "{}").Build()
+
+	_, err := fileSystem.Open("root/missing.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = fileSystem.Open("root")
+	require.ErrorIs(t, err, ErrIsDir)
+
+	_, err = fileSystem.ReadDir("missing")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = fileSystem.ReadDir("root/a.json")
+	require.ErrorIs(t, err, ErrNotDir)
+
+	require.Panics(t, func() { NewBuilder().AddFile("root/a", "").AddDir("root/a/b") })
+	require.Panics(t, func() { NewBuilder().AddDir("root/a").AddFile("root/a", "") })
+}
+
+func TestBuilderIndependence(t *testing.T) {
+	builder := NewBuilder().AddFile("a.json", "1")
+	first := builder.Build()
+	builder.AddFile("a.json", "22").AddFile("b.json", "3")
+
+	entries, err := first.ReadDir(".")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.json"}, names(entries))
+	f, err := first.Open("a.json")
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "1", string(content))
+}
+
+func TestLatency(t *testing.T) {
+	const latency = 50 * time.Millisecond
+	fileSystem := NewBuilder().AddFile("a.json", "{}").WithLatency(latency).Build()
+
+	start := time.Now()
+	_, err := fileSystem.ReadDir(".")
+	require.NoError(t, err)
+	_, err = fileSystem.Open("a.json")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 2*latency)
+}
+
+func names(entries []os.DirEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package osfs
+
+import (
+	"crawler/internal/fs"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+// FileSystem is an implementation of the fs.FileSystem interface over the operating system
+// file system. It uses os.Open, os.ReadDir and filepath.Join from the standard library and
+// wraps the errors they return with the name of the file system. The wrapped errors keep the
+// operation and the path, and errors.Is still matches errors such as os.ErrNotExist or
+// os.ErrPermission.
+// FileSystem has no state, so its methods are safe for concurrent use.
+type FileSystem struct{}
+
+// New creates a new FileSystem, it does not require any configuration.
+func New() *FileSystem {
+	return &FileSystem{}
+}
+
+// Open opens the named file for reading using os.Open.
+// If the file cannot be opened, the returned File is nil, so that it is never mistaken for an
+// open file.
+func (o *FileSystem) Open(name string) (fs.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("osfs: %w", err)
+	}
+	return f, nil
+}
+
+// ReadDir reads the named directory using os.ReadDir and returns its entries sorted by
+// file name.
+func (o *FileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, fmt.Errorf("osfs: %w", err)
+	}
+	return entries, nil
+}
+
+// Join joins any number of path elements into a single path using filepath.Join.
+func (o *FileSystem) Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
@@ -0,0 +1,49 @@
+package osfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.json"), []byte(`{"data": 1}`), 0o644))
+
+	fileSystem := New()
+
+	entries, err := fileSystem.ReadDir(root)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "dir", entries[0].Name())
+	require.True(t, entries[0].IsDir())
+	require.Equal(t, "file.json", entries[1].Name())
+	require.False(t, entries[1].IsDir())
+
+	f, err := fileSystem.Open(fileSystem.Join(root, "file.json"))
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, `{"data": 1}`, string(content))
+	require.NoError(t, f.Close())
+}
+
+func TestFileSystemErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	fileSystem := New()
+
+	f, err := fileSystem.Open(missing)
+	require.Nil(t, f)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorContains(t, err, "osfs: open")
+	require.ErrorContains(t, err, missing)
+
+	entries, err := fileSystem.ReadDir(missing)
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorContains(t, err, "osfs: open "+missing)
+}
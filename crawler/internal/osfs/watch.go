@@ -0,0 +1,117 @@
+package osfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ErrWatcherClosed is returned by Wait once the Watcher is closed.
+var ErrWatcherClosed = errors.New("osfs: watcher closed")
+
+// Watcher tells when a tree of the operating system file system may have changed, it is woken
+// up by the notifications of the operating system delivered by fsnotify. It satisfies the
+// Watcher of the crawler, so that its Watch notices the changes as soon as they are made
+// instead of at the next poll.
+// The notifications are not recursive, so every directory of the tree is watched on its own:
+// the directories existing when the Watcher is created and the ones created later as soon as
+// they are noticed. The files created in a new directory before it is watched are found by the
+// scan following the notification of the directory itself.
+type Watcher struct {
+	notify *fsnotify.Watcher
+}
+
+// Watch starts watching the tree rooted at root. The Watcher must be closed once it is not
+// needed anymore.
+func Watch(root string) (*Watcher, error) {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("osfs: %w", err)
+	}
+	w := &Watcher{notify: notify}
+	if err := w.addTree(root); err != nil {
+		_ = notify.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// addTree watches every directory of the tree rooted at root
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("osfs: %w", err)
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if err := w.notify.Add(name); err != nil {
+			return fmt.Errorf("osfs: watch %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// Wait blocks until an entry of the tree is created, written, removed, renamed or has its
+// mode changed, or the context is done, in which case it returns the error of the context.
+// The notifications which have already arrived are consumed along with the first one, so a
+// burst of changes wakes Watch up once or a few times rather than once per change.
+// A lost notification, e.g. on an overflow of the queue of the operating system, wakes it up
+// as well, since the tree is scanned on every wake-up anyway.
+func (w *Watcher) Wait(ctx context.Context) error {
+	select {
+	// ensure cancelling context is taken into account
+	case <-ctx.Done():
+		return ctx.Err()
+	case event, ok := <-w.notify.Events:
+		if !ok {
+			return ErrWatcherClosed
+		}
+		w.handle(event)
+	case _, ok := <-w.notify.Errors:
+		if !ok {
+			return ErrWatcherClosed
+		}
+	}
+	for {
+		select {
+		case event, ok := <-w.notify.Events:
+			if !ok {
+				return nil
+			}
+			w.handle(event)
+		case _, ok := <-w.notify.Errors:
+			if !ok {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// handle watches the directories created in the tree
+func (w *Watcher) handle(event fsnotify.Event) {
+	if !event.Has(fsnotify.Create) {
+		return
+	}
+	info, err := os.Lstat(event.Name)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	// the directory may be removed in the meantime, then there is nothing to watch
+	_ = w.addTree(event.Name)
+}
+
+// Close stops watching the tree, the pending and later calls of Wait return ErrWatcherClosed.
+func (w *Watcher) Close() error {
+	if err := w.notify.Close(); err != nil {
+		return fmt.Errorf("osfs: %w", err)
+	}
+	return nil
+}
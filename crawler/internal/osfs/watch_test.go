@@ -0,0 +1,55 @@
+package osfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// waitChange waits for the Watcher to wake up, failing the test if it does not in time
+func waitChange(t *testing.T, w *Watcher) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, w.Wait(ctx))
+}
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "dir"), 0o755))
+
+	w, err := Watch(root)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "dir", "file.json"), []byte(`{}`), 0o644))
+	waitChange(t, w)
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, "new"), 0o755))
+	waitChange(t, w)
+
+	// the directory created after the Watcher is watched as well
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new", "file.json"), []byte(`{}`), 0o644))
+	waitChange(t, w)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for {
+		// the notifications left from the changes above are consumed until none arrive
+		if err := w.Wait(ctx); err != nil {
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			break
+		}
+	}
+
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, w.Wait(context.Background()), ErrWatcherClosed)
+}
+
+func TestWatchMissingRoot(t *testing.T) {
+	_, err := Watch(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
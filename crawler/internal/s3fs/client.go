@@ -0,0 +1,256 @@
+package s3fs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client is the subset of the S3 API used by FileSystem. Its methods mirror the operations
+// of the same names of the AWS SDK, so a client of the SDK can be adapted with a thin wrapper,
+// while HTTPClient implements them over the REST API of any S3-compatible store.
+// Client must be safe for concurrent use.
+type Client interface {
+	// ListObjectsV2 lists up to 1000 objects of the bucket in lexicographical order of keys.
+	ListObjectsV2(ctx context.Context, input *ListObjectsV2Input) (*ListObjectsV2Output, error)
+
+	// GetObject fetches an object, the caller must close the body of the output.
+	// GetObject returns an error matching fs.ErrNotExist if there is no such object.
+	GetObject(ctx context.Context, input *GetObjectInput) (*GetObjectOutput, error)
+}
+
+// ListObjectsV2Input holds the parameters of ListObjectsV2.
+type ListObjectsV2Input struct {
+	Bucket            string
+	Prefix            string // Only keys starting with the prefix are listed.
+	Delimiter         string // Keys containing the delimiter after the prefix are rolled up.
+	ContinuationToken string // Token of the page to list, empty for the first page.
+	MaxKeys           int    // Maximal number of keys per page, 0 means the default.
+}
+
+// ListObjectsV2Output holds a page of keys listed by ListObjectsV2.
+type ListObjectsV2Output struct {
+	Contents              []Object
+	CommonPrefixes        []string // Prefixes rolled up by the delimiter, ending with it.
+	IsTruncated           bool     // Whether there are more pages.
+	NextContinuationToken string   // Token of the next page.
+}
+
+// Object describes an object listed by ListObjectsV2.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// GetObjectInput holds the parameters of GetObject.
+type GetObjectInput struct {
+	Bucket string
+	Key    string
+}
+
+// GetObjectOutput holds an object fetched by GetObject.
+type GetObjectOutput struct {
+	Body          io.ReadCloser
+	ContentLength int64
+}
+
+// Credentials hold the keys the requests are signed with. Requests of empty credentials are
+// not signed, which is enough for public buckets.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Token of temporary credentials, empty otherwise.
+}
+
+// ResponseError is an error response of the object store.
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is makes responses with the 404 status code match fs.ErrNotExist.
+func (e *ResponseError) Is(target error) bool {
+	return target == iofs.ErrNotExist && e.StatusCode == http.StatusNotFound
+}
+
+// HTTPClient is a Client over the REST API of an S3-compatible object store. It addresses
+// buckets in the path style, such as https://endpoint/bucket/key, which is supported by AWS
+// as well as by MinIO and other compatible stores, and signs the requests with Signature
+// Version 4.
+type HTTPClient struct {
+	endpoint    *url.URL
+	region      string
+	credentials Credentials
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// ClientOption configures an HTTPClient.
+type ClientOption func(*HTTPClient)
+
+// WithCredentials sets the credentials the requests are signed with.
+func WithCredentials(credentials Credentials) ClientOption {
+	return func(c *HTTPClient) {
+		c.credentials = credentials
+	}
+}
+
+// WithHTTPClient sets the HTTP client the requests are sent with, http.DefaultClient is used
+// by default.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *HTTPClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewHTTPClient creates an HTTPClient sending requests to the endpoint, such as
+// "https://s3.eu-west-1.amazonaws.com", and signing them for the region.
+func NewHTTPClient(endpoint string, region string, opts ...ClientOption) (*HTTPClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	c := &HTTPClient{
+		endpoint:   u,
+		region:     region,
+		httpClient: http.DefaultClient,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// listBucketResult is the response of ListObjectsV2
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListObjectsV2 represents HTTPClient implementation of function with the same name
+func (c *HTTPClient) ListObjectsV2(ctx context.Context, input *ListObjectsV2Input) (*ListObjectsV2Output, error) {
+	query := url.Values{"list-type": {"2"}}
+	if input.Prefix != "" {
+		query.Set("prefix", input.Prefix)
+	}
+	if input.Delimiter != "" {
+		query.Set("delimiter", input.Delimiter)
+	}
+	if input.ContinuationToken != "" {
+		query.Set("continuation-token", input.ContinuationToken)
+	}
+	if input.MaxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(input.MaxKeys))
+	}
+
+	response, err := c.do(ctx, input.Bucket, "", query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	var result listBucketResult
+	if err := xml.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode list objects response: %w", err)
+	}
+	output := &ListObjectsV2Output{
+		Contents:              make([]Object, 0, len(result.Contents)),
+		CommonPrefixes:        make([]string, 0, len(result.CommonPrefixes)),
+		IsTruncated:           result.IsTruncated,
+		NextContinuationToken: result.NextContinuationToken,
+	}
+	for _, content := range result.Contents {
+		output.Contents = append(output.Contents, Object{
+			Key:          content.Key,
+			Size:         content.Size,
+			LastModified: content.LastModified,
+		})
+	}
+	for _, commonPrefix := range result.CommonPrefixes {
+		output.CommonPrefixes = append(output.CommonPrefixes, commonPrefix.Prefix)
+	}
+	return output, nil
+}
+
+// GetObject represents HTTPClient implementation of function with the same name
+func (c *HTTPClient) GetObject(ctx context.Context, input *GetObjectInput) (*GetObjectOutput, error) {
+	response, err := c.do(ctx, input.Bucket, input.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GetObjectOutput{
+		Body:          response.Body,
+		ContentLength: response.ContentLength,
+	}, nil
+}
+
+// do sends a signed GET request for the key of the bucket, responses other than 200 OK are
+// converted to a ResponseError
+func (c *HTTPClient) do(ctx context.Context, bucket string, key string, query url.Values) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + key
+	// the path is sent encoded exactly as it is signed
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = encodeQuery(query)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.credentials.AccessKeyID != "" {
+		sign(request, c.credentials, c.region, c.now())
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusOK {
+		return response, nil
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	responseErr := &ResponseError{StatusCode: response.StatusCode}
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	// the body is empty for some errors, then the status text is used as the code
+	if err := xml.NewDecoder(response.Body).Decode(&body); err == nil {
+		responseErr.Code = body.Code
+		responseErr.Message = body.Message
+	}
+	if responseErr.Code == "" {
+		responseErr.Code = http.StatusText(response.StatusCode)
+	}
+	return nil, responseErr
+}
@@ -0,0 +1,219 @@
+package s3fs
+
+import (
+	"context"
+	"crawler/internal/fs"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+// FileSystem is an implementation of the fs.FileSystem interface over a bucket of an
+// S3-compatible object store. The bucket has no directories, so they are emulated with key
+// prefixes: ReadDir lists the objects and the common prefixes under name + "/" using
+// ListObjectsV2 with the "/" delimiter, Open fetches the object using GetObject and Join joins
+// the path elements with "/". The root of the bucket is named "" or ".".
+//
+// Listing a prefix without objects returns no entries rather than an error, since the object
+// store cannot tell an empty directory from a missing one.
+//
+// The number of requests the FileSystem runs at a time can be limited with WithConcurrency,
+// an opened object takes its slot until it is closed. FileSystem is safe for concurrent use
+// as long as its Client is.
+type FileSystem struct {
+	client Client
+	bucket string
+	// slots limits the number of requests in flight, nil means no limit
+	slots chan struct{}
+}
+
+// Option configures a FileSystem.
+type Option func(*FileSystem)
+
+// WithConcurrency limits the number of requests the file system runs at a time.
+// WithConcurrency panics if n is not positive.
+func WithConcurrency(n int) Option {
+	if n <= 0 {
+		panic("Invalid concurrency")
+	}
+	return func(f *FileSystem) {
+		f.slots = make(chan struct{}, n)
+	}
+}
+
+// New creates a FileSystem over the bucket using the client.
+func New(client Client, bucket string, opts ...Option) *FileSystem {
+	f := &FileSystem{
+		client: client,
+		bucket: bucket,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Open fetches the object named by its key.
+func (f *FileSystem) Open(name string) (fs.File, error) {
+	key := clean(name)
+	f.acquire()
+	output, err := f.client.GetObject(context.Background(), &GetObjectInput{
+		Bucket: f.bucket,
+		Key:    key,
+	})
+	if err != nil {
+		f.release()
+		return nil, fmt.Errorf("s3fs: get s3://%s/%s: %w", f.bucket, key, err)
+	}
+	return &object{GetObjectOutput: output, release: f.release}, nil
+}
+
+// ReadDir lists the objects and the common prefixes under the prefix name + "/", the common
+// prefixes are returned as directories. The entries are sorted by name, as the object store
+// returns keys in lexicographical order.
+func (f *FileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	prefix := clean(name)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var (
+		entries []os.DirEntry
+		token   string
+	)
+	for {
+		f.acquire()
+		output, err := f.client.ListObjectsV2(context.Background(), &ListObjectsV2Input{
+			Bucket:            f.bucket,
+			Prefix:            prefix,
+			Delimiter:         "/",
+			ContinuationToken: token,
+		})
+		f.release()
+		if err != nil {
+			return nil, fmt.Errorf("s3fs: list s3://%s/%s: %w", f.bucket, prefix, err)
+		}
+
+		for _, commonPrefix := range output.CommonPrefixes {
+			entries = append(entries, &entry{
+				name:  strings.TrimSuffix(strings.TrimPrefix(commonPrefix, prefix), "/"),
+				isDir: true,
+			})
+		}
+		for _, obj := range output.Contents {
+			// the object named as the prefix itself marks a directory
+			if obj.Key == prefix {
+				continue
+			}
+			entries = append(entries, &entry{
+				name:    strings.TrimPrefix(obj.Key, prefix),
+				size:    obj.Size,
+				modTime: obj.LastModified,
+			})
+		}
+
+		if !output.IsTruncated || output.NextContinuationToken == "" {
+			break
+		}
+		token = output.NextContinuationToken
+	}
+
+	// common prefixes and objects are listed separately, so they are merged by name
+	slices.SortFunc(entries, func(a, b os.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// Join joins path elements with "/".
+func (f *FileSystem) Join(elem ...string) string {
+	return strings.TrimPrefix(path.Join(elem...), "/")
+}
+
+// acquire takes a slot for a request, waiting for one if all of them are taken
+func (f *FileSystem) acquire() {
+	if f.slots != nil {
+		f.slots <- struct{}{}
+	}
+}
+
+// release frees the slot taken by acquire
+func (f *FileSystem) release() {
+	if f.slots != nil {
+		<-f.slots
+	}
+}
+
+// clean converts the name to a key or a prefix without the trailing slash
+func clean(name string) string {
+	return strings.Trim(path.Clean("/"+name), "/")
+}
+
+// object is an opened object, it frees the slot of the request when it is closed
+type object struct {
+	*GetObjectOutput
+	release func()
+	closed  bool
+}
+
+func (o *object) Read(p []byte) (int, error) {
+	return o.Body.Read(p)
+}
+
+func (o *object) Close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	defer o.release()
+	return o.Body.Close()
+}
+
+// entry is an object or a common prefix listed by ReadDir
+type entry struct {
+	name    string
+	isDir   bool
+	size    int64
+	modTime time.Time
+}
+
+func (e *entry) Name() string {
+	return e.name
+}
+
+func (e *entry) IsDir() bool {
+	return e.isDir
+}
+
+func (e *entry) Type() iofs.FileMode {
+	return e.Mode().Type()
+}
+
+func (e *entry) Info() (iofs.FileInfo, error) {
+	return e, nil
+}
+
+func (e *entry) Size() int64 {
+	return e.size
+}
+
+func (e *entry) Mode() iofs.FileMode {
+	if e.isDir {
+		return iofs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (e *entry) ModTime() time.Time {
+	return e.modTime
+}
+
+func (e *entry) Sys() any {
+	return nil
+}
@@ -0,0 +1,267 @@
+package s3fs
+
+import (
+	"context"
+	crawler "crawler/internal/filecrawler"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore is an S3-compatible server keeping objects of a single bucket in memory
+type fakeStore struct {
+	bucket  string
+	objects map[string]string
+	// pageSize is the number of keys and prefixes listed per page
+	pageSize int
+	// latency is the time every request takes
+	latency time.Duration
+
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+	requests    atomic.Int64
+}
+
+func (s *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	current := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		maximum := s.maxInFlight.Load()
+		if current <= maximum || s.maxInFlight.CompareAndSwap(maximum, current) {
+			break
+		}
+	}
+	time.Sleep(s.latency)
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "<Error><Code>NoSuchBucket</Code><Message>No such bucket</Message></Error>")
+		return
+	}
+	if r.URL.Query().Get("list-type") == "2" {
+		s.list(w, r)
+		return
+	}
+	content, exists := s.objects[key]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>No such key</Message></Error>")
+		return
+	}
+	_, _ = io.WriteString(w, content)
+}
+
+func (s *fakeStore) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+
+	// keys and common prefixes are listed together in lexicographical order
+	var names []string
+	seen := make(map[string]bool)
+	for key := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := key
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			name = key[:len(prefix)+i+len(delimiter)]
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	start, _ := strconv.Atoi(query.Get("continuation-token"))
+	end := min(start+s.pageSize, len(names))
+
+	type content struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	}
+	type commonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
+	var result struct {
+		XMLName               xml.Name       `xml:"ListBucketResult"`
+		Contents              []content      `xml:"Contents"`
+		CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+		IsTruncated           bool           `xml:"IsTruncated"`
+		NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	}
+	for _, name := range names[start:end] {
+		if delimiter != "" && strings.HasSuffix(name, delimiter) && s.objects[name] == "" {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: name})
+		} else {
+			result.Contents = append(result.Contents, content{Key: name, Size: int64(len(s.objects[name]))})
+		}
+	}
+	if end < len(names) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func newFileSystem(t *testing.T, store *fakeStore, opts ...Option) *FileSystem {
+	server := httptest.NewServer(store)
+	t.Cleanup(server.Close)
+
+	client, err := NewHTTPClient(server.URL, "us-east-1", WithCredentials(Credentials{
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+	}))
+	require.NoError(t, err)
+	return New(client, store.bucket, opts...)
+}
+
+func TestFileSystem(t *testing.T) {
+	store := &fakeStore{
+		bucket: "bucket",
+		objects: map[string]string{
+			"data/a.json":         `{"data": 1}`,
+			"data/b c.json":       `{"data": 2}`,
+			"data/inner/c.json":   `{"data": 3}`,
+			"data/inner/d/e.json": `{"data": 4}`,
+			"data/z+special.json": `{"data": 5}`,
+			"other/ignored.json":  `{"data": 6}`,
+			"top.json":            `{"data": 7}`,
+		},
+		pageSize: 2,
+	}
+	for i := 8; i < 30; i++ {
+		store.objects[fmt.Sprintf("data/inner/d/%d.json", i)] = fmt.Sprintf(`{"data": %d}`, i)
+	}
+	fileSystem := newFileSystem(t, store)
+
+	entries, err := fileSystem.ReadDir("")
+	require.NoError(t, err)
+	require.Equal(t, []string{"data", "other", "top.json"}, names(entries))
+	require.True(t, entries[0].IsDir())
+	require.False(t, entries[2].IsDir())
+
+	entries, err = fileSystem.ReadDir("data")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.json", "b c.json", "inner", "z+special.json"}, names(entries))
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	require.EqualValues(t, len(`{"data": 1}`), info.Size())
+
+	// listed across many pages
+	entries, err = fileSystem.ReadDir(fileSystem.Join("data", "inner", "d"))
+	require.NoError(t, err)
+	require.Len(t, entries, 23)
+
+	entries, err = fileSystem.ReadDir("missing")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	f, err := fileSystem.Open(fileSystem.Join("data", "z+special.json"))
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, `{"data": 5}`, string(content))
+	require.NoError(t, f.Close())
+
+	_, err = fileSystem.Open("data/missing.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	var responseErr *ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	require.Equal(t, "NoSuchKey", responseErr.Code)
+
+	c := crawler.New[struct {
+		Data int64 `json:"data"`
+	}, int64]()
+	result, err := c.Collect(context.Background(), fileSystem, "data", crawler.Configuration{
+		SearchWorkers:      2,
+		FileWorkers:        4,
+		AccumulatorWorkers: 2,
+	}, func(current struct {
+		Data int64 `json:"data"`
+	}, accum int64) int64 {
+		return accum + current.Data
+	}, func(current int64, accum int64) int64 {
+		return accum + current
+	})
+	require.NoError(t, err)
+	// everything but other/ignored.json and top.json
+	require.EqualValues(t, 29*30/2-6-7, result)
+}
+
+func TestAccessDenied(t *testing.T) {
+	server := httptest.NewServer(&fakeStore{bucket: "bucket"})
+	t.Cleanup(server.Close)
+
+	// requests of empty credentials are not signed
+	client, err := NewHTTPClient(server.URL, "us-east-1")
+	require.NoError(t, err)
+
+	_, err = New(client, "bucket").ReadDir("")
+	var responseErr *ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	require.Equal(t, http.StatusForbidden, responseErr.StatusCode)
+	require.Equal(t, "AccessDenied", responseErr.Code)
+	require.NotErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConcurrency(t *testing.T) {
+	store := &fakeStore{
+		bucket:   "bucket",
+		objects:  make(map[string]string),
+		pageSize: 1000,
+		latency:  10 * time.Millisecond,
+	}
+	for i := 0; i < 20; i++ {
+		store.objects[strconv.Itoa(i)+".json"] = "{}"
+	}
+	fileSystem := newFileSystem(t, store, WithConcurrency(3))
+
+	wg := new(sync.WaitGroup)
+	for key := range store.objects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := fileSystem.Open(key)
+			require.NoError(t, err)
+			_, err = io.ReadAll(f)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			// closing twice frees the slot once
+			require.NoError(t, f.Close())
+		}()
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 20, store.requests.Load())
+	require.LessOrEqual(t, store.maxInFlight.Load(), int64(3))
+	require.Panics(t, func() { WithConcurrency(0) })
+}
+
+func names(entries []os.DirEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
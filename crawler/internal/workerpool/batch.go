@@ -0,0 +1,103 @@
+package workerpool
+
+import (
+	"context"
+	"time"
+)
+
+// TransformBatch represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) TransformBatch(
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	size int,
+	timeout time.Duration,
+	transformer BatchTransformer[T, R],
+	opts ...Option[T],
+) <-chan R {
+	if size < 1 {
+		panic("Invalid batch size")
+	}
+	o := newOptions(opts)
+
+	batches := batch(ctx, o.buffer(ctx, input), size, timeout)
+	bo := options[[]T]{ordered: o.ordered, scaling: o.scaling, panics: o.panics}
+	if o.onPanic != nil {
+		// the panic is reported for every item of the batch the transformer panicked on
+		bo.onPanic = func(current []T, err error) {
+			for _, v := range current {
+				o.onPanic(v, err)
+			}
+		}
+	}
+	return flatMap(ctx, workers, batches, FlatMapper[[]T, R](transformer), bo)
+}
+
+// batch groups the items of the input channel into batches of up to size items, a batch is
+// sent once it is full or the timeout has passed since its first item was received
+func batch[T any](ctx context.Context, input <-chan T, size int, timeout time.Duration) <-chan []T {
+	// channel for collecting batches
+	result := make(chan []T)
+
+	go func() {
+		defer close(result)
+
+		var (
+			current []T
+			timer   *time.Timer
+			// deadline of the current batch, there is none until the batch has an item
+			deadline <-chan time.Time
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		// flush sends the current batch, reporting whether it is sent before the context
+		// is done
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+			}
+			deadline = nil
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return false
+			case result <- current:
+			}
+			current = nil
+			return true
+		}
+
+		for {
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return
+			case v, ok := <-input:
+				if !ok {
+					if len(current) > 0 {
+						flush()
+					}
+					return
+				}
+				current = append(current, v)
+				if len(current) == 1 && timeout > 0 {
+					timer = time.NewTimer(timeout)
+					deadline = timer.C
+				}
+				if len(current) == size && !flush() {
+					return
+				}
+			case <-deadline:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,112 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder records the sizes of the batches transformed
+type batchRecorder struct {
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (r *batchRecorder) double(batch []int) []int {
+	r.mu.Lock()
+	r.sizes = append(r.sizes, len(batch))
+	r.mu.Unlock()
+
+	result := make([]int, len(batch))
+	for i, v := range batch {
+		result[i] = 2 * v
+	}
+	return result
+}
+
+func TestTransformBatch(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 25)
+	for i := range s {
+		s[i] = i
+	}
+
+	recorder := new(batchRecorder)
+	result := collect(wp.TransformBatch(ctx, 3, generate(s), 10, 0, recorder.double))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	sort.Ints(result)
+	for i, r := range result {
+		require.Equal(t, 2*i, r)
+	}
+	sort.Ints(recorder.sizes)
+	require.Equal(t, []int{5, 10, 10}, recorder.sizes)
+}
+
+func TestTransformBatchTimeout(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	in := make(chan int)
+	recorder := new(batchRecorder)
+	out := wp.TransformBatch(ctx, 1, in, 10, 20*time.Millisecond, recorder.double)
+
+	// a batch which is not full is passed after the timeout
+	in <- 1
+	in <- 2
+	require.Equal(t, 2, <-out)
+	require.Equal(t, 4, <-out)
+
+	in <- 3
+	close(in)
+	require.Equal(t, []int{6}, collect(out))
+	require.Equal(t, []int{2, 1}, recorder.sizes)
+}
+
+func TestTransformBatchOrdered(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+
+	result := collect(wp.TransformBatch(ctx, 4, generate(s), 7, 0, func(batch []int) []int {
+		time.Sleep(time.Duration(len(batch)%3) * time.Millisecond)
+		return (&batchRecorder{}).double(batch)
+	}, Ordered[int]()))
+	require.Len(t, result, len(s))
+	for i, r := range result {
+		require.Equal(t, 2*i, r)
+	}
+}
+
+func TestTransformBatchContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[int, int]()
+	out := wp.TransformBatch(ctx, 2, make(chan int), 10, time.Millisecond, (&batchRecorder{}).double)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTransformBatchInvalidSize(t *testing.T) {
+	require.Panics(t, func() {
+		New[int, int]().TransformBatch(context.Background(), 1, make(chan int), 0, 0, (&batchRecorder{}).double)
+	})
+}
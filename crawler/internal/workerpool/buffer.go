@@ -0,0 +1,69 @@
+package workerpool
+
+import "context"
+
+// Buffer returns a channel passing the items of the input channel through a queue of up to
+// size items, so that the producer of the items may run ahead of their consumer by that many
+// items. The queue applies backpressure: once it is full, no item is received until the
+// consumer takes one, so the memory the items hold stays bounded.
+// The output channel is closed after the input channel is closed and the queue is empty.
+// Once the context is done the items are dropped, but the input channel is still drained
+// until it is closed, so that the output channel closes after the producer finishes.
+// A size less than one leaves the input channel as it is.
+func Buffer[T any](ctx context.Context, input <-chan T, size int) <-chan T {
+	if size < 1 {
+		return input
+	}
+
+	// channel to pass the queued items to the consumer
+	result := make(chan T)
+
+	go func() {
+		defer close(result)
+
+		// ring of the queued items
+		queue := make([]T, size)
+		var head, n int
+
+		in := input
+		for in != nil || n > 0 {
+			// the queue is full, so no item is received
+			receive := in
+			if n == size {
+				receive = nil
+			}
+			// the queue is empty, so no item is sent
+			var send chan<- T
+			var first T
+			if n > 0 {
+				send = result
+				first = queue[head]
+			}
+
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				if in != nil {
+					for range in {
+					}
+				}
+				return
+			case v, ok := <-receive:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue[(head+n)%size] = v
+				n++
+			case send <- first:
+				var zero T
+				// the item is not held by the queue any longer
+				queue[head] = zero
+				head = (head + 1) % size
+				n--
+			}
+		}
+	}()
+
+	return result
+}
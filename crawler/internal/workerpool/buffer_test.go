@@ -0,0 +1,79 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// trySend reports whether the item is taken in time
+func trySend[T any](ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-time.After(20 * time.Millisecond):
+		return false
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := Buffer(ctx, in, 3)
+
+	// the producer runs ahead by the size of the queue
+	for i := 0; i < 3; i++ {
+		require.True(t, trySend(in, i))
+	}
+	require.False(t, trySend(in, 3))
+
+	require.Equal(t, 0, <-out)
+	require.True(t, trySend(in, 3))
+	close(in)
+
+	require.Equal(t, []int{1, 2, 3}, collect(out))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestBufferUnbuffered(t *testing.T) {
+	in := make(chan int)
+	require.Equal(t, (<-chan int)(in), Buffer(context.Background(), in, 0))
+}
+
+func TestBufferContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := Buffer(ctx, in, 2)
+
+	require.True(t, trySend(in, 1))
+	cancel()
+
+	// the input channel is drained until it is closed
+	for i := 0; i < 10; i++ {
+		require.True(t, trySend(in, i))
+	}
+	close(in)
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestWithQueue(t *testing.T) {
+	in := make(chan int)
+	out := New[int, int]().Transform(context.Background(), 1, in, func(current int) int {
+		return current
+	}, WithQueue[int](3))
+
+	// the worker holds one item while the queue holds the rest of them
+	for i := 0; i < 4; i++ {
+		require.True(t, trySend(in, i))
+	}
+	require.False(t, trySend(in, 4))
+	close(in)
+
+	require.Equal(t, []int{0, 1, 2, 3}, collect(out))
+}
@@ -0,0 +1,116 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// checked is an item along with the result of the predicate
+type checked[T any] struct {
+	value T
+	keep  bool
+}
+
+// Filter represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) Filter(
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	predicate Predicate[T],
+	opts ...Option[T],
+) <-chan T {
+	o := newOptions(opts)
+	input = o.buffer(ctx, input)
+	if o.ordered && (workers > 0 || o.scaling != nil) {
+		return filterOrdered(ctx, workers, input, predicate, o)
+	}
+
+	// channel for collecting kept items
+	result := make(chan T)
+
+	// wait group to wait workers to finish their work
+	wg := new(sync.WaitGroup)
+
+	startWorkers(ctx, workers, o.scaling, wg, func(s *scaler) {
+		for {
+			s.waiting()
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				s.working()
+				return
+			case <-s.stop():
+				s.working()
+				return
+			case v, ok := <-input:
+				s.working()
+				if !ok {
+					s.exhausted()
+					return
+				}
+				// an item the predicate panics on is dropped
+				var keep bool
+				o.call(v, func() {
+					keep = predicate(v)
+				})
+				if !keep {
+					continue
+				}
+
+				select {
+				// ensure cancelling context is taken into account
+				case <-ctx.Done():
+					return
+				case result <- v:
+				}
+			}
+		}
+	})
+
+	// goroutine for closing result channel when the items are already checked
+	go func() {
+		defer close(result)
+		// wait for all workers to complete
+		wg.Wait()
+	}()
+
+	return result
+}
+
+// filterOrdered filters the items as Filter does, keeping the order of the items
+func filterOrdered[T any](
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	predicate Predicate[T],
+	o options[T],
+) <-chan T {
+	// the items are checked in order, and the ones to drop are dropped afterwards
+	checkedCh := transformOrdered(ctx, workers, input, shared(func(current T) checked[T] {
+		return checked[T]{value: current, keep: predicate(current)}
+	}), o)
+
+	// channel for collecting kept items
+	result := make(chan T)
+
+	go func() {
+		defer close(result)
+		for c := range checkedCh {
+			if !c.keep {
+				continue
+			}
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				// wait for the checking goroutines to finish, so that none of them outlives
+				// the stage
+				for range checkedCh {
+				}
+				return
+			case result <- c.value:
+			}
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,97 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func even(current int) bool {
+	time.Sleep(time.Millisecond)
+	return current%2 == 0
+}
+
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+
+	result := collect(wp.Filter(ctx, 10, generate(s), even))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	sort.Ints(result)
+	require.Len(t, result, 50)
+	for i, r := range result {
+		require.Equal(t, 2*i, r)
+	}
+}
+
+func TestFilterOrdered(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+
+	result := collect(wp.Filter(ctx, 10, generate(s), even, Ordered[int]()))
+	require.Len(t, result, 50)
+	for i, r := range result {
+		require.Equal(t, 2*i, r)
+	}
+}
+
+func TestFilterPerformance(t *testing.T) {
+	first := testing.Benchmark(func(b *testing.B) {
+		ctx := context.Background()
+		wp := New[TestType, TestType]()
+
+		for i := 0; i < b.N; i++ {
+			collect(wp.Filter(ctx, 5, generate(make([]TestType, 5)), func(current TestType) bool {
+				time.Sleep(100 * time.Millisecond)
+				return true
+			}))
+		}
+	})
+
+	second := testing.Benchmark(func(b *testing.B) {
+		ctx := context.Background()
+		wp := New[TestType, TestType]()
+
+		for i := 0; i < b.N; i++ {
+			collect(wp.Filter(ctx, 1, generate(make([]TestType, 5)), func(current TestType) bool {
+				time.Sleep(100 * time.Millisecond)
+				return true
+			}))
+		}
+	})
+
+	require.GreaterOrEqual(t, float64(second.NsPerOp())/float64(first.NsPerOp()), 4.)
+}
+
+func TestFilterContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[int, int]()
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		out := wp.Filter(ctx, 2, make(chan int), even, opts...)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		for range out {
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
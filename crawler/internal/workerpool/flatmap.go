@@ -0,0 +1,103 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// FlatMap represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) FlatMap(
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	mapper FlatMapper[T, R],
+	opts ...Option[T],
+) <-chan R {
+	return flatMap(ctx, workers, input, mapper, newOptions(opts))
+}
+
+// flatMap expands the items as FlatMap does with the options given
+func flatMap[T, R any](
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	mapper FlatMapper[T, R],
+	o options[T],
+) <-chan R {
+	input = o.buffer(ctx, input)
+
+	// channel for collecting results
+	result := make(chan R)
+
+	if o.ordered && (workers > 0 || o.scaling != nil) {
+		// the slices are made in order, and they are flattened afterwards
+		slices := transformOrdered(ctx, workers, input, shared(Transformer[T, []R](mapper)), o)
+		go func() {
+			defer close(result)
+			for slice := range slices {
+				if !send(ctx, result, slice) {
+					// wait for the mapping goroutines to finish, so that none of them
+					// outlives the stage
+					for range slices {
+					}
+					return
+				}
+			}
+		}()
+		return result
+	}
+
+	// wait group to wait workers to finish their work
+	wg := new(sync.WaitGroup)
+
+	startWorkers(ctx, workers, o.scaling, wg, func(s *scaler) {
+		for {
+			s.waiting()
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				s.working()
+				return
+			case <-s.stop():
+				s.working()
+				return
+			case v, ok := <-input:
+				s.working()
+				if !ok {
+					s.exhausted()
+					return
+				}
+				var slice []R
+				o.call(v, func() {
+					slice = mapper(v)
+				})
+				if !send(ctx, result, slice) {
+					return
+				}
+			}
+		}
+	})
+
+	// goroutine for closing result channel when the items are already expanded
+	go func() {
+		defer close(result)
+		// wait for all workers to complete
+		wg.Wait()
+	}()
+
+	return result
+}
+
+// send sends the elements of the slice in order, reporting whether all of them are sent
+// before the context is done
+func send[R any](ctx context.Context, result chan<- R, slice []R) bool {
+	for _, r := range slice {
+		select {
+		// ensure cancelling context is taken into account
+		case <-ctx.Done():
+			return false
+		case result <- r:
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lines splits a document into its lines, an empty document has none
+func lines(current string) []string {
+	time.Sleep(time.Millisecond)
+	if current == "" {
+		return nil
+	}
+	return strings.Split(current, "\n")
+}
+
+func TestFlatMap(t *testing.T) {
+	ctx := context.Background()
+	wp := New[string, string]()
+
+	in := generate([]string{"a\nb\nc", "", "d", "e\nf"})
+	result := collect(wp.FlatMap(ctx, 3, in, lines))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	sort.Strings(result)
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, result)
+}
+
+func TestFlatMapOrdered(t *testing.T) {
+	ctx := context.Background()
+	wp := New[string, string]()
+
+	in := generate([]string{"a\nb\nc", "", "d", "e\nf"})
+	result := collect(wp.FlatMap(ctx, 3, in, lines, Ordered[string]()))
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, result)
+}
+
+func TestFlatMapPerformance(t *testing.T) {
+	first := testing.Benchmark(func(b *testing.B) {
+		ctx := context.Background()
+		wp := New[TestType, TestType]()
+
+		for i := 0; i < b.N; i++ {
+			collect(wp.FlatMap(ctx, 5, generate(make([]TestType, 5)), func(current TestType) []TestType {
+				time.Sleep(100 * time.Millisecond)
+				return []TestType{current, current}
+			}))
+		}
+	})
+
+	second := testing.Benchmark(func(b *testing.B) {
+		ctx := context.Background()
+		wp := New[TestType, TestType]()
+
+		for i := 0; i < b.N; i++ {
+			collect(wp.FlatMap(ctx, 1, generate(make([]TestType, 5)), func(current TestType) []TestType {
+				time.Sleep(100 * time.Millisecond)
+				return []TestType{current, current}
+			}))
+		}
+	})
+
+	require.GreaterOrEqual(t, float64(second.NsPerOp())/float64(first.NsPerOp()), 4.)
+}
+
+func TestFlatMapContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[string, string]()
+	outs := []<-chan string{
+		wp.FlatMap(ctx, 2, generate([]string{"a\nb", "c\nd"}), lines),
+		wp.FlatMap(ctx, 2, generate([]string{"a\nb", "c\nd"}), lines, Ordered[string]()),
+	}
+	// the results are not received
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for _, out := range outs {
+		for range out {
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
@@ -0,0 +1,71 @@
+package workerpool
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures a stage of the pool processing items of type T.
+type Option[T any] func(*options[T])
+
+// options holds the configuration of a stage
+type options[T any] struct {
+	ordered  bool
+	scaling  *scaling
+	onPanic  func(current T, err error)
+	panics   *panicRecord
+	queue    int
+	priority *priority[T]
+}
+
+// newOptions applies the options to the default configuration
+func newOptions[T any](opts []Option[T]) options[T] {
+	o := options[T]{panics: new(panicRecord)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// buffer returns the input channel of the stage passed through its queue, if any
+func (o options[T]) buffer(ctx context.Context, input <-chan T) <-chan T {
+	if o.priority != nil {
+		return Prioritize(ctx, input, o.priority.size, o.priority.less)
+	}
+	return Buffer(ctx, input, o.queue)
+}
+
+// WithQueue makes a stage receive the items of its input channel through a queue of up to
+// size items, as Buffer does, so that the producer of the items may run ahead of the workers.
+// A size less than one leaves the input channel as it is.
+func WithQueue[T any](size int) Option[T] {
+	return func(o *options[T]) {
+		o.queue = size
+	}
+}
+
+// Ordered makes Transform send the results in the order of the items of the input channel.
+// A result waits for the results of the items preceding it, and the workers transform at
+// most twice as many items as there are workers ahead of the first result not sent, so
+// a slow item delays the rest of them.
+func Ordered[T any]() Option[T] {
+	return func(o *options[T]) {
+		o.ordered = true
+	}
+}
+
+// WithScaling makes the number of the workers of Transform and Accumulate change at runtime
+// between min and max, starting with the number of workers given to them. The workers are
+// checked every interval: one more worker is started if none of them is waiting for an item,
+// and one of them is stopped if more than one is waiting. The channels have no queue to
+// measure, so the idle workers tell the items are scarce, while the busy ones tell the items
+// are likely waiting.
+// WithScaling panics if min is not positive, max is less than min or interval is not positive.
+func WithScaling[T any](min, max int, interval time.Duration) Option[T] {
+	if min < 1 || max < min || interval <= 0 {
+		panic("Invalid scaling")
+	}
+	return func(o *options[T]) {
+		o.scaling = &scaling{min: min, max: max, interval: interval}
+	}
+}
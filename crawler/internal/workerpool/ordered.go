@@ -0,0 +1,152 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// sequenced is an item numbered in the order of the input channel
+type sequenced[T any] struct {
+	seq   uint64
+	value T
+	// skip marks a result the transformer panicked on, which only takes its place in order
+	skip bool
+}
+
+// transformOrdered transforms the items as Transform does, sending the results in the order
+// of the items
+func transformOrdered[T, R any](
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	newTransformer factory[T, R],
+	o options[T],
+) <-chan R {
+	// channel for collecting results
+	result := make(chan R)
+
+	// channel to pass the numbered items to the workers
+	tasks := make(chan sequenced[T])
+
+	// channel to pass the numbered results to the goroutine restoring their order
+	done := make(chan sequenced[R])
+
+	// slots bound the number of items taken ahead of the first result not sent, so that
+	// the reorder buffer stays small
+	window := workers
+	if o.scaling != nil {
+		window = max(workers, o.scaling.max)
+	}
+	slots := make(chan struct{}, 2*window)
+
+	// goroutine numbering the items
+	go func() {
+		defer close(tasks)
+		for seq := uint64(0); ; seq++ {
+			var v T
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return
+			case item, ok := <-input:
+				if !ok {
+					return
+				}
+				v = item
+			}
+
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return
+			case tasks <- sequenced[T]{seq: seq, value: v}:
+			}
+		}
+	}()
+
+	// wait group to wait workers to finish their work
+	wg := new(sync.WaitGroup)
+
+	startWorkers(ctx, workers, o.scaling, wg, func(s *scaler) {
+		transformer, teardown := newTransformer()
+		if teardown != nil {
+			defer teardown()
+		}
+
+		for {
+			s.waiting()
+			select {
+			case <-s.stop():
+				s.working()
+				return
+			case task, ok := <-tasks:
+				s.working()
+				if !ok {
+					s.exhausted()
+					return
+				}
+
+				r := sequenced[R]{seq: task.seq}
+				r.skip = !o.call(task.value, func() {
+					r.value = transformer(task.value)
+				})
+
+				select {
+				// ensure cancelling context is taken into account
+				case <-ctx.Done():
+					return
+				case done <- r:
+				}
+			}
+		}
+	})
+
+	// goroutine for closing the channel of numbered results when the workers are finished
+	go func() {
+		defer close(done)
+		wg.Wait()
+	}()
+
+	// goroutine restoring the order of the results
+	go func() {
+		defer close(result)
+
+		// results waiting for the results preceding them
+		pending := make(map[uint64]sequenced[R])
+		var next uint64
+
+		for r := range done {
+			pending[r.seq] = r
+			for {
+				first, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+
+				if !first.skip {
+					select {
+					// ensure cancelling context is taken into account
+					case <-ctx.Done():
+						// wait for the workers to finish, so that none of them outlives the stage
+						for range done {
+						}
+						return
+					case result <- first.value:
+					}
+				}
+				<-slots
+				next++
+			}
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,99 @@
+package workerpool
+
+import (
+	"context"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformOrdered(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+
+	start := time.Now()
+	out := wp.Transform(ctx, 10, generate(s), func(current int) int {
+		time.Sleep(time.Duration(rand.IntN(10)) * time.Millisecond)
+		return current * 2
+	}, Ordered[int]())
+
+	result := collect(out)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Len(t, result, len(s))
+	for i, r := range result {
+		require.Equal(t, 2*i, r)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTransformOrderedWindow(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 50)
+	for i := range s {
+		s[i] = i
+	}
+
+	// the first item is slow, so the rest of them wait for it
+	var started atomic.Int64
+	out := wp.Transform(ctx, 4, generate(s), func(current int) int {
+		started.Add(1)
+		if current == 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return current
+	}, Ordered[int]())
+
+	first := <-out
+	require.Equal(t, 0, first)
+	// the items taken ahead of the first one are bounded
+	require.LessOrEqual(t, started.Load(), int64(2*4))
+
+	result := collect(out)
+	require.Len(t, result, len(s)-1)
+	for i, r := range result {
+		require.Equal(t, i+1, r)
+	}
+}
+
+func TestTransformOrderedContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	in := make(chan TestType)
+	go func() {
+		defer close(in)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case in <- TestType{}:
+			}
+		}
+	}()
+
+	wp := New[TestType, TestType]()
+	out := wp.Transform(ctx, 2, in, func(current TestType) TestType {
+		time.Sleep(time.Millisecond)
+		return current
+	}, Ordered[TestType]())
+
+	<-out
+	cancel()
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
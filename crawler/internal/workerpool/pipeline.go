@@ -0,0 +1,154 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+)
+
+// Lister is a function type for exploring data in a hierarchical manner while passing the
+// elements found on to the next stage of a pipeline. Each call takes a parent element and
+// returns the child elements to explore, while the elements to process are passed to emit,
+// which blocks until the next stage takes the element, and reports false once the pipeline
+// is cancelled, so that the lister stops. The function is invoked concurrently by multiple
+// workers, and therefore must be thread-safe.
+type Lister[T any] func(parent T, emit func(item T) bool) ([]T, error)
+
+// Pipeline chains the stages processing elements of type S found by a search into values of
+// type V, which are aggregated into results of type R: List finds the elements, Transform
+// turns them into values, Filter drops some of the values, and Accumulate aggregates the rest.
+// Every stage has its own number of workers and options, the stages are wired by Run, which
+// closes the channels between them and collects the errors of all of them.
+// List, Transform and Accumulate are required, while there may be any number of Filter stages,
+// applied in the order they are added in.
+type Pipeline[S, V, R any] struct {
+	list       *listStage[S]
+	transform  *transformStage[S, V]
+	filters    []filterStage[V]
+	accumulate *accumulateStage[V, R]
+}
+
+// listStage is the configuration of the search of a pipeline
+type listStage[S any] struct {
+	workers int
+	start   S
+	lister  Lister[S]
+	opts    []Option[S]
+}
+
+// transformStage is the configuration of the transformation of a pipeline
+type transformStage[S, V any] struct {
+	workers     int
+	transformer TryTransformer[S, V]
+	opts        []Option[S]
+}
+
+// filterStage is the configuration of a filter of a pipeline
+type filterStage[V any] struct {
+	workers   int
+	predicate Predicate[V]
+	opts      []Option[V]
+}
+
+// accumulateStage is the configuration of the accumulation of a pipeline
+type accumulateStage[V, R any] struct {
+	workers     int
+	accumulator TryAccumulator[V, R]
+	opts        []Option[V]
+}
+
+// NewPipeline creates a pipeline with no stages
+func NewPipeline[S, V, R any]() *Pipeline[S, V, R] {
+	return &Pipeline[S, V, R]{}
+}
+
+// List sets the search of the pipeline starting from the given element, the elements the
+// lister emits are passed to Transform. The search works as TryList does.
+func (p *Pipeline[S, V, R]) List(workers int, start S, lister Lister[S], opts ...Option[S]) *Pipeline[S, V, R] {
+	p.list = &listStage[S]{workers: workers, start: start, lister: lister, opts: opts}
+	return p
+}
+
+// Transform sets the transformation of the elements found into values, which works as
+// TryTransform does.
+func (p *Pipeline[S, V, R]) Transform(workers int, transformer TryTransformer[S, V], opts ...Option[S]) *Pipeline[S, V, R] {
+	p.transform = &transformStage[S, V]{workers: workers, transformer: transformer, opts: opts}
+	return p
+}
+
+// Filter adds a filter of the values, which works as Filter of Pool does.
+func (p *Pipeline[S, V, R]) Filter(workers int, predicate Predicate[V], opts ...Option[V]) *Pipeline[S, V, R] {
+	p.filters = append(p.filters, filterStage[V]{workers: workers, predicate: predicate, opts: opts})
+	return p
+}
+
+// Accumulate sets the accumulation of the values into the results, which works as
+// TryAccumulate does.
+func (p *Pipeline[S, V, R]) Accumulate(workers int, accumulator TryAccumulator[V, R], opts ...Option[V]) *Pipeline[S, V, R] {
+	p.accumulate = &accumulateStage[V, R]{workers: workers, accumulator: accumulator, opts: opts}
+	return p
+}
+
+// Run runs the stages of the pipeline until the search is finished and all the elements found
+// are processed, or until the context is done, and returns the intermediate results of the
+// accumulator workers along with the errors of the user functions of all the stages joined,
+// including their panics. Every goroutine of the pipeline is finished once Run returns.
+// Run panics if List, Transform or Accumulate is not set.
+func (p *Pipeline[S, V, R]) Run(ctx context.Context) ([]R, error) {
+	if p.list == nil || p.transform == nil || p.accumulate == nil {
+		panic("Invalid pipeline")
+	}
+
+	// channel to pass the elements found to the transform stage
+	items := make(chan S)
+
+	// emit passes an element found to the transform stage unless the context is done
+	emit := func(item S) bool {
+		select {
+		// ensure cancelling context is taken into account
+		case <-ctx.Done():
+			return false
+		case items <- item:
+			return true
+		}
+	}
+
+	// the error of the search is read once the channel is closed
+	var listErr error
+	go func() {
+		defer close(items)
+		// every call of the lister is finished once List returns, so nothing is emitted after
+		listErr = New[S, S]().TryList(ctx, p.list.workers, p.list.start, func(parent S) ([]S, error) {
+			return p.list.lister(parent, emit)
+		}, p.list.opts...)
+	}()
+
+	values, transformGroup := New[S, V]().TryTransform(ctx, p.transform.workers, items, p.transform.transformer, p.transform.opts...)
+
+	// the channels between the stages, they are drained from the last one to the first one
+	// once the pipeline is finished
+	stages := []<-chan V{values}
+	filterPanics := new(Panics)
+	for _, f := range p.filters {
+		opts := append(f.opts[:len(f.opts):len(f.opts)], CollectPanics[V](filterPanics))
+		values = New[V, V]().Filter(ctx, f.workers, values, f.predicate, opts...)
+		stages = append(stages, values)
+	}
+
+	results, accumulateGroup := New[V, R]().TryAccumulate(ctx, p.accumulate.workers, values, p.accumulate.accumulator, p.accumulate.opts...)
+
+	var accumulated []R
+	for r := range results {
+		accumulated = append(accumulated, r)
+	}
+
+	// a cancelled stage may finish before the stages feeding it do, so wait for all of them
+	// to finish
+	for i := len(stages) - 1; i >= 0; i-- {
+		for range stages[i] {
+		}
+	}
+	for range items {
+	}
+
+	return accumulated, errors.Join(listErr, transformGroup.Wait(), filterPanics.Err(), accumulateGroup.Wait())
+}
@@ -0,0 +1,188 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tree is a directory tree, the names of the files end with their sizes
+var tree = map[string][]string{
+	"root":     {"root/a", "root/b", "root/1.txt", "root/22.txt"},
+	"root/a":   {"root/a/333.txt", "root/a/x.txt"},
+	"root/b":   {"root/b/4444.txt", "root/b/c"},
+	"root/b/c": {"root/b/c/55555.txt"},
+}
+
+// walk lists the directories of the tree, emitting its files
+func walk(parent string, emit func(string) bool) ([]string, error) {
+	var dirs []string
+	for _, child := range tree[parent] {
+		if _, ok := tree[child]; ok {
+			dirs = append(dirs, child)
+			continue
+		}
+		if !emit(child) {
+			return nil, nil
+		}
+	}
+	return dirs, nil
+}
+
+// size parses the size of the file from its name
+func size(name string) (int, error) {
+	base := name[strings.LastIndex(name, "/")+1 : strings.LastIndex(name, ".")]
+	for _, c := range base {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("size of %s: %w", name, errOdd)
+		}
+	}
+	return len(base), nil
+}
+
+func total(current int, accum int) (int, error) {
+	return accum + current, nil
+}
+
+func sumAll(results []int) int {
+	var sum int
+	for _, r := range results {
+		sum += r
+	}
+	return sum
+}
+
+func TestPipeline(t *testing.T) {
+	results, err := NewPipeline[string, int, int]().
+		List(2, "root", walk).
+		Transform(3, size, WithQueue[string](2)).
+		Filter(2, func(current int) bool {
+			return current > 1
+		}).
+		Accumulate(2, total, WithQueue[int](2)).
+		Run(context.Background())
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	require.LessOrEqual(t, len(results), 2)
+	require.Equal(t, 2+3+4+5, sumAll(results))
+
+	// the errors of the stages are returned
+	require.ErrorIs(t, err, errOdd)
+	require.ErrorContains(t, err, "size of root/a/x.txt")
+}
+
+func TestPipelineFilters(t *testing.T) {
+	results, err := NewPipeline[string, int, int]().
+		List(1, "root", walk).
+		Transform(1, func(current string) (int, error) {
+			if strings.HasSuffix(current, "x.txt") {
+				return 0, nil
+			}
+			return size(current)
+		}).
+		// the filters are applied in the order they are added in
+		Filter(1, func(current int) bool {
+			return current > 1
+		}).
+		Filter(1, func(current int) bool {
+			return current < 5
+		}).
+		Accumulate(1, total).
+		Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{2 + 3 + 4}, results)
+}
+
+func TestPipelineErrors(t *testing.T) {
+	errList := errors.New("list")
+	errAccumulate := errors.New("accumulate")
+
+	results, err := NewPipeline[string, int, int]().
+		List(1, "root", func(parent string, emit func(string) bool) ([]string, error) {
+			if parent == "root/b" {
+				return nil, errList
+			}
+			return walk(parent, emit)
+		}).
+		Transform(1, func(current string) (int, error) {
+			if strings.HasSuffix(current, "x.txt") {
+				panic("no size")
+			}
+			return size(current)
+		}).
+		Accumulate(1, func(current int, accum int) (int, error) {
+			if current == 3 {
+				return accum, errAccumulate
+			}
+			return accum + current, nil
+		}).
+		Run(context.Background())
+
+	require.Equal(t, []int{1 + 2}, results)
+	require.ErrorIs(t, err, errList)
+	require.ErrorIs(t, err, errAccumulate)
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+}
+
+func TestPipelineFilterPanic(t *testing.T) {
+	_, err := NewPipeline[string, int, int]().
+		List(1, "root", walk).
+		Transform(1, size).
+		Filter(1, func(current int) bool {
+			if current == 3 {
+				panic(errPanic)
+			}
+			return true
+		}).
+		Accumulate(1, total).
+		Run(context.Background())
+
+	// the panics of the filters are returned along with the errors of the other stages
+	require.ErrorIs(t, err, errPanic)
+	require.ErrorIs(t, err, errOdd)
+}
+
+func TestPipelineContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := NewPipeline[int, int, int]().
+		// the search never ends unless the pipeline is cancelled
+		List(2, 0, func(parent int, emit func(int) bool) ([]int, error) {
+			if !emit(parent) {
+				return nil, nil
+			}
+			return []int{parent + 1}, nil
+		}).
+		Transform(2, func(current int) (int, error) {
+			time.Sleep(time.Millisecond)
+			return current, nil
+		}, WithQueue[int](4)).
+		Filter(2, func(current int) bool {
+			return current%2 == 0
+		}).
+		Accumulate(2, total).
+		Run(ctx)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestPipelineInvalid(t *testing.T) {
+	require.Panics(t, func() {
+		_, _ = NewPipeline[string, int, int]().List(1, "root", walk).Accumulate(1, total).Run(context.Background())
+	})
+}
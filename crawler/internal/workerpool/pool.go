@@ -3,6 +3,7 @@ package workerpool
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 // Accumulator is a function type used to aggregate values of type T into a result of type R.
@@ -19,6 +20,32 @@ type Accumulator[T, R any] func(current T, accum R) R
 // manner if present.
 type Transformer[T, R any] func(current T) R
 
+// FlatMapper is a function type used to expand an element of type T into zero or more elements
+// of type R. The function is invoked concurrently by multiple workers, and therefore must be
+// thread-safe.
+type FlatMapper[T, R any] func(current T) []R
+
+// BatchTransformer is a function type used to transform a batch of elements of type T to
+// elements of type R at once. The function is invoked concurrently by multiple workers, and
+// therefore must be thread-safe.
+type BatchTransformer[T, R any] func(batch []T) []R
+
+// Predicate is a function type used to decide whether an element of type T is kept.
+// The function is invoked concurrently by multiple workers, and therefore must be thread-safe.
+type Predicate[T any] func(current T) bool
+
+// TryTransformer is a Transformer which may fail to transform an element, an element it
+// returns an error for yields no result.
+type TryTransformer[T, R any] func(current T) (R, error)
+
+// TryAccumulator is an Accumulator which may fail to accumulate an element, an element it
+// returns an error for leaves the accumulated value as it is.
+type TryAccumulator[T, R any] func(current T, accum R) (R, error)
+
+// TrySearcher is a Searcher which may fail to find the children of an element, an element it
+// returns an error for has no children.
+type TrySearcher[T any] func(parent T) ([]T, error)
+
 // Searcher is a function type for exploring data in a hierarchical manner.
 // Each call to Searcher takes a parent element of type T and returns a slice of T representing
 // its child elements. Since multiple goroutines may call Searcher concurrently, it must be
@@ -34,6 +61,11 @@ type Searcher[T any] func(parent T) []T
 // Pool is the primary interface for managing worker pools, with support for three main
 // operations: Transform, Accumulate, and List. Each operation takes an input channel, applies
 // a transformation, accumulation, or list expansion, and returns the respective output.
+// The workers of every operation recover the panics of the user functions, so that a panic
+// drops the item it happened on, and the stage goes on and shuts down as usual. The panics
+// are passed to the handler of the OnPanic option, without it List raises the first of them
+// again in the goroutine of its caller once the search is finished, and the other operations
+// drop them, unless they are collected with the CollectPanics option.
 type Pool[T, R any] interface {
 	// Transform applies a transformer function to each item received from the input channel,
 	// with results sent to the output channel. Transform operates concurrently, utilizing the
@@ -43,20 +75,85 @@ type Pool[T, R any] interface {
 	// thread-safe to prevent race conditions or unexpected results when handling shared or
 	// internal state. Each worker independently applies the transformer function to its own
 	// data subset.
-	Transform(ctx context.Context, workers int, input <-chan T, transformer Transformer[T, R]) <-chan R
+	// The results are sent in the order they are ready in, unless the Ordered option is given.
+	// The number of workers changes at runtime with the WithScaling option, and the items
+	// waiting are taken in the order of their priority with the WithPriority option.
+	Transform(ctx context.Context, workers int, input <-chan T, transformer Transformer[T, R], opts ...Option[T]) <-chan R
 
 	// Accumulate applies an accumulator function to the items received from the input channel,
 	// with results accumulated and sent to the output channel. The accumulator function must
 	// be thread-safe, as multiple workers concurrently update the accumulated result.
 	// The output channel will contain intermediate accumulated results as R
-	Accumulate(ctx context.Context, workers int, input <-chan T, accumulator Accumulator[T, R]) <-chan R
+	// With the WithScaling option every worker stopped sends its intermediate result as well.
+	// An item the accumulator panics on leaves the intermediate result as it is.
+	Accumulate(ctx context.Context, workers int, input <-chan T, accumulator Accumulator[T, R], opts ...Option[T]) <-chan R
+
+	// Filter applies a predicate function to each item received from the input channel, with
+	// the items the predicate holds for sent to the output channel, and the rest of them
+	// dropped. Filter operates concurrently, utilizing the specified number of workers, so the
+	// predicate must be thread-safe. The items are sent in the order they are checked in,
+	// unless the Ordered option is given.
+	Filter(ctx context.Context, workers int, input <-chan T, predicate Predicate[T], opts ...Option[T]) <-chan T
+
+	// FlatMap applies a flat mapper function to each item received from the input channel,
+	// with every element of the slices it returns sent to the output channel, so that one item
+	// may yield many results or none. FlatMap operates concurrently, utilizing the specified
+	// number of workers, so the flat mapper must be thread-safe. The elements of a slice are
+	// sent in their order, and the slices are sent in the order they are ready in, unless the
+	// Ordered option is given.
+	FlatMap(ctx context.Context, workers int, input <-chan T, mapper FlatMapper[T, R], opts ...Option[T]) <-chan R
+
+	// TransformBatch groups the items received from the input channel into batches of up to
+	// size items, and applies a batch transformer function to every batch, with the elements
+	// of the slices it returns sent to the output channel. A batch is passed to a worker once
+	// it is full, or once the timeout has passed since its first item was received, if the
+	// timeout is positive, and the last batch is passed once the input channel is closed.
+	// Batching amortizes the cost of a call for cheap transformations, and lets the
+	// transformer write its results in bulk. The workers, options and ordering are those of
+	// FlatMap, the batches taking the place of the items.
+	// TransformBatch panics if size is less than one.
+	TransformBatch(
+		ctx context.Context,
+		workers int,
+		input <-chan T,
+		size int,
+		timeout time.Duration,
+		transformer BatchTransformer[T, R],
+		opts ...Option[T],
+	) <-chan R
 
 	// List expands elements based on a searcher function, starting
 	// from the given element. The searcher function finds child elements for each parent,
 	// allowing exploration in a tree-like structure.
 	// The number of workers should be configured based on the workload, ensuring each worker
 	// independently processes assigned elements.
-	List(ctx context.Context, workers int, start T, searcher Searcher[T])
+	// An element the searcher panics on has no children, the OnPanic option handles the panic,
+	// without it the first panic is raised again once the search is finished.
+	List(ctx context.Context, workers int, start T, searcher Searcher[T], opts ...Option[T])
+
+	// Walk works as List does, with every element visited, that is the starting element and
+	// the children found, sent to the output channel before the searcher is applied to it,
+	// so that the elements may be processed while the search goes on. The search waits for
+	// the elements to be received, and the output channel is closed once it is finished.
+	// The search runs in a goroutine of its own, so the panics of the searcher are not raised
+	// again, as in the other operations.
+	Walk(ctx context.Context, workers int, start T, searcher Searcher[T], opts ...Option[T]) <-chan T
+
+	// TryTransform works as Transform does with a transformer which returns an error along
+	// with the result. The items the transformer fails for are dropped, and the errors are
+	// collected by the group returned, along with the panics of the transformer, so that
+	// the caller learns of them once the output channel is drained.
+	TryTransform(ctx context.Context, workers int, input <-chan T, transformer TryTransformer[T, R], opts ...Option[T]) (<-chan R, *Group)
+
+	// TryAccumulate works as Accumulate does with an accumulator which returns an error along
+	// with the accumulated value. The items the accumulator fails for are skipped, and the
+	// errors are collected by the group returned, along with the panics of the accumulator.
+	TryAccumulate(ctx context.Context, workers int, input <-chan T, accumulator TryAccumulator[T, R], opts ...Option[T]) (<-chan R, *Group)
+
+	// TryList works as List does with a searcher which returns an error along with the
+	// children. The elements the searcher fails for have no children, and the errors, along
+	// with the panics of the searcher, are returned joined once the search is finished.
+	TryList(ctx context.Context, workers int, start T, searcher TrySearcher[T], opts ...Option[T]) error
 }
 
 // poolImpl represents Pool implementation
@@ -73,41 +170,56 @@ func (p *poolImpl[T, R]) Accumulate(
 	workers int,
 	input <-chan T,
 	accumulator Accumulator[T, R],
+	opts ...Option[T],
 ) <-chan R {
+	o := newOptions(opts)
+	input = o.buffer(ctx, input)
+
 	// channel to put accumulated results in
 	result := make(chan R)
 
 	// wait group to wait workers to finish their work
 	wg := new(sync.WaitGroup)
 
-	for i := 0; i < workers; i++ {
-		// implement wait group counter pattern
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			var res R
+	startWorkers(ctx, workers, o.scaling, wg, func(s *scaler) {
+		var res R
 
-			for {
+		for {
+			s.waiting()
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				s.working()
+				return
+			case <-s.stop():
+				s.working()
+				// a stopped worker sends its result as a finished one does
 				select {
 				// ensure cancelling context is taken into account
 				case <-ctx.Done():
-					return
-				case v, ok := <-input:
-					// accumulate result until input channel closes
-					if !ok {
-						select {
-						// ensure cancelling context is taken into account
-						case <-ctx.Done():
-						case result <- res:
-						}
-						return
+				case result <- res:
+				}
+				return
+			case v, ok := <-input:
+				s.working()
+				// accumulate result until input channel closes
+				if !ok {
+					s.exhausted()
+					select {
+					// ensure cancelling context is taken into account
+					case <-ctx.Done():
+					case result <- res:
 					}
+					return
+				}
 
+				// the result is kept as it is if the accumulator panics
+				o.call(v, func() {
 					res = accumulator(v, res)
-				}
+				})
 			}
-		}()
-	}
+		}
+	})
 
 	// goroutine for closing result channel when data is in it and results are already accumulated
 	go func() {
@@ -120,7 +232,11 @@ func (p *poolImpl[T, R]) Accumulate(
 }
 
 // List represents poolImpl implementation of function with the same name
-func (p *poolImpl[T, R]) List(ctx context.Context, workers int, start T, searcher Searcher[T]) {
+func (p *poolImpl[T, R]) List(ctx context.Context, workers int, start T, searcher Searcher[T], opts ...Option[T]) {
+	o := newOptions(opts)
+	// every goroutine of the search is finished once List returns
+	defer o.panics.rethrow()
+
 	// slice for collecting results on each level
 	data := []T{start}
 
@@ -155,27 +271,26 @@ func (p *poolImpl[T, R]) List(ctx context.Context, workers int, start T, searche
 						if !ok {
 							return
 						}
+						// an element the searcher panics on has no children
+						var children []T
+						o.call(v, func() {
+							children = searcher(v)
+						})
 						select {
 						// ensure cancelling context is taken into account
 						case <-ctx.Done():
 							return
-						case result <- searcher(v):
+						case result <- children:
 						}
 					}
 				}
 			}()
 		}
 
-		// goroutine for closing result channel when data is in it and results are already searched
-		// (it relates only to current level)
-		go func() {
-			defer close(result)
-			// wait for all workers to complete
-			wg.Wait()
-		}()
-
-		// channel to read data to form new level
+		// channel to read data to form new level, the closing goroutine waits for it as well
+		wg.Add(1)
 		go func() {
+			defer wg.Done()
 			defer close(input)
 			for _, v := range data {
 				select {
@@ -187,12 +302,24 @@ func (p *poolImpl[T, R]) List(ctx context.Context, workers int, start T, searche
 			}
 		}()
 
+		// goroutine for closing result channel when data is in it and results are already searched
+		// (it relates only to current level)
+		go func() {
+			defer close(result)
+			// wait for all workers to complete
+			wg.Wait()
+		}()
+
 		// barrier synchronization on current level
 		newData := make([]T, 0)
 		for {
 			select {
 			// ensure cancelling context is taken into account
 			case <-ctx.Done():
+				// wait for the goroutines of the level to finish, so that none of them
+				// outlives List
+				for range result {
+				}
 				return
 			case r, ok := <-result:
 				if !ok {
@@ -210,45 +337,97 @@ func (p *poolImpl[T, R]) List(ctx context.Context, workers int, start T, searche
 	}
 }
 
+// Walk represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) Walk(ctx context.Context, workers int, start T, searcher Searcher[T], opts ...Option[T]) <-chan T {
+	// channel for passing visited elements
+	result := make(chan T)
+
+	go func() {
+		defer close(result)
+		// every call of the searcher is finished once List returns
+		p.List(ctx, workers, start, func(parent T) []T {
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return nil
+			case result <- parent:
+			}
+			return searcher(parent)
+		}, append(opts[:len(opts):len(opts)], dropPanics[T])...)
+	}()
+
+	return result
+}
+
 // Transform represents poolImpl implementation of function with the same name
 func (p *poolImpl[T, R]) Transform(
 	ctx context.Context,
 	workers int,
 	input <-chan T,
 	transformer Transformer[T, R],
+	opts ...Option[T],
+) <-chan R {
+	return transformItems(ctx, workers, input, shared(transformer), newOptions(opts))
+}
+
+// transformItems transforms the items as Transform does, with the transformer every worker makes
+func transformItems[T, R any](
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	newTransformer factory[T, R],
+	o options[T],
 ) <-chan R {
+	input = o.buffer(ctx, input)
+	if o.ordered && (workers > 0 || o.scaling != nil) {
+		return transformOrdered(ctx, workers, input, newTransformer, o)
+	}
+
 	// channel for collecting results
 	result := make(chan R)
 
 	// wait group to wait workers to finish their work
 	wg := new(sync.WaitGroup)
 
-	for i := 0; i < workers; i++ {
-		// implement wait group counter pattern
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+	startWorkers(ctx, workers, o.scaling, wg, func(s *scaler) {
+		transformer, teardown := newTransformer()
+		if teardown != nil {
+			defer teardown()
+		}
+
+		for {
+			s.waiting()
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				s.working()
+				return
+			case <-s.stop():
+				s.working()
+				return
+			case v, ok := <-input:
+				s.working()
+				if !ok {
+					s.exhausted()
+					return
+				}
+
+				var r R
+				if !o.call(v, func() {
+					r = transformer(v)
+				}) {
+					continue
+				}
 
-			for {
 				select {
 				// ensure cancelling context is taken into account
 				case <-ctx.Done():
 					return
-				case v, ok := <-input:
-					if !ok {
-						return
-					}
-
-					select {
-					// ensure cancelling context is taken into account
-					case <-ctx.Done():
-						return
-					case result <- transformer(v):
-					}
+				case result <- r:
 				}
 			}
-		}()
-	}
+		}
+	})
 
 	// goroutine for closing result channel when data is in it and results are
 	// already transformed
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -155,6 +156,49 @@ func TestListContextDone(t *testing.T) {
 	})
 }
 
+func TestWalk(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	// the tree of the numbers up to 30, the children of n being 2n and 2n+1
+	visited := collect(wp.Walk(ctx, 4, 1, func(parent int) []int {
+		var children []int
+		for _, child := range []int{2 * parent, 2*parent + 1} {
+			if child <= 30 {
+				children = append(children, child)
+			}
+		}
+		return children
+	}))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	sort.Ints(visited)
+	require.Len(t, visited, 30)
+	for i, v := range visited {
+		require.Equal(t, i+1, v)
+	}
+}
+
+func TestWalkContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[int, int]()
+	// the search never ends unless it is cancelled
+	out := wp.Walk(ctx, 2, 0, func(parent int) []int {
+		return []int{parent + 1, parent + 2}
+	})
+	for v := range out {
+		if v > 10 {
+			cancel()
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
 func TestListPerformance(t *testing.T) {
 	first := testing.Benchmark(func(b *testing.B) {
 		ctx := context.Background()
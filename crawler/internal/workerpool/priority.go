@@ -0,0 +1,118 @@
+package workerpool
+
+import (
+	"container/heap"
+	"context"
+)
+
+// priority holds the size of the queue of a stage and the order of its items
+type priority[T any] struct {
+	size int
+	less func(a, b T) bool
+}
+
+// WithPriority makes a stage receive the items of its input channel through a priority queue
+// of up to size items, as Prioritize does, so that a worker takes the most urgent item waiting
+// rather than the one received first, e.g. a small file rather than a huge one found before it.
+// The priority queue takes the place of the queue of the WithQueue option.
+// WithPriority panics if size is not positive or less is nil.
+func WithPriority[T any](size int, less func(a, b T) bool) Option[T] {
+	if size < 1 || less == nil {
+		panic("Invalid priority")
+	}
+	return func(o *options[T]) {
+		o.priority = &priority[T]{size: size, less: less}
+	}
+}
+
+// Prioritize returns a channel passing the items of the input channel through a priority
+// queue of up to size items, the item sent first being the least one according to less among
+// the items queued. The items are received as long as the queue is not full, so the more the
+// consumer lags behind, the more items are ordered, while an item may wait as long as there
+// are more urgent ones arriving. The queue applies backpressure as the one of Buffer does.
+// The output channel is closed after the input channel is closed and the queue is empty.
+// Once the context is done the items are dropped, but the input channel is still drained
+// until it is closed, so that the output channel closes after the producer finishes.
+// Prioritize panics if size is not positive or less is nil.
+func Prioritize[T any](ctx context.Context, input <-chan T, size int, less func(a, b T) bool) <-chan T {
+	if size < 1 || less == nil {
+		panic("Invalid priority")
+	}
+
+	// channel to pass the most urgent items to the consumer
+	result := make(chan T)
+
+	go func() {
+		defer close(result)
+
+		queue := &itemHeap[T]{items: make([]T, 0, size), less: less}
+
+		in := input
+		for in != nil || queue.Len() > 0 {
+			// the queue is full, so no item is received
+			receive := in
+			if queue.Len() == size {
+				receive = nil
+			}
+			// the queue is empty, so no item is sent
+			var send chan<- T
+			var first T
+			if queue.Len() > 0 {
+				send = result
+				first = queue.items[0]
+			}
+
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				if in != nil {
+					for range in {
+					}
+				}
+				return
+			case v, ok := <-receive:
+				if !ok {
+					in = nil
+					continue
+				}
+				heap.Push(queue, v)
+			case send <- first:
+				heap.Pop(queue)
+			}
+		}
+	}()
+
+	return result
+}
+
+// itemHeap is a heap of the queued items, the least of them on top
+type itemHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *itemHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *itemHeap[T]) Less(i, j int) bool {
+	return h.less(h.items[i], h.items[j])
+}
+
+func (h *itemHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *itemHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(T))
+}
+
+func (h *itemHeap[T]) Pop() any {
+	n := len(h.items) - 1
+	x := h.items[n]
+	var zero T
+	// the item is not held by the queue any longer
+	h.items[n] = zero
+	h.items = h.items[:n]
+	return x
+}
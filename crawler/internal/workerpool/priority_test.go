@@ -0,0 +1,93 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lessInt(a, b int) bool {
+	return a < b
+}
+
+func TestPrioritize(t *testing.T) {
+	in := make(chan int)
+	out := Prioritize(context.Background(), in, 4, lessInt)
+
+	// the queue is filled while the consumer is not ready
+	for _, v := range []int{5, 3, 1, 4} {
+		require.True(t, trySend(in, v))
+	}
+	require.False(t, trySend(in, 2))
+
+	require.Equal(t, 1, <-out)
+	require.True(t, trySend(in, 2))
+	close(in)
+
+	require.Equal(t, []int{2, 3, 4, 5}, collect(out))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestPrioritizeContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := Prioritize(ctx, in, 2, lessInt)
+
+	require.True(t, trySend(in, 1))
+	cancel()
+
+	// the input channel is drained until it is closed
+	for i := 0; i < 10; i++ {
+		require.True(t, trySend(in, i))
+	}
+	close(in)
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTransformPriority(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	in := make(chan int)
+	taken := make(chan struct{})
+	gate := make(chan struct{})
+	var once sync.Once
+	out := wp.Transform(ctx, 1, in, func(current int) int {
+		once.Do(func() {
+			close(taken)
+		})
+		<-gate
+		return current
+	}, WithPriority(10, lessInt))
+
+	// the worker is busy with the first item while the rest of them are queued
+	require.True(t, trySend(in, 100))
+	<-taken
+	for _, v := range []int{9, 2, 7, 1, 8} {
+		require.True(t, trySend(in, v))
+	}
+	close(in)
+	close(gate)
+
+	require.Equal(t, []int{100, 1, 2, 7, 8, 9}, collect(out))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestInvalidPriority(t *testing.T) {
+	require.Panics(t, func() {
+		WithPriority(0, lessInt)
+	})
+	require.Panics(t, func() {
+		WithPriority[int](1, nil)
+	})
+	require.Panics(t, func() {
+		Prioritize(context.Background(), make(chan int), 0, lessInt)
+	})
+}
@@ -0,0 +1,131 @@
+package workerpool
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is a panic of a user function recovered by a worker of the pool.
+// Value is the value the function panicked with, which is not necessarily an error, and
+// Stack is the stack of the goroutine at the time of the panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value of the panic if it is an error, so that errors.Is and errors.As
+// match it
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// OnPanic sets the handler of the panics of the user function of a stage. Every worker
+// recovers a panic of the function, converts it to a *PanicError and passes it to the
+// handler along with the item the function panicked on, and the item yields no result,
+// while the stage goes on with the rest of the items.
+// Without the handler the panics are not raised in the goroutines of the stage, which no
+// caller could recover them in. List, which runs in the goroutine of its caller, raises the
+// first of them again once the search is finished. The other stages drop the items the
+// function panicked on, the Try stages record the panics in their group, and CollectPanics
+// records them for the caller of any stage.
+// The handler is called by the workers concurrently, so it must be thread-safe.
+func OnPanic[T any](handler func(current T, err error)) Option[T] {
+	return func(o *options[T]) {
+		o.onPanic = handler
+	}
+}
+
+// Panics collects the panics of the user functions of the stages it is passed to with
+// CollectPanics. It is safe for concurrent use.
+type Panics struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// Err returns the panics collected as *PanicError joined, or nil if there are none. The
+// workers of a stage are finished once its output channel is drained, so Err is called
+// after the results are received.
+func (p *Panics) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+// add records the panic of the user function
+func (p *Panics) add(err error) {
+	p.mu.Lock()
+	p.errs = append(p.errs, err)
+	p.mu.Unlock()
+}
+
+// CollectPanics makes a stage record the panics of its user function in p, besides passing
+// them to the handler of the OnPanic option given before it, if any.
+func CollectPanics[T any](p *Panics) Option[T] {
+	return func(o *options[T]) {
+		handler := o.onPanic
+		o.onPanic = func(current T, err error) {
+			p.add(err)
+			if handler != nil {
+				handler(current, err)
+			}
+		}
+	}
+}
+
+// dropPanics makes a stage running List in a goroutine of its own drop the panics of the
+// user function rather than raise them again, unless the handler is set
+func dropPanics[T any](o *options[T]) {
+	if o.onPanic == nil {
+		o.onPanic = func(T, error) {}
+	}
+}
+
+// call calls fn for the item, recovering its panic, which is passed to the handler or
+// recorded to be raised again by List, reporting whether fn returned
+func (o options[T]) call(current T, fn func()) (ok bool) {
+	defer func() {
+		// a panic with nil is recovered as *runtime.PanicNilError
+		v := recover()
+		if v == nil {
+			return
+		}
+		err := &PanicError{Value: v, Stack: debug.Stack()}
+		if o.onPanic != nil {
+			o.onPanic(current, err)
+		} else {
+			o.panics.record(err)
+		}
+	}()
+
+	fn()
+	return true
+}
+
+// panicRecord keeps the first panic of the user function of a stage without the handler
+type panicRecord struct {
+	once  sync.Once
+	first *PanicError
+}
+
+// record keeps the panic unless there has been one already
+func (p *panicRecord) record(err *PanicError) {
+	p.once.Do(func() {
+		p.first = err
+	})
+}
+
+// rethrow raises the panic recorded again, if any, it is called by List in the goroutine of
+// its caller once the workers of the search are finished, so that the panic is not recorded
+// concurrently
+func (p *panicRecord) rethrow() {
+	if p.first != nil {
+		panic(p.first)
+	}
+}
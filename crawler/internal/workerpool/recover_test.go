@@ -0,0 +1,272 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errPanic = errors.New("test panic")
+
+// panics records the panics passed to the handler
+type panics struct {
+	mu    sync.Mutex
+	items []int
+	errs  []error
+}
+
+func (p *panics) handle(current int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.items = append(p.items, current)
+	p.errs = append(p.errs, err)
+}
+
+// panicky doubles the item, panicking with an error on the multiples of three and with a
+// string on the multiples of five
+func panicky(current int) int {
+	switch {
+	case current%3 == 0:
+		panic(errPanic)
+	case current%5 == 0:
+		panic("not an error")
+	}
+	return 2 * current
+}
+
+func TestTransformPanic(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 16)
+	for i := range s {
+		s[i] = i
+	}
+
+	for _, ordered := range []bool{false, true} {
+		recorder := new(panics)
+		opts := []Option[int]{OnPanic(recorder.handle)}
+		if ordered {
+			opts = append(opts, Ordered[int]())
+		}
+
+		result := collect(wp.Transform(ctx, 3, generate(s), panicky, opts...))
+		require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+		if !ordered {
+			sort.Ints(result)
+		}
+		require.Equal(t, []int{2, 4, 8, 14, 16, 22, 26, 28}, result)
+
+		sort.Ints(recorder.items)
+		require.Equal(t, []int{0, 3, 5, 6, 9, 10, 12, 15}, recorder.items)
+		for _, err := range recorder.errs {
+			var panicErr *PanicError
+			require.ErrorAs(t, err, &panicErr)
+			require.NotEmpty(t, panicErr.Stack)
+		}
+		// both error and non-error panics are converted to errors
+		var errs, values int
+		for _, err := range recorder.errs {
+			if errors.Is(err, errPanic) {
+				errs++
+			} else if err.Error() == "panic: not an error" {
+				values++
+			}
+		}
+		require.Equal(t, 6, errs)
+		require.Equal(t, 2, values)
+	}
+}
+
+func TestTransformPanicWithoutHandler(t *testing.T) {
+	wp := New[int, int]()
+
+	// the panics are not raised in the goroutines of the stage, the items are dropped
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		result := collect(wp.Transform(context.Background(), 2, generate([]int{1, 2, 3, 4}), panicky, opts...))
+		sort.Ints(result)
+		require.Equal(t, []int{2, 4, 8}, result)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestCollectPanics(t *testing.T) {
+	wp := New[int, int]()
+
+	collected := new(Panics)
+	recorder := new(panics)
+	result := collect(wp.Transform(context.Background(), 2, generate([]int{1, 2, 3, 4, 5}), panicky,
+		OnPanic(recorder.handle), CollectPanics[int](collected)))
+	sort.Ints(result)
+	require.Equal(t, []int{2, 4, 8}, result)
+
+	// the panics are both collected and passed to the handler
+	err := collected.Err()
+	require.ErrorIs(t, err, errPanic)
+	require.ErrorContains(t, err, "panic: not an error")
+	sort.Ints(recorder.items)
+	require.Equal(t, []int{3, 5}, recorder.items)
+
+	require.NoError(t, new(Panics).Err())
+}
+
+func TestWalkPanicWithoutHandler(t *testing.T) {
+	wp := New[int, int]()
+
+	// the search runs in a goroutine of its own, so the panic is not raised again
+	visited := collect(wp.Walk(context.Background(), 2, 1, func(parent int) []int {
+		if parent == 3 {
+			panic(errPanic)
+		}
+		if parent > 3 {
+			return nil
+		}
+		return []int{2 * parent, 2*parent + 1}
+	}))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	sort.Ints(visited)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, visited)
+}
+
+func TestListPanicWithoutHandler(t *testing.T) {
+	wp := New[int, int]()
+
+	var mu sync.Mutex
+	var visited []int
+	require.PanicsWithError(t, "panic: test panic", func() {
+		wp.List(context.Background(), 2, 1, func(parent int) []int {
+			mu.Lock()
+			visited = append(visited, parent)
+			mu.Unlock()
+
+			if parent == 3 {
+				panic(errPanic)
+			}
+			if parent > 3 {
+				return nil
+			}
+			return []int{2 * parent, 2*parent + 1}
+		})
+	})
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	// the search goes on after the panic and finishes before the panic is raised again
+	sort.Ints(visited)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, visited)
+}
+
+func TestTryPanicWithoutHandler(t *testing.T) {
+	wp := New[int, int]()
+
+	// the Try stages return the panics as errors rather than raising them
+	result, g := wp.TryTransform(context.Background(), 2, generate([]int{1, 2, 3}), func(current int) (int, error) {
+		return panicky(current), nil
+	})
+	collect(result)
+	require.ErrorIs(t, g.Wait(), errPanic)
+}
+
+func TestAccumulatePanic(t *testing.T) {
+	wp := New[int, int]()
+
+	recorder := new(panics)
+	results := collect(wp.Accumulate(context.Background(), 2, generate([]int{1, 2, 3, 4, 5}), func(current int, accum int) int {
+		return accum + panicky(current)
+	}, OnPanic(recorder.handle)))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	// the intermediate results are kept as they are on a panic
+	var sum int
+	for _, r := range results {
+		sum += r
+	}
+	require.Equal(t, 14, sum)
+
+	sort.Ints(recorder.items)
+	require.Equal(t, []int{3, 5}, recorder.items)
+}
+
+func TestListPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	var mu sync.Mutex
+	var visited []int
+	recorder := new(panics)
+	wp.List(context.Background(), 2, 1, func(parent int) []int {
+		mu.Lock()
+		visited = append(visited, parent)
+		mu.Unlock()
+
+		if parent == 3 {
+			panic(errPanic)
+		}
+		if parent > 3 {
+			return nil
+		}
+		return []int{2 * parent, 2*parent + 1}
+	}, OnPanic(recorder.handle))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	// the children of the element the searcher panicked on are not visited
+	sort.Ints(visited)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, visited)
+	require.Equal(t, []int{3}, recorder.items)
+}
+
+func TestFilterPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		recorder := new(panics)
+		result := collect(wp.Filter(context.Background(), 2, generate([]int{1, 2, 3, 4}), func(current int) bool {
+			return panicky(current) > 2
+		}, append(opts, OnPanic(recorder.handle))...))
+
+		sort.Ints(result)
+		require.Equal(t, []int{2, 4}, result)
+		require.Equal(t, []int{3}, recorder.items)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestFlatMapPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		recorder := new(panics)
+		result := collect(wp.FlatMap(context.Background(), 2, generate([]int{1, 2, 3}), func(current int) []int {
+			return []int{panicky(current), current}
+		}, append(opts, OnPanic(recorder.handle))...))
+
+		sort.Ints(result)
+		require.Equal(t, []int{1, 2, 2, 4}, result)
+		require.Equal(t, []int{3}, recorder.items)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTransformBatchPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	recorder := new(panics)
+	result := collect(wp.TransformBatch(context.Background(), 2, generate([]int{1, 2, 3, 4}), 2, 0, func(batch []int) []int {
+		result := make([]int, len(batch))
+		for i, v := range batch {
+			result[i] = panicky(v)
+		}
+		return result
+	}, OnPanic(recorder.handle)))
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	// every item of the batch is reported
+	require.Equal(t, []int{2, 4}, result)
+	sort.Ints(recorder.items)
+	require.Equal(t, []int{3, 4}, recorder.items)
+}
@@ -0,0 +1,119 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// scaling holds the bounds of the number of workers and the interval of their checks
+type scaling struct {
+	min, max int
+	interval time.Duration
+}
+
+// scaler changes the number of the workers of a stage at runtime
+type scaler struct {
+	scaling
+
+	// quit stops one of the idle workers
+	quit chan struct{}
+	// drained is closed once the input channel is closed, there is nothing to scale for then
+	drained chan struct{}
+	once    sync.Once
+
+	running atomic.Int64
+	idle    atomic.Int64
+}
+
+// waiting marks a worker waiting for an item
+func (s *scaler) waiting() {
+	if s != nil {
+		s.idle.Add(1)
+	}
+}
+
+// working marks a worker no longer waiting for an item
+func (s *scaler) working() {
+	if s != nil {
+		s.idle.Add(-1)
+	}
+}
+
+// stop returns the channel stopping an idle worker, there is none without scaling
+func (s *scaler) stop() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.quit
+}
+
+// exhausted tells the scaler the input channel is closed
+func (s *scaler) exhausted() {
+	if s != nil {
+		s.once.Do(func() {
+			close(s.drained)
+		})
+	}
+}
+
+// startWorkers starts the workers of a stage, along with the goroutine scaling them if the
+// scaling is configured, the wait group is done once every one of them is done
+func startWorkers(ctx context.Context, workers int, sc *scaling, wg *sync.WaitGroup, work func(s *scaler)) {
+	if sc == nil {
+		for i := 0; i < workers; i++ {
+			// implement wait group counter pattern
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				work(nil)
+			}()
+		}
+		return
+	}
+
+	s := &scaler{scaling: *sc, quit: make(chan struct{}), drained: make(chan struct{})}
+	spawn := func() {
+		s.running.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer s.running.Add(-1)
+			work(s)
+		}()
+	}
+	for i := 0; i < min(max(workers, s.min), s.max); i++ {
+		spawn()
+	}
+
+	// the goroutine scaling the workers is waited for as well, so that it does not start
+	// a worker once the rest of them are done
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				return
+			case <-s.drained:
+				return
+			case <-ticker.C:
+				running, idle := s.running.Load(), s.idle.Load()
+				switch {
+				case idle == 0 && running < int64(s.max):
+					spawn()
+				case idle > 1 && running > int64(s.min):
+					// the worker is stopped only if it is still idle
+					select {
+					case s.quit <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}
+	}()
+}
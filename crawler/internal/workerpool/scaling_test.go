@@ -0,0 +1,145 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// concurrency tracks the number of the calls running at once
+type concurrency struct {
+	running atomic.Int64
+	peak    atomic.Int64
+}
+
+func (c *concurrency) enter() {
+	running := c.running.Add(1)
+	for {
+		peak := c.peak.Load()
+		if running <= peak || c.peak.CompareAndSwap(peak, running) {
+			return
+		}
+	}
+}
+
+func (c *concurrency) leave() {
+	c.running.Add(-1)
+}
+
+func TestTransformScaling(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	in := make(chan int)
+	calls := new(concurrency)
+	out := wp.Transform(ctx, 1, in, func(current int) int {
+		calls.enter()
+		defer calls.leave()
+		time.Sleep(20 * time.Millisecond)
+		return current + 1
+	}, WithScaling[int](1, 6, 5*time.Millisecond))
+
+	results := make(chan []int)
+	go func() {
+		results <- collect(out)
+	}()
+
+	// a burst of items makes the workers scale up
+	for i := 0; i < 60; i++ {
+		in <- i
+	}
+	burst := runtime.NumGoroutine()
+	require.Greater(t, calls.peak.Load(), int64(1))
+	require.LessOrEqual(t, calls.peak.Load(), int64(6))
+
+	// the idle workers are stopped
+	time.Sleep(200 * time.Millisecond)
+	require.Less(t, runtime.NumGoroutine(), burst)
+
+	close(in)
+	result := <-results
+	require.Len(t, result, 60)
+	var sum int
+	for _, r := range result {
+		sum += r
+	}
+	require.Equal(t, 60*61/2, sum)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestAccumulateScaling(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	in := make(chan int)
+	out := wp.Accumulate(ctx, 4, in, func(current int, accum int) int {
+		time.Sleep(time.Millisecond)
+		return accum + current
+	}, WithScaling[int](1, 4, 5*time.Millisecond))
+
+	results := make(chan []int)
+	go func() {
+		results <- collect(out)
+	}()
+
+	// the items come slowly, so the workers are stopped, sending their results
+	for i := 1; i <= 30; i++ {
+		in <- i
+		time.Sleep(2 * time.Millisecond)
+	}
+	close(in)
+
+	var sum int
+	for _, r := range <-results {
+		sum += r
+	}
+	require.Equal(t, 30*31/2, sum)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTransformOrderedScaling(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	s := make([]int, 50)
+	for i := range s {
+		s[i] = i
+	}
+
+	out := wp.Transform(ctx, 1, generate(s), func(current int) int {
+		time.Sleep(time.Millisecond)
+		return current
+	}, Ordered[int](), WithScaling[int](1, 4, time.Millisecond))
+
+	result := collect(out)
+	require.Equal(t, s, result)
+}
+
+func TestScalingContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[int, int]()
+	out := wp.Transform(ctx, 2, make(chan int), func(current int) int {
+		return current
+	}, WithScaling[int](1, 4, time.Millisecond))
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestInvalidScaling(t *testing.T) {
+	require.Panics(t, func() { WithScaling[int](0, 1, time.Second) })
+	require.Panics(t, func() { WithScaling[int](2, 1, time.Second) })
+	require.Panics(t, func() { WithScaling[int](1, 1, 0) })
+}
@@ -0,0 +1,146 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group collects the errors of the user function of a stage, as errgroup does.
+type Group struct {
+	// done is closed once the workers of the stage are finished
+	done chan struct{}
+	mu   sync.Mutex
+	errs []error
+}
+
+// newGroup creates a group of a stage which is not finished yet
+func newGroup() *Group {
+	return &Group{done: make(chan struct{})}
+}
+
+// Wait blocks until the workers of the stage are finished, and returns the errors of the user
+// function joined, or nil if there are none. The workers are finished once the output channel
+// of the stage is drained, so Wait is called after the results are received.
+func (g *Group) Wait() error {
+	<-g.done
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// add records the error of the user function
+func (g *Group) add(err error) {
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+}
+
+// withGroup adds the option making the stage record the panics of the user function in the
+// group, besides passing them to the handler, after the options given
+func withGroup[T any](g *Group, opts []Option[T]) []Option[T] {
+	return append(opts[:len(opts):len(opts)], func(o *options[T]) {
+		handler := o.onPanic
+		o.onPanic = func(current T, err error) {
+			g.add(err)
+			if handler != nil {
+				handler(current, err)
+			}
+		}
+	})
+}
+
+// track passes the results of the stage through, the group is finished once all of them
+// are passed
+func track[R any](ctx context.Context, g *Group, input <-chan R) <-chan R {
+	// channel for passing the results
+	result := make(chan R)
+
+	go func() {
+		defer close(g.done)
+		defer close(result)
+		for r := range input {
+			select {
+			// ensure cancelling context is taken into account
+			case <-ctx.Done():
+				// wait for the workers to finish, so that none of them outlives the stage
+				for range input {
+				}
+				return
+			case result <- r:
+			}
+		}
+	}()
+
+	return result
+}
+
+// TryTransform represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) TryTransform(
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	transformer TryTransformer[T, R],
+	opts ...Option[T],
+) (<-chan R, *Group) {
+	g := newGroup()
+
+	// an item failed to be transformed yields no result
+	results := flatMap(ctx, workers, input, func(current T) []R {
+		r, err := transformer(current)
+		if err != nil {
+			g.add(err)
+			return nil
+		}
+		return []R{r}
+	}, newOptions(withGroup(g, opts)))
+
+	return track(ctx, g, results), g
+}
+
+// TryAccumulate represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) TryAccumulate(
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	accumulator TryAccumulator[T, R],
+	opts ...Option[T],
+) (<-chan R, *Group) {
+	g := newGroup()
+
+	// an item failed to be accumulated leaves the intermediate result as it is
+	results := p.Accumulate(ctx, workers, input, func(current T, accum R) R {
+		r, err := accumulator(current, accum)
+		if err != nil {
+			g.add(err)
+			return accum
+		}
+		return r
+	}, withGroup(g, opts)...)
+
+	return track(ctx, g, results), g
+}
+
+// TryList represents poolImpl implementation of function with the same name
+func (p *poolImpl[T, R]) TryList(
+	ctx context.Context,
+	workers int,
+	start T,
+	searcher TrySearcher[T],
+	opts ...Option[T],
+) error {
+	g := newGroup()
+
+	// an element failed to be searched has no children
+	p.List(ctx, workers, start, func(parent T) []T {
+		children, err := searcher(parent)
+		if err != nil {
+			g.add(err)
+			return nil
+		}
+		return children
+	}, withGroup(g, opts)...)
+	close(g.done)
+
+	return g.Wait()
+}
@@ -0,0 +1,136 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errOdd = errors.New("odd")
+
+// halve halves the even items, failing for the odd ones
+func halve(current int) (int, error) {
+	if current%2 != 0 {
+		return 0, fmt.Errorf("halve %d: %w", current, errOdd)
+	}
+	return current / 2, nil
+}
+
+func TestTryTransform(t *testing.T) {
+	ctx := context.Background()
+	wp := New[int, int]()
+
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		out, g := wp.TryTransform(ctx, 3, generate([]int{1, 2, 3, 4, 6}), halve, opts...)
+		result := collect(out)
+		err := g.Wait()
+
+		sort.Ints(result)
+		require.Equal(t, []int{1, 2, 3}, result)
+		require.ErrorIs(t, err, errOdd)
+		require.ErrorContains(t, err, "halve 1: odd")
+		require.ErrorContains(t, err, "halve 3: odd")
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTryTransformNoErrors(t *testing.T) {
+	wp := New[int, int]()
+
+	out, g := wp.TryTransform(context.Background(), 2, generate([]int{2, 4}), halve)
+	result := collect(out)
+	require.NoError(t, g.Wait())
+
+	sort.Ints(result)
+	require.Equal(t, []int{1, 2}, result)
+}
+
+func TestTryTransformPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	recorder := new(panics)
+	out, g := wp.TryTransform(context.Background(), 2, generate([]int{1, 3}), func(current int) (int, error) {
+		return panicky(current), nil
+	}, OnPanic(recorder.handle))
+	require.Equal(t, []int{2}, collect(out))
+
+	// the panic is collected and passed to the handler as well
+	err := g.Wait()
+	require.ErrorIs(t, err, errPanic)
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, []int{3}, recorder.items)
+}
+
+func TestTryTransformContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	wp := New[int, int]()
+	out, g := wp.TryTransform(ctx, 2, generate([]int{2, 4, 6}), halve)
+	// the results are not received
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range out {
+	}
+	require.NoError(t, g.Wait())
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTryAccumulate(t *testing.T) {
+	wp := New[int, int]()
+
+	out, g := wp.TryAccumulate(context.Background(), 2, generate([]int{1, 2, 3, 4, 6}), func(current int, accum int) (int, error) {
+		half, err := halve(current)
+		return accum + half, err
+	})
+
+	// the items failed to be accumulated are skipped
+	var sum int
+	for r := range out {
+		sum += r
+	}
+	require.Equal(t, 6, sum)
+	require.ErrorIs(t, g.Wait(), errOdd)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+}
+
+func TestTryList(t *testing.T) {
+	wp := New[int, int]()
+
+	var visited []int
+	err := wp.TryList(context.Background(), 1, 1, func(parent int) ([]int, error) {
+		visited = append(visited, parent)
+		if parent == 3 {
+			return []int{6, 7}, errOdd
+		}
+		if parent > 3 {
+			return nil, nil
+		}
+		return []int{2 * parent, 2*parent + 1}, nil
+	})
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	// the children of the element failed to be searched are not visited
+	sort.Ints(visited)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, visited)
+	require.ErrorIs(t, err, errOdd)
+}
+
+func TestTryListPanic(t *testing.T) {
+	wp := New[int, int]()
+
+	err := wp.TryList(context.Background(), 2, 3, func(parent int) ([]int, error) {
+		return []int{panicky(parent)}, nil
+	})
+	require.ErrorIs(t, err, errPanic)
+}
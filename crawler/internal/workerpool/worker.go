@@ -0,0 +1,55 @@
+package workerpool
+
+import "context"
+
+// WorkerTransformer is a function type used to transform an element of type T to another type R
+// with the resource of type S of the worker transforming it. Every worker has its own resource,
+// which is used by one goroutine at a time, so the resource needs no synchronisation, while
+// the function is still invoked concurrently with the resources of the other workers.
+type WorkerTransformer[S, T, R any] func(resource S, current T) R
+
+// factory makes the transformer of a worker along with its teardown, if any
+type factory[T, R any] func() (transformer Transformer[T, R], teardown func())
+
+// shared returns the factory making the same transformer for every worker
+func shared[T, R any](transformer Transformer[T, R]) factory[T, R] {
+	return func() (Transformer[T, R], func()) {
+		return transformer, nil
+	}
+}
+
+// TransformWith works as Transform does, with every worker owning a resource, such as
+// a database connection or a decoder, which is passed to the transformer along with the item.
+// A worker creates its resource with setup once it starts, before it takes any item, and
+// releases it with teardown, unless it is nil, once it exits, whether the input channel is
+// closed, the context is done or the worker is stopped by the WithScaling option, so that
+// stateful transformations need no locking of their state.
+// A panic of setup or teardown is not recovered, as the worker has nothing to work with.
+// TransformWith is a function rather than a method of Pool, as methods have no type
+// parameters of their own.
+func TransformWith[S, T, R any](
+	ctx context.Context,
+	workers int,
+	input <-chan T,
+	setup func() S,
+	teardown func(resource S),
+	transformer WorkerTransformer[S, T, R],
+	opts ...Option[T],
+) <-chan R {
+	if setup == nil {
+		panic("Invalid setup")
+	}
+
+	return transformItems(ctx, workers, input, func() (Transformer[T, R], func()) {
+		resource := setup()
+		var release func()
+		if teardown != nil {
+			release = func() {
+				teardown(resource)
+			}
+		}
+		return func(current T) R {
+			return transformer(resource, current)
+		}, release
+	}, newOptions(opts))
+}
@@ -0,0 +1,142 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// connection is a resource of a worker failing the test if it is used concurrently
+type connection struct {
+	busy   atomic.Bool
+	closed bool
+	used   int
+}
+
+// connections records the resources of the workers
+type connections struct {
+	mu     sync.Mutex
+	opened []*connection
+}
+
+func (c *connections) open() *connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conn := new(connection)
+	c.opened = append(c.opened, conn)
+	return conn
+}
+
+func (c *connections) close(conn *connection) {
+	conn.closed = true
+}
+
+func (c *connections) double(t *testing.T) WorkerTransformer[*connection, int, int] {
+	return func(conn *connection, current int) int {
+		require.True(t, conn.busy.CompareAndSwap(false, true), "the resource is shared")
+		defer conn.busy.Store(false)
+		require.False(t, conn.closed)
+
+		conn.used++
+		time.Sleep(time.Millisecond)
+		return 2 * current
+	}
+}
+
+func TestTransformWith(t *testing.T) {
+	ctx := context.Background()
+
+	s := make([]int, 50)
+	for i := range s {
+		s[i] = i
+	}
+
+	for _, opts := range [][]Option[int]{nil, {Ordered[int]()}} {
+		conns := new(connections)
+		result := collect(TransformWith(ctx, 4, generate(s), conns.open, conns.close, conns.double(t), opts...))
+		require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+		sort.Ints(result)
+		for i, r := range result {
+			require.Equal(t, 2*i, r)
+		}
+
+		// every worker has its own resource released once it exits
+		require.Len(t, conns.opened, 4)
+		var used int
+		for _, conn := range conns.opened {
+			require.True(t, conn.closed)
+			used += conn.used
+		}
+		require.Equal(t, len(s), used)
+	}
+}
+
+func TestTransformWithScaling(t *testing.T) {
+	ctx := context.Background()
+
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 40; i++ {
+			in <- i
+			// the items are scarce at first, and the workers are stopped
+			if i < 10 {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+
+	conns := new(connections)
+	result := collect(TransformWith(ctx, 4, in, conns.open, conns.close, conns.double(t), WithScaling[int](1, 4, 5*time.Millisecond)))
+	require.Len(t, result, 40)
+
+	// the resources of the stopped workers are released as well
+	for _, conn := range conns.opened {
+		require.True(t, conn.closed)
+	}
+}
+
+func TestTransformWithContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	conns := new(connections)
+	out := TransformWith(ctx, 2, make(chan int), conns.open, conns.close, conns.double(t))
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range out {
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
+
+	require.Len(t, conns.opened, 2)
+	for _, conn := range conns.opened {
+		require.True(t, conn.closed)
+	}
+}
+
+func TestTransformWithoutTeardown(t *testing.T) {
+	result := collect(TransformWith(context.Background(), 2, generate([]int{1, 2, 3}), func() int {
+		return 10
+	}, nil, func(resource int, current int) int {
+		return resource + current
+	}))
+
+	sort.Ints(result)
+	require.Equal(t, []int{11, 12, 13}, result)
+}
+
+func TestTransformWithInvalidSetup(t *testing.T) {
+	require.Panics(t, func() {
+		TransformWith[int, int, int](context.Background(), 1, make(chan int), nil, nil, nil)
+	})
+}
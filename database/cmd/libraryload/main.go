@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/loadtest"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func main() {
+	var (
+		address        = flag.String("addr", "localhost:9090", "address of the gRPC server")
+		concurrency    = flag.Int("concurrency", 8, "number of concurrent workers")
+		duration       = flag.Duration("duration", 30*time.Second, "duration of the run")
+		mix            = flag.String("mix", "read=8,write=1,stream=1", "relative weights of read, write and stream requests")
+		authors        = flag.Int("authors", 10, "number of authors created before the run")
+		booksPerAuthor = flag.Int("books", 10, "number of books per author created before the run")
+	)
+
+	flag.Parse()
+
+	parsedMix, err := loadtest.ParseMix(*mix)
+
+	if err != nil {
+		log.Fatalf("invalid mix: %s", err)
+	}
+
+	conn, err := grpc.NewClient(*address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	if err != nil {
+		log.Fatalf("can not connect to %s: %s", *address, err)
+	}
+
+	defer conn.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	report, err := loadtest.Run(ctx, desc.NewLibraryClient(conn), loadtest.Config{
+		Concurrency:    *concurrency,
+		Duration:       *duration,
+		Mix:            parsedMix,
+		Authors:        *authors,
+		BooksPerAuthor: *booksPerAuthor,
+	})
+
+	if err != nil {
+		log.Fatalf("load test failed: %s", err)
+	}
+
+	report.Print(os.Stdout)
+}
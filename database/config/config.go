@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"time"
 )
 
 type (
 	Config struct {
 		GRPC
 		PG
+		Pagination
+		Metrics
+		Failpoints
+		FeatureFlags
 	}
 
 	GRPC struct {
@@ -26,8 +31,29 @@ type (
 		Password string `env:"POSTGRES_PASSWORD"`
 		MaxConn  string `env:"POSTGRES_MAX_CONN"`
 	}
+
+	// Pagination.Secret signs page tokens, it must be at least
+	// pagination.MinSecretLength bytes long.
+	Pagination struct {
+		Secret string `env:"PAGINATION_SECRET"`
+	}
+
+	Metrics struct {
+		SummaryInterval time.Duration `env:"METRICS_SUMMARY_INTERVAL"`
+	}
+
+	FeatureFlags struct {
+		File string `env:"FEATURE_FLAGS_FILE"`
+	}
+
+	// Failpoints are honored only by binaries built with the failpoint tag.
+	Failpoints struct {
+		Spec string `env:"FAILPOINTS"`
+	}
 )
 
+const defaultMetricsSummaryInterval = time.Minute
+
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 
@@ -41,6 +67,24 @@ func NewConfig() (*Config, error) {
 	cfg.PG.Password = os.Getenv("POSTGRES_PASSWORD")
 	cfg.PG.MaxConn = os.Getenv("POSTGRES_MAX_CONN")
 
+	cfg.Pagination.Secret = os.Getenv("PAGINATION_SECRET")
+
+	cfg.Failpoints.Spec = os.Getenv("FAILPOINTS")
+
+	cfg.FeatureFlags.File = os.Getenv("FEATURE_FLAGS_FILE")
+
+	cfg.Metrics.SummaryInterval = defaultMetricsSummaryInterval
+
+	if interval := os.Getenv("METRICS_SUMMARY_INTERVAL"); interval != "" {
+		parsed, err := time.ParseDuration(interval)
+
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid METRICS_SUMMARY_INTERVAL %q", interval)
+		}
+
+		cfg.Metrics.SummaryInterval = parsed
+	}
+
 	cfg.PG.URL = fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable&pool_max_conns=%s",
 		cfg.PG.User,
 		cfg.PG.Password,
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE author_book
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'author'
+        CONSTRAINT author_book_role_check CHECK (role IN ('author', 'translator', 'editor', 'illustrator'));
+
+-- an author may take part in the same book in several roles
+ALTER TABLE author_book DROP CONSTRAINT author_book_pkey;
+ALTER TABLE author_book ADD PRIMARY KEY (author_id, book_id, role);
+
+-- +goose Down
+DELETE FROM author_book a USING author_book b
+WHERE a.author_id = b.author_id AND a.book_id = b.book_id AND a.role > b.role;
+ALTER TABLE author_book DROP CONSTRAINT author_book_pkey;
+ALTER TABLE author_book ADD PRIMARY KEY (author_id, book_id);
+ALTER TABLE author_book DROP COLUMN role;
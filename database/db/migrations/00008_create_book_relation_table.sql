@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE book_relation
+(
+    book_id UUID REFERENCES book (id) ON DELETE CASCADE,
+    related_book_id UUID REFERENCES book (id) ON DELETE CASCADE,
+    relation TEXT NOT NULL
+        CONSTRAINT book_relation_relation_check CHECK (relation IN ('sequel_of', 'translation_of', 'same_series')),
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (book_id, related_book_id, relation),
+    CONSTRAINT book_relation_self_check CHECK (book_id <> related_book_id)
+);
+
+CREATE INDEX book_relation_related_book_idx ON book_relation USING HASH (related_book_id);
+
+-- +goose Down
+DROP TABLE book_relation;
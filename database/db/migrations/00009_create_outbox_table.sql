@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE outbox
+(
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    delivered_at TIMESTAMP,
+    attempts INT DEFAULT 0 NOT NULL,
+    last_error TEXT
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL;
+
+CREATE INDEX outbox_pending_aggregate_idx ON outbox (aggregate_type, aggregate_id, id) WHERE delivered_at IS NULL;
+
+-- +goose Down
+DROP TABLE outbox;
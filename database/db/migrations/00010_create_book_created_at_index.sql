@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX book_created_at_idx ON book (created_at, id);
+
+-- +goose Down
+DROP INDEX book_created_at_idx;
@@ -862,6 +862,7 @@ func setupLibrary(
 
 	cmd.Env = append(cmd.Env, "GRPC_PORT="+grpcPort)
 	cmd.Env = append(cmd.Env, "GRPC_GATEWAY_PORT="+grpcGatewayPort)
+	cmd.Env = append(cmd.Env, "PAGINATION_SECRET="+strings.Repeat("s", 32))
 
 	require.NoError(t, cmd.Start())
 	grpcClient := newGRPCClient(t, grpcPort)
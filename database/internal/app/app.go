@@ -24,11 +24,23 @@ import (
 	"github.com/TimurUrazov/go-projects/database/config"
 	libraryGrpc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/controller"
+	"github.com/TimurUrazov/go-projects/database/internal/failpoint"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/gateway"
+	"github.com/TimurUrazov/go-projects/database/internal/metrics"
+	"github.com/TimurUrazov/go-projects/database/internal/outbox"
+	"github.com/TimurUrazov/go-projects/database/internal/pagination"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
 	"google.golang.org/grpc"
 )
 
-const gracefulShutdownTimeout = 5 * time.Second
+const (
+	gracefulShutdownTimeout = 5 * time.Second
+	outboxRelayInterval     = time.Second
+	outboxBatchSize         = 100
+
+	featureFlagsReloadInterval = 5 * time.Second
+)
 
 func Run(logger *zap.Logger, cfg *config.Config) {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -45,13 +57,49 @@ func Run(logger *zap.Logger, cfg *config.Config) {
 
 	db.SetupPostgres(dbPool, logger)
 
-	repo := repository.NewPostgresRepository(dbPool, logger)
+	if err := failpoint.Configure(cfg.Failpoints.Spec); err != nil {
+		logger.Error("cannot configure failpoints", zap.Error(err))
+		os.Exit(-1)
+	}
 
-	useCases := library.New(logger, repo, repo)
+	if failpoint.Enabled {
+		logger.Warn("failpoints are compiled in, the build must not be used in production",
+			zap.String("failpoints", cfg.Failpoints.Spec))
+	}
 
-	ctrl := controller.New(logger, useCases, useCases)
+	flags := featureflag.NewStore(logger, cfg.FeatureFlags.File)
 
-	go runRest(ctx, cfg, logger)
+	if err := flags.Load(); err != nil {
+		logger.Error("cannot load feature flags", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	pageTokens, err := pagination.NewCodec([]byte(cfg.Pagination.Secret))
+
+	if err != nil {
+		logger.Error("cannot create page token codec, check PAGINATION_SECRET", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	appMetrics := metrics.New()
+
+	postgresRepo := repository.NewPostgresRepository(dbPool, logger)
+
+	repo := repository.NewInstrumentedRepository(postgresRepo, appMetrics)
+
+	useCases := library.New(logger, repo, repo, pageTokens, flags)
+
+	outboxUseCase := library.NewOutbox(logger, postgresRepo)
+
+	ctrl := controller.New(logger, useCases, useCases, outboxUseCase, flags)
+
+	relay := outbox.NewRelay(logger, postgresRepo, outbox.NewLogPublisher(logger), appMetrics,
+		outboxRelayInterval, outboxBatchSize)
+
+	go flags.Watch(ctx, featureFlagsReloadInterval)
+	go relay.Run(ctx)
+	go appMetrics.RunSummary(ctx, logger, cfg.Metrics.SummaryInterval)
+	go runRest(ctx, cfg, logger, appMetrics)
 	go runGrpc(cfg, logger, ctrl)
 
 	<-ctx.Done()
@@ -59,8 +107,8 @@ func Run(logger *zap.Logger, cfg *config.Config) {
 	time.Sleep(gracefulShutdownTimeout)
 }
 
-func runRest(ctx context.Context, cfg *config.Config, logger *zap.Logger) {
-	mux := runtime.NewServeMux()
+func runRest(ctx context.Context, cfg *config.Config, logger *zap.Logger, appMetrics *metrics.Metrics) {
+	mux := runtime.NewServeMux(runtime.WithOutgoingHeaderMatcher(gateway.ETagHeaderMatcher(controller.ETagHeader)))
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
 	address := "localhost:" + cfg.GRPC.Port
@@ -71,10 +119,14 @@ func runRest(ctx context.Context, cfg *config.Config, logger *zap.Logger) {
 		os.Exit(-1)
 	}
 
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/metrics", appMetrics.Handler())
+	httpMux.Handle("/", gateway.WithConditionalGet(mux))
+
 	gatewayPort := ":" + cfg.GRPC.GatewayPort
 	logger.Info("gateway listening at port", zap.String("port", gatewayPort))
 
-	if err = http.ListenAndServe(gatewayPort, mux); err != nil {
+	if err = http.ListenAndServe(gatewayPort, httpMux); err != nil {
 		logger.Error("gateway listen error", zap.Error(err))
 	}
 }
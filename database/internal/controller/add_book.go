@@ -19,7 +19,12 @@ func (i *implementation) AddBook(ctx context.Context, request *desc.AddBookReque
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	book, err := i.booksUseCase.AddBook(ctx, request.GetName(), request.GetAuthorIds())
+	book, err := i.booksUseCase.AddBook(
+		ctx,
+		request.GetName(),
+		request.GetAuthorIds(),
+		toEntityContributors(request.GetContributors()),
+	)
 
 	if err != nil {
 		i.logger.Debug("error performing add book use case", zap.Error(err))
@@ -28,11 +33,12 @@ func (i *implementation) AddBook(ctx context.Context, request *desc.AddBookReque
 
 	return &desc.AddBookResponse{
 		Book: &desc.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.Authors,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
+			Id:           book.ID,
+			Name:         book.Name,
+			AuthorId:     book.Authors,
+			CreatedAt:    timestamppb.New(book.CreatedAt),
+			UpdatedAt:    timestamppb.New(book.UpdatedAt),
+			Contributors: toProtoContributors(book.Contributors),
 		},
 	}, nil
 }
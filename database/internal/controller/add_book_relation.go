@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+
+	"context"
+)
+
+func (i *implementation) AddBookRelation(
+	ctx context.Context,
+	req *desc.AddBookRelationRequest,
+) (*desc.AddBookRelationResponse, error) {
+	if err := req.ValidateAll(); err != nil {
+		i.logger.Warn("Error validating add book relation request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := i.booksUseCase.AddBookRelation(ctx, entity.BookRelation{
+		BookID:        req.GetBookId(),
+		RelatedBookID: req.GetRelatedBookId(),
+		Type:          relationTypes[req.GetRelation()],
+	})
+
+	if err != nil {
+		i.logger.Debug("Error performing add book relation use case", zap.Error(err))
+		return nil, i.convertErr(err)
+	}
+
+	return &desc.AddBookRelationResponse{}, nil
+}
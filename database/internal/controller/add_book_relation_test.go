@@ -0,0 +1,130 @@
+package controller
+
+import (
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"context"
+	"testing"
+)
+
+func Test_implementation_AddBookRelation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		request    *desc.AddBookRelationRequest
+		setupMocks func(booksUseCase *library.MockBooksUseCase)
+		wantError  bool
+		errorCode  codes.Code
+	}{
+		{
+			name: "Successful relation addition",
+			request: &desc.AddBookRelationRequest{
+				BookId:        uuid.New().String(),
+				RelatedBookId: uuid.New().String(),
+				Relation:      desc.BookRelationType_BOOK_RELATION_TYPE_SEQUEL_OF,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					AddBookRelation(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, relation entity.BookRelation) error {
+						require.Equal(t, entity.RelationSequelOf, relation.Type)
+						return nil
+					})
+			},
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name: "Relation type is not specified",
+			request: &desc.AddBookRelationRequest{
+				BookId:        uuid.New().String(),
+				RelatedBookId: uuid.New().String(),
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Invalid uuid",
+			request: &desc.AddBookRelationRequest{
+				BookId:        uuid.New().String(),
+				RelatedBookId: "1",
+				Relation:      desc.BookRelationType_BOOK_RELATION_TYPE_SAME_SERIES,
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Book not found",
+			request: &desc.AddBookRelationRequest{
+				BookId:        uuid.New().String(),
+				RelatedBookId: uuid.New().String(),
+				Relation:      desc.BookRelationType_BOOK_RELATION_TYPE_TRANSLATION_OF,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					AddBookRelation(gomock.Any(), gomock.Any()).
+					Return(entity.ErrBookNotFound)
+			},
+			wantError: true,
+			errorCode: codes.NotFound,
+		},
+		{
+			name: "Relation already exists",
+			request: &desc.AddBookRelationRequest{
+				BookId:        uuid.New().String(),
+				RelatedBookId: uuid.New().String(),
+				Relation:      desc.BookRelationType_BOOK_RELATION_TYPE_SAME_SERIES,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					AddBookRelation(gomock.Any(), gomock.Any()).
+					Return(entity.ErrBookRelationAlreadyExists)
+			},
+			wantError: true,
+			errorCode: codes.AlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorUseCase := library.NewMockAuthorUseCase(ctrl)
+			bookUseCase := library.NewMockBooksUseCase(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(bookUseCase)
+			}
+
+			ctx := context.Background()
+			_, err := impl.AddBookRelation(ctx, tt.request)
+
+			st, ok := status.FromError(err)
+
+			if tt.wantError {
+				require.True(t, ok)
+				require.Equal(t, tt.errorCode, st.Code())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
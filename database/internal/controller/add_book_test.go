@@ -3,6 +3,7 @@ package controller
 import (
 	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"context"
+	"errors"
 	"slices"
 	"testing"
 )
@@ -33,7 +35,7 @@ func Test_implementation_AddBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					AddBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					AddBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, nil)
 			},
 			wantError: false,
@@ -47,7 +49,7 @@ func Test_implementation_AddBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					AddBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					AddBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, nil)
 			},
 			wantError: false,
@@ -61,7 +63,7 @@ func Test_implementation_AddBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					AddBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					AddBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, nil)
 			},
 			wantError: false,
@@ -75,12 +77,51 @@ func Test_implementation_AddBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					AddBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					AddBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, entity.ErrAuthorNotFound)
 			},
 			wantError: true,
 			errorCode: codes.NotFound,
 		},
+		{
+			name: "Book with translator",
+			request: &desc.AddBookRequest{
+				Name:      "War and Peace",
+				AuthorIds: []string{uuid.New().String()},
+				Contributors: []*desc.Contributor{
+					{
+						AuthorId: uuid.New().String(),
+						Role:     desc.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR,
+					},
+				},
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					AddBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ []string, contributors []entity.Contributor) (entity.Book, error) {
+						if len(contributors) != 1 || contributors[0].Role != entity.RoleTranslator {
+							return entity.Book{}, errors.New("unexpected contributors")
+						}
+						return entity.Book{Contributors: contributors}, nil
+					})
+			},
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name: "Contributor role is not specified",
+			request: &desc.AddBookRequest{
+				Name: "War and Peace",
+				Contributors: []*desc.Contributor{
+					{
+						AuthorId: uuid.New().String(),
+					},
+				},
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -94,7 +135,8 @@ func Test_implementation_AddBook(t *testing.T) {
 			bookUseCase := library.NewMockBooksUseCase(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, bookUseCase, authorUseCase)
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(bookUseCase)
@@ -3,6 +3,7 @@ package controller
 import (
 	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -124,7 +125,8 @@ func Test_implementation_ChangeAuthorInfo(t *testing.T) {
 			bookUseCase := library.NewMockBooksUseCase(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, bookUseCase, authorUseCase)
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(authorUseCase)
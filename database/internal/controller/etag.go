@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ETagHeader is the metadata key the entity tag of a response is sent with.
+// The gateway forwards it as the ETag HTTP header.
+const ETagHeader = "etag"
+
+// weakETag computes weak entity tag of a resource from its id and the update
+// times of everything the response is built from. Responses with the same tag
+// are semantically equivalent, though not necessarily byte-for-byte equal.
+func weakETag(id string, updatedAt ...time.Time) string {
+	h := sha256.New()
+	h.Write([]byte(id))
+
+	for _, t := range updatedAt {
+		h.Write([]byte{0})
+		h.Write(strconv.AppendInt(nil, t.UnixNano(), 10))
+	}
+
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// setETag sends the entity tag in the response header. Failing to send it
+// only costs a client an extra full response, so the error is not returned.
+func (i *implementation) setETag(ctx context.Context, tag string) {
+	if !i.flags.Bool(featureflag.ETag, true) {
+		return
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, tag)); err != nil {
+		i.logger.Debug("Error while setting etag header", zap.Error(err))
+	}
+}
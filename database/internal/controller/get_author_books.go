@@ -18,11 +18,12 @@ func (i *implementation) GetAuthorBooks(request *desc.GetAuthorBooksRequest, str
 
 	for book := range booksCh {
 		if err := stream.Send(&desc.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.Authors,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
+			Id:           book.ID,
+			Name:         book.Name,
+			AuthorId:     book.Authors,
+			CreatedAt:    timestamppb.New(book.CreatedAt),
+			UpdatedAt:    timestamppb.New(book.UpdatedAt),
+			Contributors: toProtoContributors(book.Contributors),
 		}); err != nil {
 			if st, ok := status.FromError(err); ok {
 				i.logger.Debug("Error while performing server streaming", zap.Error(err))
@@ -24,6 +24,8 @@ func (i *implementation) GetAuthorInfo(ctx context.Context, req *desc.GetAuthorI
 		return nil, i.convertErr(err)
 	}
 
+	i.setETag(ctx, weakETag(author.ID, author.UpdatedAt))
+
 	return &desc.GetAuthorInfoResponse{
 		Id:   author.ID,
 		Name: author.Name,
@@ -4,11 +4,14 @@ import (
 	"go.uber.org/zap"
 
 	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"context"
+	"time"
 )
 
 func (i *implementation) GetBookInfo(ctx context.Context, request *desc.GetBookInfoRequest) (*desc.GetBookInfoResponse, error) {
@@ -17,20 +20,37 @@ func (i *implementation) GetBookInfo(ctx context.Context, request *desc.GetBookI
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	book, err := i.booksUseCase.GetBookInfo(ctx, request.GetId())
+	view := entity.BookViewBasic
+
+	if request.GetView() == desc.BookView_BOOK_VIEW_FULL && i.flags.Bool(featureflag.FullBookView, true) {
+		view = entity.BookViewFull
+	}
+
+	book, err := i.booksUseCase.GetBookInfo(ctx, request.GetId(), view)
 
 	if err != nil {
 		i.logger.Debug("Error performing get book info use case", zap.Error(err))
 		return nil, i.convertErr(err)
 	}
 
+	// the full view embeds authors, so their updates change the tag as well
+	updatedAt := []time.Time{book.UpdatedAt}
+
+	for _, author := range book.AuthorDetails {
+		updatedAt = append(updatedAt, author.UpdatedAt)
+	}
+
+	i.setETag(ctx, weakETag(book.ID, updatedAt...))
+
 	return &desc.GetBookInfoResponse{
 		Book: &desc.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.Authors,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
+			Id:           book.ID,
+			Name:         book.Name,
+			AuthorId:     book.Authors,
+			CreatedAt:    timestamppb.New(book.CreatedAt),
+			UpdatedAt:    timestamppb.New(book.UpdatedAt),
+			Contributors: toProtoContributors(book.Contributors),
+			Authors:      toProtoAuthors(book.AuthorDetails),
 		},
 	}, nil
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -12,6 +13,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -31,12 +34,38 @@ func Test_implementation_GetBookInfo(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					GetBookInfo(gomock.Any(), gomock.Any()).
+					GetBookInfo(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, nil)
 			},
 			wantError: false,
 			errorCode: codes.OK,
 		},
+		{
+			name: "Successful book info retrieval with full view",
+			request: &desc.GetBookInfoRequest{
+				Id:   uuid.New().String(),
+				View: desc.BookView_BOOK_VIEW_FULL,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					GetBookInfo(gomock.Any(), gomock.Any(), entity.BookViewFull).
+					Return(entity.Book{
+						AuthorDetails: []entity.Author{{ID: uuid.New().String(), Name: "Author"}},
+					}, nil)
+			},
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name: "Undefined view",
+			request: &desc.GetBookInfoRequest{
+				Id:   uuid.New().String(),
+				View: desc.BookView(42),
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
 		{
 			name: "Invalid uuid",
 			request: &desc.GetBookInfoRequest{
@@ -53,7 +82,7 @@ func Test_implementation_GetBookInfo(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					GetBookInfo(gomock.Any(), gomock.Any()).
+					GetBookInfo(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, entity.ErrBookNotFound)
 			},
 			wantError: true,
@@ -72,7 +101,8 @@ func Test_implementation_GetBookInfo(t *testing.T) {
 			bookUseCase := library.NewMockBooksUseCase(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, bookUseCase, authorUseCase)
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(bookUseCase)
@@ -92,3 +122,33 @@ func Test_implementation_GetBookInfo(t *testing.T) {
 		})
 	}
 }
+
+func Test_implementation_GetBookInfo_FullViewDisabled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"full_book_view": false}`), 0o600))
+
+	logger := zap.NewNop()
+	flags := featureflag.NewStore(logger, path)
+	require.NoError(t, flags.Load())
+
+	bookUseCase := library.NewMockBooksUseCase(ctrl)
+
+	bookUseCase.EXPECT().
+		GetBookInfo(gomock.Any(), gomock.Any(), entity.BookViewBasic).
+		Return(entity.Book{}, nil)
+
+	impl := New(logger, bookUseCase, library.NewMockAuthorUseCase(ctrl), library.NewMockOutboxUseCase(ctrl), flags)
+
+	_, err := impl.GetBookInfo(context.Background(), &desc.GetBookInfoRequest{
+		Id:   uuid.New().String(),
+		View: desc.BookView_BOOK_VIEW_FULL,
+	})
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"context"
+)
+
+func (i *implementation) GetRelatedBooks(
+	ctx context.Context,
+	req *desc.GetRelatedBooksRequest,
+) (*desc.GetRelatedBooksResponse, error) {
+	if err := req.ValidateAll(); err != nil {
+		i.logger.Warn("Error validating get related books request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	relatedBooks, err := i.booksUseCase.GetRelatedBooks(ctx, req.GetBookId())
+
+	if err != nil {
+		i.logger.Debug("Error performing get related books use case", zap.Error(err))
+		return nil, i.convertErr(err)
+	}
+
+	books := make([]*desc.RelatedBook, 0, len(relatedBooks))
+
+	for _, related := range relatedBooks {
+		books = append(books, &desc.RelatedBook{
+			Book: &desc.Book{
+				Id:           related.Book.ID,
+				Name:         related.Book.Name,
+				AuthorId:     related.Book.Authors,
+				CreatedAt:    timestamppb.New(related.Book.CreatedAt),
+				UpdatedAt:    timestamppb.New(related.Book.UpdatedAt),
+				Contributors: toProtoContributors(related.Book.Contributors),
+			},
+			Relation: toProtoRelationType(related.Type),
+			Reverse:  related.Reverse,
+		})
+	}
+
+	return &desc.GetRelatedBooksResponse{
+		Books: books,
+	}, nil
+}
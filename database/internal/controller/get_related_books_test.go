@@ -0,0 +1,104 @@
+package controller
+
+import (
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"context"
+	"testing"
+)
+
+func Test_implementation_GetRelatedBooks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		request    *desc.GetRelatedBooksRequest
+		setupMocks func(booksUseCase *library.MockBooksUseCase)
+		wantBooks  int
+		wantError  bool
+		errorCode  codes.Code
+	}{
+		{
+			name: "Successful related books retrieval",
+			request: &desc.GetRelatedBooksRequest{
+				BookId: uuid.New().String(),
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					GetRelatedBooks(gomock.Any(), gomock.Any()).
+					Return([]entity.RelatedBook{
+						{Type: entity.RelationSequelOf, Reverse: true},
+						{Type: entity.RelationSameSeries},
+					}, nil)
+			},
+			wantBooks: 2,
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name: "Invalid uuid",
+			request: &desc.GetRelatedBooksRequest{
+				BookId: "1",
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Book not found",
+			request: &desc.GetRelatedBooksRequest{
+				BookId: uuid.New().String(),
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					GetRelatedBooks(gomock.Any(), gomock.Any()).
+					Return(nil, entity.ErrBookNotFound)
+			},
+			wantError: true,
+			errorCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorUseCase := library.NewMockAuthorUseCase(ctrl)
+			bookUseCase := library.NewMockBooksUseCase(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(bookUseCase)
+			}
+
+			ctx := context.Background()
+			resp, err := impl.GetRelatedBooks(ctx, tt.request)
+
+			st, ok := status.FromError(err)
+
+			if tt.wantError {
+				require.True(t, ok)
+				require.Equal(t, tt.errorCode, st.Code())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, resp.GetBooks(), tt.wantBooks)
+				require.Equal(t, desc.BookRelationType_BOOK_RELATION_TYPE_SEQUEL_OF, resp.GetBooks()[0].GetRelation())
+				require.True(t, resp.GetBooks()[0].GetReverse())
+			}
+		})
+	}
+}
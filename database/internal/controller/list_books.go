@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"context"
+)
+
+func (i *implementation) ListBooks(
+	ctx context.Context,
+	req *desc.ListBooksRequest,
+) (*desc.ListBooksResponse, error) {
+	if err := req.ValidateAll(); err != nil {
+		i.logger.Warn("Error validating list books request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	listed, nextPageToken, err := i.booksUseCase.ListBooks(ctx, req.GetFilter(), req.GetPageSize(), req.GetPageToken())
+
+	if err != nil {
+		i.logger.Debug("Error performing list books use case", zap.Error(err))
+		return nil, i.convertErr(err)
+	}
+
+	books := make([]*desc.Book, 0, len(listed))
+
+	for _, book := range listed {
+		books = append(books, &desc.Book{
+			Id:           book.ID,
+			Name:         book.Name,
+			AuthorId:     book.Authors,
+			CreatedAt:    timestamppb.New(book.CreatedAt),
+			UpdatedAt:    timestamppb.New(book.UpdatedAt),
+			Contributors: toProtoContributors(book.Contributors),
+		})
+	}
+
+	return &desc.ListBooksResponse{
+		Books:         books,
+		NextPageToken: nextPageToken,
+	}, nil
+}
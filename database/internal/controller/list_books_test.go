@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"fmt"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"context"
+	"testing"
+)
+
+func Test_implementation_ListBooks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		request    *desc.ListBooksRequest
+		setupMocks func(booksUseCase *library.MockBooksUseCase)
+		wantBooks  int
+		wantNext   string
+		wantError  bool
+		errorCode  codes.Code
+	}{
+		{
+			name: "Successful books listing",
+			request: &desc.ListBooksRequest{
+				Filter:   `name contains "war"`,
+				PageSize: 2,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					ListBooks(gomock.Any(), `name contains "war"`, int32(2), "").
+					Return([]entity.Book{
+						{ID: uuid.New().String(), Name: "War and Peace"},
+						{ID: uuid.New().String(), Name: "The Art of War"},
+					}, "next", nil)
+			},
+			wantBooks: 2,
+			wantNext:  "next",
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name: "Too large page size",
+			request: &desc.ListBooksRequest{
+				PageSize: 101,
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Invalid filter",
+			request: &desc.ListBooksRequest{
+				Filter: `title = "war"`,
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, "", fmt.Errorf("%w: unknown field", entity.ErrInvalidFilter))
+			},
+			wantError: true,
+			errorCode: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid page token",
+			request: &desc.ListBooksRequest{
+				PageToken: "token",
+			},
+			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
+				booksUseCase.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any(), gomock.Any(), "token").
+					Return(nil, "", fmt.Errorf("%w: malformed token", entity.ErrInvalidPageToken))
+			},
+			wantError: true,
+			errorCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorUseCase := library.NewMockAuthorUseCase(ctrl)
+			bookUseCase := library.NewMockBooksUseCase(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(bookUseCase)
+			}
+
+			ctx := context.Background()
+			resp, err := impl.ListBooks(ctx, tt.request)
+
+			st, ok := status.FromError(err)
+
+			if tt.wantError {
+				require.True(t, ok)
+				require.Equal(t, tt.errorCode, st.Code())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, resp.GetBooks(), tt.wantBooks)
+				require.Equal(t, tt.wantNext, resp.GetNextPageToken())
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+
+	"context"
+)
+
+func (i *implementation) RedeliverOutboxEvents(
+	ctx context.Context,
+	req *desc.RedeliverOutboxEventsRequest,
+) (*desc.RedeliverOutboxEventsResponse, error) {
+	if err := req.ValidateAll(); err != nil {
+		i.logger.Warn("Error validating redeliver outbox events request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	redelivered, err := i.outboxUseCase.RedeliverOutboxEvents(ctx, req.GetIds())
+
+	if err != nil {
+		i.logger.Debug("Error performing redeliver outbox events use case", zap.Error(err))
+		return nil, i.convertErr(err)
+	}
+
+	return &desc.RedeliverOutboxEventsResponse{
+		Redelivered: redelivered,
+	}, nil
+}
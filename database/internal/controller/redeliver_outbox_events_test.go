@@ -0,0 +1,105 @@
+package controller
+
+import (
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"context"
+	"testing"
+)
+
+func Test_implementation_RedeliverOutboxEvents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		request    *desc.RedeliverOutboxEventsRequest
+		setupMocks func(outboxUseCase *library.MockOutboxUseCase)
+		want       int64
+		wantError  bool
+		errorCode  codes.Code
+	}{
+		{
+			name: "Successful redelivery",
+			request: &desc.RedeliverOutboxEventsRequest{
+				Ids: []int64{1, 2},
+			},
+			setupMocks: func(outboxUseCase *library.MockOutboxUseCase) {
+				outboxUseCase.EXPECT().
+					RedeliverOutboxEvents(gomock.Any(), []int64{1, 2}).
+					Return(int64(2), nil)
+			},
+			want:      2,
+			wantError: false,
+			errorCode: codes.OK,
+		},
+		{
+			name:       "No ids",
+			request:    &desc.RedeliverOutboxEventsRequest{},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Invalid id",
+			request: &desc.RedeliverOutboxEventsRequest{
+				Ids: []int64{0},
+			},
+			setupMocks: nil,
+			wantError:  true,
+			errorCode:  codes.InvalidArgument,
+		},
+		{
+			name: "Events not found",
+			request: &desc.RedeliverOutboxEventsRequest{
+				Ids: []int64{42},
+			},
+			setupMocks: func(outboxUseCase *library.MockOutboxUseCase) {
+				outboxUseCase.EXPECT().
+					RedeliverOutboxEvents(gomock.Any(), gomock.Any()).
+					Return(int64(0), entity.ErrOutboxEventNotFound)
+			},
+			wantError: true,
+			errorCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorUseCase := library.NewMockAuthorUseCase(ctrl)
+			bookUseCase := library.NewMockBooksUseCase(ctrl)
+			outboxUseCase := library.NewMockOutboxUseCase(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, bookUseCase, authorUseCase, outboxUseCase, featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(outboxUseCase)
+			}
+
+			ctx := context.Background()
+			resp, err := impl.RedeliverOutboxEvents(ctx, tt.request)
+
+			st, ok := status.FromError(err)
+
+			if tt.wantError {
+				require.True(t, ok)
+				require.Equal(t, tt.errorCode, st.Code())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, resp.GetRedelivered())
+			}
+		})
+	}
+}
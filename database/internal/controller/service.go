@@ -2,6 +2,7 @@ package controller
 
 import (
 	generated "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
 	"go.uber.org/zap"
 )
@@ -12,16 +13,22 @@ type implementation struct {
 	logger         *zap.Logger
 	booksUseCase   library.BooksUseCase
 	authorsUseCase library.AuthorUseCase
+	outboxUseCase  library.OutboxUseCase
+	flags          featureflag.Flags
 }
 
 func New(
 	logger *zap.Logger,
 	booksUseCase library.BooksUseCase,
 	authorsUseCase library.AuthorUseCase,
+	outboxUseCase library.OutboxUseCase,
+	flags featureflag.Flags,
 ) *implementation {
 	return &implementation{
 		logger:         logger,
 		booksUseCase:   booksUseCase,
 		authorsUseCase: authorsUseCase,
+		outboxUseCase:  outboxUseCase,
+		flags:          flags,
 	}
 }
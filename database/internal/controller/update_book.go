@@ -17,7 +17,13 @@ func (i *implementation) UpdateBook(ctx context.Context, req *desc.UpdateBookReq
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := i.booksUseCase.UpdateBook(ctx, req.GetId(), req.GetName(), req.GetAuthorIds())
+	err := i.booksUseCase.UpdateBook(
+		ctx,
+		req.GetId(),
+		req.GetName(),
+		req.GetAuthorIds(),
+		toEntityContributors(req.GetContributors()),
+	)
 
 	if err != nil {
 		i.logger.Debug("Error performing update book use case", zap.Error(err))
@@ -3,6 +3,7 @@ package controller
 import (
 	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/library"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -33,7 +34,7 @@ func Test_implementation_UpdateBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
 			wantError: false,
@@ -58,7 +59,7 @@ func Test_implementation_UpdateBook(t *testing.T) {
 			},
 			setupMocks: func(booksUseCase *library.MockBooksUseCase) {
 				booksUseCase.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.ErrBookNotFound)
 			},
 			wantError: true,
@@ -77,7 +78,8 @@ func Test_implementation_UpdateBook(t *testing.T) {
 			bookUseCase := library.NewMockBooksUseCase(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, bookUseCase, authorUseCase)
+			impl := New(logger, bookUseCase, authorUseCase, library.NewMockOutboxUseCase(ctrl),
+				featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(bookUseCase)
@@ -3,6 +3,7 @@ package controller
 import (
 	"errors"
 
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,7 +19,93 @@ func (i *implementation) convertErr(err error) error {
 		return status.Error(codes.AlreadyExists, err.Error())
 	case errors.Is(err, entity.ErrBookAlreadyExists):
 		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, entity.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrInvalidFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrInvalidBookRelation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrBookRelationAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, entity.ErrOutboxEventNotFound):
+		return status.Error(codes.NotFound, err.Error())
 	default:
 		return status.Error(codes.Internal, err.Error())
 	}
 }
+
+// roles maps roles of the API to the roles of the domain.
+var roles = map[desc.ContributorRole]entity.Role{
+	desc.ContributorRole_CONTRIBUTOR_ROLE_AUTHOR:      entity.RoleAuthor,
+	desc.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR:  entity.RoleTranslator,
+	desc.ContributorRole_CONTRIBUTOR_ROLE_EDITOR:      entity.RoleEditor,
+	desc.ContributorRole_CONTRIBUTOR_ROLE_ILLUSTRATOR: entity.RoleIllustrator,
+}
+
+func toEntityContributors(contributors []*desc.Contributor) []entity.Contributor {
+	result := make([]entity.Contributor, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		result = append(result, entity.Contributor{
+			AuthorID: contributor.GetAuthorId(),
+			// unknown roles become empty and are rejected by the use case
+			Role: roles[contributor.GetRole()],
+		})
+	}
+
+	return result
+}
+
+func toProtoContributors(contributors []entity.Contributor) []*desc.Contributor {
+	result := make([]*desc.Contributor, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		role := desc.ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED
+
+		for protoRole, entityRole := range roles {
+			if entityRole == contributor.Role {
+				role = protoRole
+				break
+			}
+		}
+
+		result = append(result, &desc.Contributor{
+			AuthorId: contributor.AuthorID,
+			Role:     role,
+		})
+	}
+
+	return result
+}
+
+func toProtoAuthors(authors []entity.Author) []*desc.Author {
+	result := make([]*desc.Author, 0, len(authors))
+
+	for _, author := range authors {
+		result = append(result, &desc.Author{
+			Id:   author.ID,
+			Name: author.Name,
+		})
+	}
+
+	return result
+}
+
+// relationTypes maps relation types of the API to the types of the domain.
+var relationTypes = map[desc.BookRelationType]entity.RelationType{
+	desc.BookRelationType_BOOK_RELATION_TYPE_SEQUEL_OF:      entity.RelationSequelOf,
+	desc.BookRelationType_BOOK_RELATION_TYPE_TRANSLATION_OF: entity.RelationTranslationOf,
+	desc.BookRelationType_BOOK_RELATION_TYPE_SAME_SERIES:    entity.RelationSameSeries,
+}
+
+func toProtoRelationType(relationType entity.RelationType) desc.BookRelationType {
+	for protoType, entityType := range relationTypes {
+		if entityType == relationType {
+			return protoType
+		}
+	}
+
+	return desc.BookRelationType_BOOK_RELATION_TYPE_UNSPECIFIED
+}
@@ -6,14 +6,39 @@ import (
 )
 
 type Book struct {
+	ID   string
+	Name string
+	// Authors contains ids of all contributors of the book regardless of
+	// their role, each listed once, Contributors contains the same ids along
+	// with the roles.
+	Authors      []string
+	Contributors []Contributor
+	// AuthorDetails is filled only for BookViewFull.
+	AuthorDetails []Author
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// BookPosition is a position in the listing of books ordered by creation
+// time, the listing is continued after it.
+type BookPosition struct {
 	ID        string
-	Name      string
-	Authors   []string
 	CreatedAt time.Time
-	UpdatedAt time.Time
 }
 
+// BookView defines how much information about a book is retrieved.
+type BookView int
+
+const (
+	// BookViewBasic retrieves the book with ids of its authors.
+	BookViewBasic BookView = iota
+	// BookViewFull additionally retrieves the authors themselves.
+	BookViewFull
+)
+
 var (
 	ErrBookNotFound      = errors.New("book not found")
 	ErrBookAlreadyExists = errors.New("book already exists")
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrInvalidFilter     = errors.New("invalid filter")
 )
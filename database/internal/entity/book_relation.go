@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+)
+
+// RelationType is a kind of relationship between two books. A relation is
+// directed: "A sequel_of B" means that A continues B.
+type RelationType string
+
+const (
+	RelationSequelOf      RelationType = "sequel_of"
+	RelationTranslationOf RelationType = "translation_of"
+	RelationSameSeries    RelationType = "same_series"
+)
+
+// RelationTypes lists all allowed relation types.
+var RelationTypes = []RelationType{RelationSequelOf, RelationTranslationOf, RelationSameSeries}
+
+// Valid reports whether the relation type is one of the allowed types.
+func (r RelationType) Valid() bool {
+	return slices.Contains(RelationTypes, r)
+}
+
+// BookRelation declares that BookID relates to RelatedBookID.
+type BookRelation struct {
+	BookID        string
+	RelatedBookID string
+	Type          RelationType
+}
+
+// RelatedBook is a book related to the requested one. Reverse is set when the
+// relation was declared by the related book, e.g. for a book B and its sequel
+// A the result contains A with sequel_of type and Reverse set.
+type RelatedBook struct {
+	Book    Book
+	Type    RelationType
+	Reverse bool
+}
+
+var (
+	ErrInvalidBookRelation       = errors.New("invalid book relation")
+	ErrBookRelationAlreadyExists = errors.New("book relation already exists")
+)
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+)
+
+// Role is a role in which an author took part in a book.
+type Role string
+
+const (
+	RoleAuthor      Role = "author"
+	RoleTranslator  Role = "translator"
+	RoleEditor      Role = "editor"
+	RoleIllustrator Role = "illustrator"
+)
+
+// Roles lists all allowed contributor roles.
+var Roles = []Role{RoleAuthor, RoleTranslator, RoleEditor, RoleIllustrator}
+
+// Valid reports whether the role is one of the allowed roles.
+func (r Role) Valid() bool {
+	return slices.Contains(Roles, r)
+}
+
+// Contributor links an author to a book with the given role.
+type Contributor struct {
+	AuthorID string
+	Role     Role
+}
+
+// ContributorAuthorIDs returns ids of the given contributors in order of
+// their first appearance, an author holding several roles is listed once.
+func ContributorAuthorIDs(contributors []Contributor) []string {
+	ids := make([]string, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		if !slices.Contains(ids, contributor.AuthorID) {
+			ids = append(ids, contributor.AuthorID)
+		}
+	}
+
+	return ids
+}
+
+var ErrInvalidRole = errors.New("invalid contributor role")
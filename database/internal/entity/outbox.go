@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// OutboxEvent is an event stored in the outbox table in the same transaction
+// as the change it describes, and delivered later by the outbox relay.
+type OutboxEvent struct {
+	ID            int64
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+}
+
+// OutboxLag describes events waiting for delivery.
+type OutboxLag struct {
+	Pending int
+	// OldestAge is the age of the oldest pending event, zero if there are no
+	// pending events.
+	OldestAge time.Duration
+}
+
+var (
+	ErrOutboxEventNotFound = errors.New("outbox event not found")
+	// ErrOutboxEventDeferred is returned by a delivery function to leave an
+	// event pending without counting a delivery attempt.
+	ErrOutboxEventDeferred = errors.New("outbox event deferred")
+)
@@ -0,0 +1,32 @@
+//go:build !failpoint
+
+package failpoint
+
+import (
+	"context"
+	"errors"
+)
+
+// Enabled reports whether failpoints are compiled into the binary.
+const Enabled = false
+
+// Enable does nothing without the failpoint build tag.
+func Enable(string, Action) {}
+
+// Disable does nothing without the failpoint build tag.
+func Disable(string) {}
+
+// Configure fails for a non-empty spec without the failpoint build tag, so
+// that faults are never silently ignored.
+func Configure(spec string) error {
+	if spec == "" {
+		return nil
+	}
+
+	return errors.New("failpoints are not compiled in, build with -tags failpoint")
+}
+
+// Inject does nothing without the failpoint build tag.
+func Inject(context.Context, string) error {
+	return nil
+}
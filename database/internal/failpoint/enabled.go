@@ -0,0 +1,30 @@
+//go:build failpoint
+
+package failpoint
+
+import "context"
+
+// Enabled reports whether failpoints are compiled into the binary.
+const Enabled = true
+
+var global = NewRegistry()
+
+// Enable makes the named failpoint perform the action.
+func Enable(name string, action Action) {
+	global.Enable(name, action)
+}
+
+// Disable turns off the named failpoint.
+func Disable(name string) {
+	global.Disable(name)
+}
+
+// Configure enables failpoints described by spec, see Registry.Configure.
+func Configure(spec string) error {
+	return global.Configure(spec)
+}
+
+// Inject performs the action of the named failpoint if it is enabled.
+func Inject(ctx context.Context, name string) error {
+	return global.Inject(ctx, name)
+}
@@ -0,0 +1,186 @@
+// Package failpoint injects faults into named points of the code for
+// resilience testing. Failpoints are evaluated only in binaries built with
+// the failpoint build tag, otherwise Inject is a no-op.
+package failpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Action is what happens when an enabled failpoint is reached.
+type Action struct {
+	// Delay is waited before the failpoint returns.
+	Delay time.Duration
+	// Err is returned by the failpoint, nil means the failpoint only delays.
+	Err error
+	// Count limits how many times the action is triggered, after that the
+	// failpoint is disabled. Zero means no limit.
+	Count int
+}
+
+// Registry holds enabled failpoints.
+type Registry struct {
+	mu     sync.Mutex
+	points map[string]*Action
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		points: make(map[string]*Action),
+	}
+}
+
+// Enable makes the named failpoint perform the action.
+func (r *Registry) Enable(name string, action Action) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.points[name] = &action
+}
+
+// Disable turns off the named failpoint.
+func (r *Registry) Disable(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.points, name)
+}
+
+// Inject performs the action of the named failpoint if it is enabled. The
+// delay is interrupted when ctx is done, ctx error is returned in that case.
+func (r *Registry) Inject(ctx context.Context, name string) error {
+	action, ok := r.trigger(name)
+
+	if !ok {
+		return nil
+	}
+
+	if action.Delay > 0 {
+		timer := time.NewTimer(action.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return action.Err
+}
+
+// trigger returns action of the failpoint and accounts the trigger.
+func (r *Registry) trigger(name string) (Action, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	action, ok := r.points[name]
+
+	if !ok {
+		return Action{}, false
+	}
+
+	if action.Count > 0 {
+		action.Count--
+		if action.Count == 0 {
+			delete(r.points, name)
+		}
+	}
+
+	return *action, true
+}
+
+// Configure enables failpoints described by spec like
+//
+//	repository/AddBook/begin=delay(100ms);repository/AddBook/commit=pgerror(40001)*2
+//
+// Failpoints are separated by semicolons. An action consists of terms joined
+// with '+' and an optional '*n' suffix limiting the number of triggers. Terms
+// are delay(duration), pgerror(sqlstate) and error(message).
+func (r *Registry) Configure(spec string) error {
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		name, actionSpec, found := strings.Cut(entry, "=")
+
+		if !found || name == "" {
+			return fmt.Errorf("invalid failpoint %q, expected name=action", entry)
+		}
+
+		action, err := parseAction(actionSpec)
+
+		if err != nil {
+			return fmt.Errorf("failpoint %q: %w", name, err)
+		}
+
+		r.Enable(strings.TrimSpace(name), action)
+	}
+
+	return nil
+}
+
+func parseAction(spec string) (Action, error) {
+	var action Action
+
+	if terms, count, found := strings.Cut(spec, "*"); found {
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+
+		if err != nil || n <= 0 {
+			return Action{}, fmt.Errorf("invalid count %q", count)
+		}
+
+		action.Count = n
+		spec = terms
+	}
+
+	for _, term := range strings.Split(spec, "+") {
+		term = strings.TrimSpace(term)
+
+		kind, arg, found := strings.Cut(term, "(")
+
+		if !found || !strings.HasSuffix(arg, ")") {
+			return Action{}, fmt.Errorf("invalid action %q", term)
+		}
+
+		arg = strings.TrimSuffix(arg, ")")
+
+		switch kind {
+		case "delay":
+			delay, err := time.ParseDuration(arg)
+
+			if err != nil {
+				return Action{}, fmt.Errorf("invalid delay %q", arg)
+			}
+
+			action.Delay = delay
+		case "pgerror":
+			if len(arg) != 5 {
+				return Action{}, fmt.Errorf("invalid sqlstate %q", arg)
+			}
+
+			action.Err = &pgconn.PgError{
+				Severity: "ERROR",
+				Code:     arg,
+				Message:  "injected by failpoint",
+			}
+		case "error":
+			action.Err = errors.New(arg)
+		default:
+			return Action{}, fmt.Errorf("unknown action %q", kind)
+		}
+	}
+
+	return action, nil
+}
@@ -0,0 +1,155 @@
+package failpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Inject(t *testing.T) {
+	t.Parallel()
+
+	errSome := errors.New("some error")
+
+	tests := []struct {
+		name      string
+		action    *Action
+		calls     int
+		wantErrs  int
+		wantDelay time.Duration
+	}{
+		{
+			name:     "Disabled failpoint",
+			action:   nil,
+			calls:    3,
+			wantErrs: 0,
+		},
+		{
+			name:     "Error without limit",
+			action:   &Action{Err: errSome},
+			calls:    3,
+			wantErrs: 3,
+		},
+		{
+			name:     "Error limited by count",
+			action:   &Action{Err: errSome, Count: 2},
+			calls:    3,
+			wantErrs: 2,
+		},
+		{
+			name:      "Delay only",
+			action:    &Action{Delay: 20 * time.Millisecond},
+			calls:     1,
+			wantErrs:  0,
+			wantDelay: 20 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := NewRegistry()
+
+			if tt.action != nil {
+				registry.Enable("point", *tt.action)
+			}
+
+			start := time.Now()
+			errs := 0
+
+			for range tt.calls {
+				if err := registry.Inject(context.Background(), "point"); err != nil {
+					require.ErrorIs(t, err, errSome)
+					errs++
+				}
+			}
+
+			require.Equal(t, tt.wantErrs, errs)
+			require.GreaterOrEqual(t, time.Since(start), tt.wantDelay)
+		})
+	}
+}
+
+func TestRegistry_InjectCanceled(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	registry.Enable("point", Action{Delay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, registry.Inject(ctx, "point"), context.DeadlineExceeded)
+
+	registry.Disable("point")
+	require.NoError(t, registry.Inject(context.Background(), "point"))
+}
+
+func TestRegistry_Configure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		point   string
+		want    Action
+		wantErr bool
+	}{
+		{
+			name:  "Delay",
+			spec:  "repository/AddBook/begin=delay(100ms)",
+			point: "repository/AddBook/begin",
+			want:  Action{Delay: 100 * time.Millisecond},
+		},
+		{
+			name:  "Delay and pgconn error with count",
+			spec:  " other=error(boom); repository/AddBook/commit=delay(1s)+pgerror(40001)*2",
+			point: "repository/AddBook/commit",
+			want: Action{
+				Delay: time.Second,
+				Err:   &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "injected by failpoint"},
+				Count: 2,
+			},
+		},
+		{
+			name:    "Unknown action",
+			spec:    "point=panic(now)",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid sqlstate",
+			spec:    "point=pgerror(4)",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid count",
+			spec:    "point=error(boom)*0",
+			wantErr: true,
+		},
+		{
+			name:    "Missing action",
+			spec:    "point",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := NewRegistry()
+			err := registry.Configure(tt.spec)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, *registry.points[tt.point])
+		})
+	}
+}
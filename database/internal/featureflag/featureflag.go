@@ -0,0 +1,143 @@
+// Package featureflag provides feature flags read from a JSON file, which is
+// reloaded when it changes, so that features can be toggled without
+// redeploying the service.
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Names of flags consulted by the service.
+const (
+	// FullBookView allows BOOK_VIEW_FULL in GetBookInfo, the basic view is
+	// returned when it is off.
+	FullBookView = "full_book_view"
+	// ETag enables entity tags in responses.
+	ETag = "etag"
+	// AuthorBooksBatchSize is the number of books fetched from the database
+	// at once while streaming books of an author, zero fetches all of them.
+	AuthorBooksBatchSize = "author_books_batch_size"
+)
+
+// Flags gives access to current values of feature flags. Defaults are used
+// for flags which are not set or have a value of another type.
+type Flags interface {
+	Bool(name string, def bool) bool
+	Int(name string, def int) int
+}
+
+var _ Flags = (*Store)(nil)
+
+// Store holds the flags loaded from the file. Lookups never block reloads.
+type Store struct {
+	logger *zap.Logger
+	path   string
+	flags  atomic.Pointer[map[string]any]
+	// modTime and size of the file loaded last, used to detect changes
+	modTime time.Time
+	size    int64
+}
+
+// NewStore creates store which reads flags from the file at path. An empty
+// path means all flags have their default values.
+func NewStore(logger *zap.Logger, path string) *Store {
+	s := &Store{
+		logger: logger,
+		path:   path,
+	}
+
+	empty := map[string]any{}
+	s.flags.Store(&empty)
+
+	return s
+}
+
+func (s *Store) Bool(name string, def bool) bool {
+	if value, ok := (*s.flags.Load())[name].(bool); ok {
+		return value
+	}
+	return def
+}
+
+func (s *Store) Int(name string, def int) int {
+	// JSON numbers are decoded as float64
+	if value, ok := (*s.flags.Load())[name].(float64); ok && value == float64(int(value)) {
+		return int(value)
+	}
+	return def
+}
+
+// Load reads the flags from the file. Flags stay unchanged on error.
+func (s *Store) Load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.path)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(s.path)
+
+	if err != nil {
+		return err
+	}
+
+	var flags map[string]any
+
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return fmt.Errorf("invalid feature flags file %s: %w", s.path, err)
+	}
+
+	s.flags.Store(&flags)
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+
+	return nil
+}
+
+// Watch reloads the flags every interval if the file has changed, until ctx
+// is done. Watch must not be called concurrently with Load.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	if s.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(s.path)
+
+		if err != nil {
+			s.logger.Warn("Error while checking feature flags file", zap.Error(err))
+			continue
+		}
+
+		if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+			continue
+		}
+
+		if err := s.Load(); err != nil {
+			s.logger.Warn("Error while reloading feature flags, previous values are kept", zap.Error(err))
+			continue
+		}
+
+		s.logger.Info("Feature flags reloaded", zap.String("path", s.path))
+	}
+}
@@ -0,0 +1,102 @@
+package featureflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStore_Load(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		wantBool  bool
+		wantInt   int
+		wantError bool
+	}{
+		{
+			name:     "Flags are set",
+			content:  `{"full_book_view": false, "author_books_batch_size": 50}`,
+			wantBool: false,
+			wantInt:  50,
+		},
+		{
+			name:     "Flags of other types fall back to defaults",
+			content:  `{"full_book_view": "no", "author_books_batch_size": 1.5}`,
+			wantBool: true,
+			wantInt:  10,
+		},
+		{
+			name:     "Flags are not set",
+			content:  `{}`,
+			wantBool: true,
+			wantInt:  10,
+		},
+		{
+			name:      "Malformed file",
+			content:   `{"full_book_view": `,
+			wantBool:  true,
+			wantInt:   10,
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "flags.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			store := NewStore(zap.NewNop(), path)
+			err := store.Load()
+
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.wantBool, store.Bool(FullBookView, true))
+			require.Equal(t, tt.wantInt, store.Int(AuthorBooksBatchSize, 10))
+		})
+	}
+}
+
+func TestStore_Watch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"etag": true}`), 0o600))
+
+	store := NewStore(zap.NewNop(), path)
+	require.NoError(t, store.Load())
+	require.True(t, store.Bool(ETag, false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go store.Watch(ctx, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"etag": false, "full_book_view": false}`), 0o600))
+
+	require.Eventually(t, func() bool {
+		return !store.Bool(ETag, true) && !store.Bool(FullBookView, true)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestStore_NoFile(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(zap.NewNop(), "")
+
+	require.NoError(t, store.Load())
+	require.True(t, store.Bool(ETag, true))
+	require.Equal(t, 7, store.Int(AuthorBooksBatchSize, 7))
+}
@@ -0,0 +1,320 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/google/uuid"
+)
+
+// opContains is a case-insensitive substring match operator.
+const opContains = "contains"
+
+// maxDepth limits nesting of NOT and parentheses, so that filters coming
+// from clients cannot exhaust the stack of the recursive descent parser.
+const maxDepth = 32
+
+// FieldType defines which values and operators are allowed for a field.
+type FieldType int
+
+const (
+	// FieldText accepts string values and every operator including contains.
+	FieldText FieldType = iota
+	// FieldTime accepts RFC 3339 timestamps or dates (2006-01-02) and
+	// comparison operators.
+	FieldTime
+	// FieldUUID accepts uuid values and only = and != operators.
+	FieldUUID
+)
+
+// Field maps a field name used in filter expressions to a table column.
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// Fields is a whitelist of fields which can be used in filter expressions.
+// Only columns listed here can ever get into the generated SQL.
+type Fields map[string]Field
+
+var (
+	// BookFields are fields available for filtering books.
+	BookFields = Fields{
+		"id":         {Column: "id", Type: FieldUUID},
+		"name":       {Column: "name", Type: FieldText},
+		"created_at": {Column: "created_at", Type: FieldTime},
+		"updated_at": {Column: "updated_at", Type: FieldTime},
+	}
+
+	// AuthorFields are fields available for filtering authors.
+	AuthorFields = Fields{
+		"id":         {Column: "id", Type: FieldUUID},
+		"name":       {Column: "name", Type: FieldText},
+		"created_at": {Column: "created_at", Type: FieldTime},
+		"updated_at": {Column: "updated_at", Type: FieldTime},
+	}
+)
+
+// Expression is a parsed and validated filter expression. The zero value
+// matches every row.
+type Expression struct {
+	root node
+}
+
+// node is a node of the expression tree which is able to render itself as SQL.
+type node interface {
+	// build writes SQL to sb, appending bound values to args.
+	build(sb *strings.Builder, args *[]any, firstArg int)
+}
+
+// logicalNode is a conjunction or disjunction of two expressions.
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+// notNode is a negation of an expression.
+type notNode struct {
+	operand node
+}
+
+// comparisonNode compares a column with a bound value.
+type comparisonNode struct {
+	column string
+	op     string
+	value  any
+}
+
+func (n *logicalNode) build(sb *strings.Builder, args *[]any, firstArg int) {
+	sb.WriteByte('(')
+	n.left.build(sb, args, firstArg)
+	sb.WriteString(" " + n.op + " ")
+	n.right.build(sb, args, firstArg)
+	sb.WriteByte(')')
+}
+
+func (n *notNode) build(sb *strings.Builder, args *[]any, firstArg int) {
+	sb.WriteString("NOT (")
+	n.operand.build(sb, args, firstArg)
+	sb.WriteByte(')')
+}
+
+func (n *comparisonNode) build(sb *strings.Builder, args *[]any, firstArg int) {
+	*args = append(*args, n.value)
+	placeholder := fmt.Sprintf("$%d", firstArg+len(*args)-1)
+
+	if n.op == opContains {
+		// strpos does not interpret wildcards, so the value needs no escaping
+		fmt.Fprintf(sb, "strpos(lower(%s), lower(%s)) > 0", n.column, placeholder)
+		return
+	}
+
+	fmt.Fprintf(sb, "%s %s %s", n.column, n.op, placeholder)
+}
+
+// Parse parses filter expression like
+//
+//	name contains "war" AND (created_at > "2024-01-01" OR NOT id = "...")
+//
+// against the given whitelist of fields. Keywords are case-insensitive, AND
+// binds tighter than OR. Errors are wrapped into entity.ErrInvalidFilter.
+func Parse(input string, fields Fields) (Expression, error) {
+	if strings.TrimSpace(input) == "" {
+		return Expression{}, nil
+	}
+
+	tokens, err := tokenize(input)
+
+	if err != nil {
+		return Expression{}, fmt.Errorf("%w: %w", entity.ErrInvalidFilter, err)
+	}
+
+	p := &parser{
+		tokens: tokens,
+		fields: fields,
+	}
+
+	root, err := p.parseOr()
+
+	if err == nil && p.peek().kind != tokenEOF {
+		err = fmt.Errorf("unexpected %q at position %d", p.peek().value, p.peek().pos)
+	}
+
+	if err != nil {
+		return Expression{}, fmt.Errorf("%w: %w", entity.ErrInvalidFilter, err)
+	}
+
+	return Expression{root: root}, nil
+}
+
+// Where renders the expression as SQL condition with positional parameters
+// starting from $firstArg, so it can be appended to a query which already has
+// parameters of its own. It returns the condition and values to bind.
+func (e Expression) Where(firstArg int) (string, []any) {
+	if e.root == nil {
+		return "TRUE", nil
+	}
+
+	var (
+		sb   strings.Builder
+		args []any
+	)
+
+	e.root.build(&sb, &args, firstArg)
+
+	return sb.String(), args
+}
+
+// parser is a recursive descent parser of filter expressions.
+type parser struct {
+	tokens []token
+	pos    int
+	fields Fields
+	// depth is the number of NOT and parentheses enclosing the current token.
+	depth int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseOr parses: and_expr (OR and_expr)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: unary (AND unary)*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses: NOT unary | '(' or_expr ')' | comparison
+func (p *parser) parseUnary() (node, error) {
+	p.depth++
+	defer func() {
+		p.depth--
+	}()
+
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("expression is nested deeper than %d levels at position %d", maxDepth, p.peek().pos)
+	}
+
+	switch t := p.peek(); t.kind {
+	case tokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	case tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+// parseComparison parses: field operator "value"
+func (p *parser) parseComparison() (node, error) {
+	ident := p.next()
+	if ident.kind != tokenIdent {
+		return nil, fmt.Errorf("expected field name at position %d", ident.pos)
+	}
+
+	field, ok := p.fields[ident.value]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", ident.value)
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, fmt.Errorf("expected operator after %q at position %d", ident.value, op.pos)
+	}
+
+	literal := p.next()
+	if literal.kind != tokenString {
+		return nil, fmt.Errorf("expected quoted value at position %d", literal.pos)
+	}
+
+	value, err := convertValue(field.Type, op.value, literal.value)
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", ident.value, err)
+	}
+
+	return &comparisonNode{column: field.Column, op: op.value, value: value}, nil
+}
+
+// convertValue checks that operator is applicable to the field type and
+// converts literal to the value bound to the query.
+func convertValue(fieldType FieldType, op, literal string) (any, error) {
+	switch fieldType {
+	case FieldText:
+		return literal, nil
+	case FieldTime:
+		if op == opContains {
+			return nil, fmt.Errorf("operator %q is not supported for timestamps", op)
+		}
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if t, err := time.Parse(layout, literal); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid timestamp %q", literal)
+	case FieldUUID:
+		if op != "=" && op != "!=" {
+			return nil, fmt.Errorf("operator %q is not supported for identifiers", op)
+		}
+		if _, err := uuid.Parse(literal); err != nil {
+			return nil, fmt.Errorf("invalid uuid %q", literal)
+		}
+		return literal, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %d", fieldType)
+	}
+}
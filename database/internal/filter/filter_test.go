@@ -0,0 +1,111 @@
+package filter
+
+import (
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		input    string
+		firstArg int
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "Empty filter",
+			input:    "  ",
+			firstArg: 1,
+			wantSQL:  "TRUE",
+			wantArgs: nil,
+		},
+		{
+			name:     "Contains and date comparison",
+			input:    `name contains "war" AND created_at > "2024-01-01"`,
+			firstArg: 1,
+			wantSQL:  "(strpos(lower(name), lower($1)) > 0 AND created_at > $2)",
+			wantArgs: []any{"war", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "AND binds tighter than OR",
+			input:    `name = "a" or name = "b" and not name != "c"`,
+			firstArg: 3,
+			wantSQL:  "(name = $3 OR (name = $4 AND NOT (name != $5)))",
+			wantArgs: []any{"a", "b", "c"},
+		},
+		{
+			name:     "Parentheses",
+			input:    `(name = "a" OR name = "b") AND id = "6a2f41a3-c54c-fce8-32d2-0324e1c32e22"`,
+			firstArg: 1,
+			wantSQL:  "((name = $1 OR name = $2) AND id = $3)",
+			wantArgs: []any{"a", "b", "6a2f41a3-c54c-fce8-32d2-0324e1c32e22"},
+		},
+		{
+			name:     "Escaped quote",
+			input:    `name = "say \"hi\"; DROP TABLE book"`,
+			firstArg: 1,
+			wantSQL:  "name = $1",
+			wantArgs: []any{`say "hi"; DROP TABLE book`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(tt.input, BookFields)
+			require.NoError(t, err)
+
+			sql, args := expr.Where(tt.firstArg)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestParseNesting(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Repeat("(", maxDepth-1) + `name = "war"` + strings.Repeat(")", maxDepth-1)
+
+	expr, err := Parse(input, BookFields)
+	require.NoError(t, err)
+
+	sql, _ := expr.Where(1)
+	require.Equal(t, "name = $1", sql)
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Unknown field", input: `title = "war"`},
+		{name: "Column injection", input: `name = "a" OR 1 = 1`},
+		{name: "Unquoted value", input: `name = war`},
+		{name: "Missing operator", input: `name "war"`},
+		{name: "Unterminated string", input: `name = "war`},
+		{name: "Unbalanced parentheses", input: `(name = "war"`},
+		{name: "Trailing tokens", input: `name = "war" name = "peace"`},
+		{name: "Unexpected character", input: `name ~ "war"`},
+		{name: "Contains on timestamp", input: `created_at contains "2024"`},
+		{name: "Invalid timestamp", input: `created_at > "yesterday"`},
+		{name: "Ordering on uuid", input: `id > "6a2f41a3-c54c-fce8-32d2-0324e1c32e22"`},
+		{name: "Invalid uuid", input: `id = "1"`},
+		{name: "Too deep nesting", input: strings.Repeat("NOT (", maxDepth) + `name = "war"` + strings.Repeat(")", maxDepth)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.input, AuthorFields)
+			require.ErrorIs(t, err, entity.ErrInvalidFilter)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is a kind of lexeme of filter expression.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexeme of filter expression with its position in the source.
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// operators lists comparison operators, longer ones go first so that "<=" is
+// not read as "<" followed by "=".
+var operators = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// tokenize splits filter expression into tokens.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(input); {
+		c := rune(input[pos])
+
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: pos})
+			pos++
+		case c == '"':
+			value, next, err := readString(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			pos = next
+		case c == '_' || unicode.IsLetter(c):
+			start := pos
+			for pos < len(input) && (input[pos] == '_' || unicode.IsLetter(rune(input[pos])) ||
+				unicode.IsDigit(rune(input[pos]))) {
+				pos++
+			}
+			tokens = append(tokens, keywordOrIdent(input[start:pos], start))
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(input[pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, pos)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op, pos: pos})
+			pos += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// keywordOrIdent classifies a word: keywords are case-insensitive, everything
+// else is a field name.
+func keywordOrIdent(word string, pos int) token {
+	switch strings.ToLower(word) {
+	case "and":
+		return token{kind: tokenAnd, value: word, pos: pos}
+	case "or":
+		return token{kind: tokenOr, value: word, pos: pos}
+	case "not":
+		return token{kind: tokenNot, value: word, pos: pos}
+	case opContains:
+		return token{kind: tokenOperator, value: opContains, pos: pos}
+	default:
+		return token{kind: tokenIdent, value: word, pos: pos}
+	}
+}
+
+// readString reads double-quoted string starting at pos, backslash escapes the
+// next character. It returns the unquoted value and the position after the
+// closing quote.
+func readString(input string, pos int) (string, int, error) {
+	var sb strings.Builder
+
+	for i := pos + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 == len(input) {
+				return "", 0, fmt.Errorf("unterminated string at position %d", pos)
+			}
+			i++
+			sb.WriteByte(input[i])
+		case '"':
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(input[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string at position %d", pos)
+}
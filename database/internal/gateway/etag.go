@@ -0,0 +1,106 @@
+// Package gateway contains HTTP plumbing of the grpc-gateway server.
+package gateway
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// ETagHeaderMatcher forwards the etag response metadata as the ETag header,
+// other metadata is forwarded the same way the gateway does by default.
+func ETagHeaderMatcher(etagKey string) runtime.HeaderMatcherFunc {
+	return func(key string) (string, bool) {
+		if strings.EqualFold(key, etagKey) {
+			return "ETag", true
+		}
+		return runtime.MetadataHeaderPrefix + key, true
+	}
+}
+
+// WithConditionalGet makes GET requests honor If-None-Match: when the ETag of
+// a successful response matches one of the tags sent by the client, the body
+// is dropped and 304 Not Modified is returned instead.
+func WithConditionalGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch := r.Header.Get("If-None-Match")
+
+		if ifNoneMatch == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(&conditionalWriter{ResponseWriter: w, ifNoneMatch: ifNoneMatch}, r)
+	})
+}
+
+// conditionalWriter decides whether the response is sent when its status is
+// written, as that is the moment the ETag header is known.
+type conditionalWriter struct {
+	http.ResponseWriter
+	ifNoneMatch string
+	written     bool
+	notModified bool
+}
+
+func (w *conditionalWriter) WriteHeader(statusCode int) {
+	if w.written {
+		return
+	}
+
+	w.written = true
+
+	if statusCode == http.StatusOK && etagMatches(w.ifNoneMatch, w.Header().Get("ETag")) {
+		w.notModified = true
+		// headers describing the body must not be sent with 304
+		w.Header().Del("Content-Length")
+		w.Header().Del("Content-Type")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.notModified {
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *conditionalWriter) Flush() {
+	if w.notModified {
+		return
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// etagMatches performs weak comparison of etag against the list of tags in
+// If-None-Match header, as RFC 9110 requires for this header.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
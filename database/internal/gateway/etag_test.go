@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithConditionalGet(t *testing.T) {
+	t.Parallel()
+
+	const etag = `W/"abc"`
+
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:       "No If-None-Match",
+			method:     http.MethodGet,
+			status:     http.StatusOK,
+			wantStatus: http.StatusOK,
+			wantBody:   "body",
+		},
+		{
+			name:        "Matching tag",
+			method:      http.MethodGet,
+			ifNoneMatch: etag,
+			status:      http.StatusOK,
+			wantStatus:  http.StatusNotModified,
+			wantBody:    "",
+		},
+		{
+			name:        "Matching strong tag in list",
+			method:      http.MethodGet,
+			ifNoneMatch: `"xyz", "abc"`,
+			status:      http.StatusOK,
+			wantStatus:  http.StatusNotModified,
+			wantBody:    "",
+		},
+		{
+			name:        "Wildcard",
+			method:      http.MethodGet,
+			ifNoneMatch: "*",
+			status:      http.StatusOK,
+			wantStatus:  http.StatusNotModified,
+			wantBody:    "",
+		},
+		{
+			name:        "Different tag",
+			method:      http.MethodGet,
+			ifNoneMatch: `W/"xyz"`,
+			status:      http.StatusOK,
+			wantStatus:  http.StatusOK,
+			wantBody:    "body",
+		},
+		{
+			name:        "Error response",
+			method:      http.MethodGet,
+			ifNoneMatch: etag,
+			status:      http.StatusNotFound,
+			wantStatus:  http.StatusNotFound,
+			wantBody:    "body",
+		},
+		{
+			name:        "Not a GET request",
+			method:      http.MethodPut,
+			ifNoneMatch: etag,
+			status:      http.StatusOK,
+			wantStatus:  http.StatusOK,
+			wantBody:    "body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := WithConditionalGet(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("ETag", etag)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("body"))
+			}))
+
+			request := httptest.NewRequest(tt.method, "/v1/library/book/1", nil)
+
+			if tt.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			require.Equal(t, tt.wantBody, recorder.Body.String())
+			require.Equal(t, etag, recorder.Header().Get("ETag"))
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Package loadtest drives a configurable mix of requests against a running
+// library service and reports latency percentiles per kind of request.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+)
+
+// Operation is a kind of request performed by the load test.
+type Operation string
+
+const (
+	// OpRead requests info about a random book or author.
+	OpRead Operation = "read"
+	// OpWrite adds a book or renames an author.
+	OpWrite Operation = "write"
+	// OpStream streams all books of a random author.
+	OpStream Operation = "stream"
+)
+
+// Operations lists all supported operations in the order they are reported.
+var Operations = []Operation{OpRead, OpWrite, OpStream}
+
+// Mix defines relative weights of operations.
+type Mix map[Operation]int
+
+// ParseMix parses mix like "read=8,write=1,stream=1".
+func ParseMix(s string) (Mix, error) {
+	mix := make(Mix)
+
+	for _, part := range strings.Split(s, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+
+		if !found {
+			return nil, fmt.Errorf("invalid mix entry %q, expected operation=weight", part)
+		}
+
+		op := Operation(strings.TrimSpace(name))
+
+		if !op.valid() {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight of operation %q: %q", name, value)
+		}
+
+		mix[op] = weight
+	}
+
+	if mix.total() == 0 {
+		return nil, errors.New("mix has no operations with positive weight")
+	}
+
+	return mix, nil
+}
+
+func (o Operation) valid() bool {
+	for _, op := range Operations {
+		if op == o {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Mix) total() int {
+	total := 0
+	for _, weight := range m {
+		total += weight
+	}
+	return total
+}
+
+// pick chooses an operation with probability proportional to its weight.
+func (m Mix) pick(r *rand.Rand) Operation {
+	n := r.IntN(m.total())
+
+	for _, op := range Operations {
+		if n < m[op] {
+			return op
+		}
+		n -= m[op]
+	}
+
+	return OpRead
+}
+
+// Config is configuration of a load test run.
+type Config struct {
+	Concurrency int
+	Duration    time.Duration
+	Mix         Mix
+	// Authors and BooksPerAuthor define the data set created before the run.
+	Authors        int
+	BooksPerAuthor int
+}
+
+// Stats are results of an operation.
+type Stats struct {
+	Operation Operation
+	Requests  int
+	Errors    int
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+}
+
+// Report is a result of a load test run.
+type Report struct {
+	Elapsed time.Duration
+	Stats   []Stats
+}
+
+// Print writes the report as a table.
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "elapsed: %s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "%-8s %10s %8s %10s %12s %12s %12s %12s\n",
+		"op", "requests", "errors", "rps", "p50", "p90", "p99", "max")
+
+	for _, s := range r.Stats {
+		fmt.Fprintf(w, "%-8s %10d %8d %10.1f %12s %12s %12s %12s\n",
+			s.Operation, s.Requests, s.Errors, float64(s.Requests)/r.Elapsed.Seconds(),
+			s.P50, s.P90, s.P99, s.Max)
+	}
+}
+
+// dataset holds ids of entities requests are performed on.
+type dataset struct {
+	authors []string
+	books   []string
+}
+
+// sample is a result of a single request.
+type sample struct {
+	op      Operation
+	latency time.Duration
+	err     error
+}
+
+// Run seeds the service with data and performs requests according to cfg
+// until cfg.Duration passes or ctx is done.
+func Run(ctx context.Context, client desc.LibraryClient, cfg Config) (Report, error) {
+	if cfg.Concurrency <= 0 {
+		return Report{}, errors.New("concurrency must be positive")
+	}
+
+	data, err := seed(ctx, client, cfg.Authors, cfg.BooksPerAuthor)
+
+	if err != nil {
+		return Report{}, fmt.Errorf("seeding data: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		samples []sample
+	)
+
+	start := time.Now()
+
+	for worker := range cfg.Concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r := rand.New(rand.NewPCG(uint64(start.UnixNano()), uint64(worker)))
+			var local []sample
+
+			for ctx.Err() == nil {
+				op := cfg.Mix.pick(r)
+				begin := time.Now()
+				err := perform(ctx, client, op, data, r)
+
+				// requests interrupted by the end of the run are not counted
+				if ctx.Err() != nil {
+					break
+				}
+
+				local = append(local, sample{op: op, latency: time.Since(begin), err: err})
+			}
+
+			mu.Lock()
+			samples = append(samples, local...)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return Report{
+		Elapsed: time.Since(start),
+		Stats:   aggregate(samples),
+	}, nil
+}
+
+func seed(ctx context.Context, client desc.LibraryClient, authors, booksPerAuthor int) (dataset, error) {
+	var data dataset
+
+	for i := range max(authors, 1) {
+		author, err := client.RegisterAuthor(ctx, &desc.RegisterAuthorRequest{
+			Name: fmt.Sprintf("Load Author %d", i),
+		})
+
+		if err != nil {
+			return dataset{}, err
+		}
+
+		data.authors = append(data.authors, author.GetId())
+
+		for j := range max(booksPerAuthor, 1) {
+			book, err := client.AddBook(ctx, &desc.AddBookRequest{
+				Name:      fmt.Sprintf("Load Book %d %d", i, j),
+				AuthorIds: []string{author.GetId()},
+			})
+
+			if err != nil {
+				return dataset{}, err
+			}
+
+			data.books = append(data.books, book.GetBook().GetId())
+		}
+	}
+
+	return data, nil
+}
+
+func perform(ctx context.Context, client desc.LibraryClient, op Operation, data dataset, r *rand.Rand) error {
+	author := data.authors[r.IntN(len(data.authors))]
+
+	switch op {
+	case OpRead:
+		if r.IntN(2) == 0 {
+			_, err := client.GetAuthorInfo(ctx, &desc.GetAuthorInfoRequest{Id: author})
+			return err
+		}
+		_, err := client.GetBookInfo(ctx, &desc.GetBookInfoRequest{Id: data.books[r.IntN(len(data.books))]})
+		return err
+	case OpWrite:
+		if r.IntN(2) == 0 {
+			_, err := client.ChangeAuthorInfo(ctx, &desc.ChangeAuthorInfoRequest{
+				Id:   author,
+				Name: fmt.Sprintf("Load Author %d", r.IntN(1_000_000)),
+			})
+			return err
+		}
+		_, err := client.AddBook(ctx, &desc.AddBookRequest{
+			Name:      fmt.Sprintf("Load Book %d", r.IntN(1_000_000)),
+			AuthorIds: []string{author},
+		})
+		return err
+	case OpStream:
+		stream, err := client.GetAuthorBooks(ctx, &desc.GetAuthorBooksRequest{AuthorId: author})
+		if err != nil {
+			return err
+		}
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown operation %q", op)
+	}
+}
+
+func aggregate(samples []sample) []Stats {
+	latencies := make(map[Operation][]time.Duration)
+	errs := make(map[Operation]int)
+
+	for _, s := range samples {
+		latencies[s.op] = append(latencies[s.op], s.latency)
+		if s.err != nil {
+			errs[s.op]++
+		}
+	}
+
+	var result []Stats
+
+	for _, op := range Operations {
+		values := latencies[op]
+
+		if len(values) == 0 {
+			continue
+		}
+
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		result = append(result, Stats{
+			Operation: op,
+			Requests:  len(values),
+			Errors:    errs[op],
+			P50:       percentile(values, 50),
+			P90:       percentile(values, 90),
+			P99:       percentile(values, 99),
+			Max:       values[len(values)-1],
+		})
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
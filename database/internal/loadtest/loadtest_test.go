@@ -0,0 +1,165 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	desc "github.com/TimurUrazov/go-projects/database/generated/api/library"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestParseMix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mix     string
+		want    Mix
+		wantErr bool
+	}{
+		{
+			name: "All operations",
+			mix:  "read=8, write=1,stream=1",
+			want: Mix{OpRead: 8, OpWrite: 1, OpStream: 1},
+		},
+		{
+			name: "Single operation",
+			mix:  "stream=3",
+			want: Mix{OpStream: 3},
+		},
+		{
+			name:    "Unknown operation",
+			mix:     "delete=1",
+			wantErr: true,
+		},
+		{
+			name:    "Negative weight",
+			mix:     "read=-1",
+			wantErr: true,
+		},
+		{
+			name:    "Only zero weights",
+			mix:     "read=0,write=0",
+			wantErr: true,
+		},
+		{
+			name:    "Missing weight",
+			mix:     "read",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mix, err := ParseMix(tt.mix)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, mix)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	require.Equal(t, 50*time.Millisecond, percentile(values, 50))
+	require.Equal(t, 99*time.Millisecond, percentile(values, 99))
+	require.Equal(t, time.Millisecond, percentile(values[:1], 50))
+}
+
+// fakeClient answers every request immediately and counts requests.
+type fakeClient struct {
+	desc.LibraryClient
+	reads, writes, streams atomic.Int64
+}
+
+func (c *fakeClient) RegisterAuthor(context.Context, *desc.RegisterAuthorRequest, ...grpc.CallOption) (*desc.RegisterAuthorResponse, error) {
+	return &desc.RegisterAuthorResponse{Id: uuid.New().String()}, nil
+}
+
+func (c *fakeClient) AddBook(context.Context, *desc.AddBookRequest, ...grpc.CallOption) (*desc.AddBookResponse, error) {
+	c.writes.Add(1)
+	return &desc.AddBookResponse{Book: &desc.Book{Id: uuid.New().String()}}, nil
+}
+
+func (c *fakeClient) ChangeAuthorInfo(context.Context, *desc.ChangeAuthorInfoRequest, ...grpc.CallOption) (*desc.ChangeAuthorInfoResponse, error) {
+	c.writes.Add(1)
+	return &desc.ChangeAuthorInfoResponse{}, nil
+}
+
+func (c *fakeClient) GetBookInfo(context.Context, *desc.GetBookInfoRequest, ...grpc.CallOption) (*desc.GetBookInfoResponse, error) {
+	c.reads.Add(1)
+	return &desc.GetBookInfoResponse{}, nil
+}
+
+func (c *fakeClient) GetAuthorInfo(context.Context, *desc.GetAuthorInfoRequest, ...grpc.CallOption) (*desc.GetAuthorInfoResponse, error) {
+	c.reads.Add(1)
+	return &desc.GetAuthorInfoResponse{}, nil
+}
+
+func (c *fakeClient) GetAuthorBooks(context.Context, *desc.GetAuthorBooksRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[desc.Book], error) {
+	c.streams.Add(1)
+	return &fakeStream{books: 3}, nil
+}
+
+type fakeStream struct {
+	grpc.ServerStreamingClient[desc.Book]
+	books int
+}
+
+func (s *fakeStream) Recv() (*desc.Book, error) {
+	if s.books == 0 {
+		return nil, io.EOF
+	}
+	s.books--
+	return &desc.Book{}, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{}
+
+	report, err := Run(context.Background(), client, Config{
+		Concurrency:    4,
+		Duration:       100 * time.Millisecond,
+		Mix:            Mix{OpRead: 1, OpStream: 1},
+		Authors:        2,
+		BooksPerAuthor: 2,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, report.Stats, 2)
+	require.Equal(t, OpRead, report.Stats[0].Operation)
+	require.Equal(t, OpStream, report.Stats[1].Operation)
+
+	// only books of the data set are written as the mix has no writes
+	require.Equal(t, int64(4), client.writes.Load())
+	require.Positive(t, client.reads.Load())
+	require.Positive(t, client.streams.Load())
+
+	for _, s := range report.Stats {
+		require.Zero(t, s.Errors)
+		require.LessOrEqual(t, s.P50, s.P99)
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	require.Contains(t, out.String(), "stream")
+}
@@ -0,0 +1,159 @@
+// Package metrics collects performance metrics of the service and exposes
+// them in Prometheus format.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+const namespace = "library"
+
+// Metrics holds collectors of the service. Every instance has its own
+// registry, so instances created in tests do not interfere.
+type Metrics struct {
+	registry          *prometheus.Registry
+	repositoryLatency *prometheus.HistogramVec
+	outboxPending     prometheus.Gauge
+	outboxLag         prometheus.Gauge
+	outboxDeliveries  *prometheus.CounterVec
+}
+
+// New creates metrics and registers them along with the standard process and
+// Go runtime collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		repositoryLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "duration_seconds",
+			Help:      "Latency of repository methods.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method", "status"}),
+		outboxPending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "pending_events",
+			Help:      "Number of outbox events waiting for delivery.",
+		}),
+		outboxLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "lag_seconds",
+			Help:      "Age of the oldest outbox event waiting for delivery.",
+		}),
+		outboxDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "deliveries_total",
+			Help:      "Attempts to deliver outbox events partitioned by result.",
+		}, []string{"status"}),
+	}
+
+	m.registry.MustRegister(
+		m.repositoryLatency,
+		m.outboxPending,
+		m.outboxLag,
+		m.outboxDeliveries,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// ObserveRepository records latency of a repository method call.
+func (m *Metrics) ObserveRepository(method string, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	m.repositoryLatency.WithLabelValues(method, status).Observe(time.Since(start).Seconds())
+}
+
+// SetOutboxLag records number of pending outbox events and age of the oldest
+// of them.
+func (m *Metrics) SetOutboxLag(pending int, lag time.Duration) {
+	m.outboxPending.Set(float64(pending))
+	m.outboxLag.Set(lag.Seconds())
+}
+
+// ObserveOutboxDelivery records an attempt to deliver an outbox event.
+func (m *Metrics) ObserveOutboxDelivery(err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	m.outboxDeliveries.WithLabelValues(status).Inc()
+}
+
+// Handler serves metrics in Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RunSummary logs a summary of collected metrics every interval until ctx is
+// done. It is meant for deployments which do not scrape the metrics endpoint.
+func (m *Metrics) RunSummary(ctx context.Context, logger *zap.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.logSummary(logger)
+		}
+	}
+}
+
+// logSummary logs number of calls and mean latency per repository method.
+func (m *Metrics) logSummary(logger *zap.Logger) {
+	families, err := m.registry.Gather()
+
+	if err != nil {
+		logger.Warn("Error while gathering metrics for summary", zap.Error(err))
+		return
+	}
+
+	for _, family := range families {
+		if family.GetName() != namespace+"_repository_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			histogram := metric.GetHistogram()
+			count := histogram.GetSampleCount()
+
+			if count == 0 {
+				continue
+			}
+
+			logger.Info("Repository latency summary",
+				zap.String("method", label(metric, "method")),
+				zap.String("status", label(metric, "status")),
+				zap.Uint64("calls", count),
+				zap.Duration("mean", time.Duration(histogram.GetSampleSum()/float64(count)*float64(time.Second))))
+		}
+	}
+}
+
+func label(metric *dto.Metric, name string) string {
+	for _, pair := range metric.GetLabel() {
+		if pair.GetName() == name {
+			return pair.GetValue()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMetrics_ObserveRepository(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+
+	m.ObserveRepository("GetBookInfo", time.Now(), nil)
+	m.ObserveRepository("GetBookInfo", time.Now(), errors.New("some error"))
+	m.ObserveRepository("AddBook", time.Now(), nil)
+
+	require.Equal(t, 3, testutil.CollectAndCount(m.repositoryLatency))
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body),
+		`library_repository_duration_seconds_count{method="GetBookInfo",status="error"} 1`)
+}
+
+func TestMetrics_logSummary(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+
+	m.ObserveRepository("GetBookInfo", time.Now(), nil)
+
+	core, logs := observer.New(zap.InfoLevel)
+	m.logSummary(zap.New(core))
+
+	latency := logs.FilterMessage("Repository latency summary").All()
+	require.Len(t, latency, 1)
+	require.Equal(t, "GetBookInfo", latency[0].ContextMap()["method"])
+	require.Equal(t, uint64(1), latency[0].ContextMap()["calls"])
+}
@@ -0,0 +1,174 @@
+// Package outbox delivers events stored in the outbox table.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/metrics"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+// Publisher sends an event to its consumers.
+type Publisher interface {
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+}
+
+var _ Publisher = (*logPublisher)(nil)
+
+// logPublisher writes events to the log. It is used until the service is
+// connected to a message broker.
+type logPublisher struct {
+	logger *zap.Logger
+}
+
+func NewLogPublisher(logger *zap.Logger) *logPublisher {
+	return &logPublisher{
+		logger: logger,
+	}
+}
+
+func (p *logPublisher) Publish(_ context.Context, event entity.OutboxEvent) error {
+	p.logger.Info("Outbox event published",
+		zap.Int64("event_id", event.ID),
+		zap.String("aggregate_type", event.AggregateType),
+		zap.String("aggregate_id", event.AggregateID),
+		zap.String("event_type", event.EventType),
+		zap.ByteString("payload", event.Payload))
+	return nil
+}
+
+// Relay periodically delivers pending outbox events. Events of an aggregate
+// are delivered in the order they were stored: once delivery of an event
+// fails, later events of the same aggregate wait for the next round.
+type Relay struct {
+	logger     *zap.Logger
+	repository repository.OutboxRepository
+	publisher  Publisher
+	metrics    *metrics.Metrics
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewRelay(
+	logger *zap.Logger,
+	repository repository.OutboxRepository,
+	publisher Publisher,
+	metrics *metrics.Metrics,
+	interval time.Duration,
+	batchSize int,
+) *Relay {
+	return &Relay{
+		logger:     logger,
+		repository: repository,
+		publisher:  publisher,
+		metrics:    metrics,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// maxBackoffShift limits the backoff after failed rounds to the interval
+// multiplied by 2^maxBackoffShift.
+const maxBackoffShift = 3
+
+// BatchResult summarizes a round of delivery.
+type BatchResult struct {
+	// Attempted is the number of events publishing was attempted for, events
+	// deferred behind a failed event of their aggregate are not counted.
+	Attempted int
+	// Failed is the number of events which have failed to be published.
+	Failed int
+}
+
+// Run delivers events every interval until ctx is done. A batch of events
+// which all have been delivered is followed by the next one right away, so
+// that a backlog is drained quickly. After failed rounds the interval is
+// doubled up to 2^maxBackoffShift times, so that a failing publisher is not
+// hammered.
+func (r *Relay) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	failedRounds := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		result, err := r.DeliverBatch(ctx)
+
+		if err != nil && ctx.Err() == nil {
+			r.logger.Warn("Error while delivering outbox events", zap.Error(err))
+		}
+
+		r.updateLag(ctx)
+
+		switch {
+		case err != nil || result.Failed > 0:
+			failedRounds++
+			timer.Reset(r.backoff(failedRounds))
+		case result.Attempted == r.batchSize:
+			failedRounds = 0
+			timer.Reset(0)
+		default:
+			failedRounds = 0
+			timer.Reset(r.interval)
+		}
+	}
+}
+
+// backoff returns the delay before the next round after the given number of
+// failed rounds in a row.
+func (r *Relay) backoff(failedRounds int) time.Duration {
+	return r.interval << min(failedRounds-1, maxBackoffShift)
+}
+
+// DeliverBatch delivers a batch of pending events.
+func (r *Relay) DeliverBatch(ctx context.Context) (BatchResult, error) {
+	blocked := make(map[string]bool)
+	var result BatchResult
+
+	_, err := r.repository.DeliverOutboxEvents(ctx, r.batchSize,
+		func(ctx context.Context, event entity.OutboxEvent) error {
+			key := event.AggregateType + "/" + event.AggregateID
+
+			if blocked[key] {
+				return entity.ErrOutboxEventDeferred
+			}
+
+			result.Attempted++
+
+			err := r.publisher.Publish(ctx, event)
+			r.metrics.ObserveOutboxDelivery(err)
+
+			if err != nil {
+				result.Failed++
+				blocked[key] = true
+				r.logger.Debug("Error while publishing outbox event",
+					zap.Int64("event_id", event.ID), zap.Int("attempts", event.Attempts+1), zap.Error(err))
+			}
+
+			return err
+		})
+
+	return result, err
+}
+
+func (r *Relay) updateLag(ctx context.Context) {
+	lag, err := r.repository.GetOutboxLag(ctx)
+
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Warn("Error while getting outbox lag", zap.Error(err))
+		}
+		return
+	}
+
+	r.metrics.SetOutboxLag(lag.Pending, lag.OldestAge)
+}
@@ -0,0 +1,210 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/metrics"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// failingPublisher fails events with the given ids and records the rest.
+type failingPublisher struct {
+	failing   map[int64]bool
+	published []int64
+}
+
+func (p *failingPublisher) Publish(_ context.Context, event entity.OutboxEvent) error {
+	if p.failing[event.ID] {
+		return errors.New("broker is unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestRelay_DeliverBatch(t *testing.T) {
+	t.Parallel()
+
+	events := []entity.OutboxEvent{
+		{ID: 1, AggregateType: "book", AggregateID: "a"},
+		{ID: 2, AggregateType: "book", AggregateID: "b"},
+		{ID: 3, AggregateType: "book", AggregateID: "a"},
+		{ID: 4, AggregateType: "author", AggregateID: "a"},
+		{ID: 5, AggregateType: "book", AggregateID: "b"},
+	}
+
+	tests := []struct {
+		name          string
+		failing       map[int64]bool
+		wantPublished []int64
+		wantFailed    []int64
+		wantDeferred  []int64
+	}{
+		{
+			name:          "All events are delivered in order",
+			failing:       nil,
+			wantPublished: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			name:          "Failed event holds back later events of its aggregate",
+			failing:       map[int64]bool{1: true},
+			wantPublished: []int64{2, 4, 5},
+			wantFailed:    []int64{1},
+			wantDeferred:  []int64{3},
+		},
+		{
+			name:          "Later event of an aggregate fails",
+			failing:       map[int64]bool{2: true, 3: true},
+			wantPublished: []int64{1, 4},
+			wantFailed:    []int64{2, 3},
+			wantDeferred:  []int64{5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			outboxRepository := repository.NewMockOutboxRepository(ctrl)
+
+			var failed, deferred []int64
+
+			outboxRepository.EXPECT().
+				DeliverOutboxEvents(gomock.Any(), 10, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ int,
+					deliver func(context.Context, entity.OutboxEvent) error) (int, error) {
+					delivered := 0
+					for _, event := range events {
+						switch err := deliver(ctx, event); {
+						case errors.Is(err, entity.ErrOutboxEventDeferred):
+							deferred = append(deferred, event.ID)
+						case err != nil:
+							failed = append(failed, event.ID)
+						default:
+							delivered++
+						}
+					}
+					return delivered, nil
+				})
+
+			publisher := &failingPublisher{failing: tt.failing}
+			relay := NewRelay(zap.NewNop(), outboxRepository, publisher, metrics.New(), time.Second, 10)
+
+			result, err := relay.DeliverBatch(context.Background())
+
+			require.NoError(t, err)
+			require.Equal(t, BatchResult{
+				Attempted: len(events) - len(tt.wantDeferred),
+				Failed:    len(tt.wantFailed),
+			}, result)
+			require.Equal(t, tt.wantPublished, publisher.published)
+			require.Equal(t, tt.wantFailed, failed)
+			require.Equal(t, tt.wantDeferred, deferred)
+		})
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	outboxRepository := repository.NewMockOutboxRepository(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outboxRepository.EXPECT().
+		DeliverOutboxEvents(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(0, nil)
+
+	outboxRepository.EXPECT().
+		GetOutboxLag(gomock.Any()).
+		DoAndReturn(func(context.Context) (entity.OutboxLag, error) {
+			// the relay stops once the first round is over
+			cancel()
+			return entity.OutboxLag{Pending: 3, OldestAge: time.Minute}, nil
+		})
+
+	relay := NewRelay(zap.NewNop(), outboxRepository, NewLogPublisher(zap.NewNop()), metrics.New(), time.Hour, 10)
+
+	done := make(chan struct{})
+
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
+
+func TestRelay_RunBacksOffAfterFailure(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	outboxRepository := repository.NewMockOutboxRepository(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the failed event and its deferred successors fill the whole batch
+	outboxRepository.EXPECT().
+		DeliverOutboxEvents(gomock.Any(), 3, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ int,
+			deliver func(context.Context, entity.OutboxEvent) error) (int, error) {
+			for id := int64(1); id <= 3; id++ {
+				_ = deliver(ctx, entity.OutboxEvent{ID: id, AggregateType: "book", AggregateID: "a"})
+			}
+			return 0, nil
+		}).
+		Times(1)
+
+	outboxRepository.EXPECT().
+		GetOutboxLag(gomock.Any()).
+		Return(entity.OutboxLag{}, nil).
+		AnyTimes()
+
+	publisher := &failingPublisher{failing: map[int64]bool{1: true}}
+	relay := NewRelay(zap.NewNop(), outboxRepository, publisher, metrics.New(), time.Hour, 3)
+
+	done := make(chan struct{})
+
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	// the next round would come right away if the batch was taken as full
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestRelay_backoff(t *testing.T) {
+	t.Parallel()
+
+	relay := NewRelay(zap.NewNop(), nil, nil, metrics.New(), time.Second, 10)
+
+	require.Equal(t, time.Second, relay.backoff(1))
+	require.Equal(t, 2*time.Second, relay.backoff(2))
+	require.Equal(t, 8*time.Second, relay.backoff(4))
+	require.Equal(t, 8*time.Second, relay.backoff(10))
+}
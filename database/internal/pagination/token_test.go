@@ -0,0 +1,99 @@
+package pagination
+
+import (
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_hmacCodec_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewCodec([]byte(strings.Repeat("s", MinSecretLength)))
+	require.NoError(t, err)
+
+	cursor := Cursor{
+		LastID:        uuid.New().String(),
+		LastCreatedAt: time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		Filter:        `name contains "war"`,
+		Sort:          "created_at",
+		PageSize:      20,
+	}
+
+	token, err := codec.Encode(cursor)
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode(token)
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+}
+
+func Test_hmacCodec_Decode(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewCodec([]byte(strings.Repeat("s", MinSecretLength)))
+	require.NoError(t, err)
+
+	token, err := codec.Encode(Cursor{LastID: uuid.New().String(), Sort: "name"})
+	require.NoError(t, err)
+
+	data, signature, _ := strings.Cut(token, ".")
+
+	another, err := NewCodec([]byte(strings.Repeat("a", MinSecretLength)))
+	require.NoError(t, err)
+
+	forged, err := another.Encode(Cursor{LastID: uuid.New().String()})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "Empty token",
+			token: "",
+		},
+		{
+			name:  "Missing signature",
+			token: data,
+		},
+		{
+			name:  "Payload is not base64",
+			token: "!!!." + signature,
+		},
+		{
+			name:  "Signature is not base64",
+			token: data + ".!!!",
+		},
+		{
+			name:  "Tampered payload",
+			token: "e30." + signature,
+		},
+		{
+			name:  "Signed with another secret",
+			token: forged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := codec.Decode(tt.token)
+			require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+		})
+	}
+}
+
+func TestNewCodec(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCodec(nil)
+	require.ErrorIs(t, err, ErrShortSecret)
+
+	_, err = NewCodec([]byte(strings.Repeat("s", MinSecretLength-1)))
+	require.ErrorIs(t, err, ErrShortSecret)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/google/uuid"
 )
 
+// defaultAuthorBooksBatchSize makes books of an author fetched all at once
+// unless the batch size is set by the feature flag.
+const defaultAuthorBooksBatchSize = 0
+
 func (l *libraryImpl) RegisterAuthor(ctx context.Context, authorName string) (entity.Author, error) {
 	author := entity.Author{
 		ID:   uuid.New().String(),
@@ -24,5 +29,6 @@ func (l *libraryImpl) GetAuthorInfo(ctx context.Context, id string) (entity.Auth
 }
 
 func (l *libraryImpl) GetAuthorBooks(ctx context.Context, id string) (<-chan entity.Book, <-chan error) {
-	return l.authorRepository.GetAuthorBooks(ctx, id)
+	batchSize := l.flags.Int(featureflag.AuthorBooksBatchSize, defaultAuthorBooksBatchSize)
+	return l.authorRepository.GetAuthorBooks(ctx, id, batchSize)
 }
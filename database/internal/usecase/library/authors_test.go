@@ -2,6 +2,7 @@ package library
 
 import (
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -54,7 +55,7 @@ func Test_libraryImpl_RegisterAuthor(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(authorRepository)
@@ -116,7 +117,7 @@ func Test_libraryImpl_ChangeAuthorInfo(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(authorRepository)
@@ -175,7 +176,7 @@ func Test_libraryImpl_GetAuthorInfo(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(authorRepository)
@@ -206,8 +207,8 @@ func Test_libraryImpl_GetAuthorBooks(t *testing.T) {
 			authorID: uuid.New().String(),
 			setupMocks: func(authorRepository *repository.MockAuthorRepository) {
 				authorRepository.EXPECT().
-					GetAuthorBooks(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, id string) (<-chan entity.Book, <-chan error) {
+					GetAuthorBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id string, batchSize int) (<-chan entity.Book, <-chan error) {
 						ch := make(chan entity.Book)
 						errChan := make(chan error, 1)
 						close(errChan)
@@ -234,7 +235,7 @@ func Test_libraryImpl_GetAuthorBooks(t *testing.T) {
 					errChan <- entity.ErrAuthorNotFound
 				}()
 				authorRepository.EXPECT().
-					GetAuthorBooks(gomock.Any(), gomock.Any()).
+					GetAuthorBooks(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(ch, errChan)
 			},
 			wantErr: true,
@@ -252,7 +253,7 @@ func Test_libraryImpl_GetAuthorBooks(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(authorRepository)
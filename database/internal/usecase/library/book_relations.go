@@ -0,0 +1,24 @@
+package library
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+)
+
+func (l *libraryImpl) AddBookRelation(ctx context.Context, relation entity.BookRelation) error {
+	if !relation.Type.Valid() {
+		return fmt.Errorf("%w: unknown relation type %q", entity.ErrInvalidBookRelation, relation.Type)
+	}
+
+	if relation.BookID == relation.RelatedBookID {
+		return fmt.Errorf("%w: book cannot relate to itself", entity.ErrInvalidBookRelation)
+	}
+
+	return l.booksRepository.AddBookRelation(ctx, relation)
+}
+
+func (l *libraryImpl) GetRelatedBooks(ctx context.Context, bookID string) ([]entity.RelatedBook, error) {
+	return l.booksRepository.GetRelatedBooks(ctx, bookID)
+}
@@ -0,0 +1,104 @@
+package library
+
+import (
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"context"
+	"testing"
+)
+
+func Test_libraryImpl_AddBookRelation(t *testing.T) {
+	t.Parallel()
+
+	bookID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		relation   entity.BookRelation
+		setupMocks func(booksRepository *repository.MockBooksRepository)
+		wantErr    error
+	}{
+		{
+			name: "Successful relation addition",
+			relation: entity.BookRelation{
+				BookID:        bookID,
+				RelatedBookID: uuid.New().String(),
+				Type:          entity.RelationTranslationOf,
+			},
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					AddBookRelation(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Unknown relation type",
+			relation: entity.BookRelation{
+				BookID:        bookID,
+				RelatedBookID: uuid.New().String(),
+				Type:          "prequel_of",
+			},
+			setupMocks: nil,
+			wantErr:    entity.ErrInvalidBookRelation,
+		},
+		{
+			name: "Book relates to itself",
+			relation: entity.BookRelation{
+				BookID:        bookID,
+				RelatedBookID: bookID,
+				Type:          entity.RelationSameSeries,
+			},
+			setupMocks: nil,
+			wantErr:    entity.ErrInvalidBookRelation,
+		},
+		{
+			name: "Related book not found",
+			relation: entity.BookRelation{
+				BookID:        bookID,
+				RelatedBookID: uuid.New().String(),
+				Type:          entity.RelationSequelOf,
+			},
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					AddBookRelation(gomock.Any(), gomock.Any()).
+					Return(entity.ErrBookNotFound)
+			},
+			wantErr: entity.ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorRepository := repository.NewMockAuthorRepository(ctrl)
+			booksRepository := repository.NewMockBooksRepository(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(booksRepository)
+			}
+
+			ctx := context.Background()
+			err := impl.AddBookRelation(ctx, tt.relation)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,24 +2,153 @@ package library
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/filter"
+	"github.com/TimurUrazov/go-projects/database/internal/pagination"
 	"github.com/google/uuid"
 )
 
-func (l *libraryImpl) AddBook(ctx context.Context, name string, authorIDs []string) (entity.Book, error) {
+// defaultPageSize is the number of books listed when the page size is not set.
+const defaultPageSize = 50
+
+func (l *libraryImpl) AddBook(
+	ctx context.Context,
+	name string,
+	authorIDs []string,
+	contributors []entity.Contributor,
+) (entity.Book, error) {
+	bookContributors, err := mergeContributors(authorIDs, contributors)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
 	book := entity.Book{
-		ID:      uuid.New().String(),
-		Name:    name,
-		Authors: authorIDs,
+		ID:           uuid.New().String(),
+		Name:         name,
+		Authors:      entity.ContributorAuthorIDs(bookContributors),
+		Contributors: bookContributors,
 	}
 	return l.booksRepository.AddBook(ctx, book)
 }
 
-func (l *libraryImpl) UpdateBook(ctx context.Context, id, name string, authorIDs []string) error {
-	return l.booksRepository.UpdateBook(ctx, id, name, authorIDs)
+func (l *libraryImpl) UpdateBook(
+	ctx context.Context,
+	id, name string,
+	authorIDs []string,
+	contributors []entity.Contributor,
+) error {
+	bookContributors, err := mergeContributors(authorIDs, contributors)
+
+	if err != nil {
+		return err
+	}
+
+	return l.booksRepository.UpdateBook(ctx, id, name, bookContributors)
+}
+
+func (l *libraryImpl) GetBookInfo(ctx context.Context, bookID string, view entity.BookView) (entity.Book, error) {
+	return l.booksRepository.GetBookInfo(ctx, bookID, view)
+}
+
+func (l *libraryImpl) ListBooks(
+	ctx context.Context,
+	query string,
+	pageSize int32,
+	pageToken string,
+) ([]entity.Book, string, error) {
+	var after *entity.BookPosition
+
+	if pageToken != "" {
+		cursor, err := l.pageTokens.Decode(pageToken)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if query != "" && query != cursor.Filter {
+			return nil, "", fmt.Errorf("%w: token was issued for another filter", entity.ErrInvalidPageToken)
+		}
+
+		query = cursor.Filter
+
+		if pageSize <= 0 {
+			pageSize = cursor.PageSize
+		}
+
+		after = &entity.BookPosition{
+			ID:        cursor.LastID,
+			CreatedAt: cursor.LastCreatedAt,
+		}
+	}
+
+	where, err := filter.Parse(query, filter.BookFields)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	// one book more is requested to find out whether the next page exists
+	books, err := l.booksRepository.ListBooks(ctx, where, after, int(pageSize)+1)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(books) <= int(pageSize) {
+		return books, "", nil
+	}
+
+	books = books[:pageSize]
+	last := books[len(books)-1]
+
+	nextPageToken, err := l.pageTokens.Encode(pagination.Cursor{
+		LastID:        last.ID,
+		LastCreatedAt: last.CreatedAt,
+		Filter:        query,
+		PageSize:      pageSize,
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return books, nextPageToken, nil
 }
 
-func (l *libraryImpl) GetBookInfo(ctx context.Context, bookID string) (entity.Book, error) {
-	return l.booksRepository.GetBookInfo(ctx, bookID)
+// mergeContributors treats plain author ids as contributors with author role
+// and appends the explicitly given contributors after validating their roles.
+// An author given several times in the same role is kept once.
+func mergeContributors(authorIDs []string, contributors []entity.Contributor) ([]entity.Contributor, error) {
+	result := make([]entity.Contributor, 0, len(authorIDs)+len(contributors))
+
+	for _, authorID := range authorIDs {
+		contributor := entity.Contributor{
+			AuthorID: authorID,
+			Role:     entity.RoleAuthor,
+		}
+
+		if !slices.Contains(result, contributor) {
+			result = append(result, contributor)
+		}
+	}
+
+	for _, contributor := range contributors {
+		if !contributor.Role.Valid() {
+			return nil, fmt.Errorf("%w: %q", entity.ErrInvalidRole, contributor.Role)
+		}
+
+		if !slices.Contains(result, contributor) {
+			result = append(result, contributor)
+		}
+	}
+
+	return result, nil
 }
@@ -2,6 +2,9 @@ package library
 
 import (
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/filter"
+	"github.com/TimurUrazov/go-projects/database/internal/pagination"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -9,17 +12,20 @@ import (
 	"go.uber.org/zap"
 
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_libraryImpl_AddBook(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name       string
-		bookName   string
-		authorIDs  []string
-		setupMocks func(booksRepository *repository.MockBooksRepository)
-		wantErr    bool
+		name         string
+		bookName     string
+		authorIDs    []string
+		contributors []entity.Contributor
+		setupMocks   func(booksRepository *repository.MockBooksRepository)
+		wantErr      bool
 	}{
 		{
 			name:      "Successful book addition",
@@ -43,6 +49,77 @@ func Test_libraryImpl_AddBook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:      "Book with editor",
+			bookName:  "Collected Works",
+			authorIDs: []string{"Lermontov"},
+			contributors: []entity.Contributor{
+				{AuthorID: "Belinsky", Role: entity.RoleEditor},
+			},
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					AddBook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, book entity.Book) (entity.Book, error) {
+						require.Equal(t, []string{"Lermontov", "Belinsky"}, book.Authors)
+						require.Equal(t, []entity.Contributor{
+							{AuthorID: "Lermontov", Role: entity.RoleAuthor},
+							{AuthorID: "Belinsky", Role: entity.RoleEditor},
+						}, book.Contributors)
+						return book, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Author holding two roles",
+			bookName:  "Eugene Onegin",
+			authorIDs: []string{"Nabokov"},
+			contributors: []entity.Contributor{
+				{AuthorID: "Nabokov", Role: entity.RoleTranslator},
+			},
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					AddBook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, book entity.Book) (entity.Book, error) {
+						require.Equal(t, []string{"Nabokov"}, book.Authors)
+						require.Equal(t, []entity.Contributor{
+							{AuthorID: "Nabokov", Role: entity.RoleAuthor},
+							{AuthorID: "Nabokov", Role: entity.RoleTranslator},
+						}, book.Contributors)
+						return book, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Author given twice in the same role",
+			bookName:  "Collected Works",
+			authorIDs: []string{"Lermontov", "Lermontov"},
+			contributors: []entity.Contributor{
+				{AuthorID: "Lermontov", Role: entity.RoleAuthor},
+			},
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					AddBook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, book entity.Book) (entity.Book, error) {
+						require.Equal(t, []string{"Lermontov"}, book.Authors)
+						require.Equal(t, []entity.Contributor{
+							{AuthorID: "Lermontov", Role: entity.RoleAuthor},
+						}, book.Contributors)
+						return book, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid role",
+			bookName: "Collected Works",
+			contributors: []entity.Contributor{
+				{AuthorID: "Belinsky", Role: "critic"},
+			},
+			setupMocks: nil,
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -56,14 +133,14 @@ func Test_libraryImpl_AddBook(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(booksRepository)
 			}
 
 			ctx := context.Background()
-			_, err := impl.AddBook(ctx, tt.bookName, tt.authorIDs)
+			_, err := impl.AddBook(ctx, tt.bookName, tt.authorIDs, tt.contributors)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -133,14 +210,14 @@ func Test_libraryImpl_UpdateBook(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(booksRepository)
 			}
 
 			ctx := context.Background()
-			err := impl.UpdateBook(ctx, tt.bookID, tt.bookName, tt.authorIDs)
+			err := impl.UpdateBook(ctx, tt.bookID, tt.bookName, tt.authorIDs, nil)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -164,7 +241,7 @@ func Test_libraryImpl_GetBookInfo(t *testing.T) {
 			bookID: uuid.New().String(),
 			setupMocks: func(booksRepository *repository.MockBooksRepository) {
 				booksRepository.EXPECT().
-					GetBookInfo(gomock.Any(), gomock.Any()).
+					GetBookInfo(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, nil)
 			},
 			wantErr: false,
@@ -174,7 +251,7 @@ func Test_libraryImpl_GetBookInfo(t *testing.T) {
 			bookID: uuid.New().String(),
 			setupMocks: func(booksRepository *repository.MockBooksRepository) {
 				booksRepository.EXPECT().
-					GetBookInfo(gomock.Any(), gomock.Any()).
+					GetBookInfo(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(entity.Book{}, entity.ErrBookNotFound)
 			},
 			wantErr: true,
@@ -192,14 +269,14 @@ func Test_libraryImpl_GetBookInfo(t *testing.T) {
 			booksRepository := repository.NewMockBooksRepository(ctrl)
 			logger := zap.NewNop()
 
-			impl := New(logger, authorRepository, booksRepository)
+			impl := New(logger, authorRepository, booksRepository, nil, featureflag.NewStore(logger, ""))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(booksRepository)
 			}
 
 			ctx := context.Background()
-			_, err := impl.GetBookInfo(ctx, tt.bookID)
+			_, err := impl.GetBookInfo(ctx, tt.bookID, entity.BookViewBasic)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -209,3 +286,145 @@ func Test_libraryImpl_GetBookInfo(t *testing.T) {
 		})
 	}
 }
+
+func Test_libraryImpl_ListBooks(t *testing.T) {
+	t.Parallel()
+
+	pageTokens, err := pagination.NewCodec([]byte(strings.Repeat("s", pagination.MinSecretLength)))
+	require.NoError(t, err)
+
+	books := []entity.Book{
+		{ID: uuid.New().String(), CreatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: uuid.New().String(), CreatedAt: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: uuid.New().String(), CreatedAt: time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	token := func(cursor pagination.Cursor) string {
+		encoded, err := pageTokens.Encode(cursor)
+		require.NoError(t, err)
+		return encoded
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		pageSize   int32
+		pageToken  string
+		setupMocks func(booksRepository *repository.MockBooksRepository)
+		wantBooks  int
+		wantNext   *pagination.Cursor
+		wantErr    error
+	}{
+		{
+			name:     "Default page size",
+			query:    "",
+			pageSize: 0,
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any(), nil, defaultPageSize+1).
+					Return(books, nil)
+			},
+			wantBooks: 3,
+		},
+		{
+			name:     "Filtered books with next page",
+			query:    `name contains "war"`,
+			pageSize: 2,
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any(), nil, 3).
+					DoAndReturn(func(_ context.Context, where filter.Expression, _ *entity.BookPosition, _ int) ([]entity.Book, error) {
+						condition, args := where.Where(2)
+						require.Equal(t, "strpos(lower(name), lower($2)) > 0", condition)
+						require.Equal(t, []any{"war"}, args)
+						return books, nil
+					})
+			},
+			wantBooks: 2,
+			wantNext: &pagination.Cursor{
+				LastID:        books[1].ID,
+				LastCreatedAt: books[1].CreatedAt,
+				Filter:        `name contains "war"`,
+				PageSize:      2,
+			},
+		},
+		{
+			name: "Next page takes filter and page size from token",
+			pageToken: token(pagination.Cursor{
+				LastID:        books[0].ID,
+				LastCreatedAt: books[0].CreatedAt,
+				Filter:        `name contains "war"`,
+				PageSize:      2,
+			}),
+			setupMocks: func(booksRepository *repository.MockBooksRepository) {
+				booksRepository.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any(), &entity.BookPosition{
+						ID:        books[0].ID,
+						CreatedAt: books[0].CreatedAt,
+					}, 3).
+					DoAndReturn(func(_ context.Context, where filter.Expression, _ *entity.BookPosition, _ int) ([]entity.Book, error) {
+						_, args := where.Where(4)
+						require.Equal(t, []any{"war"}, args)
+						return books[1:], nil
+					})
+			},
+			wantBooks: 2,
+		},
+		{
+			name:     "Invalid filter",
+			query:    `title = "war"`,
+			pageSize: 10,
+			wantErr:  entity.ErrInvalidFilter,
+		},
+		{
+			name:      "Forged token",
+			pageToken: "eyJ2IjoxfQ.c2lnbmF0dXJl",
+			wantErr:   entity.ErrInvalidPageToken,
+		},
+		{
+			name:      "Token of another filter",
+			query:     `name = "peace"`,
+			pageToken: token(pagination.Cursor{LastID: books[0].ID, Filter: `name contains "war"`}),
+			wantErr:   entity.ErrInvalidPageToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorRepository := repository.NewMockAuthorRepository(ctrl)
+			booksRepository := repository.NewMockBooksRepository(ctrl)
+			logger := zap.NewNop()
+
+			impl := New(logger, authorRepository, booksRepository, pageTokens, featureflag.NewStore(logger, ""))
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(booksRepository)
+			}
+
+			ctx := context.Background()
+			listed, nextPageToken, err := impl.ListBooks(ctx, tt.query, tt.pageSize, tt.pageToken)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, listed, tt.wantBooks)
+
+			if tt.wantNext == nil {
+				require.Empty(t, nextPageToken)
+				return
+			}
+
+			next, err := pageTokens.Decode(nextPageToken)
+			require.NoError(t, err)
+			require.Equal(t, *tt.wantNext, next)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/featureflag"
+	"github.com/TimurUrazov/go-projects/database/internal/pagination"
 	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
 	"go.uber.org/zap"
 )
@@ -16,9 +18,22 @@ type AuthorUseCase interface {
 }
 
 type BooksUseCase interface {
-	AddBook(ctx context.Context, name string, authorIDs []string) (entity.Book, error)
-	UpdateBook(ctx context.Context, id, name string, authorIDs []string) error
-	GetBookInfo(ctx context.Context, bookID string) (entity.Book, error)
+	AddBook(ctx context.Context, name string, authorIDs []string, contributors []entity.Contributor) (entity.Book, error)
+	UpdateBook(ctx context.Context, id, name string, authorIDs []string, contributors []entity.Contributor) error
+	GetBookInfo(ctx context.Context, bookID string, view entity.BookView) (entity.Book, error)
+	AddBookRelation(ctx context.Context, relation entity.BookRelation) error
+	GetRelatedBooks(ctx context.Context, bookID string) ([]entity.RelatedBook, error)
+	// ListBooks returns up to pageSize books matching the filter expression
+	// ordered by creation time, defaultPageSize books if pageSize is not
+	// positive, and the token of the next page, empty on the last page.
+	// Given a page token, the listing continues from it with the filter of
+	// the token. Invalid filters result in entity.ErrInvalidFilter, invalid
+	// tokens in entity.ErrInvalidPageToken.
+	ListBooks(ctx context.Context, query string, pageSize int32, pageToken string) ([]entity.Book, string, error)
+}
+
+type OutboxUseCase interface {
+	RedeliverOutboxEvents(ctx context.Context, ids []int64) (int64, error)
 }
 
 var _ AuthorUseCase = (*libraryImpl)(nil)
@@ -28,16 +43,22 @@ type libraryImpl struct {
 	logger           *zap.Logger
 	authorRepository repository.AuthorRepository
 	booksRepository  repository.BooksRepository
+	pageTokens       pagination.Codec
+	flags            featureflag.Flags
 }
 
 func New(
 	logger *zap.Logger,
 	authorRepository repository.AuthorRepository,
 	booksRepository repository.BooksRepository,
+	pageTokens pagination.Codec,
+	flags featureflag.Flags,
 ) *libraryImpl {
 	return &libraryImpl{
 		logger:           logger,
 		authorRepository: authorRepository,
 		booksRepository:  booksRepository,
+		pageTokens:       pageTokens,
+		flags:            flags,
 	}
 }
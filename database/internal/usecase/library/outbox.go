@@ -0,0 +1,26 @@
+package library
+
+import (
+	"context"
+
+	"github.com/TimurUrazov/go-projects/database/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+var _ OutboxUseCase = (*outboxImpl)(nil)
+
+type outboxImpl struct {
+	logger           *zap.Logger
+	outboxRepository repository.OutboxRepository
+}
+
+func NewOutbox(logger *zap.Logger, outboxRepository repository.OutboxRepository) *outboxImpl {
+	return &outboxImpl{
+		logger:           logger,
+		outboxRepository: outboxRepository,
+	}
+}
+
+func (o *outboxImpl) RedeliverOutboxEvents(ctx context.Context, ids []int64) (int64, error) {
+	return o.outboxRepository.RedeliverOutboxEvents(ctx, ids)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/filter"
+	"github.com/TimurUrazov/go-projects/database/internal/metrics"
+)
+
+var _ Repository = (*instrumentedRepository)(nil)
+
+// instrumentedRepository records latency of every call to the wrapped
+// repository.
+type instrumentedRepository struct {
+	repository Repository
+	metrics    *metrics.Metrics
+}
+
+func NewInstrumentedRepository(repository Repository, metrics *metrics.Metrics) *instrumentedRepository {
+	return &instrumentedRepository{
+		repository: repository,
+		metrics:    metrics,
+	}
+}
+
+func (r *instrumentedRepository) RegisterAuthor(ctx context.Context, author entity.Author) (result entity.Author, err error) {
+	defer r.observe("RegisterAuthor", time.Now(), &err)
+	return r.repository.RegisterAuthor(ctx, author)
+}
+
+func (r *instrumentedRepository) ChangeAuthorInfo(ctx context.Context, id, name string) (err error) {
+	defer r.observe("ChangeAuthorInfo", time.Now(), &err)
+	return r.repository.ChangeAuthorInfo(ctx, id, name)
+}
+
+func (r *instrumentedRepository) GetAuthorInfo(ctx context.Context, id string) (result entity.Author, err error) {
+	defer r.observe("GetAuthorInfo", time.Now(), &err)
+	return r.repository.GetAuthorInfo(ctx, id)
+}
+
+// GetAuthorBooks is measured until the stream of books is over, as most of the
+// work happens while books are being streamed.
+func (r *instrumentedRepository) GetAuthorBooks(
+	ctx context.Context,
+	id string,
+	batchSize int,
+) (<-chan entity.Book, <-chan error) {
+	start := time.Now()
+	books, errs := r.repository.GetAuthorBooks(ctx, id, batchSize)
+
+	booksOut := make(chan entity.Book)
+	errsOut := make(chan error, 1)
+
+	go func() {
+		defer close(errsOut)
+		defer close(booksOut)
+
+		var ctxErr error
+
+		for books != nil || errs != nil {
+			select {
+			case book, ok := <-books:
+				if !ok {
+					books = nil
+					continue
+				}
+				if ctxErr != nil {
+					// nobody reads books anymore, they are drained so that
+					// the wrapped repository is not blocked
+					continue
+				}
+				select {
+				case booksOut <- book:
+				case <-ctx.Done():
+					ctxErr = ctx.Err()
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				// the wrapped repository stops streaming after an error
+				r.metrics.ObserveRepository("GetAuthorBooks", start, err)
+				errsOut <- err
+				return
+			}
+		}
+
+		r.metrics.ObserveRepository("GetAuthorBooks", start, ctxErr)
+	}()
+
+	return booksOut, errsOut
+}
+
+func (r *instrumentedRepository) AddBook(ctx context.Context, book entity.Book) (result entity.Book, err error) {
+	defer r.observe("AddBook", time.Now(), &err)
+	return r.repository.AddBook(ctx, book)
+}
+
+func (r *instrumentedRepository) UpdateBook(ctx context.Context, id, name string, contributors []entity.Contributor) (err error) {
+	defer r.observe("UpdateBook", time.Now(), &err)
+	return r.repository.UpdateBook(ctx, id, name, contributors)
+}
+
+func (r *instrumentedRepository) GetBookInfo(ctx context.Context, bookID string, view entity.BookView) (result entity.Book, err error) {
+	defer r.observe("GetBookInfo", time.Now(), &err)
+	return r.repository.GetBookInfo(ctx, bookID, view)
+}
+
+func (r *instrumentedRepository) AddBookRelation(ctx context.Context, relation entity.BookRelation) (err error) {
+	defer r.observe("AddBookRelation", time.Now(), &err)
+	return r.repository.AddBookRelation(ctx, relation)
+}
+
+func (r *instrumentedRepository) GetRelatedBooks(ctx context.Context, bookID string) (result []entity.RelatedBook, err error) {
+	defer r.observe("GetRelatedBooks", time.Now(), &err)
+	return r.repository.GetRelatedBooks(ctx, bookID)
+}
+
+func (r *instrumentedRepository) ListBooks(
+	ctx context.Context,
+	where filter.Expression,
+	after *entity.BookPosition,
+	limit int,
+) (result []entity.Book, err error) {
+	defer r.observe("ListBooks", time.Now(), &err)
+	return r.repository.ListBooks(ctx, where, after, limit)
+}
+
+func (r *instrumentedRepository) observe(method string, start time.Time, err *error) {
+	r.metrics.ObserveRepository(method, start, *err)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type mockRepository struct {
+	*MockAuthorRepository
+	*MockBooksRepository
+}
+
+func Test_instrumentedRepository_GetAuthorBooks(t *testing.T) {
+	t.Parallel()
+
+	errSome := errors.New("some error")
+
+	tests := []struct {
+		name      string
+		books     []entity.Book
+		err       error
+		wantBooks int
+	}{
+		{
+			name:      "All books are forwarded",
+			books:     []entity.Book{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+			err:       nil,
+			wantBooks: 3,
+		},
+		{
+			name:      "Error is forwarded",
+			books:     []entity.Book{{ID: "1"}},
+			err:       errSome,
+			wantBooks: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			authorRepository := NewMockAuthorRepository(ctrl)
+			booksRepository := NewMockBooksRepository(ctrl)
+
+			authorRepository.EXPECT().
+				GetAuthorBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(context.Context, string, int) (<-chan entity.Book, <-chan error) {
+					booksChan := make(chan entity.Book)
+					errChan := make(chan error, 1)
+
+					go func() {
+						defer close(booksChan)
+						defer close(errChan)
+
+						for _, book := range tt.books {
+							booksChan <- book
+						}
+
+						if tt.err != nil {
+							errChan <- tt.err
+						}
+					}()
+
+					return booksChan, errChan
+				})
+
+			impl := NewInstrumentedRepository(mockRepository{authorRepository, booksRepository}, metrics.New())
+
+			books, errs := impl.GetAuthorBooks(context.Background(), "id", 0)
+
+			received := 0
+			for range books {
+				received++
+			}
+
+			require.Equal(t, tt.wantBooks, received)
+			require.ErrorIs(t, <-errs, tt.err)
+		})
+	}
+}
+
+func Test_instrumentedRepository_GetBookInfo(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	authorRepository := NewMockAuthorRepository(ctrl)
+	booksRepository := NewMockBooksRepository(ctrl)
+
+	booksRepository.EXPECT().
+		GetBookInfo(gomock.Any(), "id", entity.BookViewFull).
+		Return(entity.Book{}, entity.ErrBookNotFound)
+
+	impl := NewInstrumentedRepository(mockRepository{authorRepository, booksRepository}, metrics.New())
+
+	_, err := impl.GetBookInfo(context.Background(), "id", entity.BookViewFull)
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+}
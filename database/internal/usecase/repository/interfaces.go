@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/filter"
 )
 
 type (
@@ -11,12 +12,39 @@ type (
 		RegisterAuthor(ctx context.Context, name entity.Author) (entity.Author, error)
 		ChangeAuthorInfo(ctx context.Context, id, name string) error
 		GetAuthorInfo(ctx context.Context, id string) (entity.Author, error)
-		GetAuthorBooks(ctx context.Context, id string) (<-chan entity.Book, <-chan error)
+		GetAuthorBooks(ctx context.Context, id string, batchSize int) (<-chan entity.Book, <-chan error)
 	}
 
 	BooksRepository interface {
 		AddBook(ctx context.Context, book entity.Book) (entity.Book, error)
-		UpdateBook(ctx context.Context, id, name string, authorIDs []string) error
-		GetBookInfo(ctx context.Context, bookID string) (entity.Book, error)
+		UpdateBook(ctx context.Context, id, name string, contributors []entity.Contributor) error
+		GetBookInfo(ctx context.Context, bookID string, view entity.BookView) (entity.Book, error)
+		AddBookRelation(ctx context.Context, relation entity.BookRelation) error
+		GetRelatedBooks(ctx context.Context, bookID string) ([]entity.RelatedBook, error)
+		// ListBooks returns up to limit books matching where ordered by
+		// creation time, starting after the given position if it is set.
+		ListBooks(ctx context.Context, where filter.Expression, after *entity.BookPosition, limit int) ([]entity.Book, error)
 	}
 )
+
+// Repository is a storage of both authors and books.
+type Repository interface {
+	AuthorRepository
+	BooksRepository
+}
+
+// OutboxRepository is a storage of events waiting for delivery.
+type OutboxRepository interface {
+	// DeliverOutboxEvents passes up to limit pending events to deliver in the
+	// order they were stored. Of an aggregate whose first pending event has
+	// failed before, only that event is passed. Events deliver succeeds for
+	// are marked as delivered, failed ones stay pending with the error
+	// recorded, deferred ones stay pending untouched. Returns number of
+	// delivered events.
+	DeliverOutboxEvents(ctx context.Context, limit int,
+		deliver func(ctx context.Context, event entity.OutboxEvent) error) (int, error)
+	GetOutboxLag(ctx context.Context) (entity.OutboxLag, error)
+	// RedeliverOutboxEvents makes the events pending again and returns the
+	// number of events found.
+	RedeliverOutboxEvents(ctx context.Context, ids []int64) (int64, error)
+}
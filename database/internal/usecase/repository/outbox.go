@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var _ OutboxRepository = (*postgresRepository)(nil)
+
+// outboxRelayLockID is the key of the advisory lock taken while delivering
+// events, so that events of an aggregate are never delivered concurrently by
+// several instances of the service.
+const outboxRelayLockID = 0x6f7574626f78
+
+func (p *postgresRepository) DeliverOutboxEvents(
+	ctx context.Context,
+	limit int,
+	deliver func(ctx context.Context, event entity.OutboxEvent) error,
+) (int, error) {
+	tx, err := p.begin(ctx, "DeliverOutboxEvents")
+
+	if err != nil {
+		p.logger.Warn("Error while starting transaction in deliver outbox events method", zap.Error(err))
+		return 0, err
+	}
+
+	defer func(tx pgx.Tx, ctx context.Context) {
+		err = tx.Rollback(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrTxClosed) {
+				p.logger.Debug("Tx is closed in deliver outbox events method", zap.Error(err))
+			} else {
+				p.logger.Warn("Error while closing transaction in deliver outbox events method", zap.Error(err))
+			}
+		}
+	}(tx, ctx)
+
+	var locked bool
+
+	err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxRelayLockID).Scan(&locked)
+
+	if err != nil {
+		p.logger.Warn("Error while taking advisory lock in deliver outbox events method", zap.Error(err))
+		return 0, err
+	}
+
+	if !locked {
+		p.logger.Debug("Outbox is being delivered by another instance in deliver outbox events method")
+		return 0, nil
+	}
+
+	// an aggregate whose first pending event has failed contributes only that
+	// event, so that its backlog does not fill the batches of other aggregates
+	const selectQuery = `
+WITH head AS (
+SELECT DISTINCT ON (aggregate_type, aggregate_id) id, aggregate_type, aggregate_id, last_error FROM outbox
+WHERE delivered_at IS NULL ORDER BY aggregate_type, aggregate_id, id
+)
+SELECT o.id, o.aggregate_type, o.aggregate_id, o.event_type, o.payload, o.created_at, o.attempts FROM outbox o
+JOIN head h ON h.aggregate_type = o.aggregate_type AND h.aggregate_id = o.aggregate_id
+WHERE o.delivered_at IS NULL AND (o.id = h.id OR h.last_error IS NULL) ORDER BY o.id LIMIT $1`
+
+	rows, err := tx.Query(ctx, selectQuery, limit)
+
+	if err != nil {
+		p.logger.Warn("Error while selecting pending events in deliver outbox events method", zap.Error(err))
+		return 0, err
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.OutboxEvent, error) {
+		var event entity.OutboxEvent
+		err := row.Scan(&event.ID, &event.AggregateType, &event.AggregateID, &event.EventType,
+			&event.Payload, &event.CreatedAt, &event.Attempts)
+		return event, err
+	})
+
+	if err != nil {
+		p.logger.Warn("Error while scanning pending events in deliver outbox events method", zap.Error(err))
+		return 0, err
+	}
+
+	const (
+		deliveredQuery = `UPDATE outbox SET delivered_at = now(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+		failedQuery    = `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	)
+
+	delivered := 0
+
+	for _, event := range events {
+		deliverErr := deliver(ctx, event)
+
+		switch {
+		case errors.Is(deliverErr, entity.ErrOutboxEventDeferred):
+			continue
+		case deliverErr != nil:
+			_, err = tx.Exec(ctx, failedQuery, event.ID, deliverErr.Error())
+		default:
+			_, err = tx.Exec(ctx, deliveredQuery, event.ID)
+			delivered++
+		}
+
+		if err != nil {
+			p.logger.Warn("Error while updating event in deliver outbox events method",
+				zap.Int64("event_id", event.ID), zap.Error(err))
+			return 0, err
+		}
+	}
+
+	if err := p.commit(ctx, tx, "DeliverOutboxEvents"); err != nil {
+		p.logger.Warn("Error while commiting transaction in deliver outbox events method", zap.Error(err))
+		return 0, err
+	}
+
+	return delivered, nil
+}
+
+func (p *postgresRepository) GetOutboxLag(ctx context.Context) (entity.OutboxLag, error) {
+	// created_at has no time zone, so the age is measured by the clock of the
+	// database rather than the one of the service
+	const query = `
+SELECT count(*), COALESCE(EXTRACT(EPOCH FROM now()::timestamp - min(created_at)), 0)::float8
+FROM outbox WHERE delivered_at IS NULL`
+
+	var (
+		lag entity.OutboxLag
+		age float64
+	)
+
+	if err := p.db.QueryRow(ctx, query).Scan(&lag.Pending, &age); err != nil {
+		p.logger.Warn("Error while performing select query to table 'outbox' in get outbox lag method",
+			zap.Error(err))
+		return entity.OutboxLag{}, err
+	}
+
+	lag.OldestAge = time.Duration(age * float64(time.Second))
+
+	return lag, nil
+}
+
+func (p *postgresRepository) RedeliverOutboxEvents(ctx context.Context, ids []int64) (int64, error) {
+	const query = `UPDATE outbox SET delivered_at = NULL, attempts = 0, last_error = NULL WHERE id = ANY($1)`
+
+	tag, err := p.db.Exec(ctx, query, ids)
+
+	if err != nil {
+		p.logger.Warn("Error while performing update query to table 'outbox' in redeliver outbox events method",
+			zap.Error(err))
+		return 0, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		p.logger.Debug("Events not found in redeliver outbox events method", zap.Int64s("event_ids", ids))
+		return 0, entity.ErrOutboxEventNotFound
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -7,10 +7,13 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/TimurUrazov/go-projects/database/internal/entity"
+	"github.com/TimurUrazov/go-projects/database/internal/failpoint"
+	"github.com/TimurUrazov/go-projects/database/internal/filter"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -29,8 +32,28 @@ func NewPostgresRepository(db *pgxpool.Pool, logger *zap.Logger) *postgresReposi
 	}
 }
 
+// begin starts transaction of the named method. It can be failed or delayed
+// with failpoint repository/<method>/begin.
+func (p *postgresRepository) begin(ctx context.Context, method string) (pgx.Tx, error) {
+	if err := failpoint.Inject(ctx, "repository/"+method+"/begin"); err != nil {
+		return nil, err
+	}
+
+	return p.db.Begin(ctx)
+}
+
+// commit commits transaction of the named method. It can be failed or delayed
+// with failpoint repository/<method>/commit.
+func (p *postgresRepository) commit(ctx context.Context, tx pgx.Tx, method string) error {
+	if err := failpoint.Inject(ctx, "repository/"+method+"/commit"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (p *postgresRepository) AddBook(ctx context.Context, book entity.Book) (entity.Book, error) {
-	tx, err := p.db.Begin(ctx)
+	tx, err := p.begin(ctx, "AddBook")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in add book method", zap.Error(err))
@@ -55,16 +78,16 @@ func (p *postgresRepository) AddBook(ctx context.Context, book entity.Book) (ent
 		return entity.Book{}, err
 	}
 
-	const query = `INSERT INTO author_book (author_id, book_id) VALUES ($1, $2)`
+	const query = `INSERT INTO author_book (author_id, book_id, role) VALUES ($1, $2, $3)`
 
-	for _, authorID := range book.Authors {
-		_, er := tx.Exec(ctx, query, authorID, book.ID)
+	for _, contributor := range book.Contributors {
+		_, er := tx.Exec(ctx, query, contributor.AuthorID, book.ID, contributor.Role)
 
 		var pgErr *pgconn.PgError
 
 		if errors.As(er, &pgErr) && pgErr.Code == "23503" {
 			p.logger.Debug("Author not found error while performing insert query in 'author_book' table in add book method",
-				zap.String("author_id", authorID),
+				zap.String("author_id", contributor.AuthorID),
 				zap.Error(er))
 			return entity.Book{}, entity.ErrAuthorNotFound
 		}
@@ -76,7 +99,7 @@ func (p *postgresRepository) AddBook(ctx context.Context, book entity.Book) (ent
 		}
 	}
 
-	if err = tx.Commit(ctx); err != nil {
+	if err = p.commit(ctx, tx, "AddBook"); err != nil {
 		p.logger.Warn("Error while commiting transaction in add book method")
 		return entity.Book{}, err
 	}
@@ -84,8 +107,8 @@ func (p *postgresRepository) AddBook(ctx context.Context, book entity.Book) (ent
 	return book, nil
 }
 
-func (p *postgresRepository) GetBookInfo(ctx context.Context, bookID string) (entity.Book, error) {
-	tx, err := p.db.Begin(ctx)
+func (p *postgresRepository) GetBookInfo(ctx context.Context, bookID string, view entity.BookView) (entity.Book, error) {
+	tx, err := p.begin(ctx, "GetBookInfo")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in get book info method", zap.Error(err))
@@ -121,7 +144,7 @@ func (p *postgresRepository) GetBookInfo(ctx context.Context, bookID string) (en
 		return entity.Book{}, err
 	}
 
-	const bookAuthorsQuery = `SELECT author_id FROM author_book WHERE book_id = $1`
+	const bookAuthorsQuery = `SELECT author_id, role FROM author_book WHERE book_id = $1`
 
 	rows, err := p.db.Query(ctx, bookAuthorsQuery, bookID)
 
@@ -134,22 +157,59 @@ func (p *postgresRepository) GetBookInfo(ctx context.Context, bookID string) (en
 	defer rows.Close()
 
 	for rows.Next() {
-		var authorID string
+		var contributor entity.Contributor
 
-		if err := rows.Scan(&authorID); err != nil {
+		if err := rows.Scan(&contributor.AuthorID, &contributor.Role); err != nil {
 			p.logger.Warn("Error while scanning author of book in get book info method",
-				zap.String("book_id", bookID), zap.String("author_id", authorID), zap.Error(err))
+				zap.String("book_id", bookID), zap.String("author_id", contributor.AuthorID), zap.Error(err))
 			return entity.Book{}, err
 		}
 
-		book.Authors = append(book.Authors, authorID)
+		book.Contributors = append(book.Contributors, contributor)
+	}
+
+	book.Authors = entity.ContributorAuthorIDs(book.Contributors)
+
+	if view != entity.BookViewFull || len(book.Authors) == 0 {
+		return book, nil
+	}
+
+	// all authors are fetched at once instead of being looked up one by one
+	const authorsQuery = `SELECT id, name, created_at, updated_at FROM author WHERE id = ANY($1) ORDER BY name`
+
+	authorRows, err := p.db.Query(ctx, authorsQuery, book.Authors)
+
+	if err != nil {
+		p.logger.Warn("Error while retrieving author details in get book info method",
+			zap.String("book_id", bookID), zap.Error(err))
+		return entity.Book{}, err
+	}
+
+	defer authorRows.Close()
+
+	for authorRows.Next() {
+		var author entity.Author
+
+		if err := authorRows.Scan(&author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt); err != nil {
+			p.logger.Warn("Error while scanning author details in get book info method",
+				zap.String("book_id", bookID), zap.Error(err))
+			return entity.Book{}, err
+		}
+
+		book.AuthorDetails = append(book.AuthorDetails, author)
+	}
+
+	if err := authorRows.Err(); err != nil {
+		p.logger.Warn("Error while iterating over author details in get book info method",
+			zap.String("book_id", bookID), zap.Error(err))
+		return entity.Book{}, err
 	}
 
 	return book, nil
 }
 
-func (p *postgresRepository) UpdateBook(ctx context.Context, id, name string, authorIDs []string) error {
-	tx, err := p.db.Begin(ctx)
+func (p *postgresRepository) UpdateBook(ctx context.Context, id, name string, contributors []entity.Contributor) error {
+	tx, err := p.begin(ctx, "UpdateBook")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in update book method", zap.Error(err))
@@ -195,27 +255,27 @@ func (p *postgresRepository) UpdateBook(ctx context.Context, id, name string, au
 		return err
 	}
 
-	const queryInsertAuthor = `INSERT INTO author_book (book_id, author_id) VALUES ($1, $2)`
+	const queryInsertAuthor = `INSERT INTO author_book (book_id, author_id, role) VALUES ($1, $2, $3)`
 
-	for _, authorID := range authorIDs {
-		_, err = tx.Exec(ctx, queryInsertAuthor, id, authorID)
+	for _, contributor := range contributors {
+		_, err = tx.Exec(ctx, queryInsertAuthor, id, contributor.AuthorID, contributor.Role)
 
 		var pgErr *pgconn.PgError
 
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
 			p.logger.Debug("Author not found error while inserting author in 'author_book' table in update book method",
-				zap.String("author_id", authorID), zap.String("book_id", id))
+				zap.String("author_id", contributor.AuthorID), zap.String("book_id", id))
 			return entity.ErrAuthorNotFound
 		}
 
 		if err != nil {
 			p.logger.Warn("Error while performing insert author in 'author_book' table query in update book method",
-				zap.String("author_id", authorID), zap.String("book_id", id), zap.Error(err))
+				zap.String("author_id", contributor.AuthorID), zap.String("book_id", id), zap.Error(err))
 			return err
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := p.commit(ctx, tx, "UpdateBook"); err != nil {
 		p.logger.Warn("Error while commiting transaction in update book method", zap.Error(err))
 		return err
 	}
@@ -224,7 +284,7 @@ func (p *postgresRepository) UpdateBook(ctx context.Context, id, name string, au
 }
 
 func (p *postgresRepository) ChangeAuthorInfo(ctx context.Context, id, name string) error {
-	tx, err := p.db.Begin(ctx)
+	tx, err := p.begin(ctx, "ChangeAuthorInfo")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in change author info method", zap.Error(err))
@@ -260,7 +320,7 @@ func (p *postgresRepository) ChangeAuthorInfo(ctx context.Context, id, name stri
 		return err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := p.commit(ctx, tx, "ChangeAuthorInfo"); err != nil {
 		p.logger.Warn("Error while commiting transaction in change author info method", zap.Error(err))
 		return err
 	}
@@ -269,7 +329,7 @@ func (p *postgresRepository) ChangeAuthorInfo(ctx context.Context, id, name stri
 }
 
 func (p *postgresRepository) RegisterAuthor(ctx context.Context, author entity.Author) (entity.Author, error) {
-	tx, err := p.db.Begin(ctx)
+	tx, err := p.begin(ctx, "RegisterAuthor")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in register author method", zap.Error(err))
@@ -297,7 +357,7 @@ func (p *postgresRepository) RegisterAuthor(ctx context.Context, author entity.A
 		return entity.Author{}, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := p.commit(ctx, tx, "RegisterAuthor"); err != nil {
 		p.logger.Warn("Error while commiting transaction in register author method", zap.Error(err))
 		return entity.Author{}, err
 	}
@@ -306,7 +366,7 @@ func (p *postgresRepository) RegisterAuthor(ctx context.Context, author entity.A
 }
 
 func (p *postgresRepository) GetAuthorInfo(ctx context.Context, id string) (entity.Author, error) {
-	tx, err := p.db.Begin(ctx)
+	tx, err := p.begin(ctx, "GetAuthorInfo")
 
 	if err != nil {
 		p.logger.Warn("Error while starting transaction in get author info method", zap.Error(err))
@@ -342,7 +402,7 @@ func (p *postgresRepository) GetAuthorInfo(ctx context.Context, id string) (enti
 		return entity.Author{}, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := p.commit(ctx, tx, "GetAuthorInfo"); err != nil {
 		p.logger.Warn("Error while commiting transaction in get author info method", zap.Error(err))
 		return entity.Author{}, err
 	}
@@ -350,12 +410,18 @@ func (p *postgresRepository) GetAuthorInfo(ctx context.Context, id string) (enti
 	return author, nil
 }
 
-func (p *postgresRepository) GetAuthorBooks(ctx context.Context, id string) (<-chan entity.Book, <-chan error) {
+// GetAuthorBooks streams books of the author fetching them from the cursor by
+// batchSize rows, all at once if batchSize is not positive.
+func (p *postgresRepository) GetAuthorBooks(
+	ctx context.Context,
+	id string,
+	batchSize int,
+) (<-chan entity.Book, <-chan error) {
 	booksChan := make(chan entity.Book)
 	errChan := make(chan error, 1)
 
 	go func() {
-		tx, err := p.db.Begin(ctx)
+		tx, err := p.begin(ctx, "GetAuthorBooks")
 
 		if err != nil {
 			p.logger.Warn("Error while starting transaction in get author books method", zap.Error(err))
@@ -378,8 +444,9 @@ func (p *postgresRepository) GetAuthorBooks(ctx context.Context, id string) (<-c
 		defer close(errChan)
 
 		const queryDeclareCursor = `
-DECLARE curs CURSOR FOR SELECT b1.id, b1.name, b1.created_at, b1.updated_at, string_agg(ab1.author_id::text, '\n') FROM 
-(SELECT b.id AS id, b.name AS name, b.created_at AS created_at, b.updated_at AS updated_at FROM
+DECLARE curs CURSOR FOR SELECT b1.id, b1.name, b1.created_at, b1.updated_at, string_agg(ab1.author_id::text, '\n' ORDER BY ab1.author_id, ab1.role),
+string_agg(ab1.role, '\n' ORDER BY ab1.author_id, ab1.role) FROM 
+(SELECT DISTINCT b.id AS id, b.name AS name, b.created_at AS created_at, b.updated_at AS updated_at FROM
 book b JOIN author_book a ON b.id = a.book_id WHERE a.author_id = $1) b1 JOIN author_book ab1 ON ab1.book_id = b1.id
 GROUP BY b1.id, b1.name, b1.created_at, b1.updated_at
 `
@@ -392,35 +459,26 @@ GROUP BY b1.id, b1.name, b1.created_at, b1.updated_at
 			return
 		}
 
-		rows, err := tx.Query(ctx, "FETCH FORWARD ALL FROM curs")
+		fetchQuery := "FETCH FORWARD ALL FROM curs"
 
-		if err != nil {
-			p.logger.Warn("Error while fetching cursor in get author books method",
-				zap.String("author_id", id), zap.Error(err))
-			errChan <- err
-			return
+		if batchSize > 0 {
+			fetchQuery = fmt.Sprintf("FETCH FORWARD %d FROM curs", batchSize)
 		}
 
-		defer rows.Close()
-
-		for rows.Next() {
-			book := entity.Book{}
-
-			var authors string
+		for {
+			fetched, err := p.fetchAuthorBooks(ctx, tx, fetchQuery, id, booksChan)
 
-			if err := rows.Scan(&book.ID, &book.Name, &book.CreatedAt, &book.UpdatedAt, &authors); err != nil {
-				p.logger.Warn("Error while scanning row cursor pointing on in get author books method",
-					zap.String("author_id", id), zap.Error(err))
+			if err != nil {
 				errChan <- err
 				return
 			}
 
-			book.Authors = strings.Split(authors, "\\n")
-
-			booksChan <- book
+			if batchSize <= 0 || fetched < batchSize {
+				break
+			}
 		}
 
-		if err := tx.Commit(ctx); err != nil {
+		if err := p.commit(ctx, tx, "GetAuthorBooks"); err != nil {
 			p.logger.Warn("Error while commiting transaction in get author books method", zap.Error(err))
 			errChan <- err
 			return
@@ -429,3 +487,296 @@ GROUP BY b1.id, b1.name, b1.created_at, b1.updated_at
 
 	return booksChan, errChan
 }
+
+// fetchAuthorBooks performs fetch query on the cursor of get author books
+// method and sends fetched books. Returns the number of fetched books.
+func (p *postgresRepository) fetchAuthorBooks(
+	ctx context.Context,
+	tx pgx.Tx,
+	fetchQuery, id string,
+	booksChan chan<- entity.Book,
+) (int, error) {
+	rows, err := tx.Query(ctx, fetchQuery)
+
+	if err != nil {
+		p.logger.Warn("Error while fetching cursor in get author books method",
+			zap.String("author_id", id), zap.Error(err))
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	fetched := 0
+
+	for rows.Next() {
+		book := entity.Book{}
+
+		var authors, roles string
+
+		if err := rows.Scan(&book.ID, &book.Name, &book.CreatedAt, &book.UpdatedAt, &authors, &roles); err != nil {
+			p.logger.Warn("Error while scanning row cursor pointing on in get author books method",
+				zap.String("author_id", id), zap.Error(err))
+			return 0, err
+		}
+
+		authorIDs := strings.Split(authors, "\\n")
+
+		// both aggregates are computed over the same rows, so roles go in
+		// the same order as authors
+		for i, role := range strings.Split(roles, "\\n") {
+			book.Contributors = append(book.Contributors, entity.Contributor{
+				AuthorID: authorIDs[i],
+				Role:     entity.Role(role),
+			})
+		}
+
+		book.Authors = entity.ContributorAuthorIDs(book.Contributors)
+
+		booksChan <- book
+		fetched++
+	}
+
+	if err := rows.Err(); err != nil {
+		p.logger.Warn("Error while iterating over cursor in get author books method",
+			zap.String("author_id", id), zap.Error(err))
+		return 0, err
+	}
+
+	return fetched, nil
+}
+
+func (p *postgresRepository) AddBookRelation(ctx context.Context, relation entity.BookRelation) error {
+	const query = `INSERT INTO book_relation (book_id, related_book_id, relation) VALUES ($1, $2, $3)`
+
+	// injected errors go through the same mapping as the ones of the database
+	err := failpoint.Inject(ctx, "repository/AddBookRelation/exec")
+
+	if err == nil {
+		_, err = p.db.Exec(ctx, query, relation.BookID, relation.RelatedBookID, relation.Type)
+	}
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			p.logger.Debug("Book not found error while inserting into 'book_relation' table in add book relation method",
+				zap.String("book_id", relation.BookID), zap.String("related_book_id", relation.RelatedBookID))
+			return entity.ErrBookNotFound
+		case "23505":
+			p.logger.Debug("Relation already exists in 'book_relation' table in add book relation method",
+				zap.String("book_id", relation.BookID), zap.String("related_book_id", relation.RelatedBookID))
+			return entity.ErrBookRelationAlreadyExists
+		case "23514":
+			p.logger.Debug("Check constraint violation in 'book_relation' table in add book relation method",
+				zap.String("book_id", relation.BookID), zap.String("related_book_id", relation.RelatedBookID))
+			return entity.ErrInvalidBookRelation
+		}
+	}
+
+	if err != nil {
+		p.logger.Warn("Error while inserting into 'book_relation' table in add book relation method",
+			zap.String("book_id", relation.BookID), zap.String("related_book_id", relation.RelatedBookID),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) GetRelatedBooks(ctx context.Context, bookID string) ([]entity.RelatedBook, error) {
+	tx, err := p.begin(ctx, "GetRelatedBooks")
+
+	if err != nil {
+		p.logger.Warn("Error while starting transaction in get related books method", zap.Error(err))
+		return nil, err
+	}
+
+	defer func(tx pgx.Tx, ctx context.Context) {
+		err = tx.Rollback(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrTxClosed) {
+				p.logger.Debug("Tx is closed in get related books method", zap.Error(err))
+			} else {
+				p.logger.Warn("Error while closing transaction in get related books method", zap.Error(err))
+			}
+		}
+	}(tx, ctx)
+
+	const queryBookExists = `SELECT EXISTS (SELECT 1 FROM book WHERE id = $1)`
+
+	var exists bool
+
+	if err = tx.QueryRow(ctx, queryBookExists, bookID).Scan(&exists); err != nil {
+		p.logger.Warn("Error while checking book existence in get related books method",
+			zap.String("book_id", bookID), zap.Error(err))
+		return nil, err
+	}
+
+	if !exists {
+		p.logger.Debug("Book not found in get related books method", zap.String("book_id", bookID))
+		return nil, entity.ErrBookNotFound
+	}
+
+	// relations are taken in both directions, reverse marks the ones
+	// declared by the related book
+	const queryRelatedBooks = `
+SELECT b.id, b.name, b.created_at, b.updated_at, r.relation, r.reverse,
+COALESCE(array_agg(ab.author_id::text ORDER BY ab.author_id, ab.role) FILTER (WHERE ab.author_id IS NOT NULL), '{}'),
+COALESCE(array_agg(ab.role ORDER BY ab.author_id, ab.role) FILTER (WHERE ab.author_id IS NOT NULL), '{}') FROM
+(SELECT related_book_id AS id, relation, false AS reverse FROM book_relation WHERE book_id = $1
+UNION ALL
+SELECT book_id AS id, relation, true AS reverse FROM book_relation WHERE related_book_id = $1) r
+JOIN book b ON b.id = r.id LEFT JOIN author_book ab ON ab.book_id = b.id
+GROUP BY b.id, b.name, b.created_at, b.updated_at, r.relation, r.reverse
+ORDER BY r.relation, b.created_at
+`
+
+	rows, err := tx.Query(ctx, queryRelatedBooks, bookID)
+
+	if err != nil {
+		p.logger.Warn("Error while selecting related books in get related books method",
+			zap.String("book_id", bookID), zap.Error(err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var related []entity.RelatedBook
+
+	for rows.Next() {
+		var (
+			relatedBook entity.RelatedBook
+			authorIDs   []string
+			roles       []string
+		)
+
+		book := &relatedBook.Book
+
+		if err := rows.Scan(&book.ID, &book.Name, &book.CreatedAt, &book.UpdatedAt,
+			&relatedBook.Type, &relatedBook.Reverse, &authorIDs, &roles); err != nil {
+			p.logger.Warn("Error while scanning related book in get related books method",
+				zap.String("book_id", bookID), zap.Error(err))
+			return nil, err
+		}
+
+		for i, role := range roles {
+			book.Contributors = append(book.Contributors, entity.Contributor{
+				AuthorID: authorIDs[i],
+				Role:     entity.Role(role),
+			})
+		}
+
+		book.Authors = entity.ContributorAuthorIDs(book.Contributors)
+
+		related = append(related, relatedBook)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.logger.Warn("Error while iterating over related books in get related books method",
+			zap.String("book_id", bookID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := p.commit(ctx, tx, "GetRelatedBooks"); err != nil {
+		p.logger.Warn("Error while commiting transaction in get related books method", zap.Error(err))
+		return nil, err
+	}
+
+	return related, nil
+}
+
+func (p *postgresRepository) ListBooks(
+	ctx context.Context,
+	where filter.Expression,
+	after *entity.BookPosition,
+	limit int,
+) ([]entity.Book, error) {
+	tx, err := p.begin(ctx, "ListBooks")
+
+	if err != nil {
+		p.logger.Warn("Error while starting transaction in list books method", zap.Error(err))
+		return nil, err
+	}
+
+	defer func(tx pgx.Tx, ctx context.Context) {
+		err = tx.Rollback(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrTxClosed) {
+				p.logger.Debug("Tx is closed in list books method", zap.Error(err))
+			} else {
+				p.logger.Warn("Error while closing transaction in list books method", zap.Error(err))
+			}
+		}
+	}(tx, ctx)
+
+	// $1 is the limit, the position and parameters of the filter follow it
+	args := []any{limit}
+	position := "TRUE"
+
+	if after != nil {
+		position = "(created_at, id) > ($2::timestamp, $3::uuid)"
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	condition, filterArgs := where.Where(len(args) + 1)
+	args = append(args, filterArgs...)
+
+	queryListBooks := `
+SELECT b.id, b.name, b.created_at, b.updated_at,
+COALESCE(array_agg(ab.author_id::text ORDER BY ab.author_id, ab.role) FILTER (WHERE ab.author_id IS NOT NULL), '{}'),
+COALESCE(array_agg(ab.role ORDER BY ab.author_id, ab.role) FILTER (WHERE ab.author_id IS NOT NULL), '{}') FROM
+(SELECT id, name, created_at, updated_at FROM book WHERE ` + position + ` AND ` + condition + `
+ORDER BY created_at, id LIMIT $1) b
+LEFT JOIN author_book ab ON ab.book_id = b.id
+GROUP BY b.id, b.name, b.created_at, b.updated_at
+ORDER BY b.created_at, b.id
+`
+
+	rows, err := tx.Query(ctx, queryListBooks, args...)
+
+	if err != nil {
+		p.logger.Warn("Error while selecting books in list books method", zap.Error(err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []entity.Book
+
+	for rows.Next() {
+		var (
+			book      entity.Book
+			authorIDs []string
+			roles     []string
+		)
+
+		if err := rows.Scan(&book.ID, &book.Name, &book.CreatedAt, &book.UpdatedAt, &authorIDs, &roles); err != nil {
+			p.logger.Warn("Error while scanning book in list books method", zap.Error(err))
+			return nil, err
+		}
+
+		for i, role := range roles {
+			book.Contributors = append(book.Contributors, entity.Contributor{
+				AuthorID: authorIDs[i],
+				Role:     entity.Role(role),
+			})
+		}
+
+		book.Authors = entity.ContributorAuthorIDs(book.Contributors)
+
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.logger.Warn("Error while iterating over books in list books method", zap.Error(err))
+		return nil, err
+	}
+
+	if err := p.commit(ctx, tx, "ListBooks"); err != nil {
+		p.logger.Warn("Error while commiting transaction in list books method", zap.Error(err))
+		return nil, err
+	}
+
+	return books, nil
+}
@@ -0,0 +1,174 @@
+// Package backend builds the cache selected by the configuration, so that a
+// service switches its read-through cache between the in-process LFU cache
+// and the shared Redis by changing the environment only.
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"lfucache/internal/cache"
+	"lfucache/internal/lfu"
+	"lfucache/internal/rediscache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Kind is the kind of the cache backend.
+type Kind string
+
+const (
+	// KindLFU is the in-process LFU cache.
+	KindLFU Kind = "lfu"
+	// KindRedis is the cache shared through Redis.
+	KindRedis Kind = "redis"
+)
+
+// Codec is the name of the codec of the values stored in Redis.
+type Codec string
+
+const (
+	CodecJSON    Codec = "json"
+	CodecMsgpack Codec = "msgpack"
+)
+
+// ErrInvalidConfig is returned for the configurations which cannot be built.
+var ErrInvalidConfig = errors.New("invalid cache backend config")
+
+// Config is the configuration of the cache backend.
+type Config struct {
+	// Backend is the kind of the cache, KindLFU by default.
+	Backend Kind
+	// TTL makes the values expire after it unless it is zero.
+	TTL time.Duration
+	// Capacity is the capacity of the LFU cache.
+	Capacity int
+	// Redis configures the Redis cache.
+	Redis RedisConfig
+}
+
+// RedisConfig is the configuration of the Redis cache.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix is prepended to the keys of the cache.
+	Prefix string
+	// Codec is the codec of the values, CodecJSON by default.
+	Codec Codec
+}
+
+// ConfigFromEnv reads the configuration from CACHE_BACKEND, CACHE_TTL,
+// CACHE_CAPACITY, REDIS_ADDR, REDIS_PASSWORD, REDIS_DB, REDIS_PREFIX and
+// REDIS_CODEC.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Backend:  Kind(os.Getenv("CACHE_BACKEND")),
+		Capacity: lfu.DefaultCapacity,
+		Redis: RedisConfig{
+			Addr:     os.Getenv("REDIS_ADDR"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+			Prefix:   os.Getenv("REDIS_PREFIX"),
+			Codec:    Codec(os.Getenv("REDIS_CODEC")),
+		},
+	}
+	if ttl := os.Getenv("CACHE_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: CACHE_TTL %q", ErrInvalidConfig, ttl)
+		}
+		config.TTL = parsed
+	}
+	if capacity := os.Getenv("CACHE_CAPACITY"); capacity != "" {
+		parsed, err := strconv.Atoi(capacity)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: CACHE_CAPACITY %q", ErrInvalidConfig, capacity)
+		}
+		config.Capacity = parsed
+	}
+	if db := os.Getenv("REDIS_DB"); db != "" {
+		parsed, err := strconv.Atoi(db)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: REDIS_DB %q", ErrInvalidConfig, db)
+		}
+		config.Redis.DB = parsed
+	}
+	return config, config.Validate()
+}
+
+// Validate reports an error wrapping ErrInvalidConfig if the configuration
+// cannot be built.
+func (c Config) Validate() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("%w: negative TTL %v", ErrInvalidConfig, c.TTL)
+	}
+	switch c.Backend {
+	case "", KindLFU:
+		if c.Capacity < 0 {
+			return fmt.Errorf("%w: negative capacity %d", ErrInvalidConfig, c.Capacity)
+		}
+	case KindRedis:
+		if c.Redis.Addr == "" {
+			return fmt.Errorf("%w: no Redis address", ErrInvalidConfig)
+		}
+		switch c.Redis.Codec {
+		case "", CodecJSON, CodecMsgpack:
+		default:
+			return fmt.Errorf("%w: unknown codec %q", ErrInvalidConfig, c.Redis.Codec)
+		}
+	default:
+		return fmt.Errorf("%w: unknown backend %q", ErrInvalidConfig, c.Backend)
+	}
+	return nil
+}
+
+// New builds the cache of the configuration. The closer releases the
+// connections of the backend and must be called once the cache is no longer
+// used.
+func New[K comparable, V any](config Config) (cache.Cache[K, V], io.Closer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if config.Backend == KindRedis {
+		return newRedis[K, V](config)
+	}
+	var opts []lfu.Option[K, V]
+	if config.TTL != 0 {
+		opts = append(opts, lfu.WithTTL[K, V](config.TTL))
+	}
+	l, err := lfu.NewWithConfig(lfu.Config[K, V]{
+		Capacity: config.Capacity,
+		Options:  opts,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, nopCloser{}, nil
+}
+
+func newRedis[K comparable, V any](config Config) (cache.Cache[K, V], io.Closer, error) {
+	client := rediscache.NewClient(redis.NewClient(&redis.Options{
+		Addr:     config.Redis.Addr,
+		Password: config.Redis.Password,
+		DB:       config.Redis.DB,
+	}))
+	var codec rediscache.Codec[V] = rediscache.JSONCodec[V]{}
+	if config.Redis.Codec == CodecMsgpack {
+		codec = rediscache.MsgpackCodec[V]{}
+	}
+	c := rediscache.New(client, config.Redis.Prefix,
+		rediscache.WithCodec[K](codec),
+		rediscache.WithTTL[K, V](config.TTL),
+	)
+	return c, client, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
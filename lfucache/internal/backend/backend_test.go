@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"lfucache/internal/cache"
+	"lfucache/internal/lfu"
+	"lfucache/internal/rediscache"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CACHE_BACKEND", "redis")
+	t.Setenv("CACHE_TTL", "1m")
+	t.Setenv("CACHE_CAPACITY", "")
+	t.Setenv("REDIS_ADDR", "localhost:6379")
+	t.Setenv("REDIS_PASSWORD", "secret")
+	t.Setenv("REDIS_DB", "2")
+	t.Setenv("REDIS_PREFIX", "books:")
+	t.Setenv("REDIS_CODEC", "msgpack")
+
+	config, err := ConfigFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Backend:  KindRedis,
+		TTL:      time.Minute,
+		Capacity: lfu.DefaultCapacity,
+		Redis: RedisConfig{
+			Addr:     "localhost:6379",
+			Password: "secret",
+			DB:       2,
+			Prefix:   "books:",
+			Codec:    CodecMsgpack,
+		},
+	}, config)
+
+	t.Setenv("REDIS_DB", "two")
+	_, err = ConfigFromEnv()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "unknown backend", config: Config{Backend: "memcached"}},
+		{name: "negative TTL", config: Config{TTL: -time.Second}},
+		{name: "negative capacity", config: Config{Capacity: -1}},
+		{name: "no Redis address", config: Config{Backend: KindRedis}},
+		{name: "unknown codec", config: Config{
+			Backend: KindRedis,
+			Redis:   RedisConfig{Addr: "localhost:6379", Codec: "gob"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, tt.config.Validate(), ErrInvalidConfig)
+			_, _, err := New[string, int](tt.config)
+			require.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c, closer, err := New[string, int](Config{Capacity: 1, TTL: time.Minute})
+	require.NoError(t, err)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	require.Equal(t, 1, c.Len())
+	_, err = c.Get("a")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.NoError(t, closer.Close())
+
+	c, closer, err = New[string, int](Config{
+		Backend: KindRedis,
+		Redis:   RedisConfig{Addr: "localhost:6379"},
+	})
+	require.NoError(t, err)
+	require.IsType(t, &rediscache.Cache[string, int]{}, c)
+	require.IsType(t, &rediscache.RedisClient{}, closer)
+	require.NoError(t, closer.Close())
+}
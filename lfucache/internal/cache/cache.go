@@ -0,0 +1,25 @@
+// Package cache defines the minimal cache interface shared by the in-process
+// caches and the remote backends, so that service code does not depend on a
+// particular implementation.
+package cache
+
+import "errors"
+
+// ErrKeyNotFound is returned by Get when the key does not exist in the cache.
+// Implementations return it, possibly wrapped, so that callers can tell a
+// miss from a failure of the backend.
+var ErrKeyNotFound = errors.New("key not found")
+
+// Cache is the minimal cache.
+type Cache[K comparable, V any] interface {
+	// Get returns the value of the key or ErrKeyNotFound.
+	Get(key K) (V, error)
+	// Put puts the value of the key into the cache. The cache may drop the
+	// value at any time, e.g. to make room for other values.
+	Put(key K, value V)
+	// Remove deletes the key from the cache and reports whether the key was
+	// present.
+	Remove(key K) bool
+	// Len returns the number of keys in the cache.
+	Len() int
+}
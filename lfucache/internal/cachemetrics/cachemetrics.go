@@ -0,0 +1,90 @@
+// Package cachemetrics exposes statistics of the caches as expvar variables.
+package cachemetrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+
+	"lfucache/internal/lfu"
+)
+
+// caches is the expvar map with the statistics of every published cache
+// under its name.
+var caches = expvar.NewMap("lfucache")
+
+// StatsSource is a cache reporting its statistics.
+type StatsSource interface {
+	Stats() lfu.Stats
+}
+
+// Publish exposes the statistics of the cache under the given name in the
+// "lfucache" expvar map, which is served on /debug/vars by the expvar package.
+// Publishing a cache under a name already in use replaces the previous cache.
+func Publish(name string, cache StatsSource) *Var {
+	v := NewVar(cache)
+	caches.Set(name, v)
+	return v
+}
+
+// Var is an expvar variable reporting the statistics of a cache.
+type Var struct {
+	cache StatsSource
+	now   func() time.Time
+
+	mu            sync.Mutex
+	lastEvictions uint64
+	lastTime      time.Time
+}
+
+var _ expvar.Var = (*Var)(nil)
+
+// NewVar creates the variable reporting the statistics of the cache, it is
+// not published.
+func NewVar(cache StatsSource) *Var {
+	return &Var{
+		cache:    cache,
+		now:      time.Now,
+		lastTime: time.Now(),
+	}
+}
+
+// snapshot is the JSON representation of the variable.
+type snapshot struct {
+	Hits               uint64  `json:"hits"`
+	Misses             uint64  `json:"misses"`
+	Puts               uint64  `json:"puts"`
+	Evictions          uint64  `json:"evictions"`
+	Size               int     `json:"size"`
+	HitRatio           float64 `json:"hit_ratio"`
+	EvictionsPerSecond float64 `json:"evictions_per_second"`
+}
+
+// String returns the statistics as a JSON object. The eviction rate is
+// computed over the time passed since the previous call.
+func (v *Var) String() string {
+	stats := v.cache.Stats()
+
+	v.mu.Lock()
+	now := v.now()
+	var evictionsPerSecond float64
+	if elapsed := now.Sub(v.lastTime).Seconds(); elapsed > 0 && stats.Evictions >= v.lastEvictions {
+		evictionsPerSecond = float64(stats.Evictions-v.lastEvictions) / elapsed
+	}
+	v.lastEvictions = stats.Evictions
+	v.lastTime = now
+	v.mu.Unlock()
+
+	// Marshalling of the plain struct cannot fail.
+	data, _ := json.Marshal(snapshot{
+		Hits:               stats.Hits,
+		Misses:             stats.Misses,
+		Puts:               stats.Puts,
+		Evictions:          stats.Evictions,
+		Size:               stats.Size,
+		HitRatio:           stats.HitRatio(),
+		EvictionsPerSecond: evictionsPerSecond,
+	})
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package cachemetrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"lfucache/internal/lfu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVar(t *testing.T) {
+	t.Parallel()
+
+	cache := lfu.New[int, int](1)
+	v := NewVar(cache)
+
+	now := time.Unix(0, 0)
+	v.now = func() time.Time { return now }
+	v.lastTime = now
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	_, _ = cache.Get(3)
+	_, _ = cache.Get(1)
+
+	now = now.Add(2 * time.Second)
+	var got snapshot
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &got))
+	require.Equal(t, snapshot{
+		Hits:               1,
+		Misses:             1,
+		Puts:               3,
+		Evictions:          2,
+		Size:               1,
+		HitRatio:           0.5,
+		EvictionsPerSecond: 1,
+	}, got)
+
+	now = now.Add(time.Second)
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &got))
+	require.Zero(t, got.EvictionsPerSecond)
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	cache := lfu.NewSharded[string, int](10)
+	cache.Put("a", 1)
+
+	Publish("books", cache)
+
+	published := expvar.Get("lfucache").(*expvar.Map).Get("books")
+	require.NotNil(t, published)
+
+	var got snapshot
+	require.NoError(t, json.Unmarshal([]byte(published.String()), &got))
+	require.Equal(t, 1, got.Size)
+	require.Equal(t, uint64(1), got.Puts)
+}
@@ -0,0 +1,210 @@
+// Package compat adapts the LFU cache to the interfaces of popular
+// third-party caches, so that code written against them can switch to this
+// package by changing the constructor only.
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"lfucache/internal/lfu"
+)
+
+// ErrInvalidType is returned by GCache when a key or a value is not of the
+// type of the cache.
+var ErrInvalidType = errors.New("invalid type")
+
+// LRU adapts the cache to the interface of hashicorp/golang-lru/v2. The
+// "oldest" key is the one which would be invalidated next.
+type LRU[K comparable, V any] struct {
+	cache lfu.Cache[K, V]
+}
+
+// NewLRU wraps the cache.
+func NewLRU[K comparable, V any](cache lfu.Cache[K, V]) *LRU[K, V] {
+	return &LRU[K, V]{cache: cache}
+}
+
+// Add puts the value of the key and reports whether a key has been
+// invalidated for it.
+func (c *LRU[K, V]) Add(key K, value V) (evicted bool) {
+	evictions := c.cache.Stats().Evictions
+	c.cache.Put(key, value)
+	return c.cache.Stats().Evictions != evictions
+}
+
+// Get returns the value of the key and reports whether the key exists.
+func (c *LRU[K, V]) Get(key K) (value V, ok bool) {
+	value, err := c.cache.Get(key)
+	return value, err == nil
+}
+
+// Contains reports whether the key exists without using it.
+func (c *LRU[K, V]) Contains(key K) bool {
+	return c.cache.Contains(key)
+}
+
+// Peek returns the value of the key without using it and reports whether the
+// key exists.
+func (c *LRU[K, V]) Peek(key K) (value V, ok bool) {
+	value, err := c.cache.Peek(key)
+	return value, err == nil
+}
+
+// ContainsOrAdd reports whether the key exists and puts the value otherwise,
+// evicted reports whether a key has been invalidated for it.
+func (c *LRU[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	if c.cache.Contains(key) {
+		return true, false
+	}
+	return false, c.Add(key, value)
+}
+
+// Remove deletes the key and reports whether the key was present.
+func (c *LRU[K, V]) Remove(key K) (present bool) {
+	return c.cache.Remove(key)
+}
+
+// Purge removes all keys.
+func (c *LRU[K, V]) Purge() {
+	c.cache.Clear()
+}
+
+// Keys returns the keys from the oldest to the newest.
+func (c *LRU[K, V]) Keys() []K {
+	keys := make([]K, 0, c.cache.Size())
+	for key := range c.cache.All() {
+		keys = append(keys, key)
+	}
+	slices.Reverse(keys)
+	return keys
+}
+
+// Values returns the values in the order of Keys.
+func (c *LRU[K, V]) Values() []V {
+	values := make([]V, 0, c.cache.Size())
+	for _, value := range c.cache.All() {
+		values = append(values, value)
+	}
+	slices.Reverse(values)
+	return values
+}
+
+// Len returns the number of keys.
+func (c *LRU[K, V]) Len() int {
+	return c.cache.Size()
+}
+
+// Cap returns the capacity.
+func (c *LRU[K, V]) Cap() int {
+	return c.cache.Capacity()
+}
+
+// Resize changes the capacity and returns the number of invalidated keys.
+func (c *LRU[K, V]) Resize(size int) (evicted int) {
+	before := c.cache.Size()
+	c.cache.Resize(size)
+	return before - c.cache.Size()
+}
+
+// GCache adapts the cache to the interface of bluele/gcache, which keys and
+// values are untyped.
+type GCache[K comparable, V any] struct {
+	cache lfu.Cache[K, V]
+}
+
+// NewGCache wraps the cache.
+func NewGCache[K comparable, V any](cache lfu.Cache[K, V]) *GCache[K, V] {
+	return &GCache[K, V]{cache: cache}
+}
+
+func typed[T any](v any) (T, error) {
+	typed, ok := v.(T)
+	if !ok {
+		return typed, fmt.Errorf("%w: %T", ErrInvalidType, v)
+	}
+	return typed, nil
+}
+
+// Set puts the value of the key.
+func (c *GCache[K, V]) Set(key, value any) error {
+	k, err := typed[K](key)
+	if err != nil {
+		return err
+	}
+	v, err := typed[V](value)
+	if err != nil {
+		return err
+	}
+	c.cache.Put(k, v)
+	return nil
+}
+
+// Get returns the value of the key or lfu.ErrKeyNotFound.
+func (c *GCache[K, V]) Get(key any) (any, error) {
+	k, err := typed[K](key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := c.cache.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// GetIFPresent returns the value of the key as Get does.
+func (c *GCache[K, V]) GetIFPresent(key any) (any, error) {
+	return c.Get(key)
+}
+
+// Has reports whether the key exists without using it.
+func (c *GCache[K, V]) Has(key any) bool {
+	k, err := typed[K](key)
+	return err == nil && c.cache.Contains(k)
+}
+
+// Remove deletes the key and reports whether the key was present.
+func (c *GCache[K, V]) Remove(key any) bool {
+	k, err := typed[K](key)
+	return err == nil && c.cache.Remove(k)
+}
+
+// Purge removes all keys.
+func (c *GCache[K, V]) Purge() {
+	c.cache.Clear()
+}
+
+// GetALL returns the keys with their values. Expired keys are never
+// returned, so checkExpired has no effect.
+func (c *GCache[K, V]) GetALL(checkExpired bool) map[any]any {
+	all := make(map[any]any, c.cache.Size())
+	for key, value := range c.cache.All() {
+		all[key] = value
+	}
+	return all
+}
+
+// Keys returns the keys in the order of All. Expired keys are never
+// returned, so checkExpired has no effect.
+func (c *GCache[K, V]) Keys(checkExpired bool) []any {
+	keys := make([]any, 0, c.cache.Size())
+	for key := range c.cache.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Len returns the number of keys. Expired keys which have not been removed
+// yet are counted unless checkExpired is set.
+func (c *GCache[K, V]) Len(checkExpired bool) int {
+	if !checkExpired {
+		return c.cache.Size()
+	}
+	length := 0
+	for range c.cache.All() {
+		length++
+	}
+	return length
+}
@@ -0,0 +1,102 @@
+package compat
+
+import (
+	"testing"
+
+	"lfucache/internal/lfu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lruCache is the part of the interface of hashicorp/golang-lru/v2 provided
+// by LRU.
+type lruCache[K comparable, V any] interface {
+	Add(key K, value V) bool
+	Get(key K) (V, bool)
+	Contains(key K) bool
+	Peek(key K) (V, bool)
+	ContainsOrAdd(key K, value V) (bool, bool)
+	Remove(key K) bool
+	Purge()
+	Keys() []K
+	Values() []V
+	Len() int
+	Cap() int
+	Resize(size int) int
+}
+
+// gCache is the part of the interface of bluele/gcache provided by GCache.
+type gCache interface {
+	Set(key, value any) error
+	Get(key any) (any, error)
+	GetIFPresent(key any) (any, error)
+	GetALL(checkExpired bool) map[any]any
+	Remove(key any) bool
+	Purge()
+	Keys(checkExpired bool) []any
+	Len(checkExpired bool) int
+	Has(key any) bool
+}
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	var c lruCache[int, string] = NewLRU[int, string](lfu.New[int, string](2))
+
+	require.False(t, c.Add(1, "a"))
+	require.False(t, c.Add(2, "b"))
+	value, ok := c.Get(1)
+	require.True(t, ok)
+	require.Equal(t, "a", value)
+	require.Equal(t, []int{2, 1}, c.Keys())
+	require.Equal(t, []string{"b", "a"}, c.Values())
+
+	ok, evicted := c.ContainsOrAdd(1, "c")
+	require.True(t, ok)
+	require.False(t, evicted)
+	ok, evicted = c.ContainsOrAdd(3, "c")
+	require.False(t, ok)
+	require.True(t, evicted)
+	require.False(t, c.Contains(2))
+
+	_, ok = c.Peek(2)
+	require.False(t, ok)
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, 1, c.Resize(1))
+	require.Equal(t, 1, c.Cap())
+	require.True(t, c.Remove(1))
+	require.False(t, c.Remove(1))
+	c.Add(4, "d")
+	c.Purge()
+	require.Zero(t, c.Len())
+}
+
+func TestGCache(t *testing.T) {
+	t.Parallel()
+
+	var c gCache = NewGCache[string, int](lfu.NewSharded[string, int](4, 2))
+
+	require.NoError(t, c.Set("a", 1))
+	require.ErrorIs(t, c.Set(1, 1), ErrInvalidType)
+	require.ErrorIs(t, c.Set("b", "b"), ErrInvalidType)
+
+	value, err := c.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	_, err = c.GetIFPresent("b")
+	require.ErrorIs(t, err, lfu.ErrKeyNotFound)
+	_, err = c.Get(1)
+	require.ErrorIs(t, err, ErrInvalidType)
+
+	require.NoError(t, c.Set("b", 2))
+	require.True(t, c.Has("b"))
+	require.False(t, c.Has(2))
+	require.Equal(t, map[any]any{"a": 1, "b": 2}, c.GetALL(true))
+	require.Equal(t, []any{"a", "b"}, c.Keys(false))
+	require.Equal(t, 2, c.Len(true))
+
+	require.True(t, c.Remove("a"))
+	require.False(t, c.Remove(1))
+	c.Purge()
+	require.Zero(t, c.Len(false))
+}
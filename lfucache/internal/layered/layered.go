@@ -0,0 +1,132 @@
+// Package layered composes two caches into one: a small in-process front,
+// usually the LFU cache, and a larger remote back, e.g. Redis, shared by the
+// processes.
+package layered
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"lfucache/internal/cache"
+)
+
+// LevelStats is a snapshot of the statistics of one level.
+type LevelStats struct {
+	// Hits is the number of lookups which have found the key on the level.
+	Hits uint64
+	// Misses is the number of lookups which have not found the key on the
+	// level.
+	Misses uint64
+	// Errors is the number of lookups which have failed on the level.
+	Errors uint64
+}
+
+// HitRatio returns the share of lookups which have found the key, or 0 if
+// there have been no lookups.
+func (s LevelStats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses + s.Errors
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// Stats is a snapshot of the statistics of both levels. The back is looked up
+// only on the misses of the front.
+type Stats struct {
+	Front LevelStats
+	Back  LevelStats
+}
+
+type levelCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+func (c *levelCounters) count(err error) {
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+	case errors.Is(err, cache.ErrKeyNotFound):
+		c.misses.Add(1)
+	default:
+		c.errors.Add(1)
+	}
+}
+
+func (c *levelCounters) stats() LevelStats {
+	return LevelStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Errors: c.errors.Load(),
+	}
+}
+
+// Cache is the two-level cache. Get looks the key up in the front and then in
+// the back, promoting the values found in the back to the front. Put writes
+// through to both levels.
+//
+// The cache is safe for concurrent use if both levels are.
+type Cache[K comparable, V any] struct {
+	front cache.Cache[K, V]
+	back  cache.Cache[K, V]
+
+	frontCounters levelCounters
+	backCounters  levelCounters
+}
+
+var _ cache.Cache[string, int] = (*Cache[string, int])(nil)
+
+// New composes the front and the back into the two-level cache.
+func New[K comparable, V any](front, back cache.Cache[K, V]) *Cache[K, V] {
+	return &Cache[K, V]{front: front, back: back}
+}
+
+// Get returns the value of the key from the front or, if the front misses it,
+// from the back, putting the value into the front. It returns
+// cache.ErrKeyNotFound if neither level has the key, or the error of the back
+// as is.
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	value, err := c.front.Get(key)
+	c.frontCounters.count(err)
+	if err == nil {
+		return value, nil
+	}
+
+	value, err = c.back.Get(key)
+	c.backCounters.count(err)
+	if err != nil {
+		return value, err
+	}
+	c.front.Put(key, value)
+	return value, nil
+}
+
+// Put puts the value of the key into both levels.
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.front.Put(key, value)
+	c.back.Put(key, value)
+}
+
+// Remove deletes the key from both levels and reports whether any of them had
+// the key.
+func (c *Cache[K, V]) Remove(key K) bool {
+	removedFront := c.front.Remove(key)
+	removedBack := c.back.Remove(key)
+	return removedFront || removedBack
+}
+
+// Len returns the number of keys in the back, which holds every key of the
+// front unless the back has dropped it.
+func (c *Cache[K, V]) Len() int {
+	return c.back.Len()
+}
+
+// Stats returns the snapshot of the statistics of both levels.
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Front: c.frontCounters.stats(),
+		Back:  c.backCounters.stats(),
+	}
+}
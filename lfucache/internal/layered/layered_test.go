@@ -0,0 +1,69 @@
+package layered
+
+import (
+	"errors"
+	"testing"
+
+	"lfucache/internal/cache"
+	"lfucache/internal/lfu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayered(t *testing.T) {
+	t.Parallel()
+
+	front := lfu.New[int, string](2)
+	back := lfu.New[int, string](10)
+	c := New[int, string](front, back)
+
+	for i := range 5 {
+		c.Put(i, "value")
+	}
+	require.Equal(t, 2, front.Size())
+	require.Equal(t, 5, c.Len())
+
+	// Key 4 is in the front, key 0 has been invalidated in it.
+	_, err := c.Get(4)
+	require.NoError(t, err)
+	_, err = c.Get(0)
+	require.NoError(t, err)
+	require.True(t, front.Contains(0))
+
+	_, err = c.Get(5)
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+	require.Equal(t, Stats{
+		Front: LevelStats{Hits: 1, Misses: 2},
+		Back:  LevelStats{Hits: 1, Misses: 1},
+	}, c.Stats())
+	require.InDelta(t, 1.0/3, c.Stats().Front.HitRatio(), 1e-9)
+
+	require.True(t, c.Remove(0))
+	require.False(t, front.Contains(0))
+	require.False(t, back.Contains(0))
+	require.False(t, c.Remove(0))
+	require.True(t, c.Remove(1))
+}
+
+type failingCache struct {
+	cache.Cache[int, string]
+	err error
+}
+
+func (f failingCache) Get(int) (string, error) {
+	return "", f.err
+}
+
+func TestBackError(t *testing.T) {
+	t.Parallel()
+
+	backErr := errors.New("connection refused")
+	front := lfu.New[int, string](2)
+	c := New[int, string](front, failingCache{err: backErr})
+
+	_, err := c.Get(1)
+	require.ErrorIs(t, err, backErr)
+	require.Zero(t, front.Size())
+	require.Equal(t, LevelStats{Errors: 1}, c.Stats().Back)
+}
@@ -0,0 +1,52 @@
+package lfu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmission(t *testing.T) {
+	t.Parallel()
+
+	var evicted []string
+	cache := NewWithOptions(2,
+		WithAdmission(func(key string, _ int) bool { return !strings.HasPrefix(key, "tmp/") }),
+		WithOnEvict(func(key string, _ int, _ EvictionReason) { evicted = append(evicted, key) }),
+	)
+
+	// The cache which is not full admits any key.
+	cache.Put("tmp/a", 1)
+	cache.Put("b", 2)
+	cache.Put("tmp/c", 3)
+	require.False(t, cache.Contains("tmp/c"))
+	require.Empty(t, evicted)
+
+	// Present keys are updated regardless of the admission.
+	cache.Put("tmp/a", 4)
+	value, err := cache.Peek("tmp/a")
+	require.NoError(t, err)
+	require.Equal(t, 4, value)
+
+	cache.Put("d", 5)
+	require.True(t, cache.Contains("d"))
+	require.Equal(t, []string{"b"}, evicted)
+}
+
+func TestAdmissionWeighted(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(10,
+		WithWeigher(func(_ int, value int) int { return value }),
+		WithAdmission(func(_ int, value int) bool { return value < 5 }),
+	)
+	cache.Put(1, 4)
+	cache.Put(2, 5)
+	require.Equal(t, 2, cache.Size())
+
+	require.Equal(t, PutRejected, cache.PutWithCost(3, 6, 6))
+	require.Equal(t, PutAdmittedWithEvictions, cache.PutWithCost(4, 3, 3))
+	require.False(t, cache.Contains(1))
+	require.True(t, cache.Contains(4))
+}
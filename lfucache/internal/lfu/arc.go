@@ -0,0 +1,346 @@
+package lfu
+
+import (
+	"iter"
+	"lfucache/internal/linkedlist"
+)
+
+// arcListKind tells which of the ARC lists the entry belongs to.
+type arcListKind int
+
+const (
+	// arcT1 holds resident keys seen once recently.
+	arcT1 arcListKind = iota
+	// arcT2 holds resident keys seen at least twice recently.
+	arcT2
+	// arcB1 holds keys recently invalidated from T1, without values.
+	arcB1
+	// arcB2 holds keys recently invalidated from T2, without values.
+	arcB2
+)
+
+// arcEntry is the entry of one of the ARC lists.
+type arcEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// frequency of usage of the key while it is resident
+	frequency int
+	// kind of the list the entry belongs to
+	kind arcListKind
+}
+
+// arcList is a doubly linked list of ARC entries.
+type arcList[K comparable, V any] struct {
+	list linkedlist.LinkedList[arcEntry[K, V]]
+}
+
+func newARCList[K comparable, V any]() arcList[K, V] {
+	return arcList[K, V]{list: linkedlist.NewEmpty[arcEntry[K, V]]()}
+}
+
+func (l *arcList[K, V]) pushFront(node *linkedlist.Node[arcEntry[K, V]]) {
+	l.list.PushFront(node)
+}
+
+func (l *arcList[K, V]) remove(node *linkedlist.Node[arcEntry[K, V]]) {
+	linkedlist.RemoveNode(node)
+}
+
+// len returns the number of entries of the list.
+func (l *arcList[K, V]) len() int {
+	return l.list.Len()
+}
+
+// back returns the least recently used entry of the list.
+func (l *arcList[K, V]) back() *linkedlist.Node[arcEntry[K, V]] {
+	return l.list.Last()
+}
+
+// arcCacheImpl is the Adaptive Replacement Cache. It splits the capacity
+// between the keys seen once (T1) and the keys seen at least twice (T2)
+// recently, and adapts the target size of T1 using the ghost lists B1 and B2
+// of keys recently invalidated from T1 and T2: a hit in B1 means T1 is too
+// small, a hit in B2 means T2 is. Unlike LFU, heavy hitters which are no
+// longer used eventually leave T2.
+type arcCacheImpl[K comparable, V any] struct {
+	// keyToEntry maps each key, resident or ghost, to its entry.
+	keyToEntry map[K]*linkedlist.Node[arcEntry[K, V]]
+	// lists serve T1, T2, B1 and B2 indexed by arcListKind.
+	lists [4]arcList[K, V]
+	// target serves the target size of T1.
+	target int
+	// capacity serves the cache capacity.
+	capacity int
+	// stats serves the cache statistics, except for the size.
+	stats Stats
+}
+
+// NewARC initializes the Adaptive Replacement Cache with the given capacity.
+// It implements the same interface as the LFU cache: the frequency of a key
+// is the number of its uses since it has become resident, and All lists the
+// keys of T2 before the keys of T1, the most recently used first.
+func NewARC[K comparable, V any](capacity int) *arcCacheImpl[K, V] {
+	// Capacity cannot be negative.
+	if capacity < 0 {
+		panic("Invalid capacity")
+	}
+	c := &arcCacheImpl[K, V]{
+		// Ghost lists may hold as many keys as the resident ones.
+		keyToEntry: make(map[K]*linkedlist.Node[arcEntry[K, V]], 2*capacity),
+		capacity:   capacity,
+	}
+	for i := range c.lists {
+		c.lists[i] = newARCList[K, V]()
+	}
+	return c
+}
+
+// resident returns the resident entry of the key.
+func (c *arcCacheImpl[K, V]) resident(key K) (*linkedlist.Node[arcEntry[K, V]], bool) {
+	node, ok := c.keyToEntry[key]
+	if !ok || node.Value.kind > arcT2 {
+		return nil, false
+	}
+	return node, true
+}
+
+// moveTo makes the entry the most recently used one of the list.
+func (c *arcCacheImpl[K, V]) moveTo(node *linkedlist.Node[arcEntry[K, V]], kind arcListKind) {
+	c.lists[node.Value.kind].remove(node)
+	node.Value.kind = kind
+	c.lists[kind].pushFront(node)
+}
+
+// hit moves the resident entry to T2 and increases its frequency.
+func (c *arcCacheImpl[K, V]) hit(node *linkedlist.Node[arcEntry[K, V]]) {
+	node.Value.frequency++
+	c.moveTo(node, arcT2)
+}
+
+func (c *arcCacheImpl[K, V]) Get(key K) (V, error) {
+	if node, ok := c.resident(key); ok {
+		c.hit(node)
+		c.stats.Hits++
+		return node.Value.value, nil
+	}
+	c.stats.Misses++
+	var value V
+	return value, ErrKeyNotFound
+}
+
+func (c *arcCacheImpl[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	if value, err := c.Get(key); err == nil {
+		return value, nil
+	}
+	value, err := compute()
+	if err != nil {
+		return value, err
+	}
+	c.Put(key, value)
+	return value, nil
+}
+
+func (c *arcCacheImpl[K, V]) Contains(key K) bool {
+	_, ok := c.resident(key)
+	return ok
+}
+
+func (c *arcCacheImpl[K, V]) Peek(key K) (V, error) {
+	if node, ok := c.resident(key); ok {
+		return node.Value.value, nil
+	}
+	var value V
+	return value, ErrKeyNotFound
+}
+
+func (c *arcCacheImpl[K, V]) Put(key K, value V) {
+	c.stats.Puts++
+	if c.capacity == 0 {
+		return
+	}
+	t1, b1, b2 := &c.lists[arcT1], &c.lists[arcB1], &c.lists[arcB2]
+
+	node, ok := c.keyToEntry[key]
+	if ok {
+		switch node.Value.kind {
+		case arcT1, arcT2:
+			node.Value.value = value
+			c.hit(node)
+			return
+		case arcB1:
+			// T1 would have kept the key if it had been larger.
+			c.target = min(c.capacity, c.target+max(b2.len()/b1.len(), 1))
+			c.replace(false)
+		case arcB2:
+			// T2 would have kept the key if it had been larger.
+			c.target = max(0, c.target-max(b1.len()/b2.len(), 1))
+			c.replace(true)
+		}
+		node.Value.value = value
+		node.Value.frequency = 1
+		c.moveTo(node, arcT2)
+		return
+	}
+
+	if t1.len()+b1.len() == c.capacity {
+		if t1.len() < c.capacity {
+			c.dropGhost(arcB1)
+			c.replace(false)
+		} else {
+			c.evict(t1.back())
+		}
+	} else if c.residents()+b1.len()+b2.len() >= c.capacity {
+		if c.residents()+b1.len()+b2.len() == 2*c.capacity {
+			c.dropGhost(arcB2)
+		}
+		c.replace(false)
+	}
+
+	node = linkedlist.NewNode(arcEntry[K, V]{
+		key:       key,
+		value:     value,
+		frequency: 1,
+		kind:      arcT1,
+	})
+	c.lists[arcT1].pushFront(node)
+	c.keyToEntry[key] = node
+}
+
+// residents returns the number of resident keys.
+func (c *arcCacheImpl[K, V]) residents() int {
+	return c.lists[arcT1].len() + c.lists[arcT2].len()
+}
+
+// replace invalidates the least recently used key of T1 or T2 depending on
+// the target size of T1 if there is no room for a new key, the key is
+// remembered in the corresponding ghost list.
+func (c *arcCacheImpl[K, V]) replace(hitInB2 bool) {
+	if c.residents() < c.capacity {
+		return
+	}
+	t1 := &c.lists[arcT1]
+	if t1.len() != 0 && (t1.len() > c.target || (hitInB2 && t1.len() == c.target) || c.lists[arcT2].len() == 0) {
+		node := t1.back()
+		c.evicted(node)
+		c.moveTo(node, arcB1)
+	} else {
+		node := c.lists[arcT2].back()
+		c.evicted(node)
+		c.moveTo(node, arcB2)
+	}
+}
+
+// evict invalidates the resident key without remembering it.
+func (c *arcCacheImpl[K, V]) evict(node *linkedlist.Node[arcEntry[K, V]]) {
+	c.evicted(node)
+	c.lists[node.Value.kind].remove(node)
+	delete(c.keyToEntry, node.Value.key)
+}
+
+// evicted counts the invalidated resident entry and forgets its value.
+func (c *arcCacheImpl[K, V]) evicted(node *linkedlist.Node[arcEntry[K, V]]) {
+	c.stats.Evictions++
+	var value V
+	node.Value.value = value
+	node.Value.frequency = 0
+}
+
+// dropGhost forgets the least recently used key of the ghost list.
+func (c *arcCacheImpl[K, V]) dropGhost(kind arcListKind) {
+	list := &c.lists[kind]
+	if list.len() == 0 {
+		return
+	}
+	node := list.back()
+	list.remove(node)
+	delete(c.keyToEntry, node.Value.key)
+}
+
+func (c *arcCacheImpl[K, V]) Remove(key K) bool {
+	node, ok := c.keyToEntry[key]
+	if !ok {
+		return false
+	}
+	c.lists[node.Value.kind].remove(node)
+	delete(c.keyToEntry, key)
+	return node.Value.kind <= arcT2
+}
+
+func (c *arcCacheImpl[K, V]) Clear() {
+	clear(c.keyToEntry)
+	for i := range c.lists {
+		c.lists[i] = newARCList[K, V]()
+	}
+	c.target = 0
+}
+
+func (c *arcCacheImpl[K, V]) Resize(newCapacity int) {
+	// Capacity cannot be negative.
+	if newCapacity < 0 {
+		panic("Invalid capacity")
+	}
+	c.capacity = newCapacity
+	c.target = min(c.target, newCapacity)
+	for c.residents() > c.capacity {
+		c.replace(false)
+	}
+	// Restore the invariants of the ghost lists.
+	for c.lists[arcT1].len()+c.lists[arcB1].len() > c.capacity {
+		c.dropGhost(arcB1)
+	}
+	for c.residents()+c.lists[arcB1].len()+c.lists[arcB2].len() > 2*c.capacity {
+		c.dropGhost(arcB2)
+	}
+}
+
+func (c *arcCacheImpl[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := range c.Entries() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (c *arcCacheImpl[K, V]) Entries() iter.Seq[Entry[K, V]] {
+	return func(yield func(Entry[K, V]) bool) {
+		for _, kind := range []arcListKind{arcT2, arcT1} {
+			for entry := range c.lists[kind].list.All() {
+				if !yield(Entry[K, V]{
+					Key:       entry.key,
+					Value:     entry.value,
+					Frequency: entry.frequency,
+				}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *arcCacheImpl[K, V]) Size() int {
+	return c.residents()
+}
+
+// Len returns the cache size, as required by cache.Cache.
+func (c *arcCacheImpl[K, V]) Len() int {
+	return c.residents()
+}
+
+func (c *arcCacheImpl[K, V]) Capacity() int {
+	return c.capacity
+}
+
+func (c *arcCacheImpl[K, V]) Stats() Stats {
+	stats := c.stats
+	stats.Size = c.residents()
+	return stats
+}
+
+func (c *arcCacheImpl[K, V]) GetKeyFrequency(key K) (int, error) {
+	if node, ok := c.resident(key); ok {
+		return node.Value.frequency, nil
+	}
+	return 0, ErrKeyNotFound
+}
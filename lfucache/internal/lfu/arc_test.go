@@ -0,0 +1,193 @@
+package lfu
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// must compile
+func testARCImplements[K comparable, V any]() Cache[K, V] {
+	return NewARC[K, V](1)
+}
+
+func TestARCWithoutInvalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](10)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i*i)
+	}
+	for i := 0; i < 10; i++ {
+		value, err := cache.Get(i)
+		require.NoError(t, err)
+		require.Equal(t, i*i, value)
+	}
+	require.Equal(t, 10, cache.Size())
+}
+
+func TestARCScanResistance(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](4)
+
+	// The keys used twice move to T2.
+	for _, key := range []int{1, 2} {
+		cache.Put(key, key)
+		_, err := cache.Get(key)
+		require.NoError(t, err)
+	}
+
+	// A scan of keys seen once only replaces keys of T1.
+	for key := 100; key < 120; key++ {
+		cache.Put(key, key)
+	}
+
+	require.True(t, cache.Contains(1))
+	require.True(t, cache.Contains(2))
+	require.Equal(t, 4, cache.Size())
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{2, 1, 119, 118}, keys)
+}
+
+func TestARCStaleHeavyHitters(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](2)
+
+	// Heavy hitter of the past.
+	cache.Put(1, 1)
+	for i := 0; i < 100; i++ {
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+	}
+
+	// The new working set is used repeatedly, so it pushes the stale key out
+	// of T2, which LFU would keep forever.
+	for i := 0; i < 10; i++ {
+		for _, key := range []int{2, 3} {
+			if _, err := cache.Get(key); err != nil {
+				cache.Put(key, key)
+			}
+		}
+	}
+
+	require.False(t, cache.Contains(1))
+	require.True(t, cache.Contains(2))
+	require.True(t, cache.Contains(3))
+}
+
+func TestARCGhostHitAdaptsTarget(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](2)
+
+	cache.Put(1, 1)
+	_, err := cache.Get(1)
+	require.NoError(t, err)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	require.False(t, cache.Contains(2))
+
+	// 2 is in B1, so the target size of T1 grows and T2 gives up its key.
+	cache.Put(2, 20)
+	require.Equal(t, 1, cache.target)
+	require.False(t, cache.Contains(1))
+	value, err := cache.Peek(2)
+	require.NoError(t, err)
+	require.Equal(t, 20, value)
+	require.Equal(t, 2, cache.Size())
+
+	// 1 is in B2, so the target size of T1 shrinks back.
+	cache.Put(1, 10)
+	require.Zero(t, cache.target)
+	require.True(t, cache.Contains(1))
+}
+
+func TestARCFrequency(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](2)
+
+	cache.Put(1, 1)
+	_, err := cache.Get(1)
+	require.NoError(t, err)
+	cache.Put(1, 1)
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 3, frequency)
+
+	_, err = cache.GetKeyFrequency(2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestARCRemoveClearResize(t *testing.T) {
+	t.Parallel()
+
+	cache := NewARC[int, int](4)
+
+	for i := 0; i < 6; i++ {
+		cache.Put(i, i)
+	}
+	require.False(t, cache.Remove(0))
+	require.True(t, cache.Remove(5))
+	require.False(t, cache.Remove(5))
+	require.Equal(t, 3, cache.Size())
+
+	cache.Put(6, 6)
+	cache.Put(7, 7)
+	require.Equal(t, 4, cache.Size())
+
+	cache.Resize(2)
+	require.Equal(t, 2, cache.Size())
+	require.Equal(t, 2, cache.Capacity())
+	require.True(t, cache.Contains(7))
+
+	cache.Clear()
+	require.Zero(t, cache.Size())
+	cache.Put(1, 1)
+	require.True(t, cache.Contains(1))
+
+	cache.Resize(0)
+	cache.Put(2, 2)
+	require.Zero(t, cache.Size())
+}
+
+func TestARCInvariants(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 16
+
+	cache := NewARC[int, int](capacity)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 100_000; i++ {
+		key := r.IntN(64)
+		switch r.IntN(10) {
+		case 0:
+			cache.Remove(key)
+		case 1, 2, 3:
+			cache.Put(key, key)
+		default:
+			if value, err := cache.Get(key); err == nil {
+				require.Equal(t, key, value)
+			}
+		}
+		if i%1000 == 0 {
+			cache.Resize(capacity/2 + r.IntN(capacity))
+		}
+
+		t1, t2 := cache.lists[arcT1].len(), cache.lists[arcT2].len()
+		b1, b2 := cache.lists[arcB1].len(), cache.lists[arcB2].len()
+		require.LessOrEqual(t, t1+t2, cache.capacity)
+		require.LessOrEqual(t, t1+b1, cache.capacity)
+		require.LessOrEqual(t, t1+t2+b1+b2, 2*cache.capacity)
+		require.Len(t, cache.keyToEntry, t1+t2+b1+b2)
+		require.GreaterOrEqual(t, cache.target, 0)
+		require.LessOrEqual(t, cache.target, cache.capacity)
+	}
+}
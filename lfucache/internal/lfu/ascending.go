@@ -0,0 +1,64 @@
+package lfu
+
+import "iter"
+
+// AllAscending returns the iterator over the cache in ascending order of
+// frequency, the least recently used items of a frequency go first. That is
+// the order in which the items would be invalidated.
+func (l *cacheImpl[K, V]) AllAscending() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for cacheItem := range l.itemsAscending() {
+			if !yield(cacheItem.key, cacheItem.value) {
+				return
+			}
+		}
+	}
+}
+
+// itemsAscending iterates over cache items in the reverse order of items.
+func (l *cacheImpl[K, V]) itemsAscending() iter.Seq[CacheItem[K, V]] {
+	return func(yield func(CacheItem[K, V]) bool) {
+		if l.size == 0 {
+			return
+		}
+		now := l.expirationTime()
+		for freqGroup := range l.freqGroupsList.Backward() {
+			for cacheItem := range freqGroup.elementsList.Backward() {
+				if cacheItem.expiresAt != 0 && cacheItem.expiresAt <= now {
+					continue
+				}
+				if !yield(cacheItem) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllAscending returns the iterator over a snapshot of the cache in ascending
+// order of frequency. Keys of the same shard with the same frequency go from
+// the least recently used, ties between shards are broken by the shard order.
+func (c *shardedCacheImpl[K, V]) AllAscending() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		cursors := c.snapshot((*cacheImpl[K, V]).itemsAscending)
+		mergeShardCursors(ascendingShardCursors[K, V]{&cursors}, &cursors, func(item CacheItem[K, V]) bool {
+			return yield(item.key, item.value)
+		})
+	}
+}
+
+// ascendingShardCursors is a min-heap of cursors ordered by frequency of the
+// items they point to.
+type ascendingShardCursors[K comparable, V any] struct {
+	*shardCursors[K, V]
+}
+
+func (h ascendingShardCursors[K, V]) Less(i, j int) bool {
+	cursors := *h.shardCursors
+	a := cursors[i].items[cursors[i].position].frequency
+	b := cursors[j].items[cursors[j].position].frequency
+	if a != b {
+		return a < b
+	}
+	return cursors[i].shard < cursors[j].shard
+}
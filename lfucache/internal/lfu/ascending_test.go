@@ -0,0 +1,70 @@
+package lfu
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllAscending(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+
+	_, values := collect(cache.AllAscending())
+	require.Empty(t, values)
+
+	for i := 0; i < 6; i++ {
+		cache.Put(i, i)
+	}
+	for _, key := range []int{4, 2, 4} {
+		_, err := cache.Get(key)
+		require.NoError(t, err)
+	}
+
+	keys, _ := collect(cache.AllAscending())
+	require.Equal(t, []int{0, 1, 3, 5, 2, 4}, keys)
+
+	descending, _ := collect(cache.All())
+	slices.Reverse(descending)
+	require.Equal(t, descending, keys)
+
+	// The first key is the one to be invalidated next.
+	cache.Resize(5)
+	require.False(t, cache.Contains(0))
+
+	for range cache.AllAscending() {
+		break
+	}
+}
+
+func TestShardedAllAscending(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](400, 8)
+
+	for i := 0; i < 50; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i%7; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	keys, values := collect(cache.AllAscending())
+	require.Len(t, keys, 50)
+	require.Equal(t, keys, values)
+
+	frequencies := make([]int, 0, len(keys))
+	for _, key := range keys {
+		frequency, err := cache.GetKeyFrequency(key)
+		require.NoError(t, err)
+		frequencies = append(frequencies, frequency)
+	}
+	require.True(t, slices.IsSorted(frequencies))
+
+	for range cache.AllAscending() {
+		break
+	}
+}
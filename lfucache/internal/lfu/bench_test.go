@@ -0,0 +1,169 @@
+package lfu
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+const benchmarkKeys = 1 << 16
+
+// benchmarkKeySequence returns the keys requested by the benchmarks, following
+// either the Zipfian or the uniform distribution.
+func benchmarkKeySequence(zipfian bool) []int {
+	random := rand.New(rand.NewSource(42))
+	zipf := rand.NewZipf(random, 1.1, 1, benchmarkKeys-1)
+	keys := make([]int, benchmarkKeys)
+	for i := range keys {
+		if zipfian {
+			keys[i] = int(zipf.Uint64())
+		} else {
+			keys[i] = random.Intn(benchmarkKeys)
+		}
+	}
+	return keys
+}
+
+// benchmarkCases lists the distributions and the shares of reads of the
+// benchmarks.
+var benchmarkCases = []struct {
+	distribution string
+	zipfian      bool
+	readPercent  int
+}{
+	{distribution: "zipfian", zipfian: true, readPercent: 100},
+	{distribution: "zipfian", zipfian: true, readPercent: 90},
+	{distribution: "zipfian", zipfian: true, readPercent: 50},
+	{distribution: "uniform", zipfian: false, readPercent: 100},
+	{distribution: "uniform", zipfian: false, readPercent: 90},
+	{distribution: "uniform", zipfian: false, readPercent: 50},
+}
+
+func BenchmarkCache(b *testing.B) {
+	for _, bc := range benchmarkCases {
+		b.Run(fmt.Sprintf("%s/reads=%d%%", bc.distribution, bc.readPercent), func(b *testing.B) {
+			keys := benchmarkKeySequence(bc.zipfian)
+			cache := New[int, int](benchmarkKeys / 8)
+			for _, key := range keys {
+				cache.Put(key, key)
+			}
+
+			var hits int
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				if i%100 < bc.readPercent {
+					if _, err := cache.Get(key); err == nil {
+						hits++
+					}
+				} else {
+					cache.Put(key, key)
+				}
+			}
+			b.ReportMetric(float64(hits)/float64(b.N), "hits/op")
+		})
+	}
+}
+
+func BenchmarkShardedCacheParallel(b *testing.B) {
+	benchmarkShardedCacheParallel(b)
+}
+
+// BenchmarkShardedCacheParallelBufferedReads is BenchmarkShardedCacheParallel
+// with the lookups taking the lock of the shard for reading only.
+func BenchmarkShardedCacheParallelBufferedReads(b *testing.B) {
+	benchmarkShardedCacheParallel(b, WithBufferedReads[int, int](64))
+}
+
+func benchmarkShardedCacheParallel(b *testing.B, opts ...Option[int, int]) {
+	for _, bc := range benchmarkCases {
+		b.Run(fmt.Sprintf("%s/reads=%d%%", bc.distribution, bc.readPercent), func(b *testing.B) {
+			keys := benchmarkKeySequence(bc.zipfian)
+			cache := NewShardedWithOptions(benchmarkKeys/8, DefaultShards, opts...)
+			for _, key := range keys {
+				cache.Put(key, key)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.Intn(len(keys))
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if i%100 < bc.readPercent {
+						_, _ = cache.Get(key)
+					} else {
+						cache.Put(key, key)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkARC(b *testing.B) {
+	for _, bc := range benchmarkCases {
+		b.Run(fmt.Sprintf("%s/reads=%d%%", bc.distribution, bc.readPercent), func(b *testing.B) {
+			keys := benchmarkKeySequence(bc.zipfian)
+			cache := NewARC[int, int](benchmarkKeys / 8)
+			for _, key := range keys {
+				cache.Put(key, key)
+			}
+
+			var hits int
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				if i%100 < bc.readPercent {
+					if _, err := cache.Get(key); err == nil {
+						hits++
+					}
+				} else {
+					cache.Put(key, key)
+				}
+			}
+			b.ReportMetric(float64(hits)/float64(b.N), "hits/op")
+		})
+	}
+}
+
+// BenchmarkChurn puts new keys only, so that every put invalidates a key, and
+// reports the allocations made per put.
+func BenchmarkChurn(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{name: "free-list"},
+		{name: "node-pool", opts: []Option[int, int]{WithNodePool[int, int]()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			cache := NewWithOptions(benchmarkKeys/8, bc.opts...)
+			for i := range benchmarkKeys / 8 {
+				cache.Put(i, i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Put(benchmarkKeys+i, i)
+			}
+		})
+		b.Run(bc.name+"/sharded", func(b *testing.B) {
+			cache := NewShardedWithOptions(benchmarkKeys/8, DefaultShards, bc.opts...)
+			for i := range benchmarkKeys / 8 {
+				cache.Put(i, i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.Int()
+				for pb.Next() {
+					cache.Put(i, i)
+					i++
+				}
+			})
+		})
+	}
+}
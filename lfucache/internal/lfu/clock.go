@@ -0,0 +1,153 @@
+package lfu
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the cache, it may be replaced with a fake
+// clock to test expiration deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates the timer which sends the current time to its channel
+	// once d has passed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the timer created by Clock.
+type Timer interface {
+	// C returns the channel the time is sent to when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing and reports whether it has been
+	// stopped before firing.
+	Stop() bool
+	// Reset makes the timer fire once d has passed and reports whether it
+	// had been active.
+	Reset(d time.Duration) bool
+}
+
+// WithClock sets the clock of the cache, the system clock is used by default.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.clock = clock
+	}
+}
+
+// realClock is the system clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+// FakeClock is the clock which only moves when it is advanced. It is safe for
+// concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock creates the fake clock showing the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d and fires the timers which are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	active := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			active = append(active, timer)
+			continue
+		}
+		// The channel has room for one value as the one of time.Timer, a
+		// value which has not been received is dropped.
+		select {
+		case timer.c <- c.now:
+		default:
+		}
+	}
+	clear(c.timers[len(active):])
+	c.timers = active
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{
+		clock:    c,
+		c:        make(chan time.Time, 1),
+		deadline: c.now.Add(d),
+	}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// fakeTimer is the timer of FakeClock.
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.remove()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.remove()
+	t.deadline = t.clock.now.Add(d)
+	t.clock.timers = append(t.clock.timers, t)
+	return active
+}
+
+// remove removes the timer from the clock and reports whether it has been
+// active, the clock must be locked.
+func (t *fakeTimer) remove() bool {
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package lfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClockTimer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	clock := NewFakeClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+	stopped := clock.NewTimer(time.Second)
+	require.True(t, stopped.Stop())
+	require.False(t, stopped.Stop())
+
+	clock.Advance(30 * time.Second)
+	require.Empty(t, timer.C())
+	require.Equal(t, start.Add(30*time.Second), clock.Now())
+
+	clock.Advance(30 * time.Second)
+	require.Equal(t, start.Add(time.Minute), <-timer.C())
+	require.False(t, timer.Stop())
+	require.Empty(t, stopped.C())
+
+	require.False(t, timer.Reset(time.Second))
+	require.True(t, timer.Reset(2*time.Second))
+	clock.Advance(2 * time.Second)
+	require.Equal(t, start.Add(time.Minute+2*time.Second), <-timer.C())
+}
+
+func TestRealClock(t *testing.T) {
+	t.Parallel()
+
+	var clock Clock = realClock{}
+	require.WithinDuration(t, time.Now(), clock.Now(), time.Second)
+
+	timer := clock.NewTimer(time.Millisecond)
+	<-timer.C()
+	require.False(t, timer.Stop())
+}
@@ -0,0 +1,90 @@
+package lfu
+
+import (
+	"slices"
+
+	"lfucache/internal/linkedlist"
+)
+
+// Clone returns an independent copy of the cache with the same entries,
+// frequencies, recency order, expiration times, pins and statistics. The copy
+// is configured as the cache is, including the callbacks and the clock, but
+// it has no event stream, so that the events of the copy are not mixed with
+// the events of the cache.
+//
+// O(size)
+func (l *cacheImpl[K, V]) Clone() *cacheImpl[K, V] {
+	clone := &cacheImpl[K, V]{
+		capacity:       l.capacity,
+		policy:         l.policy,
+		tinyLFU:        l.tinyLFU,
+		admission:      l.admission,
+		weigher:        l.weigher,
+		costPolicy:     l.costPolicy,
+		sizer:          l.sizer,
+		nodePool:       l.nodePool,
+		readBufferSize: l.readBufferSize,
+		stats:          l.stats,
+		onEvict:        l.onEvict,
+		onExpire:       l.onExpire,
+		onHit:          l.onHit,
+		onMiss:         l.onMiss,
+		ttl:            l.ttl,
+		sliding:        l.sliding,
+		expiring:       l.expiring,
+		clock:          l.clock,
+
+		freqToFreqGroupNode: make(map[int]*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]], len(l.freqToFreqGroupNode)),
+		keyToCacheItem:      make(map[K]*linkedlist.Node[CacheItem[K, V]], l.size),
+	}
+	if l.sketch != nil {
+		clone.sketch = l.sketch.clone()
+	}
+	if l.size == 0 {
+		return clone
+	}
+	// Expired items are copied as well, they are removed on access.
+	for frequencyGroup := range l.freqGroupsList.All() {
+		for cacheItem := range frequencyGroup.elementsList.All() {
+			cacheItemNode := clone.appendCacheItemNode(cacheItem.key, cacheItem.value, cacheItem.frequency)
+			cacheItemNode.Value.weight = cacheItem.weight
+			cacheItemNode.Value.expiresAt = cacheItem.expiresAt
+			cacheItemNode.Value.slidingTTL = cacheItem.slidingTTL
+			cacheItemNode.Value.pinned = cacheItem.pinned
+		}
+	}
+	clone.weight = l.weight
+	clone.pinned = l.pinned
+	clone.pinnedWeight = l.pinnedWeight
+	return clone
+}
+
+// clone returns an independent copy of the sketch.
+func (s *countMinSketch[K]) clone() *countMinSketch[K] {
+	clone := *s
+	for i := range clone.rows {
+		clone.rows[i] = slices.Clone(s.rows[i])
+	}
+	return &clone
+}
+
+// Clone returns an independent copy of the cache, every shard is copied as
+// the LFU cache is copied by Clone. The shards are locked one after another,
+// so the copy is consistent within a shard only.
+func (c *shardedCacheImpl[K, V]) Clone() *shardedCacheImpl[K, V] {
+	clone := &shardedCacheImpl[K, V]{
+		shards: make([]shard[K, V], len(c.shards)),
+		// The keys of the copy are routed to the same shards.
+		seed: c.seed,
+	}
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		clone.shards[i].cache = s.cache.Clone()
+		s.mu.Unlock()
+		if s.reads != nil {
+			clone.shards[i].reads = newReadBuffer[K](cap(s.reads.keys))
+		}
+	}
+	return clone
+}
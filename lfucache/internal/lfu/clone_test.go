@@ -0,0 +1,67 @@
+package lfu
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(5, WithClock[int, int](clock))
+	for i := range 5 {
+		cache.Put(i, i)
+	}
+	for _, key := range []int{3, 1, 3} {
+		_, err := cache.Get(key)
+		require.NoError(t, err)
+	}
+	cache.PutWithTTL(0, 10, time.Minute)
+
+	clone := cache.Clone()
+	require.Equal(t, slices.Collect(cache.Entries()), slices.Collect(clone.Entries()))
+	require.Equal(t, cache.Stats(), clone.Stats())
+
+	// The copy is independent of the cache.
+	clone.Put(5, 5)
+	require.False(t, cache.Contains(5))
+	require.True(t, cache.Contains(2))
+	require.False(t, clone.Contains(2))
+	_, err := clone.Get(4)
+	require.NoError(t, err)
+	frequency, err := cache.GetKeyFrequency(4)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+
+	// The expiration times are copied.
+	clock.Advance(time.Minute)
+	require.False(t, clone.Contains(0))
+	require.False(t, cache.Contains(0))
+
+	require.Zero(t, New[int, int](2).Clone().Size())
+}
+
+func TestShardedClone(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(100, 4, WithTinyLFU[int, int]())
+	for i := range 50 {
+		cache.Put(i, i)
+		_, err := cache.Get(i % 10)
+		require.NoError(t, err)
+	}
+
+	clone := cache.Clone()
+	require.ElementsMatch(t, slices.Collect(cache.Entries()), slices.Collect(clone.Entries()))
+	for i := range 50 {
+		value, err := clone.Peek(i)
+		require.NoError(t, err)
+		require.Equal(t, i, value)
+	}
+	require.True(t, clone.Remove(1))
+	require.True(t, cache.Contains(1))
+}
@@ -0,0 +1,126 @@
+package lfu
+
+import "lfucache/internal/linkedlist"
+
+// PutDecision tells what PutWithCost has done with the item.
+type PutDecision int
+
+const (
+	// PutAdmitted means the item has been put without invalidating others.
+	PutAdmitted PutDecision = iota
+	// PutAdmittedWithEvictions means the item has been put and the least
+	// frequently used items have been invalidated for it to fit.
+	PutAdmittedWithEvictions
+	// PutRejected means the item has not been put. The old value of the key
+	// is kept unless the item is more costly than the whole budget.
+	PutRejected
+)
+
+func (d PutDecision) String() string {
+	switch d {
+	case PutAdmitted:
+		return "admitted"
+	case PutAdmittedWithEvictions:
+		return "admitted with evictions"
+	case PutRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// CostPolicy decides whether the item of the given cost may invalidate the
+// victims, the least frequently used items in the order of invalidation, to
+// fit into the budget.
+type CostPolicy[K comparable, V any] func(key K, cost int, victims []Entry[K, V]) bool
+
+// WithCostPolicy sets the policy deciding whether an item may invalidate
+// other items to fit into the capacity, which is the budget of the total cost
+// of the items. The cost of an item is computed by the weigher or passed to
+// PutWithCost, if no weigher is set, every item put by Put costs 1.
+func WithCostPolicy[K comparable, V any](policy CostPolicy[K, V]) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.costPolicy = policy
+	}
+}
+
+// MaxVictims is the cost policy which rejects the item if more than n items
+// would be invalidated for it.
+func MaxVictims[K comparable, V any](n int) CostPolicy[K, V] {
+	return func(_ K, _ int, victims []Entry[K, V]) bool {
+		return len(victims) <= n
+	}
+}
+
+// MaxVictimFrequency is the cost policy which rejects the item if any item
+// used more than frequency times would be invalidated for it.
+func MaxVictimFrequency[K comparable, V any](frequency int) CostPolicy[K, V] {
+	return func(_ K, _ int, victims []Entry[K, V]) bool {
+		for _, victim := range victims {
+			if victim.Frequency > frequency {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func unitWeigher[K comparable, V any](K, V) int {
+	return 1
+}
+
+// PutWithCost puts the item of the given cost, regardless of the weigher,
+// and returns the decision made. It panics if the capacity of the cache does
+// not limit the cost, i.e. neither a weigher nor a cost policy is set.
+func (l *cacheImpl[K, V]) PutWithCost(key K, value V, cost int) PutDecision {
+	if l.weigher == nil {
+		panic("Cache without cost budget")
+	}
+	if cost < 0 {
+		panic("Invalid weight")
+	}
+	l.beforePut(key)
+	cacheItemNode, decision := l.putWithCost(key, value, cost)
+	if cacheItemNode != nil {
+		l.setExpiration(cacheItemNode, l.ttl, l.sliding)
+		l.emitPut(cacheItemNode)
+	}
+	return decision
+}
+
+// victims returns the items which would be invalidated, other than skip and
+// the pinned items, for the total weight to fit into the capacity.
+func (l *cacheImpl[K, V]) victims(
+	skip *linkedlist.Node[CacheItem[K, V]],
+	weight int,
+) []Entry[K, V] {
+	var victims []Entry[K, V]
+	frequencyGroupNode := l.freqGroupsList.Last()
+	for range len(l.freqToFreqGroupNode) {
+		cacheItemNode := frequencyGroupNode.Value.elementsList.Last()
+		for range frequencyGroupNode.Value.elementsList.Len() {
+			if weight <= l.capacity {
+				return victims
+			}
+			if cacheItemNode != skip && !cacheItemNode.Value.pinned {
+				weight -= cacheItemNode.Value.weight
+				victims = append(victims, Entry[K, V]{
+					Key:       cacheItemNode.Value.key,
+					Value:     cacheItemNode.Value.value,
+					Frequency: cacheItemNode.Value.frequency,
+				})
+			}
+			cacheItemNode = cacheItemNode.Prev
+		}
+		frequencyGroupNode = frequencyGroupNode.Prev
+	}
+	return victims
+}
+
+// PutWithCost puts the item of the given cost and returns the decision made.
+func (c *shardedCacheImpl[K, V]) PutWithCost(key K, value V, cost int) PutDecision {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.PutWithCost(key, value, cost)
+}
@@ -0,0 +1,73 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutWithCost(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(10, WithCostPolicy(MaxVictims[int, int](1)))
+
+	require.Equal(t, PutAdmitted, cache.PutWithCost(1, 1, 4))
+	require.Equal(t, PutAdmitted, cache.PutWithCost(2, 2, 3))
+	// Put costs 1 without a weigher.
+	cache.Put(3, 3)
+	_, err := cache.Get(1)
+	require.NoError(t, err)
+
+	// 2 and 3 would have to go.
+	require.Equal(t, PutRejected, cache.PutWithCost(4, 4, 6))
+	require.False(t, cache.Contains(4))
+	require.Equal(t, 3, cache.Size())
+
+	// Only 2 has to go.
+	require.Equal(t, PutAdmittedWithEvictions, cache.PutWithCost(4, 4, 3))
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{1, 4, 3}, keys)
+
+	// Growing the key is checked as well, the old value is kept.
+	require.Equal(t, PutRejected, cache.PutWithCost(1, 10, 9))
+	value, err := cache.Peek(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	require.Equal(t, PutRejected, cache.PutWithCost(5, 5, 11))
+	require.Panics(t, func() { cache.PutWithCost(5, 5, -1) })
+	require.Panics(t, func() { New[int, int](10).PutWithCost(1, 1, 1) })
+}
+
+func TestMaxVictimFrequency(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(10,
+		WithWeigher(byLength),
+		WithCostPolicy(MaxVictimFrequency[int, string](1)),
+	)
+
+	cache.Put(1, "aaaaa")
+	cache.Put(2, "bbbbb")
+	_, err := cache.Get(1)
+	require.NoError(t, err)
+
+	require.Equal(t, PutAdmittedWithEvictions, cache.PutWithCost(3, "c", 5))
+	require.False(t, cache.Contains(2))
+
+	// The only remaining victim has been used twice.
+	require.Equal(t, PutRejected, cache.PutWithCost(4, "d", 9))
+	require.True(t, cache.Contains(1))
+
+	require.Equal(t, "admitted with evictions", PutAdmittedWithEvictions.String())
+}
+
+func TestShardedPutWithCost(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(10, 1, WithCostPolicy(MaxVictims[int, int](0)))
+
+	require.Equal(t, PutAdmitted, cache.PutWithCost(1, 1, 10))
+	require.Equal(t, PutRejected, cache.PutWithCost(2, 2, 1))
+	require.True(t, cache.Contains(1))
+}
@@ -0,0 +1,77 @@
+package lfu
+
+import (
+	"encoding/json"
+	"iter"
+	"time"
+)
+
+// CacheDump is the structured view of the cache contents for debugging, e.g.
+// to be served by a /debug/cache endpoint.
+type CacheDump[K comparable, V any] struct {
+	Capacity int `json:"capacity"`
+	Size     int `json:"size"`
+	// Groups are the frequency groups in descending order of frequency.
+	Groups []DumpGroup[K, V] `json:"groups"`
+}
+
+// DumpGroup is the view of the keys used the same number of times.
+type DumpGroup[K comparable, V any] struct {
+	Frequency int `json:"frequency"`
+	// Entries are sorted from the most recently used one.
+	Entries []DumpEntry[K, V] `json:"entries"`
+}
+
+// DumpEntry is the view of a key of the cache.
+type DumpEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+	// ExpiresAt is the time when the key expires, it is nil if the key
+	// never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Dump returns the view of the cache contents grouped by frequency, expired
+// keys are skipped. Neither frequencies nor recency are changed.
+//
+// O(size)
+func (l *cacheImpl[K, V]) Dump() CacheDump[K, V] {
+	return dump(l.capacity, l.items())
+}
+
+// MarshalJSON encodes the cache as its Dump.
+func (l *cacheImpl[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Dump())
+}
+
+// Dump returns the view of a snapshot of the cache contents grouped by
+// frequency, the keys of a group are sorted as by All.
+//
+// O(size)
+func (c *shardedCacheImpl[K, V]) Dump() CacheDump[K, V] {
+	return dump(c.Capacity(), c.items())
+}
+
+// MarshalJSON encodes the cache as its Dump.
+func (c *shardedCacheImpl[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Dump())
+}
+
+// dump groups the items sorted by descending frequency.
+func dump[K comparable, V any](capacity int, items iter.Seq[CacheItem[K, V]]) CacheDump[K, V] {
+	d := CacheDump[K, V]{Capacity: capacity, Groups: []DumpGroup[K, V]{}}
+	for item := range items {
+		if len(d.Groups) == 0 || d.Groups[len(d.Groups)-1].Frequency != item.frequency {
+			d.Groups = append(d.Groups, DumpGroup[K, V]{Frequency: item.frequency})
+		}
+		entry := DumpEntry[K, V]{Key: item.key, Value: item.value}
+		if item.expiresAt != 0 {
+			expiresAt := time.Unix(0, item.expiresAt)
+			entry.ExpiresAt = &expiresAt
+		}
+		group := &d.Groups[len(d.Groups)-1]
+		group.Entries = append(group.Entries, entry)
+		d.Size++
+	}
+	return d
+}
@@ -0,0 +1,74 @@
+package lfu
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(4, WithClock[string, int](clock))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.PutWithTTL("c", 3, time.Minute)
+	_, err := cache.Get("a")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(cache)
+	require.NoError(t, err)
+	expiresAt, err := json.Marshal(time.Unix(1_000_060, 0))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"capacity": 4,
+		"size": 3,
+		"groups": [
+			{"frequency": 2, "entries": [{"key": "a", "value": 1}]},
+			{"frequency": 1, "entries": [
+				{"key": "c", "value": 3, "expires_at": `+string(expiresAt)+`},
+				{"key": "b", "value": 2}
+			]}
+		]
+	}`, string(data))
+
+	// Dump changes neither frequencies nor recency.
+	frequency, err := cache.GetKeyFrequency("b")
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+
+	data, err = json.Marshal(New[string, int](1))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"capacity": 1, "size": 0, "groups": []}`, string(data))
+}
+
+func TestShardedDump(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+	for i := range 10 {
+		cache.Put(i, i)
+		for range i % 3 {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	d := cache.Dump()
+	require.Equal(t, 100, d.Capacity)
+	require.Equal(t, 10, d.Size)
+	require.Len(t, d.Groups, 3)
+	for i, group := range d.Groups {
+		require.Equal(t, 3-i, group.Frequency)
+		for _, entry := range group.Entries {
+			require.Equal(t, group.Frequency, entry.Key%3+1)
+			require.Nil(t, entry.ExpiresAt)
+		}
+	}
+
+	_, err := json.Marshal(cache)
+	require.NoError(t, err)
+}
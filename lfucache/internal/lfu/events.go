@@ -0,0 +1,112 @@
+package lfu
+
+import (
+	"sync/atomic"
+
+	"lfucache/internal/linkedlist"
+)
+
+// EventKind tells what has happened to the key.
+type EventKind int
+
+const (
+	// EventPut means the value of the key has been put into the cache.
+	EventPut EventKind = iota
+	// EventEvict means the key has left the cache, including the explicit
+	// removal, see the reason of the event.
+	EventEvict
+	// EventExpire means the key has left the cache since it has expired.
+	EventExpire
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPut:
+		return "put"
+	case EventEvict:
+		return "evict"
+	case EventExpire:
+		return "expire"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is the change of the cache sent to the stream returned by Events.
+type Event[K comparable, V any] struct {
+	Kind  EventKind
+	Key   K
+	Value V
+	// Reason is the reason of EventEvict and EventExpire.
+	Reason EvictionReason
+}
+
+// eventStream is the bounded stream of events, events which do not fit are
+// dropped and counted.
+type eventStream[K comparable, V any] struct {
+	events  chan Event[K, V]
+	dropped atomic.Uint64
+}
+
+// send sends the event without blocking.
+func (s *eventStream[K, V]) send(event Event[K, V]) {
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// WithEvents enables the stream of events returned by Events, which holds up
+// to size events not received yet. The cache never blocks on the stream:
+// events which do not fit are dropped and counted by DroppedEvents. The shards
+// of the sharded cache share the stream.
+func WithEvents[K comparable, V any](size int) Option[K, V] {
+	if size <= 0 {
+		panic("Invalid size of events stream")
+	}
+	stream := &eventStream[K, V]{events: make(chan Event[K, V], size)}
+	return func(l *cacheImpl[K, V]) {
+		l.events = stream
+	}
+}
+
+// emitPut sends the put of the cache item to the stream of events.
+func (l *cacheImpl[K, V]) emitPut(cacheItemNode *linkedlist.Node[CacheItem[K, V]]) {
+	if l.events != nil {
+		l.events.send(Event[K, V]{
+			Kind:  EventPut,
+			Key:   cacheItemNode.Value.key,
+			Value: cacheItemNode.Value.value,
+		})
+	}
+}
+
+// Events returns the stream of events, it is nil unless the cache has been
+// created with WithEvents.
+func (l *cacheImpl[K, V]) Events() <-chan Event[K, V] {
+	if l.events == nil {
+		return nil
+	}
+	return l.events.events
+}
+
+// DroppedEvents returns the number of events dropped since the stream was
+// full.
+func (l *cacheImpl[K, V]) DroppedEvents() uint64 {
+	if l.events == nil {
+		return 0
+	}
+	return l.events.dropped.Load()
+}
+
+// Events returns the stream of events shared by the shards.
+func (c *shardedCacheImpl[K, V]) Events() <-chan Event[K, V] {
+	return c.shards[0].cache.Events()
+}
+
+// DroppedEvents returns the number of events dropped since the stream was
+// full.
+func (c *shardedCacheImpl[K, V]) DroppedEvents() uint64 {
+	return c.shards[0].cache.DroppedEvents()
+}
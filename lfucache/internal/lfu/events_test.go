@@ -0,0 +1,90 @@
+package lfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func receive[K comparable, V any](events <-chan Event[K, V]) []Event[K, V] {
+	var received []Event[K, V]
+	for {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(2, WithClock[int, int](clock), WithEvents[int, int](10))
+
+	cache.Put(1, 1)
+	cache.PutWithTTL(2, 2, time.Minute)
+	require.True(t, cache.Update(1, func(old int) int { return old + 1 }))
+	cache.Put(3, 3)
+	clock.Advance(time.Minute)
+	require.False(t, cache.Contains(2))
+	require.True(t, cache.Remove(1))
+	_, err := cache.Get(3)
+	require.NoError(t, err)
+
+	require.Equal(t, []Event[int, int]{
+		{Kind: EventPut, Key: 1, Value: 1},
+		{Kind: EventPut, Key: 2, Value: 2},
+		{Kind: EventPut, Key: 1, Value: 2},
+		{Kind: EventEvict, Key: 2, Value: 2, Reason: EvictionReasonCapacity},
+		{Kind: EventPut, Key: 3, Value: 3},
+		{Kind: EventEvict, Key: 1, Value: 2, Reason: EvictionReasonRemoved},
+	}, receive(cache.Events()))
+
+	cache.PutWithTTL(4, 4, time.Minute)
+	clock.Advance(time.Minute)
+	_, err = cache.Get(4)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, []Event[int, int]{
+		{Kind: EventPut, Key: 4, Value: 4},
+		{Kind: EventExpire, Key: 4, Value: 4, Reason: EvictionReasonExpired},
+	}, receive(cache.Events()))
+	require.Zero(t, cache.DroppedEvents())
+}
+
+func TestEventsDropped(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(10, WithEvents[int, int](2))
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+
+	require.Len(t, receive(cache.Events()), 2)
+	require.Equal(t, uint64(3), cache.DroppedEvents())
+
+	require.Nil(t, New[int, int](1).Events())
+	require.Panics(t, func() { WithEvents[int, int](0) })
+}
+
+func TestShardedEvents(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(100, 4, WithEvents[int, int](100))
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+	}
+
+	keys := make([]int, 0, 20)
+	for _, event := range receive(cache.Events()) {
+		require.Equal(t, EventPut, event.Kind)
+		keys = append(keys, event.Key)
+	}
+	require.ElementsMatch(t, []int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19,
+	}, keys)
+	require.Zero(t, cache.DroppedEvents())
+}
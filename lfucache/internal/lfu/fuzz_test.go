@@ -0,0 +1,135 @@
+package lfu
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// referenceItem is an item of the reference model.
+type referenceItem struct {
+	value     int
+	frequency int
+	// lastUsed is the time of the last use of the item, in operations.
+	lastUsed int
+}
+
+// referenceCache is the straightforward LFU model the cache is checked
+// against: the least frequently used key is invalidated, ties are broken by
+// the time of the last use.
+type referenceCache struct {
+	capacity int
+	items    map[int]*referenceItem
+	time     int
+}
+
+func newReferenceCache(capacity int) *referenceCache {
+	return &referenceCache{capacity: capacity, items: make(map[int]*referenceItem)}
+}
+
+func (r *referenceCache) use(item *referenceItem) {
+	r.time++
+	item.frequency++
+	item.lastUsed = r.time
+}
+
+func (r *referenceCache) Get(key int) (int, bool) {
+	item, ok := r.items[key]
+	if !ok {
+		return 0, false
+	}
+	r.use(item)
+	return item.value, true
+}
+
+func (r *referenceCache) Put(key, value int) {
+	if item, ok := r.items[key]; ok {
+		r.use(item)
+		item.value = value
+		return
+	}
+	if r.capacity == 0 {
+		return
+	}
+	if len(r.items) == r.capacity {
+		victim := slices.MinFunc(r.keys(), func(a, b int) int {
+			return cmp.Or(
+				cmp.Compare(r.items[a].frequency, r.items[b].frequency),
+				cmp.Compare(r.items[a].lastUsed, r.items[b].lastUsed),
+			)
+		})
+		delete(r.items, victim)
+	}
+	item := &referenceItem{value: value}
+	r.items[key] = item
+	r.use(item)
+}
+
+func (r *referenceCache) Remove(key int) bool {
+	_, ok := r.items[key]
+	delete(r.items, key)
+	return ok
+}
+
+// keys returns the keys in the order of All.
+func (r *referenceCache) keys() []int {
+	keys := make([]int, 0, len(r.items))
+	for key := range r.items {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b int) int {
+		return cmp.Or(
+			cmp.Compare(r.items[b].frequency, r.items[a].frequency),
+			cmp.Compare(r.items[b].lastUsed, r.items[a].lastUsed),
+		)
+	})
+	return keys
+}
+
+// FuzzCacheAgainstReference runs the operations encoded by the input on the
+// cache and the reference model and compares the results. The first byte is
+// the capacity, every following pair of bytes is an operation and its key.
+func FuzzCacheAgainstReference(f *testing.F) {
+	f.Add([]byte{3, 0, 1, 0, 2, 0, 3, 1, 1, 0, 4, 1, 2})
+	f.Add([]byte{1, 0, 1, 1, 1, 0, 2, 1, 1, 1, 2})
+	f.Add([]byte{5, 0, 1, 0, 1, 0, 2, 2, 1, 0, 3, 3, 0, 0, 4, 1, 3, 0, 5, 0, 6})
+	f.Add([]byte{0, 0, 1, 1, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		capacity := int(data[0] % 8)
+		cache := New[int, int](capacity)
+		reference := newReferenceCache(capacity)
+
+		for i := 1; i+1 < len(data); i += 2 {
+			key := int(data[i+1] % 16)
+			switch data[i] % 5 {
+			case 0, 1:
+				cache.Put(key, i)
+				reference.Put(key, i)
+			case 2:
+				value, err := cache.Get(key)
+				wantValue, ok := reference.Get(key)
+				require.Equal(t, ok, err == nil)
+				require.Equal(t, wantValue, value)
+			case 3:
+				require.Equal(t, reference.Remove(key), cache.Remove(key))
+			case 4:
+				frequency, err := cache.GetKeyFrequency(key)
+				item, ok := reference.items[key]
+				require.Equal(t, ok, err == nil)
+				if ok {
+					require.Equal(t, item.frequency, frequency)
+				}
+			}
+
+			require.Equal(t, len(reference.items), cache.Size())
+			keys, _ := collect(cache.All())
+			require.Equal(t, reference.keys(), keys)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package lfu
+
+import (
+	"cmp"
+	"slices"
+)
+
+// FrequencyCount is the number of keys used the same number of times.
+type FrequencyCount struct {
+	Frequency int
+	Count     int
+}
+
+// FrequencyHistogram returns the number of keys of every frequency in
+// descending order of frequency. Expired keys which have not been removed yet
+// are counted.
+//
+// O(number of frequencies)
+func (l *cacheImpl[K, V]) FrequencyHistogram() []FrequencyCount {
+	histogram := make([]FrequencyCount, 0, len(l.freqToFreqGroupNode))
+	if l.size == 0 {
+		return histogram
+	}
+	for frequencyGroup := range l.freqGroupsList.All() {
+		histogram = append(histogram, FrequencyCount{
+			Frequency: frequencyGroup.frequency,
+			Count:     frequencyGroup.elementsList.Len(),
+		})
+	}
+	return histogram
+}
+
+// FrequencyHistogram returns the number of keys of every frequency in
+// descending order of frequency, summed over the shards.
+//
+// O(number of frequencies * log(number of frequencies))
+func (c *shardedCacheImpl[K, V]) FrequencyHistogram() []FrequencyCount {
+	counts := make(map[int]int)
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		for _, frequencyCount := range s.cache.FrequencyHistogram() {
+			counts[frequencyCount.Frequency] += frequencyCount.Count
+		}
+		s.mu.Unlock()
+	}
+	histogram := make([]FrequencyCount, 0, len(counts))
+	for frequency, count := range counts {
+		histogram = append(histogram, FrequencyCount{Frequency: frequency, Count: count})
+	}
+	slices.SortFunc(histogram, func(a, b FrequencyCount) int {
+		return cmp.Compare(b.Frequency, a.Frequency)
+	})
+	return histogram
+}
@@ -0,0 +1,52 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrequencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	require.Empty(t, cache.FrequencyHistogram())
+
+	for i := range 6 {
+		cache.Put(i, i)
+		for range i % 3 {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+	require.Equal(t, []FrequencyCount{
+		{Frequency: 3, Count: 2},
+		{Frequency: 2, Count: 2},
+		{Frequency: 1, Count: 2},
+	}, cache.FrequencyHistogram())
+
+	require.True(t, cache.Remove(2))
+	require.True(t, cache.Remove(5))
+	require.Equal(t, []FrequencyCount{
+		{Frequency: 2, Count: 2},
+		{Frequency: 1, Count: 2},
+	}, cache.FrequencyHistogram())
+}
+
+func TestShardedFrequencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+	for i := range 30 {
+		cache.Put(i, i)
+		for range i % 3 {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+	require.Equal(t, []FrequencyCount{
+		{Frequency: 3, Count: 10},
+		{Frequency: 2, Count: 10},
+		{Frequency: 1, Count: 10},
+	}, cache.FrequencyHistogram())
+}
@@ -0,0 +1,112 @@
+package lfu
+
+import "iter"
+
+// KeyedValue is the value stored by KeyedCache in the underlying cache: the
+// original key is kept to be returned by the iterators.
+type KeyedValue[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// KeyedCache maps keys, which are not necessarily comparable, to comparable
+// surrogate keys of the underlying cache, e.g. a struct with slices to its
+// canonical string, or a string to its lower case for case-insensitive keys.
+// Keys with the same surrogate key are the same key of the cache.
+type KeyedCache[K any, S comparable, V any] struct {
+	keyFunc func(key K) S
+	cache   Cache[S, KeyedValue[K, V]]
+}
+
+// NewKeyed creates the cache storing the values in the given cache under the
+// surrogate keys computed by keyFunc.
+func NewKeyed[K any, S comparable, V any](
+	keyFunc func(key K) S,
+	cache Cache[S, KeyedValue[K, V]],
+) *KeyedCache[K, S, V] {
+	return &KeyedCache[K, S, V]{
+		keyFunc: keyFunc,
+		cache:   cache,
+	}
+}
+
+// Get returns the value of the key as Cache.Get does.
+func (c *KeyedCache[K, S, V]) Get(key K) (V, error) {
+	keyed, err := c.cache.Get(c.keyFunc(key))
+	return keyed.Value, err
+}
+
+// GetOrCompute returns the value of the key as Cache.GetOrCompute does.
+func (c *KeyedCache[K, S, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	keyed, err := c.cache.GetOrCompute(c.keyFunc(key), func() (KeyedValue[K, V], error) {
+		value, err := compute()
+		return KeyedValue[K, V]{Key: key, Value: value}, err
+	})
+	return keyed.Value, err
+}
+
+// Peek returns the value of the key as Cache.Peek does.
+func (c *KeyedCache[K, S, V]) Peek(key K) (V, error) {
+	keyed, err := c.cache.Peek(c.keyFunc(key))
+	return keyed.Value, err
+}
+
+// Contains reports whether the key exists in the cache.
+func (c *KeyedCache[K, S, V]) Contains(key K) bool {
+	return c.cache.Contains(c.keyFunc(key))
+}
+
+// Put updates the value of the key as Cache.Put does, the key is remembered
+// as the original key of the value.
+func (c *KeyedCache[K, S, V]) Put(key K, value V) {
+	c.cache.Put(c.keyFunc(key), KeyedValue[K, V]{Key: key, Value: value})
+}
+
+// Remove deletes the key from the cache and reports whether the key was
+// present.
+func (c *KeyedCache[K, S, V]) Remove(key K) bool {
+	return c.cache.Remove(c.keyFunc(key))
+}
+
+// GetKeyFrequency returns the frequency of the key as Cache.GetKeyFrequency
+// does.
+func (c *KeyedCache[K, S, V]) GetKeyFrequency(key K) (int, error) {
+	return c.cache.GetKeyFrequency(c.keyFunc(key))
+}
+
+// All returns the iterator over the original keys and values in the order of
+// the underlying cache.
+func (c *KeyedCache[K, S, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, keyed := range c.cache.All() {
+			if !yield(keyed.Key, keyed.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all keys from the cache.
+func (c *KeyedCache[K, S, V]) Clear() {
+	c.cache.Clear()
+}
+
+// Resize changes the cache capacity as Cache.Resize does.
+func (c *KeyedCache[K, S, V]) Resize(newCapacity int) {
+	c.cache.Resize(newCapacity)
+}
+
+// Size returns the cache size.
+func (c *KeyedCache[K, S, V]) Size() int {
+	return c.cache.Size()
+}
+
+// Capacity returns the cache capacity.
+func (c *KeyedCache[K, S, V]) Capacity() int {
+	return c.cache.Capacity()
+}
+
+// Stats returns the snapshot of the cache statistics.
+func (c *KeyedCache[K, S, V]) Stats() Stats {
+	return c.cache.Stats()
+}
@@ -0,0 +1,68 @@
+package lfu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type query struct {
+	authors []string
+	limit   int
+}
+
+func TestKeyedCacheNonComparableKeys(t *testing.T) {
+	t.Parallel()
+
+	cache := NewKeyed(
+		func(q query) string { return strings.Join(q.authors, ",") + "/" + string(rune('0'+q.limit)) },
+		New[string, KeyedValue[query, int]](2),
+	)
+
+	cache.Put(query{authors: []string{"a", "b"}, limit: 1}, 1)
+	cache.Put(query{authors: []string{"c"}, limit: 2}, 2)
+
+	value, err := cache.Get(query{authors: []string{"a", "b"}, limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	require.False(t, cache.Contains(query{authors: []string{"a"}, limit: 1}))
+
+	computed, err := cache.GetOrCompute(query{authors: []string{"d"}, limit: 3}, func() (int, error) {
+		return 3, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, computed)
+
+	keys, values := collect(cache.All())
+	require.Equal(t, []query{
+		{authors: []string{"a", "b"}, limit: 1},
+		{authors: []string{"d"}, limit: 3},
+	}, keys)
+	require.Equal(t, []int{1, 3}, values)
+}
+
+func TestKeyedCacheCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	cache := NewKeyed(strings.ToLower, NewSharded[string, KeyedValue[string, int]](10))
+
+	cache.Put("Pushkin", 1)
+	cache.Put("PUSHKIN", 2)
+	require.Equal(t, 1, cache.Size())
+
+	value, err := cache.Peek("pushkin")
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	frequency, err := cache.GetKeyFrequency("pUsHkIn")
+	require.NoError(t, err)
+	require.Equal(t, 2, frequency)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []string{"PUSHKIN"}, keys)
+
+	require.True(t, cache.Remove("pushkin"))
+	require.Zero(t, cache.Size())
+}
@@ -0,0 +1,82 @@
+package lfu
+
+import "sync"
+
+// keyLock is the lock of a key shared by the goroutines holding or waiting
+// for it.
+type keyLock struct {
+	mu sync.Mutex
+	// references is the number of goroutines holding or waiting for the lock,
+	// it is guarded by the lock of the shard.
+	references int
+}
+
+// KeyLock is the lock of a key of the sharded cache, e.g. to serialize the
+// read-modify-write of the key against the backing store without a global
+// mutex. The locks are kept by the shard of the key only while they are held
+// or waited for, they do not lock the cache itself.
+type KeyLock[K comparable, V any] struct {
+	shard *shard[K, V]
+	key   K
+}
+
+// KeyLock returns the lock of the key.
+func (c *shardedCacheImpl[K, V]) KeyLock(key K) KeyLock[K, V] {
+	return KeyLock[K, V]{shard: c.shardFor(key), key: key}
+}
+
+// acquire returns the lock of the key counting the caller as its user.
+func (l KeyLock[K, V]) acquire() *keyLock {
+	s := l.shard
+	s.lock()
+	defer s.mu.Unlock()
+	lock, ok := s.keyLocks[l.key]
+	if !ok {
+		lock = &keyLock{}
+		if s.keyLocks == nil {
+			s.keyLocks = make(map[K]*keyLock)
+		}
+		s.keyLocks[l.key] = lock
+	}
+	lock.references++
+	return lock
+}
+
+// release stops counting the caller as the user of the lock of the key.
+func (l KeyLock[K, V]) release(lock *keyLock) {
+	s := l.shard
+	s.lock()
+	defer s.mu.Unlock()
+	lock.references--
+	if lock.references == 0 {
+		delete(s.keyLocks, l.key)
+	}
+}
+
+// Lock locks the key, blocking until the key is unlocked if it is locked.
+func (l KeyLock[K, V]) Lock() {
+	l.acquire().mu.Lock()
+}
+
+// TryLock tries to lock the key and reports whether it has succeeded.
+func (l KeyLock[K, V]) TryLock() bool {
+	lock := l.acquire()
+	if lock.mu.TryLock() {
+		return true
+	}
+	l.release(lock)
+	return false
+}
+
+// Unlock unlocks the key. It panics if the key is not locked.
+func (l KeyLock[K, V]) Unlock() {
+	s := l.shard
+	s.lock()
+	lock, ok := s.keyLocks[l.key]
+	s.mu.Unlock()
+	if !ok {
+		panic("Unlock of unlocked key")
+	}
+	lock.mu.Unlock()
+	l.release(lock)
+}
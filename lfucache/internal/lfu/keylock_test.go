@@ -0,0 +1,49 @@
+package lfu
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyLock(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](16, 4)
+	lock := cache.KeyLock(1)
+	lock.Lock()
+	require.False(t, cache.KeyLock(1).TryLock())
+	// Other keys and the cache itself are not locked.
+	require.True(t, cache.KeyLock(2).TryLock())
+	cache.KeyLock(2).Unlock()
+	cache.Put(1, 1)
+	lock.Unlock()
+	require.True(t, lock.TryLock())
+	lock.Unlock()
+	require.Panics(t, func() { lock.Unlock() })
+
+	// Concurrent read-modify-write of the key is serialized.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				lock := cache.KeyLock(1)
+				lock.Lock()
+				value, _ := cache.Peek(1)
+				cache.Put(1, value+1)
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	value, err := cache.Peek(1)
+	require.NoError(t, err)
+	require.Equal(t, 8001, value)
+
+	for i := range cache.shards {
+		require.Empty(t, cache.shards[i].keyLocks)
+	}
+}
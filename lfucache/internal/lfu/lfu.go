@@ -2,17 +2,41 @@ package lfu
 
 import (
 	"errors"
+	"fmt"
 	"iter"
+	"lfucache/internal/cache"
 	"lfucache/internal/linkedlist"
+	"sync"
+	"time"
 )
 
 // ErrKeyNotFound is an error that indicates that a requested key does not
 // exist in the cache. It is used for operations that attempt to retrieve a
-// value in the cache when the specified key is not found.
-var ErrKeyNotFound = errors.New("key not found")
+// value in the cache when the specified key is not found. It is the same
+// error as cache.ErrKeyNotFound.
+var ErrKeyNotFound = cache.ErrKeyNotFound
+
+var (
+	// ErrInvalidCapacity is returned by NewWithConfig for a negative capacity.
+	ErrInvalidCapacity = errors.New("invalid capacity")
+	// ErrInvalidPolicy is returned by NewWithConfig for an unknown eviction
+	// policy.
+	ErrInvalidPolicy = errors.New("invalid eviction policy")
+	// ErrInvalidTTL is returned by NewWithConfig for a negative TTL.
+	ErrInvalidTTL = errors.New("invalid TTL")
+)
 
 const DefaultCapacity = 5
 
+// Config is the configuration of the cache.
+type Config[K comparable, V any] struct {
+	// Capacity is the cache capacity. The cache of zero capacity holds
+	// nothing: Put does nothing and lookups always miss.
+	Capacity int
+	// Options configure the cache as in NewWithOptions.
+	Options []Option[K, V]
+}
+
 // CacheItem is the item stored in the cache.
 type CacheItem[K comparable, V any] struct {
 	// value of cache item
@@ -21,6 +45,23 @@ type CacheItem[K comparable, V any] struct {
 	key K
 	// frequency of usage of cache item
 	frequency int
+	// weight of cache item, it is zero unless the cache has a weigher
+	weight int
+	// expiresAt is the Unix time in nanoseconds when the cache item expires,
+	// it is zero if the cache item never expires.
+	expiresAt int64
+	// slidingTTL is the time to live restarted by every Get of the cache
+	// item, it is zero if the expiration is not sliding.
+	slidingTTL time.Duration
+	// pinned cache items are never invalidated to make room for others.
+	pinned bool
+}
+
+// Entry is a key of the cache with its value and usage frequency.
+type Entry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	Frequency int
 }
 
 // Frequency is cache item usage frequency.
@@ -34,49 +75,109 @@ type FrequencyGroup[V any] struct {
 	frequency int
 	// elementsList contains elements with the same frequency.
 	elementsList linkedlist.LinkedList[V]
-	// size of list
-	size int
+}
+
+// View is the read-only view of the cache. None of its methods changes the
+// frequencies of the keys or their recency.
+type View[K comparable, V any] interface {
+	// Contains reports whether the key exists in the cache.
+	//
+	// O(1)
+	Contains(key K) bool
+
+	// Peek returns the value of the key if the key exists in the cache,
+	// otherwise, returns ErrKeyNotFound. Unlike Get, it changes neither the
+	// frequency of the key nor its recency.
+	//
+	// O(1)
+	Peek(key K) (V, error)
+
+	// Size returns the cache size.
+	//
+	// O(1)
+	Size() int
+
+	// Capacity returns the cache capacity.
+	//
+	// O(1)
+	Capacity() int
+
+	// Stats returns the snapshot of the cache statistics. Lookups are counted
+	// by Get and GetOrCompute.
+	//
+	// O(1)
+	Stats() Stats
+
+	// GetKeyFrequency returns the element's frequency if the key exists in the cache,
+	// otherwise, returns ErrKeyNotFound.
+	//
+	// O(1)
+	GetKeyFrequency(key K) (int, error)
 }
 
 // Cache
 // O(capacity) memory
 type Cache[K comparable, V any] interface {
+	View[K, V]
+
 	// Get returns the value of the key if the key exists in the cache,
 	// otherwise, returns ErrKeyNotFound.
 	//
 	// O(1)
 	Get(key K) (V, error)
 
+	// GetOrCompute returns the value of the key if the key exists in the
+	// cache, otherwise, computes the value with compute, puts it into the
+	// cache and returns it. The error of compute is returned as is and
+	// nothing is put into the cache in that case.
+	//
+	// O(1) besides compute
+	GetOrCompute(key K, compute func() (V, error)) (V, error)
+
 	// Put updates the value of the key if present, or inserts the key if not already present.
 	//
 	// When the cache reaches its capacity, it should invalidate and remove the least frequently used key
 	// before inserting a new item. For this problem, when there is a tie
 	// (i.e., two or more keys with the same frequency), the least recently used key would be invalidated.
+	// The cache of zero capacity ignores Put.
 	//
 	// O(1)
 	Put(key K, value V)
 
-	// All returns the iterator in descending order of frequency.
-	// If two or more keys have the same frequency, the most recently used key will be listed first.
+	// Remove deletes the key from the cache and reports whether the key was
+	// present.
 	//
-	// O(capacity)
-	All() iter.Seq2[K, V]
+	// O(1)
+	Remove(key K) bool
 
-	// Size returns the cache size.
+	// Clear removes all keys from the cache.
 	//
-	// O(1)
-	Size() int
+	// O(size)
+	Clear()
 
-	// Capacity returns the cache capacity.
+	// Resize changes the cache capacity. If the cache holds more keys than
+	// the new capacity, the least frequently used keys are invalidated until
+	// the size fits, ties are broken the same way as in Put: the keys are
+	// invalidated strictly in the order of AllAscending, and the eviction
+	// callbacks and events are delivered for every invalidated key in that
+	// order with EvictionReasonCapacity. With a weigher, the keys are
+	// invalidated until the total weight fits. Pinned keys are skipped.
 	//
-	// O(1)
-	Capacity() int
+	// O(max(1, size - newCapacity))
+	Resize(newCapacity int)
 
-	// GetKeyFrequency returns the element's frequency if the key exists in the cache,
-	// otherwise, returns ErrKeyNotFound.
+	// All returns the iterator in descending order of frequency.
+	// If two or more keys have the same frequency, the most recently used key will be listed first.
 	//
-	// O(1)
-	GetKeyFrequency(key K) (int, error)
+	// O(capacity)
+	All() iter.Seq2[K, V]
+
+	// Entries returns the iterator over entries with their frequencies in
+	// the same order as All. Unlike GetKeyFrequency, it does not require a
+	// lookup per key.
+	//
+	// O(capacity)
+	Entries() iter.Seq[Entry[K, V]]
 }
 
 // cacheImpl represents LFU cache implementation
@@ -92,67 +193,254 @@ type cacheImpl[K comparable, V any] struct {
 	capacity int
 	// size serves the cache size.
 	size int
+	// policy serves the eviction policy.
+	policy Policy
+	// sketch estimates the recent frequencies of the keys, including the
+	// ones which are not in the cache, if TinyLFU admission is enabled.
+	sketch *countMinSketch[K]
+	// tinyLFU enables TinyLFU admission, the sketch is created once the
+	// capacity is known.
+	tinyLFU bool
+	// admission decides whether a new item may be put into the full cache.
+	admission func(key K, value V) bool
+	// weigher computes weights of cache items, if it is set, the capacity
+	// limits the total weight of the items rather than their number.
+	weigher Weigher[K, V]
+	// weight serves the total weight of the cache items.
+	weight int
+	// pinned serves the number of pinned cache items.
+	pinned int
+	// pinnedWeight serves the total weight of the pinned cache items.
+	pinnedWeight int
+	// costPolicy decides whether an item may invalidate other items to fit.
+	costPolicy CostPolicy[K, V]
+	// sizer computes the memory referenced by the items for EstimateMemory.
+	sizer Sizer[K, V]
 	// freeNodesOfFreqGroups serves unused nodes of frequency groups.
 	freeNodesOfFreqGroups []*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]]
+	// freeCacheItemNodes serves unused nodes of cache items.
+	freeCacheItemNodes []*linkedlist.Node[CacheItem[K, V]]
+	// readBufferSize is the size of the buffer of lookups of every shard of
+	// the sharded cache, zero means that lookups are not buffered.
+	readBufferSize int
+	// nodePool serves unused nodes of cache items instead of
+	// freeCacheItemNodes if it is set.
+	nodePool *sync.Pool
+	// stats serves the cache statistics, except for the size.
+	stats Stats
+	// onEvict is called when an item leaves the cache.
+	onEvict func(key K, value V, reason EvictionReason)
+	// onExpire is called when an item leaves the cache since it has expired.
+	onExpire func(key K, value V)
+	// onHit is called when a lookup finds the key.
+	onHit func(key K)
+	// onMiss is called when a lookup does not find the key.
+	onMiss func(key K, loadLatency time.Duration)
+	// events serves the stream of events of the cache, it is nil unless
+	// enabled.
+	events *eventStream[K, V]
+	// ttl serves the time to live of the items put by Put, zero means that
+	// they never expire.
+	ttl time.Duration
+	// sliding makes Get restart the time to live of the items put by Put.
+	sliding bool
+	// expiring is set once any item may expire, so that the caches without
+	// expiration do not check it.
+	expiring bool
+	// clock serves the current time.
+	clock Clock
 }
 
 // New initializes the cache with the given capacity.
 // If no capacity is provided, the cache will use DefaultCapacity.
 func New[K comparable, V any](capacity ...int) *cacheImpl[K, V] {
-	var cacheCapacity int
+	return NewWithOptions[K, V](cacheCapacity(capacity))
+}
+
+// NewWithOptions initializes the cache with the given capacity and options.
+// It panics if the configuration is invalid, see NewWithConfig.
+func NewWithOptions[K comparable, V any](capacity int, opts ...Option[K, V]) *cacheImpl[K, V] {
+	l, err := NewWithConfig(Config[K, V]{
+		Capacity: capacity,
+		Options:  opts,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// NewWithConfig initializes the cache with the given configuration. Unlike
+// the other constructors, it returns an error if the configuration is
+// invalid instead of panicking.
+func NewWithConfig[K comparable, V any](config Config[K, V]) (*cacheImpl[K, V], error) {
+	capacity := config.Capacity
+	// Capacity cannot be negative.
+	if capacity < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCapacity, capacity)
+	}
+	l := &cacheImpl[K, V]{
+		capacity: capacity,
+		clock:    realClock{},
+	}
+	for _, opt := range config.Options {
+		opt(l)
+	}
+	if l.policy != PolicyLFU && l.policy != PolicyLRU {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPolicy, l.policy)
+	}
+	if l.ttl < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTTL, l.ttl)
+	}
+	// Since the maximum size of the cache is known, memory for its elements
+	// can be allocated in advance. It is unknown if the capacity limits the
+	// weight of the elements.
+	size := capacity
+	if l.weigher != nil {
+		size = 0
+	}
+	if l.costPolicy != nil && l.weigher == nil {
+		l.weigher = unitWeigher[K, V]
+		size = 0
+	}
+	if l.tinyLFU {
+		l.sketch = newCountMinSketch[K](capacity)
+	}
+	l.freqToFreqGroupNode = make(map[int]*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]], size)
+	l.keyToCacheItem = make(map[K]*linkedlist.Node[CacheItem[K, V]], size)
+	l.freeNodesOfFreqGroups = make([]*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]], 0, size)
+	return l, nil
+}
+
+// cacheCapacity returns the capacity passed to New or DefaultCapacity.
+func cacheCapacity(capacity []int) int {
 	length := len(capacity)
 	if length == 0 {
-		cacheCapacity = DefaultCapacity
-	} else if length > 2 {
+		return DefaultCapacity
+	} else if length > 1 {
 		panic("Invalid capacity")
-	} else {
-		cacheCapacity = capacity[0]
-		// Capacity cannot be negative.
-		if cacheCapacity < 0 {
-			panic("Invalid capacity")
-		}
-	}
-	// Since the maximum size of the cache is known, memory for its elements
-	// can be allocated in advance.
-	return &cacheImpl[K, V]{
-		capacity:              cacheCapacity,
-		freqToFreqGroupNode:   make(map[int]*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]], cacheCapacity),
-		keyToCacheItem:        make(map[K]*linkedlist.Node[CacheItem[K, V]], cacheCapacity),
-		freeNodesOfFreqGroups: make([]*linkedlist.Node[FrequencyGroup[CacheItem[K, V]]], 0, cacheCapacity),
 	}
+	return capacity[0]
 }
 
 func (l *cacheImpl[K, V]) Get(key K) (V, error) {
+	return l.get(key, true)
+}
+
+// get looks the key up as Get does, the miss is reported to the observer only
+// if reportMiss is set, so that the loading methods can report it with the
+// latency of the load.
+func (l *cacheImpl[K, V]) get(key K, reportMiss bool) (V, error) {
 	var value V
 
+	if l.sketch != nil {
+		l.sketch.increment(key)
+	}
+
 	// If the cache item exists, find it in the keyToCacheItem mapping;
 	// otherwise, return an error.
-	if cacheItem, ok := l.keyToCacheItem[key]; ok {
+	if cacheItem, ok := l.keyToCacheItem[key]; ok && !l.expireIfDue(cacheItem) {
 		value = cacheItem.Value.value
 		// If it exists, its frequency will be updated.
 		l.updateFreqAndMoveCacheItemNode(cacheItem)
+		if cacheItem.Value.slidingTTL != 0 {
+			l.setExpiration(cacheItem, cacheItem.Value.slidingTTL, true)
+		}
+		l.stats.Hits++
+		if l.onHit != nil {
+			l.onHit(key)
+		}
+		return value, nil
+	}
+
+	l.stats.Misses++
+	if reportMiss && l.onMiss != nil {
+		l.onMiss(key, 0)
+	}
+	return value, ErrKeyNotFound
+}
+
+func (l *cacheImpl[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	if value, err := l.get(key, false); err == nil {
 		return value, nil
 	}
+	start := l.loadStart()
+	value, err := compute()
+	l.loaded(key, start)
+	if err != nil {
+		return value, err
+	}
+	l.Put(key, value)
+	return value, nil
+}
+
+func (l *cacheImpl[K, V]) Contains(key K) bool {
+	cacheItem, ok := l.keyToCacheItem[key]
+	return ok && !l.isExpired(cacheItem.Value.expiresAt)
+}
 
+func (l *cacheImpl[K, V]) Peek(key K) (V, error) {
+	if cacheItem, ok := l.keyToCacheItem[key]; ok && !l.isExpired(cacheItem.Value.expiresAt) {
+		return cacheItem.Value.value, nil
+	}
+	var value V
 	return value, ErrKeyNotFound
 }
 
 func (l *cacheImpl[K, V]) Put(key K, value V) {
+	cacheItemNode := l.put(key, value)
+	if cacheItemNode == nil {
+		return
+	}
+	// Items never expire until any TTL is set, so there is nothing to reset.
+	if l.expiring {
+		l.setExpiration(cacheItemNode, l.ttl, l.sliding)
+	}
+	if l.events != nil {
+		l.emitPut(cacheItemNode)
+	}
+}
+
+// put places the value into the cache and returns its cache item, or nil if
+// the value has not been put.
+func (l *cacheImpl[K, V]) put(key K, value V) *linkedlist.Node[CacheItem[K, V]] {
+	l.beforePut(key)
+	if l.weigher != nil {
+		return l.putWeighted(key, value)
+	}
 	// Before placing the cache item, it should be checked whether such an item
 	// exists.
-	if cacheItem, ok := l.keyToCacheItem[key]; ok {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if ok {
 		// If it exists, its frequency should be updated.
-		l.updateFreqAndMoveCacheItemNode(cacheItem)
-		cacheItem.Value.value = value
+		l.updateFreqAndMoveCacheItemNode(cacheItemNode)
+		cacheItemNode.Value.value = value
 	} else {
 		// If it does not exist, it should be checked whether the capacity has
-		// been exceeded.
-		var cacheItemNode *linkedlist.Node[CacheItem[K, V]]
-		if l.size == l.capacity {
+		// been exceeded. Nothing fits into the cache of zero capacity.
+		if l.capacity == 0 {
+			return nil
+		}
+		if l.size >= l.capacity {
+			if l.pinned != 0 {
+				return l.putReplacingUnpinned(key, value)
+			}
 			// Retrieve the element with the lowest usage frequency and its
 			// group.
 			minFrequencyGroup := l.freqGroupsList.Last()
 			cacheItemNode = minFrequencyGroup.Value.elementsList.Last()
+			// The new item is not admitted if it is used less often than
+			// the one it would replace.
+			if l.admission != nil && !l.admission(key, value) {
+				return nil
+			}
+			if l.sketch != nil && !l.sketch.admit(key, cacheItemNode.Value.key) {
+				return nil
+			}
+			// Remember the evicted item to report it once the cache is
+			// consistent again.
+			evictedKey, evictedValue := cacheItemNode.Value.key, cacheItemNode.Value.value
+			defer l.evicted(evictedKey, evictedValue, EvictionReasonCapacity)
 			// Update the value of the last item and remove the old item from
 			// keyToCacheItem.
 			delete(l.keyToCacheItem, cacheItemNode.Value.key)
@@ -166,64 +454,391 @@ func (l *cacheImpl[K, V]) Put(key K, value V) {
 				// group's frequency to 1 will suffice. Otherwise, remove the
 				// item from the old group and place it into the group with
 				// frequency 1.
-				if minFrequencyGroup.Value.size == 1 {
+				if minFrequencyGroup.Value.elementsList.Len() == 1 {
+					delete(l.freqToFreqGroupNode, minFrequencyGroup.Value.frequency)
 					minFrequencyGroup.Value.frequency = 1
+					cacheItemNode.Value.frequency = 1
 					l.freqToFreqGroupNode[1] = minFrequencyGroup
 				} else {
-					minFrequencyGroup.Value.size--
 					linkedlist.RemoveNode(cacheItemNode)
 					l.freqToFreqGroupNode[1] = l.getNewFrequencyGroupNode(
 						cacheItemNode, 1,
 					)
 					l.freqGroupsList.PushBack(l.freqToFreqGroupNode[1])
 				}
-			} else if minFrequencyGroup.Value.size != 1 {
+			} else if minFrequencyGroup.Value.elementsList.Len() != 1 {
 				linkedlist.RemoveNode(cacheItemNode)
 				minFrequencyGroup.Value.elementsList.PushFront(cacheItemNode)
 				cacheItemNode.Value.frequency =
 					minFrequencyGroup.Value.frequency
 			}
 		} else {
-			var unitFrequencyGroupNode *linkedlist.Node[FrequencyGroup[CacheItem[K, V]]]
-			// Create a cache item node to insert it into either the newly
-			// created list or an existing one.
-			cacheItemNode = linkedlist.NewNode(CacheItem[K, V]{
-				key:   key,
-				value: value,
-			})
-			// If the list is empty, it needs to be created.
-			if l.size == 0 {
-				unitFrequencyGroupNode = createFrequencyGroupNode(
-					cacheItemNode, 1,
-				)
-				l.freqGroupsList = linkedlist.New(
-					unitFrequencyGroupNode,
-				)
-			} else {
-				// If the list has already been created, locate the group with
-				// frequency 1 and place the element there. If such a group
-				// does not exist, create it.
-				if l.freqGroupsList.Last().Value.frequency == 1 {
-					lastListElement := l.freqGroupsList.Last()
-					unitFrequencyGroupNode = lastListElement
-					cacheItemNode.Value.frequency =
-						unitFrequencyGroupNode.Value.frequency
-					unitFrequencyGroupNode.Value.elementsList.PushFront(cacheItemNode)
-					unitFrequencyGroupNode.Value.size++
-				} else {
-					unitFrequencyGroupNode = l.getNewFrequencyGroupNode(
-						cacheItemNode, 1,
-					)
-					l.freqGroupsList.PushBack(unitFrequencyGroupNode)
-				}
-			}
-			l.freqToFreqGroupNode[1] = unitFrequencyGroupNode
-			// Increase the size of the cache.
-			l.size++
+			cacheItemNode = l.insertCacheItemNode(key, value)
 		}
 		// Also, create a mapping from key to cacheItemNode.
 		l.keyToCacheItem[key] = cacheItemNode
 	}
+	return cacheItemNode
+}
+
+// beforePut counts the put of the key and removes its cache item if it has
+// expired.
+func (l *cacheImpl[K, V]) beforePut(key K) {
+	l.stats.Puts++
+	if l.sketch != nil {
+		l.sketch.increment(key)
+	}
+	// The expired item is replaced as if it had not been in the cache.
+	if l.expiring {
+		if cacheItem, ok := l.keyToCacheItem[key]; ok {
+			l.expireIfDue(cacheItem)
+		}
+	}
+}
+
+// insertCacheItemNode places a new cache item into the group with
+// frequency 1, the cache must have room for it.
+func (l *cacheImpl[K, V]) insertCacheItemNode(key K, value V) *linkedlist.Node[CacheItem[K, V]] {
+	var unitFrequencyGroupNode *linkedlist.Node[FrequencyGroup[CacheItem[K, V]]]
+	// Create a cache item node to insert it into either the newly
+	// created list or an existing one.
+	cacheItemNode := l.getNewCacheItemNode(key, value)
+	// If the list has not been created yet, create it. A list emptied
+	// by Remove or Clear is reused.
+	if l.freqGroupsList == nil {
+		unitFrequencyGroupNode = createFrequencyGroupNode(
+			cacheItemNode, 1,
+		)
+		l.freqGroupsList = linkedlist.New(
+			unitFrequencyGroupNode,
+		)
+	} else {
+		// If the list has already been created, locate the group with
+		// frequency 1 and place the element there. If such a group
+		// does not exist, create it.
+		if l.freqGroupsList.Last().Value.frequency == 1 {
+			lastListElement := l.freqGroupsList.Last()
+			unitFrequencyGroupNode = lastListElement
+			cacheItemNode.Value.frequency =
+				unitFrequencyGroupNode.Value.frequency
+			unitFrequencyGroupNode.Value.elementsList.PushFront(cacheItemNode)
+		} else {
+			unitFrequencyGroupNode = l.getNewFrequencyGroupNode(
+				cacheItemNode, 1,
+			)
+			l.freqGroupsList.PushBack(unitFrequencyGroupNode)
+		}
+	}
+	l.freqToFreqGroupNode[1] = unitFrequencyGroupNode
+	// Increase the size of the cache.
+	l.size++
+	return cacheItemNode
+}
+
+// putWeighted is Put of the cache with a weigher.
+func (l *cacheImpl[K, V]) putWeighted(key K, value V) *linkedlist.Node[CacheItem[K, V]] {
+	weight := l.weigher(key, value)
+	if weight < 0 {
+		panic("Invalid weight")
+	}
+	cacheItemNode, _ := l.putWithCost(key, value, weight)
+	return cacheItemNode
+}
+
+// putWithCost puts the item of the given weight into the cache with a
+// weigher. Items which are heavier than the capacity are rejected, otherwise
+// the least frequently used items are invalidated until the item fits unless
+// the cost policy rejects the item.
+func (l *cacheImpl[K, V]) putWithCost(
+	key K,
+	value V,
+	weight int,
+) (*linkedlist.Node[CacheItem[K, V]], PutDecision) {
+	if l.capacity == 0 {
+		return nil, PutRejected
+	}
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if weight > l.capacity {
+		// The item never fits, so the old value is invalidated as well
+		// unless it is pinned.
+		if ok && !cacheItemNode.Value.pinned {
+			l.removeCacheItemNode(cacheItemNode, EvictionReasonCapacity)
+		}
+		return nil, PutRejected
+	}
+	if ok {
+		newWeight := l.weight + weight - cacheItemNode.Value.weight
+		if cacheItemNode.Value.pinned {
+			if l.pinnedWeight-cacheItemNode.Value.weight+weight > l.capacity {
+				return nil, PutRejected
+			}
+		} else if l.pinnedWeight+weight > l.capacity {
+			return nil, PutRejected
+		}
+		if newWeight > l.capacity && l.costPolicy != nil &&
+			!l.costPolicy(key, weight, l.victims(cacheItemNode, newWeight)) {
+			return nil, PutRejected
+		}
+		l.updateFreqAndMoveCacheItemNode(cacheItemNode)
+		cacheItemNode.Value.value = value
+		l.weight = newWeight
+		if cacheItemNode.Value.pinned {
+			l.pinnedWeight += weight - cacheItemNode.Value.weight
+		}
+		cacheItemNode.Value.weight = weight
+		decision := PutAdmitted
+		for l.weight > l.capacity {
+			l.removeCacheItemNode(l.leastFrequentlyUsed(cacheItemNode), EvictionReasonCapacity)
+			decision = PutAdmittedWithEvictions
+		}
+		return cacheItemNode, decision
+	}
+	decision := PutAdmitted
+	// Pinned items are never invalidated, so the item must fit besides them.
+	if l.pinnedWeight+weight > l.capacity {
+		return nil, PutRejected
+	}
+	if l.size != 0 && l.weight+weight > l.capacity {
+		if l.admission != nil && !l.admission(key, value) {
+			return nil, PutRejected
+		}
+		if l.sketch != nil && !l.sketch.admit(key, l.leastFrequentlyUsed(nil).Value.key) {
+			return nil, PutRejected
+		}
+		if l.costPolicy != nil && !l.costPolicy(key, weight, l.victims(nil, l.weight+weight)) {
+			return nil, PutRejected
+		}
+		decision = PutAdmittedWithEvictions
+	}
+	for l.size != 0 && l.weight+weight > l.capacity {
+		l.removeCacheItemNode(l.leastFrequentlyUsed(nil), EvictionReasonCapacity)
+	}
+	cacheItemNode = l.insertCacheItemNode(key, value)
+	cacheItemNode.Value.weight = weight
+	l.weight += weight
+	l.keyToCacheItem[key] = cacheItemNode
+	return cacheItemNode, decision
+}
+
+// leastFrequentlyUsed returns the cache item to be invalidated next other
+// than skip, which is the most recently used item of its group. Pinned items
+// are skipped, nil is returned if every item other than skip is pinned.
+//
+// O(1) unless any item is pinned, otherwise, O(number of pinned items)
+func (l *cacheImpl[K, V]) leastFrequentlyUsed(
+	skip *linkedlist.Node[CacheItem[K, V]],
+) *linkedlist.Node[CacheItem[K, V]] {
+	if l.pinned != 0 {
+		return l.leastFrequentlyUsedUnpinned(skip)
+	}
+	minFrequencyGroup := l.freqGroupsList.Last()
+	cacheItemNode := minFrequencyGroup.Value.elementsList.Last()
+	if cacheItemNode == skip {
+		// Since skip goes first in its group, it is the only item there.
+		cacheItemNode = minFrequencyGroup.Prev.Value.elementsList.Last()
+	}
+	return cacheItemNode
+}
+
+// leastFrequentlyUsedUnpinned walks the cache items from the least
+// frequently used one until an unpinned item other than skip is found.
+func (l *cacheImpl[K, V]) leastFrequentlyUsedUnpinned(
+	skip *linkedlist.Node[CacheItem[K, V]],
+) *linkedlist.Node[CacheItem[K, V]] {
+	if l.size == 0 {
+		return nil
+	}
+	frequencyGroupNode := l.freqGroupsList.Last()
+	for range len(l.freqToFreqGroupNode) {
+		cacheItemNode := frequencyGroupNode.Value.elementsList.Last()
+		for range frequencyGroupNode.Value.elementsList.Len() {
+			if cacheItemNode != skip && !cacheItemNode.Value.pinned {
+				return cacheItemNode
+			}
+			cacheItemNode = cacheItemNode.Prev
+		}
+		frequencyGroupNode = frequencyGroupNode.Prev
+	}
+	return nil
+}
+
+// putReplacingUnpinned puts the new item into the full cache with pinned
+// items, invalidating the least frequently used unpinned item. Nothing is put
+// if every item is pinned.
+func (l *cacheImpl[K, V]) putReplacingUnpinned(key K, value V) *linkedlist.Node[CacheItem[K, V]] {
+	victim := l.leastFrequentlyUsed(nil)
+	if victim == nil {
+		return nil
+	}
+	if l.admission != nil && !l.admission(key, value) {
+		return nil
+	}
+	if l.sketch != nil && !l.sketch.admit(key, victim.Value.key) {
+		return nil
+	}
+	l.removeCacheItemNode(victim, EvictionReasonCapacity)
+	cacheItemNode := l.insertCacheItemNode(key, value)
+	l.keyToCacheItem[key] = cacheItemNode
+	return cacheItemNode
+}
+
+func (l *cacheImpl[K, V]) Remove(key K) bool {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || l.expireIfDue(cacheItemNode) {
+		return false
+	}
+	l.removeCacheItemNode(cacheItemNode, EvictionReasonRemoved)
+	return true
+}
+
+// removeCacheItemNode removes the cache item from the cache and reports it to
+// the callbacks.
+func (l *cacheImpl[K, V]) removeCacheItemNode(
+	cacheItemNode *linkedlist.Node[CacheItem[K, V]],
+	reason EvictionReason,
+) {
+	delete(l.keyToCacheItem, cacheItemNode.Value.key)
+	l.unlinkCacheItemNode(cacheItemNode)
+	l.evicted(cacheItemNode.Value.key, cacheItemNode.Value.value, reason)
+	l.releaseCacheItemNode(cacheItemNode)
+}
+
+func (l *cacheImpl[K, V]) Clear() {
+	if l.size == 0 {
+		return
+	}
+	groupsNumber := len(l.freqToFreqGroupNode)
+	frequencyGroupNode := l.freqGroupsList.First()
+	// The cache becomes empty before the callbacks are called, the lists
+	// are still walked to recycle their nodes.
+	clear(l.freqToFreqGroupNode)
+	clear(l.keyToCacheItem)
+	l.size = 0
+	l.weight = 0
+	l.pinned = 0
+	l.pinnedWeight = 0
+	for range groupsNumber {
+		nextFrequencyGroupNode := frequencyGroupNode.Next
+		for !frequencyGroupNode.Value.elementsList.IsEmpty() {
+			cacheItemNode := frequencyGroupNode.Value.elementsList.First()
+			linkedlist.RemoveNode(cacheItemNode)
+			l.evicted(cacheItemNode.Value.key, cacheItemNode.Value.value, EvictionReasonRemoved)
+			l.releaseCacheItemNode(cacheItemNode)
+		}
+		linkedlist.RemoveNode(frequencyGroupNode)
+		l.freeNodesOfFreqGroups = append(l.freeNodesOfFreqGroups, frequencyGroupNode)
+		frequencyGroupNode = nextFrequencyGroupNode
+	}
+}
+
+func (l *cacheImpl[K, V]) Resize(newCapacity int) {
+	// Capacity cannot be negative.
+	if newCapacity < 0 {
+		panic("Invalid capacity")
+	}
+	l.capacity = newCapacity
+	l.shrink()
+}
+
+// shrink invalidates the least frequently used unpinned items until the cache
+// fits into its capacity or only pinned items are left.
+func (l *cacheImpl[K, V]) shrink() {
+	// The size is checked again after every callback, which may change the
+	// cache.
+	for l.overCapacity() {
+		cacheItemNode := l.leastFrequentlyUsed(nil)
+		if cacheItemNode == nil {
+			return
+		}
+		l.removeCacheItemNode(cacheItemNode, EvictionReasonCapacity)
+	}
+}
+
+// overCapacity reports whether the cache holds more than its capacity allows.
+func (l *cacheImpl[K, V]) overCapacity() bool {
+	if l.weigher != nil {
+		return l.weight > l.capacity
+	}
+	return l.size > l.capacity
+}
+
+// getNewCacheItemNode retrieves a new cache item node with the given key and
+// value, reusing an unused node if there is one.
+func (l *cacheImpl[K, V]) getNewCacheItemNode(key K, value V) *linkedlist.Node[CacheItem[K, V]] {
+	if l.nodePool != nil {
+		cacheItemNode := l.nodePool.Get().(*linkedlist.Node[CacheItem[K, V]])
+		cacheItemNode.Value.key = key
+		cacheItemNode.Value.value = value
+		return cacheItemNode
+	}
+	freeNodesLength := len(l.freeCacheItemNodes)
+	if freeNodesLength == 0 {
+		return linkedlist.NewNode(CacheItem[K, V]{
+			key:   key,
+			value: value,
+		})
+	}
+	cacheItemNode := l.freeCacheItemNodes[freeNodesLength-1]
+	l.freeCacheItemNodes = l.freeCacheItemNodes[:freeNodesLength-1]
+	cacheItemNode.Value.key = key
+	cacheItemNode.Value.value = value
+	return cacheItemNode
+}
+
+// releaseCacheItemNode places the unlinked cache item node in the list of
+// unused nodes. The key and the value are reset, so that the node does not
+// keep them alive.
+func (l *cacheImpl[K, V]) releaseCacheItemNode(cacheItemNode *linkedlist.Node[CacheItem[K, V]]) {
+	cacheItemNode.Value = CacheItem[K, V]{}
+	cacheItemNode.Next = nil
+	cacheItemNode.Prev = nil
+	if l.nodePool != nil {
+		l.nodePool.Put(cacheItemNode)
+		return
+	}
+	l.freeCacheItemNodes = append(l.freeCacheItemNodes, cacheItemNode)
+}
+
+// unlinkCacheItemNode removes the cache item from its frequency group. If the
+// group becomes empty, it is removed from freqGroupsList and its node is
+// placed in the list of unused nodes.
+func (l *cacheImpl[K, V]) unlinkCacheItemNode(
+	cacheItemNode *linkedlist.Node[CacheItem[K, V]],
+) {
+	frequency := cacheItemNode.Value.frequency
+	frequencyGroupNode := l.freqToFreqGroupNode[frequency]
+	linkedlist.RemoveNode(cacheItemNode)
+	if frequencyGroupNode.Value.elementsList.IsEmpty() {
+		delete(l.freqToFreqGroupNode, frequency)
+		linkedlist.RemoveNode(frequencyGroupNode)
+		l.freeNodesOfFreqGroups = append(l.freeNodesOfFreqGroups, frequencyGroupNode)
+	}
+	l.size--
+	l.weight -= cacheItemNode.Value.weight
+	if cacheItemNode.Value.pinned {
+		l.pinned--
+		l.pinnedWeight -= cacheItemNode.Value.weight
+	}
+}
+
+// evicted reports the item which has left the cache to the callbacks.
+func (l *cacheImpl[K, V]) evicted(key K, value V, reason EvictionReason) {
+	if reason != EvictionReasonRemoved {
+		l.stats.Evictions++
+	}
+	if reason == EvictionReasonExpired && l.onExpire != nil {
+		l.onExpire(key, value)
+	}
+	if l.onEvict != nil {
+		l.onEvict(key, value, reason)
+	}
+	if l.events != nil {
+		kind := EventEvict
+		if reason == EvictionReasonExpired {
+			kind = EventExpire
+		}
+		l.events.send(Event[K, V]{Kind: kind, Key: key, Value: value, Reason: reason})
+	}
 }
 
 // createFrequencyGroupNode creates node with group of given frequency which
@@ -235,7 +850,6 @@ func createFrequencyGroupNode[K comparable, V any](
 	frequencyGroupNode := linkedlist.NewNode(
 		FrequencyGroup[CacheItem[K, V]]{
 			elementsList: linkedlist.New(cacheItemNode),
-			size:         1,
 			frequency:    frequency,
 		},
 	)
@@ -248,17 +862,23 @@ func createFrequencyGroupNode[K comparable, V any](
 func (l *cacheImpl[K, V]) updateFreqAndMoveCacheItemNode(
 	cacheItemNode *linkedlist.Node[CacheItem[K, V]],
 ) {
+	// The LRU cache keeps all items in the group with frequency 1, so the
+	// item only becomes the most recently used one.
+	if l.policy == PolicyLRU {
+		linkedlist.RemoveNode(cacheItemNode)
+		l.freqToFreqGroupNode[1].Value.elementsList.PushFront(cacheItemNode)
+		return
+	}
 	// Retrieve frequency group of the cacheItemNode.
 	currentFrequency := cacheItemNode.Value.frequency
 	currentFrequencyGroupNode := l.freqToFreqGroupNode[currentFrequency]
 
 	// Increase the cache item's frequency by 1.
 	newFrequency := currentFrequency + 1
-	// Reduce the size of the frequency group before removing the element.
-	currentFrequencyGroupNode.Value.size--
-
-	// Check that the cache item being moved is not the last item in the list.
-	if currentFrequencyGroupNode.Value.size == 0 {
+	// Check whether the cache item being moved is the only item in its
+	// group.
+	onlyItem := currentFrequencyGroupNode.Value.elementsList.Len() == 1
+	if onlyItem {
 		// Otherwise, remove the frequency group from freqToFreqGroupNode.
 		delete(l.freqToFreqGroupNode, currentFrequency)
 	}
@@ -271,26 +891,24 @@ func (l *cacheImpl[K, V]) updateFreqAndMoveCacheItemNode(
 		// current cache item as the most recently used item in that group.
 		linkedlist.RemoveNode(cacheItemNode)
 		greaterFrequencyGroup.elementsList.PushFront(cacheItemNode)
-		currentFrequencyGroupNode.Prev.Value.size++
 		// Change the pointer to the frequency of the new group.
 		cacheItemNode.Value.frequency = greaterFrequencyGroup.frequency
 		// If the element was the last one in the old group, remember to place
 		// the node with the frequency group in the list of unused nodes.
-		if currentFrequencyGroupNode.Value.size == 0 {
+		if onlyItem {
 			linkedlist.RemoveNode(currentFrequencyGroupNode)
 			l.freeNodesOfFreqGroups = append(l.freeNodesOfFreqGroups, currentFrequencyGroupNode)
 		}
 	} else {
 		// If there is no group with a frequency equal to newFrequency, create
 		// this group and place the given cache item into it.
-		if currentFrequencyGroupNode.Value.size == 0 {
+		if onlyItem {
 			// If the element is the only one in the current group, simply
 			// update the frequency counter of the current group to the new
 			// value, and create a mapping from the new frequency to this
 			// group.
 			currentFrequencyGroupNode.Value.frequency = newFrequency
 			l.freqToFreqGroupNode[newFrequency] = currentFrequencyGroupNode
-			currentFrequencyGroupNode.Value.size++
 			cacheItemNode.Value.frequency = newFrequency
 		} else {
 			// If there are other elements remaining in the current group, the
@@ -333,7 +951,6 @@ func (l *cacheImpl[K, V]) getNewFrequencyGroupNode(
 		newFrequencyGroupNode.Value.elementsList.PushFront(cacheItemNode)
 		// Update the pointer in the cache item to the new frequency and
 		// refresh the frequency of the group.
-		newFrequencyGroupNode.Value.size = 1
 		newFrequencyGroupNode.Value.frequency = newFrequency
 		cacheItemNode.Value.frequency = newFrequency
 	}
@@ -342,17 +959,53 @@ func (l *cacheImpl[K, V]) getNewFrequencyGroupNode(
 
 func (l *cacheImpl[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
+		for cacheItem := range l.items() {
+			if !yield(cacheItem.key, cacheItem.value) {
+				return
+			}
+		}
+	}
+}
+
+func (l *cacheImpl[K, V]) Entries() iter.Seq[Entry[K, V]] {
+	return entries(l.items())
+}
+
+// entries converts cache items to entries.
+func entries[K comparable, V any](items iter.Seq[CacheItem[K, V]]) iter.Seq[Entry[K, V]] {
+	return func(yield func(Entry[K, V]) bool) {
+		for cacheItem := range items {
+			if !yield(Entry[K, V]{
+				Key:       cacheItem.key,
+				Value:     cacheItem.value,
+				Frequency: cacheItem.frequency,
+			}) {
+				return
+			}
+		}
+	}
+}
+
+// items iterates over cache items in descending order of frequency, the most
+// recently used items of a frequency go first.
+func (l *cacheImpl[K, V]) items() iter.Seq[CacheItem[K, V]] {
+	return func(yield func(CacheItem[K, V]) bool) {
 		// If nothing has been placed in the cache, then the freqGroupsList
 		// has not been created.
 		if l.size == 0 {
 			return
 		}
+		now := l.expirationTime()
 		// If there is at least one element in the cache, then freqGroupsList
 		// can be iterated over (as can elementsList).
 		l.freqGroupsList.All()(func(freqGroup FrequencyGroup[CacheItem[K, V]]) bool {
 			yieldResult := true
 			freqGroup.elementsList.All()(func(cacheItem CacheItem[K, V]) bool {
-				yieldResult = yield(cacheItem.key, cacheItem.value)
+				// Expired items are skipped, they are removed on access.
+				if cacheItem.expiresAt != 0 && cacheItem.expiresAt <= now {
+					return true
+				}
+				yieldResult = yield(cacheItem)
 				return yieldResult
 			})
 			return yieldResult
@@ -364,16 +1017,27 @@ func (l *cacheImpl[K, V]) Size() int {
 	return l.size
 }
 
+// Len returns the cache size, as required by cache.Cache.
+func (l *cacheImpl[K, V]) Len() int {
+	return l.size
+}
+
 func (l *cacheImpl[K, V]) Capacity() int {
 	return l.capacity
 }
 
+func (l *cacheImpl[K, V]) Stats() Stats {
+	stats := l.stats
+	stats.Size = l.size
+	return stats
+}
+
 func (l *cacheImpl[K, V]) GetKeyFrequency(key K) (int, error) {
 	// If the element exists, it will be found in the keyToCacheItem mapping,
 	// or an error will be returned otherwise.
 	// At the same time, there is no need to increase the frequency since the
 	// cache item itself is not being retrieving.
-	if element, ok := l.keyToCacheItem[key]; !ok {
+	if element, ok := l.keyToCacheItem[key]; !ok || l.isExpired(element.Value.expiresAt) {
 		return 0, ErrKeyNotFound
 	} else {
 		return element.Value.frequency, nil
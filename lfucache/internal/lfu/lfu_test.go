@@ -1,10 +1,13 @@
 package lfu
 
 import (
+	"errors"
 	"iter"
+	"lfucache/internal/cache"
 	"math/rand/v2"
 	"slices"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/stretchr/testify/require"
@@ -15,6 +18,11 @@ func testImplements[K comparable, V any]() Cache[K, V] {
 	return New[K, V](1)
 }
 
+// must compile
+func testImplementsCommonCache[K comparable, V any]() []cache.Cache[K, V] {
+	return []cache.Cache[K, V]{New[K, V](1), NewSharded[K, V](1), NewARC[K, V](1)}
+}
+
 func TestWithoutInvalidation(t *testing.T) {
 	t.Parallel()
 
@@ -518,7 +526,7 @@ func TestAllIterator(t *testing.T) {
 	require.Equal(t, []int{50, 40, 30, 20, 10}, values)
 }
 
-func collect[K comparable, V any](iterator iter.Seq2[K, V]) ([]K, []V) {
+func collect[K any, V any](iterator iter.Seq2[K, V]) ([]K, []V) {
 	keys := make([]K, 0)
 	values := make([]V, 0)
 
@@ -529,3 +537,469 @@ func collect[K comparable, V any](iterator iter.Seq2[K, V]) ([]K, []V) {
 
 	return keys, values
 }
+
+func TestOnEvict(t *testing.T) {
+	t.Parallel()
+
+	type eviction struct {
+		key    int
+		value  string
+		reason EvictionReason
+	}
+	var evictions []eviction
+
+	cache := NewWithOptions(2, WithOnEvict(func(key int, value string, reason EvictionReason) {
+		evictions = append(evictions, eviction{key, value, reason})
+	}))
+
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	cache.Put(2, "second")
+	require.Empty(t, evictions)
+
+	cache.Put(3, "three")
+	require.Equal(t, []eviction{{1, "one", EvictionReasonCapacity}}, evictions)
+
+	cache.Put(4, "four")
+	require.Equal(t, []eviction{
+		{1, "one", EvictionReasonCapacity},
+		{3, "three", EvictionReasonCapacity},
+	}, evictions)
+}
+
+func TestEvictedIntoItemHasUnitFrequency(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](1)
+
+	cache.Put(1, 1)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+
+	frequency, err := cache.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	var removed []int
+	cache := NewWithOptions(3, WithOnEvict(func(key int, _ int, reason EvictionReason) {
+		require.Equal(t, EvictionReasonRemoved, reason)
+		removed = append(removed, key)
+	}))
+
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	cache.Put(3, 30)
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+
+	require.True(t, cache.Remove(2))
+	require.False(t, cache.Remove(2))
+	require.False(t, cache.Remove(42))
+	require.Equal(t, []int{2}, removed)
+	require.Equal(t, 2, cache.Size())
+
+	_, err = cache.Get(2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	keys, values := collect(cache.All())
+	require.Equal(t, []int{3, 1}, keys)
+	require.Equal(t, []int{30, 10}, values)
+
+	// The freed slot is reused without eviction.
+	cache.Put(4, 40)
+	require.Equal(t, 3, cache.Size())
+	require.Equal(t, []int{2}, removed)
+
+	frequency, err := cache.GetKeyFrequency(4)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+}
+
+func TestRemoveAll(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](4)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j <= i; j++ {
+			cache.Put(i, i)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		require.True(t, cache.Remove(i))
+	}
+	require.Zero(t, cache.Size())
+	keys, _ := collect(cache.All())
+	require.Empty(t, keys)
+
+	for i := 0; i < 6; i++ {
+		cache.Put(i, i)
+	}
+	_, err := cache.Get(5)
+	require.NoError(t, err)
+
+	keys, _ = collect(cache.All())
+	require.Equal(t, []int{5, 4, 3, 2}, keys)
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	var cleared []int
+	cache := NewWithOptions(4, WithOnEvict(func(key int, _ int, reason EvictionReason) {
+		if reason == EvictionReasonRemoved {
+			cleared = append(cleared, key)
+		}
+	}))
+
+	cache.Clear()
+	require.Empty(t, cleared)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j <= i%2; j++ {
+			cache.Put(i, i)
+		}
+	}
+
+	cache.Clear()
+	slices.Sort(cleared)
+	require.Equal(t, []int{0, 1, 2, 3}, cleared)
+	require.Zero(t, cache.Size())
+	require.Equal(t, 4, cache.Capacity())
+	keys, _ := collect(cache.All())
+	require.Empty(t, keys)
+	_, err := cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	for i := 10; i < 15; i++ {
+		cache.Put(i, i)
+	}
+	_, err = cache.Get(14)
+	require.NoError(t, err)
+
+	keys, values := collect(cache.All())
+	require.Equal(t, []int{14, 13, 12, 11}, keys)
+	require.Equal(t, keys, values)
+}
+
+func TestClearReusesNodes(t *testing.T) {
+	const capacity = 1000
+
+	cache := New[int, int](capacity)
+	fill := func() {
+		for i := 0; i < capacity; i++ {
+			for j := 0; j <= i%3; j++ {
+				cache.Put(i, i)
+			}
+		}
+	}
+
+	fill()
+	cache.Clear()
+
+	allocs := testing.AllocsPerRun(10, func() {
+		fill()
+		cache.Clear()
+	})
+	require.Zero(t, allocs)
+}
+
+func TestResize(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(5, WithOnEvict(func(key int, _ int, reason EvictionReason) {
+		require.Equal(t, EvictionReasonCapacity, reason)
+		evicted = append(evicted, key)
+	}))
+
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	_, err := cache.Get(0)
+	require.NoError(t, err)
+	_, err = cache.Get(3)
+	require.NoError(t, err)
+
+	cache.Resize(2)
+	require.Equal(t, []int{1, 2, 4}, evicted)
+	require.Equal(t, 2, cache.Size())
+	require.Equal(t, 2, cache.Capacity())
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{3, 0}, keys)
+
+	cache.Resize(4)
+	cache.Put(5, 5)
+	cache.Put(6, 6)
+	require.Equal(t, 4, cache.Size())
+	require.Equal(t, []int{1, 2, 4}, evicted)
+
+	cache.Put(7, 7)
+	require.Equal(t, []int{1, 2, 4, 5}, evicted)
+
+	cache.Resize(0)
+	require.Zero(t, cache.Size())
+	cache.Put(8, 8)
+	require.Zero(t, cache.Size())
+	_, err = cache.Get(8)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	require.Panics(t, func() { cache.Resize(-1) })
+}
+
+func TestResizeEvictionOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []Policy{PolicyLFU, PolicyLRU} {
+		var evicted []int
+		cache := NewWithOptions(20,
+			WithPolicy[int, int](policy),
+			WithEvents[int, int](20),
+			WithOnEvict(func(key int, _ int, reason EvictionReason) {
+				require.Equal(t, EvictionReasonCapacity, reason)
+				evicted = append(evicted, key)
+			}),
+		)
+		for i := range 20 {
+			cache.Put(i, i)
+			for range i % 4 {
+				_, err := cache.Get(i)
+				require.NoError(t, err)
+			}
+		}
+		receive(cache.Events())
+
+		ascending, _ := collect(cache.AllAscending())
+		cache.Resize(7)
+		require.Equal(t, ascending[:13], evicted)
+		kept, _ := collect(cache.AllAscending())
+		require.Equal(t, ascending[13:], kept)
+
+		var eventKeys []int
+		for _, event := range receive(cache.Events()) {
+			require.Equal(t, EventEvict, event.Kind)
+			eventKeys = append(eventKeys, event.Key)
+		}
+		require.Equal(t, evicted, eventKeys)
+		require.Equal(t, uint64(13), cache.Stats().Evictions)
+	}
+}
+
+func TestResizeWeighted(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(10,
+		WithWeigher(func(_ int, value int) int { return value }),
+		WithOnEvict(func(key int, _ int, _ EvictionReason) { evicted = append(evicted, key) }),
+	)
+	// Items of zero weight do not count towards the capacity.
+	cache.Put(1, 0)
+	cache.Put(2, 0)
+	cache.Put(3, 4)
+	cache.Put(4, 3)
+
+	cache.Resize(3)
+	require.Equal(t, []int{1, 2, 3}, evicted)
+	cache.Resize(2)
+	require.Equal(t, []int{1, 2, 3, 4}, evicted)
+
+	cache.Put(5, 0)
+	cache.Put(6, 0)
+	cache.Put(7, 0)
+	cache.Resize(0)
+	require.Equal(t, 3, cache.Size())
+}
+
+func TestPeek(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](2)
+
+	_, err := cache.Peek(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.Peek(1)
+		require.NoError(t, err)
+		require.Equal(t, "one", value)
+	}
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+
+	// Peek does not make the key the most recently used one, so it is still
+	// the one to be invalidated.
+	cache.Put(3, "three")
+	_, err = cache.Peek(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](2)
+	var view View[int, int] = cache
+
+	require.False(t, view.Contains(1))
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	require.True(t, view.Contains(1))
+	require.Equal(t, 2, view.Size())
+	require.Equal(t, 2, view.Capacity())
+
+	// Contains does not make the key the most recently used one.
+	cache.Put(3, 3)
+	require.False(t, view.Contains(1))
+	require.True(t, view.Contains(2))
+	require.True(t, view.Contains(3))
+
+	frequency, err := view.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](3)
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	for i := 0; i < 2; i++ {
+		_, err := cache.Get("b")
+		require.NoError(t, err)
+	}
+	_, err := cache.Get("a")
+	require.NoError(t, err)
+
+	require.Equal(t, []Entry[string, int]{
+		{Key: "b", Value: 2, Frequency: 3},
+		{Key: "a", Value: 1, Frequency: 2},
+		{Key: "c", Value: 3, Frequency: 1},
+	}, slices.Collect(cache.Entries()))
+
+	for entry := range cache.Entries() {
+		require.Equal(t, "b", entry.Key)
+		break
+	}
+
+	require.Empty(t, slices.Collect(New[string, int]().Entries()))
+}
+
+func TestGetOrCompute(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](2)
+	calls := 0
+	compute := func() (string, error) {
+		calls++
+		return "computed", nil
+	}
+
+	value, err := cache.GetOrCompute(1, compute)
+	require.NoError(t, err)
+	require.Equal(t, "computed", value)
+	require.Equal(t, 1, calls)
+
+	value, err = cache.GetOrCompute(1, compute)
+	require.NoError(t, err)
+	require.Equal(t, "computed", value)
+	require.Equal(t, 1, calls)
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 2, frequency)
+
+	computeErr := errors.New("compute failed")
+	_, err = cache.GetOrCompute(2, func() (string, error) {
+		return "", computeErr
+	})
+	require.ErrorIs(t, err, computeErr)
+	require.False(t, cache.Contains(2))
+}
+
+func TestZeroCapacity(t *testing.T) {
+	t.Parallel()
+
+	caches := map[string]Cache[int, int]{
+		"lfu":      New[int, int](0),
+		"weighted": NewWithOptions(0, WithWeigher(func(int, int) int { return 0 })),
+		"sharded":  NewSharded[int, int](0),
+		"arc":      NewARC[int, int](0),
+	}
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cache.Put(1, 1)
+			cache.Put(1, 1)
+			require.Zero(t, cache.Size())
+			require.Zero(t, cache.Capacity())
+
+			_, err := cache.Get(1)
+			require.ErrorIs(t, err, ErrKeyNotFound)
+			require.False(t, cache.Remove(1))
+
+			keys, _ := collect(cache.All())
+			require.Empty(t, keys)
+		})
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewWithConfig(Config[int, int]{
+		Capacity: 2,
+		Options:  []Option[int, int]{WithPolicy[int, int](PolicyLRU)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Capacity())
+
+	_, err = NewWithConfig(Config[int, int]{Capacity: -1})
+	require.ErrorIs(t, err, ErrInvalidCapacity)
+
+	_, err = NewWithConfig(Config[int, int]{
+		Capacity: 1,
+		Options:  []Option[int, int]{WithPolicy[int, int](Policy(42))},
+	})
+	require.ErrorIs(t, err, ErrInvalidPolicy)
+
+	_, err = NewWithConfig(Config[int, int]{
+		Capacity: 1,
+		Options:  []Option[int, int]{WithTTL[int, int](-time.Second)},
+	})
+	require.ErrorIs(t, err, ErrInvalidTTL)
+
+	require.Panics(t, func() { NewWithOptions(1, WithPolicy[int, int](Policy(42))) })
+	require.Panics(t, func() { NewWithOptions(1, WithTTL[int, int](-time.Second)) })
+	require.Panics(t, func() { New[int, int](1, 2) })
+}
+
+func TestCommonCache(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range testImplementsCommonCache[int, int]() {
+		c.Put(1, 1)
+		require.Equal(t, 1, c.Len())
+
+		_, err := c.Get(2)
+		require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+		require.True(t, c.Remove(1))
+		require.Zero(t, c.Len())
+	}
+}
@@ -0,0 +1,124 @@
+package lfu
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetOrLoad returns the value of the key, if it is missing, the value is
+// loaded with the context of the caller and put into the cache unless load
+// returns an error.
+func (l *cacheImpl[K, V]) GetOrLoad(
+	ctx context.Context,
+	key K,
+	load func(ctx context.Context) (V, error),
+) (V, error) {
+	if value, err := l.get(key, false); err == nil {
+		return value, nil
+	}
+	if err := ctx.Err(); err != nil {
+		l.loaded(key, time.Time{})
+		var value V
+		return value, err
+	}
+	start := l.loadStart()
+	value, err := load(ctx)
+	l.loaded(key, start)
+	if err != nil {
+		return value, err
+	}
+	l.Put(key, value)
+	return value, nil
+}
+
+// GetOrLoad returns the value of the key, if it is missing, goroutines
+// requesting it at the same time share a single call of load, which runs in
+// its own goroutine. The context passed to load carries the values of the
+// context of the goroutine which has started the load, it is cancelled only
+// once every waiting goroutine has given up. A goroutine gives up when its own
+// context is done and gets the error of its context, the others keep waiting.
+// A goroutine of GetOrCompute joining the load never gives up.
+// A panic in load is returned as ErrComputePanicked.
+func (c *shardedCacheImpl[K, V]) GetOrLoad(
+	ctx context.Context,
+	key K,
+	load func(ctx context.Context) (V, error),
+) (V, error) {
+	s := c.shardFor(key)
+	s.lock()
+	if value, err := s.cache.get(key, false); err == nil {
+		s.mu.Unlock()
+		return value, nil
+	}
+	if err := ctx.Err(); err != nil {
+		s.cache.loaded(key, time.Time{})
+		s.mu.Unlock()
+		var value V
+		return value, err
+	}
+	f, ok := s.flights[key]
+	if !ok {
+		f = &flight[V]{done: make(chan struct{})}
+		if s.flights == nil {
+			s.flights = make(map[K]*flight[V])
+		}
+		s.flights[key] = f
+		var loadCtx context.Context
+		loadCtx, f.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		go s.load(loadCtx, key, f, load)
+	} else {
+		// The latency of the load is reported by the call which has started
+		// it.
+		s.cache.loaded(key, time.Time{})
+	}
+	f.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		s.lock()
+		f.waiters--
+		// The computation of GetOrCompute cannot be cancelled.
+		if f.waiters == 0 && f.cancel != nil {
+			f.cancel()
+			// The goroutines coming later start a new load instead of
+			// joining the cancelled one.
+			delete(s.flights, key)
+		}
+		s.mu.Unlock()
+		var value V
+		return value, ctx.Err()
+	}
+}
+
+// load runs the load of the flight and puts its result into the shard.
+func (s *shard[K, V]) load(
+	ctx context.Context,
+	key K,
+	f *flight[V],
+	load func(ctx context.Context) (V, error),
+) {
+	start := s.cache.loadStart()
+	defer func() {
+		if r := recover(); r != nil {
+			var value V
+			f.value, f.err = value, fmt.Errorf("%w: %v", ErrComputePanicked, r)
+		}
+		s.lock()
+		s.cache.loaded(key, start)
+		if f.err == nil {
+			s.cache.Put(key, f.value)
+		}
+		// The flight may have been abandoned and replaced with a new one.
+		if s.flights[key] == f {
+			delete(s.flights, key)
+		}
+		s.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+	f.value, f.err = load(ctx)
+}
@@ -0,0 +1,218 @@
+package lfu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type loadKey struct{}
+
+func TestGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	ctx := context.WithValue(context.Background(), loadKey{}, 42)
+
+	value, err := cache.GetOrLoad(ctx, 1, func(ctx context.Context) (int, error) {
+		return ctx.Value(loadKey{}).(int), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 42, value)
+
+	value, err = cache.GetOrLoad(ctx, 1, func(context.Context) (int, error) { return 0, nil })
+	require.NoError(t, err)
+	require.Equal(t, 42, value)
+
+	loadErr := errors.New("load failed")
+	_, err = cache.GetOrLoad(ctx, 2, func(context.Context) (int, error) { return 0, loadErr })
+	require.ErrorIs(t, err, loadErr)
+	require.False(t, cache.Contains(2))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = cache.GetOrLoad(cancelled, 2, func(context.Context) (int, error) { return 2, nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestShardedGetOrLoadWaitersCancelledIndividually(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		require.Equal(t, 42, ctx.Value(loadKey{}))
+		select {
+		case <-release:
+			return 42, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// The goroutine which starts the load gives up first.
+	leaderCtx, cancelLeader := context.WithCancel(context.WithValue(context.Background(), loadKey{}, 42))
+	leaderErr := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(leaderCtx, 1, load)
+		leaderErr <- err
+	}()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetOrLoad(context.Background(), 1, load)
+			require.NoError(t, err)
+			require.Equal(t, 42, value)
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		s := cache.shardFor(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.flights[1].waiters == 5
+	}, time.Second, time.Millisecond)
+
+	cancelLeader()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+	require.True(t, cache.Contains(1))
+}
+
+func TestShardedGetOrLoadJoinedByGetOrCompute(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	loaderErr := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(ctx, 1, func(ctx context.Context) (int, error) {
+			close(started)
+			select {
+			case <-release:
+				return 42, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		})
+		loaderErr <- err
+	}()
+	<-started
+
+	computed := make(chan int)
+	go func() {
+		value, err := cache.GetOrCompute(1, func() (int, error) { return 0, nil })
+		require.NoError(t, err)
+		computed <- value
+	}()
+	require.Eventually(t, func() bool {
+		s := cache.shardFor(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.flights[1].waiters == 2
+	}, time.Second, time.Millisecond)
+
+	// The load is not cancelled, since GetOrCompute still waits for it.
+	cancel()
+	require.ErrorIs(t, <-loaderErr, context.Canceled)
+	close(release)
+	require.Equal(t, 42, <-computed)
+}
+
+func TestShardedGetOrLoadCancelledByAllWaiters(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	loadDone := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := cache.GetOrLoad(ctx, 1, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		loadDone <- ctx.Err()
+		return 0, ctx.Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, <-loadDone, context.Canceled)
+
+	require.Eventually(t, func() bool {
+		value, err := cache.GetOrLoad(context.Background(), 1, func(context.Context) (int, error) {
+			return 1, nil
+		})
+		return err == nil && value == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestShardedGetOrLoadAfterCancel(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		// The load ignores the cancellation until it is released.
+		_, err := cache.GetOrLoad(ctx, 1, func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 0, context.Canceled
+		})
+		errs <- err
+	}()
+	<-started
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	// The cancelled load is still running, a new caller starts its own.
+	value, err := cache.GetOrLoad(context.Background(), 1, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		s := cache.shardFor(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.flights) == 0
+	}, time.Second, time.Millisecond)
+	value, err = cache.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+}
+
+func TestShardedGetOrLoadPanic(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	_, err := cache.GetOrLoad(context.Background(), 1, func(context.Context) (int, error) {
+		panic("boom")
+	})
+	require.ErrorIs(t, err, ErrComputePanicked)
+	require.False(t, cache.Contains(1))
+}
@@ -0,0 +1,27 @@
+package lfu
+
+import "context"
+
+// Loader is the cache which loads the missing values, both the LFU and the
+// sharded caches implement it.
+type Loader[K comparable, V any] interface {
+	GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error)
+}
+
+// Memoize returns the function which returns the results of fn cached in the
+// cache, fn is called only for the keys missing in the cache and its errors
+// are not cached. With the sharded cache, concurrent calls for the same key
+// share a single call of fn, and the cache created with WithTTL makes the
+// results expire, e.g.
+//
+//	getBook := lfu.Memoize(lfu.NewShardedWithOptions(1024, 16, lfu.WithTTL[int, Book](time.Minute)), usecase.GetBook)
+func Memoize[K comparable, V any](
+	cache Loader[K, V],
+	fn func(ctx context.Context, key K) (V, error),
+) func(ctx context.Context, key K) (V, error) {
+	return func(ctx context.Context, key K) (V, error) {
+		return cache.GetOrLoad(ctx, key, func(ctx context.Context) (V, error) {
+			return fn(ctx, key)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package lfu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoize(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	cache := NewShardedWithOptions(10, 2, WithTTL[int, int](time.Minute), WithClock[int, int](clock))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loadErr := errors.New("load failed")
+	square := Memoize(cache, func(_ context.Context, key int) (int, error) {
+		calls.Add(1)
+		<-release
+		if key < 0 {
+			return 0, loadErr
+		}
+		return key * key, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := square(context.Background(), 3)
+			require.NoError(t, err)
+			require.Equal(t, 9, value)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		s := cache.shardFor(3)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.flights[3] != nil && s.flights[3].waiters == 5
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+
+	value, err := square(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 9, value)
+	require.Equal(t, int32(1), calls.Load())
+
+	clock.Advance(time.Minute)
+	_, err = square(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+
+	_, err = square(context.Background(), -1)
+	require.ErrorIs(t, err, loadErr)
+	_, err = square(context.Background(), -1)
+	require.ErrorIs(t, err, loadErr)
+	require.Equal(t, int32(4), calls.Load())
+}
@@ -0,0 +1,67 @@
+package lfu
+
+import (
+	"unsafe"
+
+	"lfucache/internal/linkedlist"
+)
+
+// mapEntryOverhead is the approximate number of bytes a map spends on an
+// entry besides its key and value: the control byte and the unused slots.
+const mapEntryOverhead = 8
+
+// Sizer computes the number of bytes held by the key and the value besides
+// their shallow size, e.g. the bytes of a string or a slice.
+type Sizer[K comparable, V any] func(key K, value V) int
+
+// WithSizer sets the sizer used by EstimateMemory to account for the memory
+// referenced by the keys and the values.
+func WithSizer[K comparable, V any](sizer Sizer[K, V]) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.sizer = sizer
+	}
+}
+
+// EstimateMemory returns the approximate number of bytes held by the cache:
+// the nodes of the items and the frequency groups with their map entries, and
+// the memory referenced by the items as computed by the sizer, if it is set.
+// Unused nodes kept for reuse are counted as well.
+//
+// O(1) without a sizer, O(size) otherwise.
+func (l *cacheImpl[K, V]) EstimateMemory() int {
+	var (
+		key            K
+		cacheItemNode  linkedlist.Node[CacheItem[K, V]]
+		frequencyGroup linkedlist.Node[FrequencyGroup[CacheItem[K, V]]]
+	)
+	itemSize := int(unsafe.Sizeof(cacheItemNode)) +
+		int(unsafe.Sizeof(key)) + int(unsafe.Sizeof(&cacheItemNode)) + mapEntryOverhead
+	groupSize := int(unsafe.Sizeof(frequencyGroup)) +
+		int(unsafe.Sizeof(0)) + int(unsafe.Sizeof(&frequencyGroup)) + mapEntryOverhead
+
+	memory := int(unsafe.Sizeof(*l)) +
+		l.size*itemSize + len(l.freqToFreqGroupNode)*groupSize +
+		cap(l.freeCacheItemNodes)*int(unsafe.Sizeof(&cacheItemNode)) +
+		len(l.freeCacheItemNodes)*int(unsafe.Sizeof(cacheItemNode)) +
+		cap(l.freeNodesOfFreqGroups)*int(unsafe.Sizeof(&frequencyGroup)) +
+		len(l.freeNodesOfFreqGroups)*int(unsafe.Sizeof(frequencyGroup))
+	if l.sizer != nil {
+		// Expired items still hold their memory.
+		for _, cacheItemNode := range l.keyToCacheItem {
+			memory += l.sizer(cacheItemNode.Value.key, cacheItemNode.Value.value)
+		}
+	}
+	return memory
+}
+
+// EstimateMemory returns the approximate number of bytes held by the shards.
+func (c *shardedCacheImpl[K, V]) EstimateMemory() int {
+	memory := int(unsafe.Sizeof(*c))
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		memory += int(unsafe.Sizeof(*s)) + s.cache.EstimateMemory()
+		s.mu.Unlock()
+	}
+	return memory
+}
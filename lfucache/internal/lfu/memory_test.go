@@ -0,0 +1,48 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](100)
+	empty := cache.EstimateMemory()
+	require.Positive(t, empty)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, "value")
+	}
+	shallow := cache.EstimateMemory()
+	require.Greater(t, shallow, empty)
+
+	for i := 10; i < 20; i++ {
+		cache.Put(i, "value")
+	}
+	require.Greater(t, cache.EstimateMemory(), shallow)
+
+	sized := NewWithOptions(100, WithSizer(func(_ int, value string) int {
+		return len(value)
+	}))
+	for i := 0; i < 20; i++ {
+		sized.Put(i, "value")
+	}
+	require.Equal(t, cache.EstimateMemory()+20*len("value"), sized.EstimateMemory())
+}
+
+func TestShardedEstimateMemory(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(100, 4, WithSizer(func(_ int, value []byte) int {
+		return cap(value)
+	}))
+	empty := cache.EstimateMemory()
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, make([]byte, 1024))
+	}
+	require.GreaterOrEqual(t, cache.EstimateMemory(), empty+10*1024)
+}
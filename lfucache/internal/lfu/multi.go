@@ -0,0 +1,79 @@
+package lfu
+
+// GetMulti returns the values of the keys found in the cache and the keys
+// which are missing, in the order of keys. Found keys are used as by Get.
+func (l *cacheImpl[K, V]) GetMulti(keys []K) (map[K]V, []K) {
+	found := make(map[K]V, len(keys))
+	var missing []K
+	for _, key := range keys {
+		if value, err := l.Get(key); err == nil {
+			found[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// PutMulti puts the values of the map into the cache as by Put, in no
+// particular order.
+func (l *cacheImpl[K, V]) PutMulti(values map[K]V) {
+	for key, value := range values {
+		l.Put(key, value)
+	}
+}
+
+// GetMulti returns the values of the keys found in the cache and the keys
+// which are missing, in the order of keys. The lock of every shard is taken
+// once.
+func (c *shardedCacheImpl[K, V]) GetMulti(keys []K) (map[K]V, []K) {
+	shardKeys := make([][]K, len(c.shards))
+	for _, key := range keys {
+		i := c.shardIndex(key)
+		shardKeys[i] = append(shardKeys[i], key)
+	}
+
+	found := make(map[K]V, len(keys))
+	for i := range c.shards {
+		if len(shardKeys[i]) == 0 {
+			continue
+		}
+		s := &c.shards[i]
+		s.lock()
+		for _, key := range shardKeys[i] {
+			if value, err := s.cache.Get(key); err == nil {
+				found[key] = value
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	var missing []K
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// PutMulti puts the values of the map into the cache as by Put, in no
+// particular order. The lock of every shard is taken once.
+func (c *shardedCacheImpl[K, V]) PutMulti(values map[K]V) {
+	shardEntries := make([][]Entry[K, V], len(c.shards))
+	for key, value := range values {
+		i := c.shardIndex(key)
+		shardEntries[i] = append(shardEntries[i], Entry[K, V]{Key: key, Value: value})
+	}
+	for i := range c.shards {
+		if len(shardEntries[i]) == 0 {
+			continue
+		}
+		s := &c.shards[i]
+		s.lock()
+		for _, entry := range shardEntries[i] {
+			s.cache.Put(entry.Key, entry.Value)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,46 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPutMulti(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](10)
+
+	cache.PutMulti(map[string]int{"a": 1, "b": 2, "c": 3})
+	require.Equal(t, 3, cache.Size())
+
+	found, missing := cache.GetMulti([]string{"x", "a", "c", "y"})
+	require.Equal(t, map[string]int{"a": 1, "c": 3}, found)
+	require.Equal(t, []string{"x", "y"}, missing)
+
+	frequency, err := cache.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, frequency)
+
+	found, missing = cache.GetMulti(nil)
+	require.Empty(t, found)
+	require.Empty(t, missing)
+}
+
+func TestShardedGetPutMulti(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	values := make(map[int]int)
+	for i := 0; i < 20; i++ {
+		values[i] = i * i
+	}
+	cache.PutMulti(values)
+	require.Equal(t, 20, cache.Size())
+
+	keys := []int{25, 3, 7, 20, 19}
+	found, missing := cache.GetMulti(keys)
+	require.Equal(t, map[int]int{3: 9, 7: 49, 19: 361}, found)
+	require.Equal(t, []int{25, 20}, missing)
+}
@@ -0,0 +1,165 @@
+package lfu
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// NamespacedKey is the key of the underlying cache of NamespacedCache: the
+// key of a namespace prefixed with its name.
+type NamespacedKey[K comparable] struct {
+	Namespace string
+	Key       K
+}
+
+// NamespacedCache shares one cache, and so one capacity, between several
+// logical datasets, e.g. books, authors and sessions. The keys of different
+// namespaces never collide and compete for the capacity as the keys of a
+// single cache do.
+type NamespacedCache[K comparable, V any] struct {
+	cache Cache[NamespacedKey[K], V]
+
+	mu         sync.Mutex
+	namespaces map[string]*Namespace[K, V]
+}
+
+// NewNamespaced creates the cache storing the keys of all namespaces in the
+// given cache.
+func NewNamespaced[K comparable, V any](cache Cache[NamespacedKey[K], V]) *NamespacedCache[K, V] {
+	return &NamespacedCache[K, V]{
+		cache:      cache,
+		namespaces: make(map[string]*Namespace[K, V]),
+	}
+}
+
+// Namespace returns the view of the namespace with the given name, the same
+// view is returned for the same name.
+func (c *NamespacedCache[K, V]) Namespace(name string) *Namespace[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	namespace, ok := c.namespaces[name]
+	if !ok {
+		namespace = &Namespace[K, V]{name: name, cache: c.cache}
+		c.namespaces[name] = namespace
+	}
+	return namespace
+}
+
+// Cache returns the underlying cache, e.g. to resize it or to get the total
+// statistics.
+func (c *NamespacedCache[K, V]) Cache() Cache[NamespacedKey[K], V] {
+	return c.cache
+}
+
+// Namespace is the view of the keys of one namespace of NamespacedCache. It is
+// safe for concurrent use if the underlying cache is.
+type Namespace[K comparable, V any] struct {
+	name  string
+	cache Cache[NamespacedKey[K], V]
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	puts   atomic.Uint64
+}
+
+func (n *Namespace[K, V]) key(key K) NamespacedKey[K] {
+	return NamespacedKey[K]{Namespace: n.name, Key: key}
+}
+
+// Name returns the name of the namespace.
+func (n *Namespace[K, V]) Name() string {
+	return n.name
+}
+
+// Get returns the value of the key as Cache.Get does.
+func (n *Namespace[K, V]) Get(key K) (V, error) {
+	value, err := n.cache.Get(n.key(key))
+	if err == nil {
+		n.hits.Add(1)
+	} else {
+		n.misses.Add(1)
+	}
+	return value, err
+}
+
+// GetOrCompute returns the value of the key as Cache.GetOrCompute does.
+func (n *Namespace[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	computed := false
+	value, err := n.cache.GetOrCompute(n.key(key), func() (V, error) {
+		computed = true
+		return compute()
+	})
+	if computed {
+		n.misses.Add(1)
+		if err == nil {
+			n.puts.Add(1)
+		}
+	} else if err == nil {
+		n.hits.Add(1)
+	}
+	return value, err
+}
+
+// Peek returns the value of the key as Cache.Peek does.
+func (n *Namespace[K, V]) Peek(key K) (V, error) {
+	return n.cache.Peek(n.key(key))
+}
+
+// Contains reports whether the key exists in the namespace.
+func (n *Namespace[K, V]) Contains(key K) bool {
+	return n.cache.Contains(n.key(key))
+}
+
+// Put updates the value of the key as Cache.Put does.
+func (n *Namespace[K, V]) Put(key K, value V) {
+	n.puts.Add(1)
+	n.cache.Put(n.key(key), value)
+}
+
+// Remove deletes the key from the namespace and reports whether the key was
+// present.
+func (n *Namespace[K, V]) Remove(key K) bool {
+	return n.cache.Remove(n.key(key))
+}
+
+// Len returns the number of keys of the namespace.
+//
+// O(size of the underlying cache)
+func (n *Namespace[K, V]) Len() int {
+	length := 0
+	for key := range n.cache.All() {
+		if key.Namespace == n.name {
+			length++
+		}
+	}
+	return length
+}
+
+// Clear removes the keys of the namespace, the keys of other namespaces are
+// kept.
+//
+// O(size of the underlying cache)
+func (n *Namespace[K, V]) Clear() {
+	var keys []NamespacedKey[K]
+	for key := range n.cache.All() {
+		if key.Namespace == n.name {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		n.cache.Remove(key)
+	}
+}
+
+// Stats returns the snapshot of the statistics of the namespace. Lookups and
+// puts are counted by the view, evictions are not counted per namespace.
+//
+// O(size of the underlying cache)
+func (n *Namespace[K, V]) Stats() Stats {
+	return Stats{
+		Hits:   n.hits.Load(),
+		Misses: n.misses.Load(),
+		Puts:   n.puts.Load(),
+		Size:   n.Len(),
+	}
+}
@@ -0,0 +1,59 @@
+package lfu
+
+import (
+	"errors"
+	"testing"
+
+	"lfucache/internal/cache"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaces(t *testing.T) {
+	t.Parallel()
+
+	c := NewNamespaced[int, string](New[NamespacedKey[int], string](4))
+	books := c.Namespace("books")
+	authors := c.Namespace("authors")
+	require.Same(t, books, c.Namespace("books"))
+	require.Equal(t, "books", books.Name())
+
+	books.Put(1, "Onegin")
+	authors.Put(1, "Pushkin")
+	value, err := books.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, "Onegin", value)
+	value, err = authors.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, "Pushkin", value)
+	_, err = authors.Get(2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	computed, err := authors.GetOrCompute(2, func() (string, error) { return "Gogol", nil })
+	require.NoError(t, err)
+	require.Equal(t, "Gogol", computed)
+	_, err = authors.GetOrCompute(3, func() (string, error) { return "", errors.New("failed") })
+	require.Error(t, err)
+	_, err = authors.GetOrCompute(1, func() (string, error) { return "", nil })
+	require.NoError(t, err)
+
+	require.Equal(t, Stats{Hits: 1, Puts: 1, Size: 1}, books.Stats())
+	require.Equal(t, Stats{Hits: 2, Misses: 3, Puts: 2, Size: 2}, authors.Stats())
+
+	// The namespaces share the capacity.
+	books.Put(2, "Dead Souls")
+	books.Put(3, "The Captain's Daughter")
+	require.Equal(t, 4, c.Cache().Size())
+	require.Equal(t, 3, books.Len())
+	require.False(t, authors.Contains(2))
+
+	authors.Clear()
+	require.Zero(t, authors.Len())
+	require.Equal(t, 3, books.Len())
+	require.True(t, books.Remove(2))
+	require.False(t, books.Contains(2))
+	_, err = books.Peek(3)
+	require.NoError(t, err)
+
+	var _ cache.Cache[int, string] = books
+}
@@ -0,0 +1,48 @@
+package lfu
+
+import "time"
+
+// WithOnHit registers the function called whenever a lookup by Get,
+// GetOrCompute or GetOrLoad finds the key, e.g. for custom logging or
+// metrics. For the sharded cache it is called under the lock of the shard, so
+// it must not use the cache.
+func WithOnHit[K comparable, V any](onHit func(key K)) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.onHit = onHit
+	}
+}
+
+// WithOnMiss registers the function called whenever a lookup by Get,
+// GetOrCompute or GetOrLoad does not find the key. The call which has loaded
+// the value reports the latency of the load, measured by the clock of the
+// cache, once the load has finished, successfully or not. Get and the calls
+// which share the load of another call or give up before loading report zero
+// latency. For the sharded cache it is called under the lock of the shard, so
+// it must not use the cache.
+func WithOnMiss[K comparable, V any](onMiss func(key K, loadLatency time.Duration)) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.onMiss = onMiss
+	}
+}
+
+// loadStart returns the start time of the load reported to the observer of
+// misses, or the zero time if there is no observer.
+func (l *cacheImpl[K, V]) loadStart() time.Time {
+	if l.onMiss == nil {
+		return time.Time{}
+	}
+	return l.clock.Now()
+}
+
+// loaded reports the miss of the key to the observer, with the latency of the
+// load started at start unless start is the zero time.
+func (l *cacheImpl[K, V]) loaded(key K, start time.Time) {
+	if l.onMiss == nil {
+		return
+	}
+	var latency time.Duration
+	if !start.IsZero() {
+		latency = l.clock.Now().Sub(start)
+	}
+	l.onMiss(key, latency)
+}
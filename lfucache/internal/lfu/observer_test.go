@@ -0,0 +1,116 @@
+package lfu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type miss struct {
+	key     int
+	latency time.Duration
+}
+
+func TestOnHitOnMiss(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits   []int
+		misses []miss
+	)
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithOnHit[int, int](func(key int) { hits = append(hits, key) }),
+		WithOnMiss[int, int](func(key int, latency time.Duration) {
+			misses = append(misses, miss{key: key, latency: latency})
+		}),
+	)
+
+	_, err := cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	cache.Put(1, 1)
+	_, err = cache.Get(1)
+	require.NoError(t, err)
+
+	_, err = cache.GetOrCompute(2, func() (int, error) {
+		clock.Advance(time.Second)
+		return 2, nil
+	})
+	require.NoError(t, err)
+	_, err = cache.GetOrCompute(2, func() (int, error) { return 0, nil })
+	require.NoError(t, err)
+
+	_, err = cache.GetOrLoad(context.Background(), 3, func(context.Context) (int, error) {
+		clock.Advance(time.Millisecond)
+		return 0, errors.New("load failed")
+	})
+	require.Error(t, err)
+
+	require.Equal(t, []int{1, 2}, hits)
+	require.Equal(t, []miss{
+		{key: 1},
+		{key: 2, latency: time.Second},
+		{key: 3, latency: time.Millisecond},
+	}, misses)
+}
+
+func TestShardedOnMiss(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		misses []miss
+	)
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewShardedWithOptions(10, 2,
+		WithClock[int, int](clock),
+		WithOnMiss[int, int](func(key int, latency time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			misses = append(misses, miss{key: key, latency: latency})
+		}),
+	)
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetOrLoad(context.Background(), 1, func(context.Context) (int, error) {
+				<-release
+				clock.Advance(time.Second)
+				return 1, nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, value)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		s := cache.shardFor(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.flights[1] != nil && s.flights[1].waiters == 3
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	value, err := cache.GetOrCompute(2, func() (int, error) {
+		clock.Advance(time.Minute)
+		return 2, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	require.ElementsMatch(t, []miss{
+		{key: 1},
+		{key: 1},
+		{key: 1, latency: time.Second},
+		{key: 2, latency: time.Minute},
+	}, misses)
+}
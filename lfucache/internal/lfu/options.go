@@ -0,0 +1,105 @@
+package lfu
+
+// EvictionReason tells why an item has left the cache.
+type EvictionReason int
+
+const (
+	// EvictionReasonCapacity means the item was the least frequently used one
+	// when a new item did not fit into the cache.
+	EvictionReasonCapacity EvictionReason = iota
+	// EvictionReasonExpired means the item has expired.
+	EvictionReasonExpired
+	// EvictionReasonRemoved means the item was removed explicitly.
+	EvictionReasonRemoved
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonCapacity:
+		return "capacity"
+	case EvictionReasonExpired:
+		return "expired"
+	case EvictionReasonRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Policy is the eviction policy of the cache.
+type Policy int
+
+const (
+	// PolicyLFU invalidates the least frequently used key, ties are broken
+	// by recency.
+	PolicyLFU Policy = iota
+	// PolicyLRU invalidates the least recently used key. The frequency of
+	// every key stays 1.
+	PolicyLRU
+)
+
+// Weigher computes the weight of the item, e.g. its size in bytes. The weight
+// must not be negative.
+type Weigher[K comparable, V any] func(key K, value V) int
+
+// Option configures the cache at construction.
+type Option[K comparable, V any] func(*cacheImpl[K, V])
+
+// WithOnEvict registers the function called whenever an item leaves the
+// cache, e.g. to release resources held by the value. The function is called
+// after the cache has been updated, for the sharded cache it is called under
+// the lock of the shard, so it must not use the cache.
+func WithOnEvict[K comparable, V any](onEvict func(key K, value V, reason EvictionReason)) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.onEvict = onEvict
+	}
+}
+
+// WithOnExpire registers the function called when an item leaves the cache
+// since it has expired. It is called before the function registered with
+// WithOnEvict.
+func WithOnExpire[K comparable, V any](onExpire func(key K, value V)) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.onExpire = onExpire
+	}
+}
+
+// WithWeigher makes the capacity limit the total weight of the items computed
+// by the weigher rather than their number. Put invalidates as many least
+// frequently used items as needed for the new item to fit, an item heavier
+// than the capacity is not put at all.
+func WithWeigher[K comparable, V any](weigher Weigher[K, V]) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.weigher = weigher
+	}
+}
+
+// WithPolicy sets the eviction policy, PolicyLFU is used by default.
+func WithPolicy[K comparable, V any](policy Policy) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.policy = policy
+	}
+}
+
+// WithAdmission registers the function deciding whether a new item may be put
+// into the full cache, e.g. to reject the keys which are known to be used
+// once. The item is not put if the function returns false, so no item is
+// invalidated for it. Updates of the present keys and puts into the cache
+// which is not full are not checked. With TinyLFU, the item must be admitted
+// by both.
+func WithAdmission[K comparable, V any](admission func(key K, value V) bool) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.admission = admission
+	}
+}
+
+// WithTinyLFU enables TinyLFU admission: a count-min sketch estimates how
+// often keys have been used recently, including the keys which have left the
+// cache or have never been admitted. A new key is put into the full cache only
+// if it is estimated to be used more often than the key it would invalidate,
+// so that one-off keys do not push out the warm ones.
+func WithTinyLFU[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.tinyLFU = true
+	}
+}
@@ -0,0 +1,58 @@
+package lfu
+
+// Pin protects the key from invalidation: the pinned key is never invalidated
+// to make room for other keys, neither by Put nor by Resize, but it still
+// counts towards the size and the weight of the cache, it may expire and it
+// may be removed explicitly, which unpins it. Once the unpinned keys are not
+// enough to make room for a new key, the new key is not put. If Resize shrinks
+// the capacity below the pinned keys, the cache keeps holding more than its
+// capacity until enough keys are unpinned. It reports whether the key exists.
+//
+// O(1)
+func (l *cacheImpl[K, V]) Pin(key K) bool {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || l.expireIfDue(cacheItemNode) {
+		return false
+	}
+	if !cacheItemNode.Value.pinned {
+		cacheItemNode.Value.pinned = true
+		l.pinned++
+		l.pinnedWeight += cacheItemNode.Value.weight
+	}
+	return true
+}
+
+// Unpin makes the pinned key subject to invalidation again and reports
+// whether the key has been pinned. If the cache holds more than its capacity,
+// the least frequently used unpinned keys are invalidated until it fits.
+//
+// O(1) unless the cache holds more than its capacity
+func (l *cacheImpl[K, V]) Unpin(key K) bool {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || !cacheItemNode.Value.pinned {
+		return false
+	}
+	cacheItemNode.Value.pinned = false
+	l.pinned--
+	l.pinnedWeight -= cacheItemNode.Value.weight
+	l.shrink()
+	return true
+}
+
+// Pin protects the key from invalidation as the LFU cache does, the key
+// counts towards the capacity of its shard.
+func (c *shardedCacheImpl[K, V]) Pin(key K) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Pin(key)
+}
+
+// Unpin makes the pinned key subject to invalidation again and reports
+// whether the key has been pinned.
+func (c *shardedCacheImpl[K, V]) Unpin(key K) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Unpin(key)
+}
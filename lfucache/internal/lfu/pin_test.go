@@ -0,0 +1,112 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPin(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(3, WithOnEvict(func(key int, _ int, reason EvictionReason) {
+		if reason == EvictionReasonCapacity {
+			evicted = append(evicted, key)
+		}
+	}))
+	require.False(t, cache.Pin(1))
+
+	for i := range 3 {
+		cache.Put(i, i)
+	}
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+	require.True(t, cache.Pin(0))
+	require.True(t, cache.Pin(0))
+
+	// The least frequently used key is pinned, so the next one is
+	// invalidated.
+	cache.Put(3, 3)
+	require.Equal(t, []int{1}, evicted)
+	cache.Put(4, 4)
+	require.Equal(t, []int{1, 3}, evicted)
+	require.True(t, cache.Contains(0))
+
+	// Overflow: every key is pinned, so new keys are not put.
+	require.True(t, cache.Pin(2))
+	require.True(t, cache.Pin(4))
+	cache.Put(5, 5)
+	require.False(t, cache.Contains(5))
+	require.Equal(t, 3, cache.Size())
+	_, _, ok := cache.PopLFU()
+	require.False(t, ok)
+
+	// Pinned keys are updated as usual.
+	cache.Put(0, 10)
+	value, err := cache.Peek(0)
+	require.NoError(t, err)
+	require.Equal(t, 10, value)
+
+	// The cache holds more than its capacity until keys are unpinned.
+	cache.Resize(1)
+	require.Equal(t, 3, cache.Size())
+	require.True(t, cache.Unpin(4))
+	require.False(t, cache.Unpin(4))
+	require.Equal(t, []int{1, 3, 4}, evicted)
+	require.Equal(t, 2, cache.Size())
+	require.True(t, cache.Unpin(2))
+	require.Equal(t, []int{1, 3, 4, 2}, evicted)
+
+	// The removed key is unpinned.
+	require.True(t, cache.Remove(0))
+	cache.Put(6, 6)
+	cache.Put(7, 7)
+	require.Equal(t, []int{1, 3, 4, 2, 6}, evicted)
+	require.Equal(t, 0, cache.pinned)
+}
+
+func TestPinWeighted(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(10, WithWeigher(func(_ int, value int) int { return value }))
+	cache.Put(1, 6)
+	cache.Put(2, 3)
+	require.True(t, cache.Pin(1))
+
+	require.Equal(t, PutRejected, cache.PutWithCost(3, 5, 5))
+	require.True(t, cache.Contains(2))
+	require.Equal(t, PutAdmittedWithEvictions, cache.PutWithCost(3, 4, 4))
+	require.False(t, cache.Contains(2))
+
+	// The pinned key grows only while it fits besides the other pinned keys.
+	cache.Put(1, 11)
+	value, err := cache.Peek(1)
+	require.NoError(t, err)
+	require.Equal(t, 6, value)
+	cache.Put(1, 7)
+	require.False(t, cache.Contains(3))
+	require.Equal(t, 7, cache.pinnedWeight)
+
+	require.True(t, cache.Unpin(1))
+	require.Zero(t, cache.pinnedWeight)
+}
+
+func TestShardedPin(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](4, 1)
+	cache.Put(1, 1)
+	require.True(t, cache.Pin(1))
+	for i := 2; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	require.True(t, cache.Contains(1))
+	key, _, ok := cache.PopLFU()
+	require.True(t, ok)
+	require.NotEqual(t, 1, key)
+	require.True(t, cache.Unpin(1))
+
+	clone := cache.Clone()
+	require.Zero(t, clone.shards[0].cache.pinned)
+}
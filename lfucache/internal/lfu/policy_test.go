@@ -0,0 +1,49 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyLRU(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(3, WithPolicy[int, int](PolicyLRU))
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	for i := 0; i < 5; i++ {
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+	}
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{2, 1, 3}, keys)
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+
+	cache.Put(4, 4)
+	require.False(t, cache.Contains(3))
+
+	// Unlike LFU, the frequently used key is invalidated once it becomes the
+	// least recently used one.
+	cache.Put(5, 5)
+	require.False(t, cache.Contains(1))
+
+	cache.Put(2, 20)
+	cache.Put(6, 6)
+	keys, values := collect(cache.All())
+	require.Equal(t, []int{6, 2, 5}, keys)
+	require.Equal(t, []int{6, 20, 5}, values)
+
+	require.True(t, cache.Remove(2))
+	cache.Put(7, 7)
+	keys, _ = collect(cache.All())
+	require.Equal(t, []int{7, 6, 5}, keys)
+}
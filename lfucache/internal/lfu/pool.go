@@ -0,0 +1,24 @@
+package lfu
+
+import (
+	"sync"
+
+	"lfucache/internal/linkedlist"
+)
+
+// WithNodePool makes the cache recycle the nodes of its items through a
+// sync.Pool instead of keeping them in its own free list. The pool is shared
+// by every cache created with the returned option, e.g. by all shards of the
+// sharded cache, so that the nodes released by one cache are reused by
+// another, and the garbage collector may free the nodes which have stayed
+// unused, e.g. after Clear or Resize.
+func WithNodePool[K comparable, V any]() Option[K, V] {
+	pool := &sync.Pool{
+		New: func() any {
+			return linkedlist.NewNode(CacheItem[K, V]{})
+		},
+	}
+	return func(l *cacheImpl[K, V]) {
+		l.nodePool = pool
+	}
+}
@@ -0,0 +1,55 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePool(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(3,
+		WithNodePool[int, int](),
+		WithOnEvict(func(key int, _ int, _ EvictionReason) { evicted = append(evicted, key) }),
+	)
+	for i := range 6 {
+		cache.Put(i, i)
+		_, err := cache.Get(i % 2)
+		if i < 2 {
+			require.NoError(t, err)
+		}
+	}
+	require.Empty(t, cache.freeCacheItemNodes)
+	keys, values := collect(cache.All())
+	require.Equal(t, []int{1, 0, 5}, keys)
+	require.Equal(t, []int{1, 0, 5}, values)
+	require.Equal(t, []int{2, 3, 4}, evicted)
+
+	cache.Clear()
+	require.Empty(t, cache.freeCacheItemNodes)
+	cache.Put(7, 7)
+	frequency, err := cache.GetKeyFrequency(7)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+}
+
+func TestShardedNodePool(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(64, 4, WithNodePool[int, int]())
+	pool := cache.shards[0].cache.nodePool
+	require.NotNil(t, pool)
+	for i := range cache.shards {
+		require.Same(t, pool, cache.shards[i].cache.nodePool)
+	}
+
+	for i := range 1000 {
+		cache.Put(i, i)
+	}
+	require.Equal(t, 64, cache.Size())
+	for key, value := range cache.All() {
+		require.Equal(t, key, value)
+	}
+}
@@ -0,0 +1,61 @@
+package lfu
+
+// PopLFU removes the item which would be invalidated next and returns it, so
+// that it can be moved elsewhere instead of being dropped. The removal is
+// reported to the callbacks as EvictionReasonRemoved. Pinned items are never
+// popped.
+func (l *cacheImpl[K, V]) PopLFU() (K, V, bool) {
+	for l.size != 0 {
+		cacheItemNode := l.leastFrequentlyUsed(nil)
+		if cacheItemNode == nil {
+			break
+		}
+		// Expired items are not returned, they are removed on the way.
+		if l.expireIfDue(cacheItemNode) {
+			continue
+		}
+		key, value := cacheItemNode.Value.key, cacheItemNode.Value.value
+		l.removeCacheItemNode(cacheItemNode, EvictionReasonRemoved)
+		return key, value, true
+	}
+	var (
+		key   K
+		value V
+	)
+	return key, value, false
+}
+
+// PopLFU removes the least frequently used item of the whole cache and
+// returns it. Ties between shards are broken by the shard order. All shards
+// are locked for the time of the call.
+func (c *shardedCacheImpl[K, V]) PopLFU() (K, V, bool) {
+	for i := range c.shards {
+		c.shards[i].lock()
+		defer c.shards[i].mu.Unlock()
+	}
+	var (
+		victim          *cacheImpl[K, V]
+		victimFrequency int
+	)
+	for i := range c.shards {
+		cache := c.shards[i].cache
+		if cache.size == 0 {
+			continue
+		}
+		cacheItemNode := cache.leastFrequentlyUsed(nil)
+		if cacheItemNode == nil {
+			continue
+		}
+		if victim == nil || cacheItemNode.Value.frequency < victimFrequency {
+			victim, victimFrequency = cache, cacheItemNode.Value.frequency
+		}
+	}
+	if victim == nil {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+	return victim.PopLFU()
+}
@@ -0,0 +1,67 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopLFU(t *testing.T) {
+	t.Parallel()
+
+	var removed []EvictionReason
+	cache := NewWithOptions(10, WithOnEvict(func(_ int, _ int, reason EvictionReason) {
+		removed = append(removed, reason)
+	}))
+
+	_, _, ok := cache.PopLFU()
+	require.False(t, ok)
+
+	for i := 0; i < 4; i++ {
+		cache.Put(i, i*10)
+	}
+	_, err := cache.Get(0)
+	require.NoError(t, err)
+
+	want, _ := collect(cache.AllAscending())
+	for _, wantKey := range want {
+		key, value, ok := cache.PopLFU()
+		require.True(t, ok)
+		require.Equal(t, wantKey, key)
+		require.Equal(t, wantKey*10, value)
+		require.False(t, cache.Contains(key))
+	}
+
+	_, _, ok = cache.PopLFU()
+	require.False(t, ok)
+	require.Zero(t, cache.Size())
+	require.Equal(t, []EvictionReason{
+		EvictionReasonRemoved, EvictionReasonRemoved, EvictionReasonRemoved, EvictionReasonRemoved,
+	}, removed)
+	require.Zero(t, cache.Stats().Evictions)
+}
+
+func TestShardedPopLFU(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	_, _, ok := cache.PopLFU()
+	require.False(t, ok)
+
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		key, value, ok := cache.PopLFU()
+		require.True(t, ok)
+		require.Equal(t, i, key)
+		require.Equal(t, i, value)
+	}
+	require.Zero(t, cache.Size())
+}
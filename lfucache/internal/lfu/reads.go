@@ -0,0 +1,143 @@
+package lfu
+
+import "sync/atomic"
+
+// WithBufferedReads makes Get of the sharded cache take the lock of the shard
+// for reading only, so that lookups of the same shard do not serialize. The
+// keys found by lookups are recorded in a buffer of the given size, and their
+// frequencies are incremented in batches, under the lock, once the buffer is
+// full or the shard is locked for writing by another operation. If the
+// buffer is full and the shard is busy, the lookup is not recorded, so the
+// frequencies are approximate under contention. The sliding TTL is restarted
+// by the lookup itself, so it does not depend on the lookup being recorded.
+// Expired keys found by lookups are reported as missing and removed later. The observers of hits
+// and misses are called without the lock, so they may be called
+// concurrently. The option has no effect on the LFU cache, which is not safe
+// for concurrent use anyway.
+func WithBufferedReads[K comparable, V any](size int) Option[K, V] {
+	if size <= 0 {
+		panic("Invalid read buffer size")
+	}
+	return func(l *cacheImpl[K, V]) {
+		l.readBufferSize = size
+	}
+}
+
+// readBuffer records the lookups of the shard until they are applied.
+type readBuffer[K comparable] struct {
+	keys   chan K
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newReadBuffer[K comparable](size int) *readBuffer[K] {
+	return &readBuffer[K]{keys: make(chan K, size)}
+}
+
+// stats returns the lookups counted by the buffer.
+func (b *readBuffer[K]) stats() Stats {
+	return Stats{Hits: b.hits.Load(), Misses: b.misses.Load()}
+}
+
+// resetStats returns the lookups counted by the buffer and resets them.
+func (b *readBuffer[K]) resetStats() Stats {
+	return Stats{Hits: b.hits.Swap(0), Misses: b.misses.Swap(0)}
+}
+
+// lock locks the shard for writing, applying the buffered lookups first.
+func (s *shard[K, V]) lock() {
+	s.mu.Lock()
+	if s.reads != nil {
+		s.drainReads()
+	}
+}
+
+// drainReads applies the buffered lookups, the shard must be locked for
+// writing. Lookups recorded meanwhile are left for the next drain.
+func (s *shard[K, V]) drainReads() {
+	for range len(s.reads.keys) {
+		s.cache.replayLookup(<-s.reads.keys)
+	}
+}
+
+// bufferedGet looks the key up under the read lock of the shard and records
+// the lookup to be applied later.
+func (s *shard[K, V]) bufferedGet(key K) (V, error) {
+	s.mu.RLock()
+	value, ok := s.cache.lookup(key)
+	s.mu.RUnlock()
+
+	s.recordLookup(key)
+	l := s.cache
+	if !ok {
+		s.reads.misses.Add(1)
+		if l.onMiss != nil {
+			l.onMiss(key, 0)
+		}
+		return value, ErrKeyNotFound
+	}
+	s.reads.hits.Add(1)
+	if l.onHit != nil {
+		l.onHit(key)
+	}
+	return value, nil
+}
+
+// recordLookup places the key into the buffer, the buffer is drained if it is
+// full and the shard is not busy, otherwise, the lookup is dropped.
+func (s *shard[K, V]) recordLookup(key K) {
+	select {
+	case s.reads.keys <- key:
+		return
+	default:
+	}
+	if !s.mu.TryLock() {
+		return
+	}
+	s.drainReads()
+	s.cache.replayLookup(key)
+	s.mu.Unlock()
+}
+
+// lookup returns the value of the key unless it is missing or expired and
+// restarts the sliding TTL of the item. It changes nothing else, so that it
+// may be called under the read lock. The expiration time is the only field
+// lookups change, so they access it atomically.
+func (l *cacheImpl[K, V]) lookup(key K) (V, bool) {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok {
+		var value V
+		return value, false
+	}
+	expiresAt := &cacheItemNode.Value.expiresAt
+	if l.isExpired(atomic.LoadInt64(expiresAt)) {
+		var value V
+		return value, false
+	}
+	if slidingTTL := cacheItemNode.Value.slidingTTL; slidingTTL != 0 {
+		refreshed := l.clock.Now().Add(slidingTTL).UnixNano()
+		// concurrent lookups may refresh the item at once, the latest
+		// expiration time wins
+		for {
+			current := atomic.LoadInt64(expiresAt)
+			if current >= refreshed || atomic.CompareAndSwapInt64(expiresAt, current, refreshed) {
+				break
+			}
+		}
+	}
+	return cacheItemNode.Value.value, true
+}
+
+// replayLookup applies the buffered lookup of the key as Get does, except for
+// the statistics, the observers and the sliding TTL, which have been updated
+// by the lookup.
+func (l *cacheImpl[K, V]) replayLookup(key K) {
+	if l.sketch != nil {
+		l.sketch.increment(key)
+	}
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || l.expireIfDue(cacheItemNode) {
+		return
+	}
+	l.updateFreqAndMoveCacheItemNode(cacheItemNode)
+}
@@ -0,0 +1,130 @@
+package lfu
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedReads(t *testing.T) {
+	t.Parallel()
+
+	var hits []int
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewShardedWithOptions(4, 1,
+		WithBufferedReads[int, int](4),
+		WithClock[int, int](clock),
+		WithOnHit[int, int](func(key int) { hits = append(hits, key) }),
+	)
+	for i := range 4 {
+		cache.Put(i, i)
+	}
+
+	for range 2 {
+		value, err := cache.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+	}
+	_, err := cache.Get(5)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, []int{1, 1}, hits)
+	require.Equal(t, Stats{Hits: 2, Misses: 1, Puts: 4, Size: 4}, cache.Stats())
+
+	// The lookups are applied before the frequencies are read.
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 3, frequency)
+
+	// The full buffer is drained by the lookup.
+	for range 10 {
+		_, err := cache.Get(2)
+		require.NoError(t, err)
+	}
+	require.LessOrEqual(t, len(cache.shards[0].reads.keys), 4)
+	frequency, err = cache.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 11, frequency)
+
+	// Expired keys are reported as missing.
+	cache.PutWithTTL(3, 3, time.Minute)
+	clock.Advance(time.Minute)
+	_, err = cache.Get(3)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	// The expired key is removed once the lookup is applied.
+	require.Equal(t, 3, cache.Size())
+
+	require.Equal(t, uint64(12), cache.ResetStats().Hits)
+	require.Zero(t, cache.Stats().Hits)
+
+	require.Panics(t, func() { WithBufferedReads[int, int](0) })
+}
+
+func TestBufferedReadsSlidingTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewShardedWithOptions(4, 1,
+		WithBufferedReads[int, int](4),
+		WithClock[int, int](clock),
+	)
+	cache.PutWithSlidingTTL(1, 1, time.Minute)
+
+	// The lookups stay in the buffer, still each of them restarts the TTL.
+	for range 3 {
+		clock.Advance(40 * time.Second)
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+	}
+
+	// The lookups are dropped while the buffer is full and the shard is
+	// busy, the TTL is restarted anyway.
+	s := &cache.shards[0]
+	s.mu.RLock()
+	for len(s.reads.keys) < cap(s.reads.keys) {
+		s.reads.keys <- 2
+	}
+	for range 3 {
+		clock.Advance(40 * time.Second)
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+	}
+	s.mu.RUnlock()
+
+	_, expiresAt, err := cache.GetWithExpiration(1)
+	require.NoError(t, err)
+	require.Equal(t, clock.Now().Add(time.Minute), expiresAt)
+
+	clock.Advance(time.Minute)
+	_, err = cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestBufferedReadsConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	cache := NewShardedWithOptions(64, 4, WithBufferedReads[int, int](16))
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 5000 {
+				key := (i * (worker + 1)) % 128
+				if i%10 == 0 {
+					cache.Put(key, key)
+				}
+				if value, err := cache.Get(key); err == nil {
+					require.Equal(t, key, value)
+				}
+				if i%1000 == 0 {
+					for range cache.All() {
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, cache.Size(), cache.Capacity())
+}
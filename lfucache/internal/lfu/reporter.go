@@ -0,0 +1,66 @@
+package lfu
+
+import (
+	"sync"
+	"time"
+)
+
+// Reporter reports the statistics of the cache accumulated over every
+// interval, so that long-running services see rates rather than lifetime
+// totals. The counters of the cache are not reset, if they are reset by
+// ResetStats within an interval, the ones accumulated since the reset are
+// reported.
+type Reporter struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// StartReporter starts the goroutine calling report with the statistics of
+// the cache accumulated since the previous call once every interval. The
+// cache must be safe for concurrent use, e.g. the sharded one. The system
+// clock is used unless the clock is provided.
+func StartReporter(
+	cache interface{ Stats() Stats },
+	interval time.Duration,
+	report func(delta Stats),
+	clock ...Clock,
+) *Reporter {
+	if interval <= 0 {
+		panic("Invalid interval")
+	}
+	var c Clock = realClock{}
+	if len(clock) > 1 {
+		panic("Invalid clock")
+	} else if len(clock) == 1 {
+		c = clock[0]
+	}
+	r := &Reporter{stop: make(chan struct{})}
+	previous := cache.Stats()
+	timer := c.NewTimer(interval)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer timer.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-timer.C():
+				current := cache.Stats()
+				report(current.Delta(previous))
+				previous = current
+				timer.Reset(interval)
+			}
+		}
+	}()
+	return r
+}
+
+// Stop stops the reporter and waits for the report in progress, if any.
+func (r *Reporter) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+	r.wg.Wait()
+}
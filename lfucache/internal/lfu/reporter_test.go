@@ -0,0 +1,77 @@
+package lfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetStats(t *testing.T) {
+	t.Parallel()
+
+	caches := map[string]interface {
+		Put(int, int)
+		Get(int) (int, error)
+		Stats() Stats
+		ResetStats() Stats
+	}{
+		"lfu":     New[int, int](10),
+		"sharded": NewSharded[int, int](10, 2),
+		"arc":     NewARC[int, int](10),
+	}
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cache.Put(1, 1)
+			_, _ = cache.Get(1)
+			_, _ = cache.Get(2)
+
+			require.Equal(t, Stats{Hits: 1, Misses: 1, Puts: 1, Size: 1}, cache.ResetStats())
+			stats := cache.Stats()
+			require.NotZero(t, stats.Resets)
+			require.Equal(t, Stats{Size: 1, Resets: stats.Resets}, stats)
+
+			_, _ = cache.Get(1)
+			require.Equal(t, Stats{Hits: 1, Size: 1, Resets: stats.Resets}, cache.ResetStats())
+		})
+	}
+}
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewSharded[int, int](10, 2)
+	cache.Put(1, 1)
+
+	reports := make(chan Stats)
+	reporter := StartReporter(cache, time.Minute, func(delta Stats) {
+		reports <- delta
+	}, clock)
+
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(2)
+	clock.Advance(time.Minute)
+	require.Equal(t, Stats{Hits: 1, Misses: 1, Size: 1}, <-reports)
+
+	cache.Put(2, 2)
+	// The timer is reset once the report is done.
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Minute)
+		select {
+		case delta := <-reports:
+			require.Equal(t, Stats{Puts: 1, Size: 2}, delta)
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	reporter.Stop()
+	reporter.Stop()
+
+	// Lifetime totals are kept.
+	require.Equal(t, Stats{Hits: 1, Misses: 1, Puts: 2, Size: 2}, cache.Stats())
+}
@@ -0,0 +1,401 @@
+package lfu
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"hash/maphash"
+	"iter"
+	"sync"
+	"time"
+)
+
+// DefaultShards is the number of shards used by NewSharded when no number of
+// shards is provided.
+const DefaultShards = 16
+
+// ErrComputePanicked is returned by GetOrCompute to the goroutines waiting
+// for the value which computation has panicked in another goroutine.
+var ErrComputePanicked = errors.New("compute panicked")
+
+// shard is an LFU cache guarded by its own mutex.
+type shard[K comparable, V any] struct {
+	// mu is held for reading only by the buffered lookups.
+	mu    sync.RWMutex
+	cache *cacheImpl[K, V]
+	// flights serve the values of the shard being computed by GetOrCompute.
+	flights map[K]*flight[V]
+	// keyLocks serve the locks of the keys of the shard taken by KeyLock.
+	keyLocks map[K]*keyLock
+	// reads serve the buffered lookups, they are nil unless enabled by
+	// WithBufferedReads.
+	reads *readBuffer[K]
+}
+
+// flight is a computation of a missing value shared by all goroutines
+// requesting it.
+type flight[V any] struct {
+	// done is closed once value and err are set.
+	done  chan struct{}
+	value V
+	err   error
+	// cancel cancels the context of the load started by GetOrLoad, it is nil
+	// for the computation of GetOrCompute.
+	cancel context.CancelFunc
+	// waiters is the number of goroutines waiting for the load started by
+	// GetOrLoad, including the ones of GetOrCompute, which never give up. It
+	// is guarded by the lock of the shard.
+	waiters int
+}
+
+// shardedCacheImpl partitions keys across independent LFU shards by hash of
+// the key, so that operations on keys of different shards do not contend for
+// the same lock. Every shard evicts its own least frequently used key, which
+// makes eviction approximate with respect to the whole cache. It is safe for
+// concurrent use.
+type shardedCacheImpl[K comparable, V any] struct {
+	// shards serve the partitions of the cache.
+	shards []shard[K, V]
+	// seed is used for hashing keys.
+	seed maphash.Seed
+}
+
+// NewSharded initializes the concurrent cache with the given capacity split
+// between the given number of shards. If no number of shards is provided,
+// DefaultShards is used. The number of shards never exceeds the capacity, so
+// that every shard can hold at least one key.
+func NewSharded[K comparable, V any](capacity int, shards ...int) *shardedCacheImpl[K, V] {
+	shardsNumber := DefaultShards
+	if len(shards) > 1 {
+		panic("Invalid number of shards")
+	} else if len(shards) == 1 {
+		shardsNumber = shards[0]
+	}
+	return NewShardedWithOptions[K, V](capacity, shardsNumber)
+}
+
+// NewShardedWithOptions initializes the concurrent cache with the given
+// capacity split between the given number of shards, every shard is
+// configured with the options.
+func NewShardedWithOptions[K comparable, V any](
+	capacity, shardsNumber int,
+	opts ...Option[K, V],
+) *shardedCacheImpl[K, V] {
+	if capacity < 0 {
+		panic("Invalid capacity")
+	}
+	if shardsNumber <= 0 {
+		panic("Invalid number of shards")
+	}
+	shardsNumber = max(min(shardsNumber, capacity), 1)
+
+	c := &shardedCacheImpl[K, V]{
+		shards: make([]shard[K, V], shardsNumber),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range c.shards {
+		c.shards[i].cache = NewWithOptions(shardCapacity(capacity, shardsNumber, i), opts...)
+		if size := c.shards[i].cache.readBufferSize; size > 0 {
+			c.shards[i].reads = newReadBuffer[K](size)
+		}
+	}
+	return c
+}
+
+// shardCapacity returns the capacity of the shard with the given index, the
+// remainder of the capacity is spread over the first shards.
+func shardCapacity(capacity, shardsNumber, shard int) int {
+	if shard < capacity%shardsNumber {
+		return capacity/shardsNumber + 1
+	}
+	return capacity / shardsNumber
+}
+
+// shardFor returns the shard the key belongs to.
+func (c *shardedCacheImpl[K, V]) shardFor(key K) *shard[K, V] {
+	return &c.shards[c.shardIndex(key)]
+}
+
+// shardIndex returns the index of the shard the key belongs to.
+func (c *shardedCacheImpl[K, V]) shardIndex(key K) int {
+	return int(maphash.Comparable(c.seed, key) % uint64(len(c.shards)))
+}
+
+func (c *shardedCacheImpl[K, V]) Get(key K) (V, error) {
+	s := c.shardFor(key)
+	if s.reads != nil {
+		return s.bufferedGet(key)
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Get(key)
+}
+
+// GetOrCompute computes the missing value outside of the lock of the shard.
+// Goroutines requesting the same missing key at the same time share a single
+// call of compute: the first one calls it, the others wait for its result,
+// including the error.
+func (c *shardedCacheImpl[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	s := c.shardFor(key)
+	s.lock()
+	if value, err := s.cache.get(key, false); err == nil {
+		s.mu.Unlock()
+		return value, nil
+	}
+	if f, ok := s.flights[key]; ok {
+		s.cache.loaded(key, time.Time{})
+		// The goroutine cannot give up waiting, so the load of GetOrLoad it
+		// joins is never cancelled.
+		f.waiters++
+		s.mu.Unlock()
+		<-f.done
+		return f.value, f.err
+	}
+	f := &flight[V]{done: make(chan struct{})}
+	if s.flights == nil {
+		s.flights = make(map[K]*flight[V])
+	}
+	s.flights[key] = f
+	s.mu.Unlock()
+
+	// The flight is finished even if compute panics, so that the waiting
+	// goroutines are not blocked forever.
+	f.err = ErrComputePanicked
+	start := s.cache.loadStart()
+	defer func() {
+		s.lock()
+		s.cache.loaded(key, start)
+		if f.err == nil {
+			s.cache.Put(key, f.value)
+		}
+		delete(s.flights, key)
+		s.mu.Unlock()
+		close(f.done)
+	}()
+	f.value, f.err = compute()
+	return f.value, f.err
+}
+
+func (c *shardedCacheImpl[K, V]) Contains(key K) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Contains(key)
+}
+
+func (c *shardedCacheImpl[K, V]) Peek(key K) (V, error) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Peek(key)
+}
+
+func (c *shardedCacheImpl[K, V]) Put(key K, value V) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.Put(key, value)
+}
+
+func (c *shardedCacheImpl[K, V]) Remove(key K) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Remove(key)
+}
+
+func (c *shardedCacheImpl[K, V]) Clear() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		s.cache.Clear()
+		s.mu.Unlock()
+	}
+}
+
+// Resize splits the new capacity between the shards the same way as
+// NewSharded does, the number of shards does not change. Every shard
+// invalidates its own least frequently used keys if it holds more keys than
+// its new capacity. The order of invalidation is guaranteed within a shard
+// only, the shards are resized one after another.
+func (c *shardedCacheImpl[K, V]) Resize(newCapacity int) {
+	if newCapacity < 0 {
+		panic("Invalid capacity")
+	}
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		s.cache.Resize(shardCapacity(newCapacity, len(c.shards), i))
+		s.mu.Unlock()
+	}
+}
+
+// All returns the iterator over a snapshot of the cache in descending order
+// of frequency. The snapshot is taken at once for all shards when iteration
+// starts, so the cache may be changed while ranging over it. Keys of the same
+// shard with the same frequency keep their recency order, ties between shards
+// are broken by the shard order.
+func (c *shardedCacheImpl[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for item := range c.items() {
+			if !yield(item.key, item.value) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns the iterator over a snapshot of the cache in the same order
+// as All.
+func (c *shardedCacheImpl[K, V]) Entries() iter.Seq[Entry[K, V]] {
+	return entries(c.items())
+}
+
+// items iterates over a snapshot of the cache items in the order of All.
+func (c *shardedCacheImpl[K, V]) items() iter.Seq[CacheItem[K, V]] {
+	return func(yield func(CacheItem[K, V]) bool) {
+		cursors := c.snapshot((*cacheImpl[K, V]).items)
+		mergeShardCursors(&cursors, &cursors, yield)
+	}
+}
+
+// snapshot copies the items of every shard in the order given by items. The
+// locks of all shards are held while the items are copied, so that the
+// snapshot is consistent across the shards: it reflects the cache at a single
+// point in time. The locks are taken in the order of the shards and released
+// before the snapshot is iterated over, so that iteration does not block
+// other goroutines.
+//
+// O(size) memory
+func (c *shardedCacheImpl[K, V]) snapshot(
+	items func(*cacheImpl[K, V]) iter.Seq[CacheItem[K, V]],
+) shardCursors[K, V] {
+	for i := range c.shards {
+		c.shards[i].lock()
+	}
+	cursors := make(shardCursors[K, V], 0, len(c.shards))
+	for i := range c.shards {
+		s := &c.shards[i]
+		shardItems := make([]CacheItem[K, V], 0, s.cache.Size())
+		for item := range items(s.cache) {
+			shardItems = append(shardItems, item)
+		}
+		if len(shardItems) != 0 {
+			cursors = append(cursors, &shardCursor[K, V]{items: shardItems, shard: i})
+		}
+	}
+	for i := range c.shards {
+		c.shards[i].mu.Unlock()
+	}
+	return cursors
+}
+
+// mergeShardCursors merges snapshots of the shards, each of them is already
+// sorted in the order of the heap h over cursors.
+func mergeShardCursors[K comparable, V any](
+	h heap.Interface,
+	cursors *shardCursors[K, V],
+	yield func(CacheItem[K, V]) bool,
+) {
+	heap.Init(h)
+	for len(*cursors) != 0 {
+		cursor := (*cursors)[0]
+		if !yield(cursor.items[cursor.position]) {
+			return
+		}
+		cursor.position++
+		if cursor.position == len(cursor.items) {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+}
+
+func (c *shardedCacheImpl[K, V]) Size() int {
+	size := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		size += s.cache.Size()
+		s.mu.Unlock()
+	}
+	return size
+}
+
+// Len returns the cache size, as required by cache.Cache.
+func (c *shardedCacheImpl[K, V]) Len() int {
+	return c.Size()
+}
+
+func (c *shardedCacheImpl[K, V]) Capacity() int {
+	capacity := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		capacity += s.cache.Capacity()
+		s.mu.Unlock()
+	}
+	return capacity
+}
+
+func (c *shardedCacheImpl[K, V]) Stats() Stats {
+	var stats Stats
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		stats = stats.add(s.cache.Stats())
+		s.mu.Unlock()
+		if s.reads != nil {
+			stats = stats.add(s.reads.stats())
+		}
+	}
+	return stats
+}
+
+func (c *shardedCacheImpl[K, V]) GetKeyFrequency(key K) (int, error) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.GetKeyFrequency(key)
+}
+
+// shardCursor points to the next item of a shard snapshot to be merged.
+type shardCursor[K comparable, V any] struct {
+	items    []CacheItem[K, V]
+	position int
+	shard    int
+}
+
+// shardCursors is a max-heap of cursors ordered by frequency of the items
+// they point to.
+type shardCursors[K comparable, V any] []*shardCursor[K, V]
+
+func (h shardCursors[K, V]) Len() int {
+	return len(h)
+}
+
+func (h shardCursors[K, V]) Less(i, j int) bool {
+	a := h[i].items[h[i].position].frequency
+	b := h[j].items[h[j].position].frequency
+	if a != b {
+		return a > b
+	}
+	return h[i].shard < h[j].shard
+}
+
+func (h shardCursors[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *shardCursors[K, V]) Push(x any) {
+	*h = append(*h, x.(*shardCursor[K, V]))
+}
+
+func (h *shardCursors[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
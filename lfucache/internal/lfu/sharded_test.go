@@ -0,0 +1,385 @@
+package lfu
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// must compile
+func testShardedImplements[K comparable, V any]() Cache[K, V] {
+	return NewSharded[K, V](1)
+}
+
+func TestShardedWithoutInvalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i*i)
+	}
+
+	for i := 0; i < 10; i++ {
+		value, err := cache.Get(i)
+		require.NoError(t, err)
+		require.Equal(t, i*i, value)
+	}
+
+	frequency, err := cache.GetKeyFrequency(3)
+	require.NoError(t, err)
+	require.Equal(t, 2, frequency)
+
+	_, err = cache.Get(42)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	require.Equal(t, 10, cache.Size())
+	require.Equal(t, 100, cache.Capacity())
+}
+
+func TestShardedCapacity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		capacity   int
+		shards     int
+		wantShards int
+	}{
+		{name: "Evenly split", capacity: 64, shards: 16, wantShards: 16},
+		{name: "Remainder", capacity: 67, shards: 16, wantShards: 16},
+		{name: "More shards than capacity", capacity: 3, shards: 16, wantShards: 3},
+		{name: "Zero capacity", capacity: 0, shards: 4, wantShards: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewSharded[int, int](tt.capacity, tt.shards)
+			require.Len(t, cache.shards, tt.wantShards)
+			require.Equal(t, tt.capacity, cache.Capacity())
+		})
+	}
+}
+
+func TestShardedInvalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](8, 2)
+
+	for i := 0; i < 1000; i++ {
+		cache.Put(i, i)
+	}
+
+	require.Equal(t, 8, cache.Size())
+}
+
+func TestShardedAllOrdering(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](400, 8)
+
+	for i := 0; i < 50; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i%7; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	keys, values := collect(cache.All())
+	require.Len(t, keys, 50)
+	require.Equal(t, keys, values)
+
+	frequencies := make([]int, 0, len(keys))
+	for _, key := range keys {
+		frequency, err := cache.GetKeyFrequency(key)
+		require.NoError(t, err)
+		frequencies = append(frequencies, frequency)
+	}
+
+	require.True(t, slices.IsSortedFunc(frequencies, func(a, b int) int {
+		return b - a
+	}))
+
+	// iteration can be stopped early
+	for range cache.All() {
+		break
+	}
+}
+
+func TestShardedConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](128)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10_000; i++ {
+				key := (i * (worker + 1)) % 256
+				cache.Put(key, key)
+				if value, err := cache.Get(key); err == nil {
+					require.Equal(t, key, value)
+				}
+				if i%1000 == 0 {
+					for range cache.All() {
+					}
+					require.LessOrEqual(t, cache.Size(), cache.Capacity())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, cache.Size(), cache.Capacity())
+}
+
+func TestShardedConsistentSnapshot(t *testing.T) {
+	t.Parallel()
+
+	const keys = 2000
+	// Every shard can hold all keys, so none of them is invalidated.
+	cache := NewSharded[int, int](16*(keys+1), 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Keys are put one after another, so any consistent snapshot holds
+		// a prefix of them.
+		for i := range keys {
+			cache.Put(i, i)
+		}
+	}()
+
+	check := func() int {
+		seen := make([]bool, keys)
+		count := 0
+		// The cache is changed while ranging over it.
+		for key, value := range cache.All() {
+			require.Equal(t, key, value)
+			cache.Put(keys+key, key)
+			require.True(t, cache.Remove(keys+key))
+			seen[key] = true
+			count++
+		}
+		for i := range count {
+			require.True(t, seen[i], "key %d is missing in a snapshot of %d keys", i, count)
+		}
+		return count
+	}
+	for {
+		select {
+		case <-done:
+			require.Equal(t, keys, check())
+			return
+		default:
+			check()
+		}
+	}
+}
+
+func TestShardedInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() { NewSharded[int, int](-1) })
+	require.Panics(t, func() { NewSharded[int, int](10, 0) })
+	require.Panics(t, func() { NewSharded[int, int](10, 1, 2) })
+}
+
+func TestShardedRemove(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+
+	for i := 0; i < 10; i += 2 {
+		require.True(t, cache.Remove(i))
+		require.False(t, cache.Remove(i))
+	}
+	require.Equal(t, 5, cache.Size())
+
+	keys, _ := collect(cache.All())
+	slices.Sort(keys)
+	require.Equal(t, []int{1, 3, 5, 7, 9}, keys)
+}
+
+func TestShardedClear(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+
+	cache.Clear()
+	require.Zero(t, cache.Size())
+	require.Equal(t, 100, cache.Capacity())
+
+	cache.Put(1, 1)
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{1}, keys)
+}
+
+func TestShardedResize(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+
+	cache.Resize(10)
+	require.Equal(t, 10, cache.Capacity())
+	require.LessOrEqual(t, cache.Size(), 10)
+
+	cache.Resize(2)
+	require.Equal(t, 2, cache.Capacity())
+	require.LessOrEqual(t, cache.Size(), 2)
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+	require.LessOrEqual(t, cache.Size(), 2)
+}
+
+func TestShardedPeek(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	cache.Put(1, 10)
+
+	value, err := cache.Peek(1)
+	require.NoError(t, err)
+	require.Equal(t, 10, value)
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+
+	_, err = cache.Peek(2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestShardedContains(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	cache.Put(1, 1)
+	require.True(t, cache.Contains(1))
+	require.False(t, cache.Contains(2))
+
+	frequency, err := cache.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+}
+
+func TestShardedEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	entries := slices.Collect(cache.Entries())
+	require.Len(t, entries, 10)
+	for i, entry := range entries {
+		require.Equal(t, 9-i, entry.Key)
+		require.Equal(t, entry.Key, entry.Value)
+		require.Equal(t, entry.Key+1, entry.Frequency)
+	}
+}
+
+func TestShardedGetOrCompute(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+
+	value, err := cache.GetOrCompute(1, func() (int, error) { return 10, nil })
+	require.NoError(t, err)
+	require.Equal(t, 10, value)
+
+	value, err = cache.GetOrCompute(1, func() (int, error) { return 20, nil })
+	require.NoError(t, err)
+	require.Equal(t, 10, value)
+}
+
+func TestShardedGetOrComputeSingleFlight(t *testing.T) {
+	t.Parallel()
+
+	const goroutines = 16
+
+	cache := NewSharded[int, int](10, 2)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]int, goroutines)
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetOrCompute(1, compute)
+			require.NoError(t, err)
+			values[i] = value
+		}()
+	}
+
+	// Other keys of the shard are not blocked by the computation.
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	require.True(t, cache.Contains(2))
+
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	for _, value := range values {
+		require.Equal(t, 42, value)
+	}
+}
+
+func TestShardedGetOrComputeError(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](10, 2)
+	computeErr := errors.New("compute failed")
+
+	_, err := cache.GetOrCompute(1, func() (int, error) { return 0, computeErr })
+	require.ErrorIs(t, err, computeErr)
+	require.False(t, cache.Contains(1))
+
+	require.Panics(t, func() {
+		_, _ = cache.GetOrCompute(1, func() (int, error) { panic("boom") })
+	})
+
+	value, err := cache.GetOrCompute(1, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+}
@@ -0,0 +1,102 @@
+package lfu
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// sketchDepth is the number of rows of the count-min sketch.
+const sketchDepth = 4
+
+// sketchWidthFactor is the number of counters of a row per key of the cache.
+const sketchWidthFactor = 8
+
+// sketchMaxCounter is the maximum value of a counter, the counters saturate
+// at it.
+const sketchMaxCounter = 15
+
+// countMinSketch estimates how often keys have been used recently. Every key
+// increments a counter in each row, the estimate is the minimum of them, so
+// collisions can only overestimate. The counters are halved once the number
+// of increments reaches the sample size, so that the history ages.
+type countMinSketch[K comparable] struct {
+	rows [sketchDepth][]uint8
+	// mask selects a counter of a row, rows have a power of two length.
+	mask uint64
+	seed maphash.Seed
+	// increments since the last halving
+	increments int
+	sampleSize int
+}
+
+// newCountMinSketch creates the sketch for a cache of the given capacity.
+func newCountMinSketch[K comparable](capacity int) *countMinSketch[K] {
+	// Several counters per key of the cache keep the collisions rare.
+	width := uint64(1) << bits.Len64(uint64(sketchWidthFactor*max(capacity, 1)))
+	s := &countMinSketch[K]{
+		mask:       width - 1,
+		seed:       maphash.MakeSeed(),
+		sampleSize: 10 * max(capacity, 1),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// indexes returns the counters of the key in every row. Every row mixes the
+// hash of the key differently, so that keys colliding in one row rarely
+// collide in the others.
+func (s *countMinSketch[K]) indexes(key K) [sketchDepth]uint64 {
+	hash := maphash.Comparable(s.seed, key)
+	var indexes [sketchDepth]uint64
+	for i := range indexes {
+		indexes[i] = mix(hash+uint64(i)*0x9e3779b97f4a7c15) & s.mask
+	}
+	return indexes
+}
+
+// mix is the finalizer of SplitMix64.
+func mix(x uint64) uint64 {
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// increment records a use of the key.
+func (s *countMinSketch[K]) increment(key K) {
+	for i, index := range s.indexes(key) {
+		if s.rows[i][index] < sketchMaxCounter {
+			s.rows[i][index]++
+		}
+	}
+	s.increments++
+	if s.increments == s.sampleSize {
+		s.reset()
+	}
+}
+
+// estimate returns the estimated number of recent uses of the key.
+func (s *countMinSketch[K]) estimate(key K) uint8 {
+	estimate := uint8(sketchMaxCounter)
+	for i, index := range s.indexes(key) {
+		estimate = min(estimate, s.rows[i][index])
+	}
+	return estimate
+}
+
+// reset halves all counters.
+func (s *countMinSketch[K]) reset() {
+	for _, row := range s.rows {
+		for i := range row {
+			row[i] >>= 1
+		}
+	}
+	s.increments /= 2
+}
+
+// admit reports whether the candidate is used more often than the victim, so
+// that it is worth invalidating the victim.
+func (s *countMinSketch[K]) admit(candidate, victim K) bool {
+	return s.estimate(candidate) > s.estimate(victim)
+}
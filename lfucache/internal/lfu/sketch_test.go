@@ -0,0 +1,105 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	t.Parallel()
+
+	sketch := newCountMinSketch[int](64)
+
+	for i := 0; i < 5; i++ {
+		sketch.increment(1)
+	}
+	sketch.increment(2)
+
+	require.GreaterOrEqual(t, sketch.estimate(1), uint8(5))
+	require.GreaterOrEqual(t, sketch.estimate(2), uint8(1))
+	require.True(t, sketch.admit(1, 2))
+	require.False(t, sketch.admit(2, 1))
+
+	for i := 0; i < 100; i++ {
+		sketch.increment(3)
+	}
+	require.Equal(t, uint8(sketchMaxCounter), sketch.estimate(3))
+}
+
+func TestCountMinSketchAging(t *testing.T) {
+	t.Parallel()
+
+	sketch := newCountMinSketch[int](1)
+
+	for i := 0; i < 8; i++ {
+		sketch.increment(1)
+	}
+	before := sketch.estimate(1)
+
+	// Other keys fill the sample, so the counters are halved.
+	for i := 100; sketch.increments != 0 && i < 200; i++ {
+		sketch.increment(i)
+	}
+	require.Less(t, sketch.estimate(1), before)
+}
+
+func TestTinyLFURejectsColdKeys(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(2, WithTinyLFU[int, int]())
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	for i := 0; i < 3; i++ {
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+		_, err = cache.Get(2)
+		require.NoError(t, err)
+	}
+
+	// The key seen once is not worth invalidating the warm ones.
+	cache.Put(3, 3)
+	require.False(t, cache.Contains(3))
+	require.True(t, cache.Contains(1))
+	require.True(t, cache.Contains(2))
+
+	// Once it becomes warmer than the victim, it is admitted.
+	for i := 0; i < 10; i++ {
+		_, err := cache.Get(3)
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	cache.Put(3, 3)
+	require.True(t, cache.Contains(3))
+}
+
+func TestTinyLFUImprovesHitRatio(t *testing.T) {
+	t.Parallel()
+
+	const (
+		capacity = 10
+		rounds   = 1000
+	)
+
+	hitRatio := func(opts ...Option[int, int]) float64 {
+		cache := NewWithOptions(capacity, opts...)
+		scanKey := 1_000
+		for i := 0; i < rounds; i++ {
+			// Hot keys interleaved with a scan of keys used only once.
+			for key := 0; key < capacity; key++ {
+				if _, err := cache.Get(key); err != nil {
+					cache.Put(key, key)
+				}
+				cache.Put(scanKey, scanKey)
+				scanKey++
+			}
+		}
+		return cache.Stats().HitRatio()
+	}
+
+	plain := hitRatio(WithPolicy[int, int](PolicyLRU))
+	tinyLFU := hitRatio(WithPolicy[int, int](PolicyLRU), WithTinyLFU[int, int]())
+
+	require.Greater(t, tinyLFU, 0.8)
+	require.Greater(t, tinyLFU, plain)
+}
@@ -0,0 +1,255 @@
+package lfu
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"lfucache/internal/linkedlist"
+)
+
+var (
+	// ErrSnapshotVersion is returned when the snapshot has been written in an
+	// unsupported format version.
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	// ErrSnapshotCapacity is returned when the snapshot does not fit into the
+	// cache.
+	ErrSnapshotCapacity = errors.New("snapshot does not fit into the cache")
+	// ErrSnapshotCorrupted is returned when the entries of the snapshot are
+	// inconsistent.
+	ErrSnapshotCorrupted = errors.New("snapshot is corrupted")
+)
+
+// snapshotVersion is the version of the snapshot format written by Save.
+const snapshotVersion = 1
+
+// snapshotHeader precedes the entries of the snapshot.
+type snapshotHeader struct {
+	Version  int
+	Capacity int
+	Size     int
+}
+
+// Save writes the snapshot of the cache to w: the entries with their
+// frequencies in the order of Entries, so that the recency order is kept as
+// well. Keys and values are encoded with encoding/gob, so they must be
+// encodable by it.
+func (l *cacheImpl[K, V]) Save(w io.Writer) error {
+	return saveSnapshot(w, l.capacity, l.size, l.Entries())
+}
+
+// Save writes the snapshot of the cache to w in the same format as the LFU
+// cache does, the entries of the shards are merged in the order of Entries.
+func (c *shardedCacheImpl[K, V]) Save(w io.Writer) error {
+	entries := make([]Entry[K, V], 0, c.Size())
+	for entry := range c.Entries() {
+		entries = append(entries, entry)
+	}
+	return saveSnapshot(w, c.Capacity(), len(entries), func(yield func(Entry[K, V]) bool) {
+		for _, entry := range entries {
+			if !yield(entry) {
+				return
+			}
+		}
+	})
+}
+
+// saveSnapshot writes the header followed by size entries.
+func saveSnapshot[K comparable, V any](
+	w io.Writer,
+	capacity, size int,
+	entries iter.Seq[Entry[K, V]],
+) error {
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(snapshotHeader{
+		Version:  snapshotVersion,
+		Capacity: capacity,
+		Size:     size,
+	}); err != nil {
+		return fmt.Errorf("encode snapshot header: %w", err)
+	}
+	for entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("encode snapshot entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewFromSnapshot initializes the cache with the capacity and the entries of
+// the snapshot written by Save, the options are applied as in NewWithOptions.
+// The entries, or their total weight if a weigher is set, must fit into the
+// capacity. The restored entries expire as put by Put if a TTL is set.
+func NewFromSnapshot[K comparable, V any](r io.Reader, opts ...Option[K, V]) (*cacheImpl[K, V], error) {
+	header, entries, err := readSnapshot[K, V](r)
+	if err != nil {
+		return nil, err
+	}
+	if header.Capacity < 0 {
+		return nil, fmt.Errorf("%w: negative capacity", ErrSnapshotCorrupted)
+	}
+	l := NewWithOptions(header.Capacity, opts...)
+	if err := checkCapacity(entries, l.capacity, l.weigher); err != nil {
+		return nil, err
+	}
+	l.restore(entries)
+	return l, nil
+}
+
+// Load replaces the entries of the cache with the entries of the snapshot
+// written by Save, restoring their frequencies and recency order. The
+// snapshot must fit into the capacity of the cache. The current entries are
+// removed as by Clear only if the snapshot has been read successfully and
+// fits.
+func (l *cacheImpl[K, V]) Load(r io.Reader) error {
+	_, entries, err := readSnapshot[K, V](r)
+	if err != nil {
+		return err
+	}
+	if err := checkCapacity(entries, l.capacity, l.weigher); err != nil {
+		return err
+	}
+	l.Clear()
+	l.restore(entries)
+	return nil
+}
+
+// Load replaces the entries of the cache with the entries of the snapshot
+// written by Save. The snapshot must fit into the capacity of the cache, but
+// since keys are spread over the shards unevenly, a shard keeps only its most
+// frequently used entries if it cannot hold all of its entries. The shards
+// are left intact if the snapshot does not fit.
+func (c *shardedCacheImpl[K, V]) Load(r io.Reader) error {
+	_, entries, err := readSnapshot[K, V](r)
+	if err != nil {
+		return err
+	}
+	// The shards share the options, so they share the weigher as well.
+	if err := checkCapacity(entries, c.Capacity(), c.shards[0].cache.weigher); err != nil {
+		return err
+	}
+	// Entries of a shard keep their order in the snapshot.
+	shardEntries := make(map[*shard[K, V]][]Entry[K, V], len(c.shards))
+	for _, entry := range entries {
+		s := c.shardFor(entry.Key)
+		shardEntries[s] = append(shardEntries[s], entry)
+	}
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		s.cache.Clear()
+		entries := shardEntries[s]
+		s.cache.restore(entries[:fitting(entries, s.cache.capacity, s.cache.weigher)])
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// checkCapacity returns ErrSnapshotCapacity if the entries do not fit into
+// the capacity, by their total weight if the weigher is set.
+func checkCapacity[K comparable, V any](entries []Entry[K, V], capacity int, weigher Weigher[K, V]) error {
+	if fitting(entries, capacity, weigher) == len(entries) {
+		return nil
+	}
+	if weigher == nil {
+		return fmt.Errorf("%w: %d entries, capacity is %d", ErrSnapshotCapacity, len(entries), capacity)
+	}
+	return fmt.Errorf("%w: total weight exceeds capacity %d", ErrSnapshotCapacity, capacity)
+}
+
+// fitting returns the number of the leading entries which fit into the
+// capacity, by their total weight if the weigher is set.
+func fitting[K comparable, V any](entries []Entry[K, V], capacity int, weigher Weigher[K, V]) int {
+	if weigher == nil {
+		return min(len(entries), capacity)
+	}
+	weight := 0
+	for i, entry := range entries {
+		weight += weigher(entry.Key, entry.Value)
+		if weight > capacity {
+			return i
+		}
+	}
+	return len(entries)
+}
+
+// readSnapshot reads the snapshot and checks that its entries are sorted as
+// Entries sorts them and do not repeat keys.
+func readSnapshot[K comparable, V any](r io.Reader) (snapshotHeader, []Entry[K, V], error) {
+	decoder := gob.NewDecoder(r)
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return header, nil, fmt.Errorf("decode snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return header, nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+	}
+	if header.Size < 0 {
+		return header, nil, fmt.Errorf("%w: negative size", ErrSnapshotCorrupted)
+	}
+
+	entries := make([]Entry[K, V], 0, header.Size)
+	keys := make(map[K]struct{}, header.Size)
+	for i := 0; i < header.Size; i++ {
+		var entry Entry[K, V]
+		if err := decoder.Decode(&entry); err != nil {
+			return header, nil, fmt.Errorf("decode snapshot entry: %w", err)
+		}
+		if entry.Frequency < 1 || (i != 0 && entry.Frequency > entries[i-1].Frequency) {
+			return header, nil, fmt.Errorf("%w: entries are not sorted by frequency", ErrSnapshotCorrupted)
+		}
+		if _, ok := keys[entry.Key]; ok {
+			return header, nil, fmt.Errorf("%w: duplicate key", ErrSnapshotCorrupted)
+		}
+		keys[entry.Key] = struct{}{}
+		entries = append(entries, entry)
+	}
+	return header, entries, nil
+}
+
+// restore appends the entries sorted as by Entries to the empty cache, they
+// must fit into its capacity.
+func (l *cacheImpl[K, V]) restore(entries []Entry[K, V]) {
+	for _, entry := range entries {
+		weight := 0
+		if l.weigher != nil {
+			weight = l.weigher(entry.Key, entry.Value)
+		}
+		frequency := entry.Frequency
+		// The LRU cache keeps all items in the group with frequency 1.
+		if l.policy == PolicyLRU {
+			frequency = 1
+		}
+		cacheItemNode := l.appendCacheItemNode(entry.Key, entry.Value, frequency)
+		cacheItemNode.Value.weight = weight
+		l.weight += weight
+		if l.ttl != 0 {
+			l.setExpiration(cacheItemNode, l.ttl, l.sliding)
+		}
+	}
+}
+
+// appendCacheItemNode places a new cache item with the given frequency as the
+// least recently used one. The frequency must not exceed the frequency of
+// any cache item.
+func (l *cacheImpl[K, V]) appendCacheItemNode(key K, value V, frequency int) *linkedlist.Node[CacheItem[K, V]] {
+	cacheItemNode := l.getNewCacheItemNode(key, value)
+	if l.size != 0 && l.freqGroupsList.Last().Value.frequency == frequency {
+		frequencyGroupNode := l.freqGroupsList.Last()
+		frequencyGroupNode.Value.elementsList.PushBack(cacheItemNode)
+		cacheItemNode.Value.frequency = frequency
+	} else {
+		frequencyGroupNode := l.getNewFrequencyGroupNode(cacheItemNode, frequency)
+		if l.freqGroupsList == nil {
+			l.freqGroupsList = linkedlist.New(frequencyGroupNode)
+		} else {
+			l.freqGroupsList.PushBack(frequencyGroupNode)
+		}
+		l.freqToFreqGroupNode[frequency] = frequencyGroupNode
+	}
+	l.keyToCacheItem[key] = cacheItemNode
+	l.size++
+	return cacheItemNode
+}
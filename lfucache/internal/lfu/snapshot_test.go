@@ -0,0 +1,277 @@
+package lfu
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeSnapshot[K comparable, V any](t *testing.T, data []byte) (snapshotHeader, []Entry[K, V]) {
+	t.Helper()
+
+	decoder := gob.NewDecoder(bytes.NewReader(data))
+	var header snapshotHeader
+	require.NoError(t, decoder.Decode(&header))
+
+	entries := make([]Entry[K, V], header.Size)
+	for i := range entries {
+		require.NoError(t, decoder.Decode(&entries[i]))
+	}
+	return header, entries
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](4)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	_, err := cache.Get("a")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	header, entries := decodeSnapshot[string, int](t, buf.Bytes())
+	require.Equal(t, snapshotHeader{Version: snapshotVersion, Capacity: 4, Size: 3}, header)
+	require.Equal(t, []Entry[string, int]{
+		{Key: "a", Value: 1, Frequency: 2},
+		{Key: "c", Value: 3, Frequency: 1},
+		{Key: "b", Value: 2, Frequency: 1},
+	}, entries)
+}
+
+func TestShardedSave(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	header, entries := decodeSnapshot[int, int](t, buf.Bytes())
+	require.Equal(t, 100, header.Capacity)
+	require.Len(t, entries, 10)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk is full")
+}
+
+func TestSaveError(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](1)
+	cache.Put(1, 1)
+
+	require.Error(t, cache.Save(failingWriter{}))
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, string](5)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, strconv.Itoa(i))
+		for j := 0; j < i%3; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+	data := buf.Bytes()
+
+	restored, err := NewFromSnapshot[int, string](bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, cache.Capacity(), restored.Capacity())
+	require.Equal(t, slices.Collect(cache.Entries()), slices.Collect(restored.Entries()))
+
+	// The restored cache evicts in the same order as the original one.
+	cache.Put(10, "10")
+	restored.Put(10, "10")
+	require.Equal(t, slices.Collect(cache.Entries()), slices.Collect(restored.Entries()))
+	_, err = restored.Get(0)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Load replaces the current entries.
+	other := New[int, string](10)
+	other.Put(42, "42")
+	require.NoError(t, other.Load(bytes.NewReader(data)))
+	require.False(t, other.Contains(42))
+	require.Equal(t, 5, other.Size())
+	require.Equal(t, 10, other.Capacity())
+
+	frequency, err := other.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 3, frequency)
+
+	// The freed and restored structures stay consistent.
+	for i := 0; i < 20; i++ {
+		other.Put(i, strconv.Itoa(i))
+	}
+	require.Equal(t, 10, other.Size())
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](3)
+	for i := 0; i < 3; i++ {
+		cache.Put(i, i)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	small := New[int, int](2)
+	small.Put(42, 42)
+	require.ErrorIs(t, small.Load(bytes.NewReader(buf.Bytes())), ErrSnapshotCapacity)
+	require.True(t, small.Contains(42))
+
+	heavy := NewWithOptions(3, WithWeigher(func(key int, value int) int {
+		return 2
+	}))
+	heavy.Put(42, 42)
+	require.ErrorIs(t, heavy.Load(bytes.NewReader(buf.Bytes())), ErrSnapshotCapacity)
+	require.True(t, heavy.Contains(42))
+
+	write := func(header snapshotHeader, entries ...Entry[int, int]) []byte {
+		var buf bytes.Buffer
+		encoder := gob.NewEncoder(&buf)
+		require.NoError(t, encoder.Encode(header))
+		for _, entry := range entries {
+			require.NoError(t, encoder.Encode(entry))
+		}
+		return buf.Bytes()
+	}
+
+	_, err := NewFromSnapshot[int, int](bytes.NewReader(write(snapshotHeader{Version: 42})))
+	require.ErrorIs(t, err, ErrSnapshotVersion)
+
+	_, err = NewFromSnapshot[int, int](bytes.NewReader(write(
+		snapshotHeader{Version: snapshotVersion, Capacity: 2, Size: 2},
+		Entry[int, int]{Key: 1, Value: 1, Frequency: 1},
+		Entry[int, int]{Key: 2, Value: 2, Frequency: 2},
+	)))
+	require.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	_, err = NewFromSnapshot[int, int](bytes.NewReader(write(
+		snapshotHeader{Version: snapshotVersion, Capacity: 2, Size: 2},
+		Entry[int, int]{Key: 1, Value: 1, Frequency: 1},
+		Entry[int, int]{Key: 1, Value: 1, Frequency: 1},
+	)))
+	require.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	oversized := write(
+		snapshotHeader{Version: snapshotVersion, Capacity: 1, Size: 2},
+		Entry[int, int]{Key: 1, Value: 1, Frequency: 1},
+		Entry[int, int]{Key: 2, Value: 2, Frequency: 1},
+	)
+	_, err = NewFromSnapshot[int, int](bytes.NewReader(oversized))
+	require.ErrorIs(t, err, ErrSnapshotCapacity)
+
+	// The entries fit by number, but not by weight.
+	_, err = NewFromSnapshot(bytes.NewReader(buf.Bytes()), WithWeigher(func(key int, value int) int {
+		return 2
+	}))
+	require.ErrorIs(t, err, ErrSnapshotCapacity)
+
+	_, err = NewFromSnapshot[int, int](bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	require.Error(t, err)
+}
+
+func TestShardedSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i%4; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	restored := NewSharded[int, int](100, 8)
+	require.NoError(t, restored.Load(bytes.NewReader(buf.Bytes())))
+	require.Equal(t, 20, restored.Size())
+
+	for entry := range cache.Entries() {
+		frequency, err := restored.GetKeyFrequency(entry.Key)
+		require.NoError(t, err)
+		require.Equal(t, entry.Frequency, frequency)
+	}
+}
+
+func TestShardedLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](8)
+	for i := 0; i < 8; i++ {
+		cache.Put(i, i)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	// The snapshot fits by number, but not by weight, so no shard is
+	// replaced.
+	sharded := NewShardedWithOptions(8, 4, WithWeigher(func(key int, value int) int {
+		return 2
+	}))
+	sharded.Put(42, 42)
+	require.ErrorIs(t, sharded.Load(bytes.NewReader(buf.Bytes())), ErrSnapshotCapacity)
+	require.True(t, sharded.Contains(42))
+	require.Equal(t, 1, sharded.Size())
+}
+
+func TestSnapshotTTL(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	var buf bytes.Buffer
+	require.NoError(t, cache.Save(&buf))
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	restored, err := NewFromSnapshot(bytes.NewReader(buf.Bytes()),
+		WithClock[int, int](clock),
+		WithTTL[int, int](time.Minute),
+	)
+	require.NoError(t, err)
+	require.True(t, restored.Contains(1))
+
+	loaded := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithTTL[int, int](time.Minute),
+		WithSlidingTTL[int, int](),
+	)
+	require.NoError(t, loaded.Load(bytes.NewReader(buf.Bytes())))
+
+	clock.Advance(40 * time.Second)
+	_, err = loaded.Get(1)
+	require.NoError(t, err)
+
+	clock.Advance(40 * time.Second)
+	require.False(t, restored.Contains(1))
+	require.False(t, restored.Contains(2))
+	// The sliding TTL is restarted by Get.
+	require.True(t, loaded.Contains(1))
+	require.False(t, loaded.Contains(2))
+}
@@ -0,0 +1,92 @@
+package lfu
+
+// Stats is a snapshot of the cache statistics.
+type Stats struct {
+	// Hits is the number of lookups which have found the key.
+	Hits uint64
+	// Misses is the number of lookups which have not found the key.
+	Misses uint64
+	// Puts is the number of values put into the cache.
+	Puts uint64
+	// Evictions is the number of keys invalidated by the cache itself, keys
+	// removed explicitly are not counted.
+	Evictions uint64
+	// Size is the cache size.
+	Size int
+	// Resets is the number of times the counters have been reset by
+	// ResetStats, summed over the shards of the sharded cache. Delta compares
+	// it to tell whether the counters have been reset in between.
+	Resets uint64
+}
+
+// HitRatio returns the share of lookups which have found the key, or 0 if
+// there have been no lookups.
+func (s Stats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// add returns the sum of the statistics.
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Hits:      s.Hits + other.Hits,
+		Misses:    s.Misses + other.Misses,
+		Puts:      s.Puts + other.Puts,
+		Evictions: s.Evictions + other.Evictions,
+		Size:      s.Size + other.Size,
+		Resets:    s.Resets + other.Resets,
+	}
+}
+
+// Delta returns the statistics accumulated since the previous snapshot, the
+// size and the number of resets are the current ones. If the counters have
+// been reset by ResetStats in between, they have been accumulated since the
+// reset, so they are reported as they are.
+func (s Stats) Delta(previous Stats) Stats {
+	if s.Resets != previous.Resets {
+		return s
+	}
+	return Stats{
+		Hits:      s.Hits - previous.Hits,
+		Misses:    s.Misses - previous.Misses,
+		Puts:      s.Puts - previous.Puts,
+		Evictions: s.Evictions - previous.Evictions,
+		Size:      s.Size,
+		Resets:    s.Resets,
+	}
+}
+
+// ResetStats returns the snapshot of the cache statistics and resets the
+// counters, the size is not affected.
+func (l *cacheImpl[K, V]) ResetStats() Stats {
+	stats := l.Stats()
+	l.stats = Stats{Resets: l.stats.Resets + 1}
+	return stats
+}
+
+// ResetStats returns the snapshot of the cache statistics and resets the
+// counters of every shard.
+func (c *shardedCacheImpl[K, V]) ResetStats() Stats {
+	var stats Stats
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		stats = stats.add(s.cache.ResetStats())
+		s.mu.Unlock()
+		if s.reads != nil {
+			stats = stats.add(s.reads.resetStats())
+		}
+	}
+	return stats
+}
+
+// ResetStats returns the snapshot of the cache statistics and resets the
+// counters.
+func (c *arcCacheImpl[K, V]) ResetStats() Stats {
+	stats := c.Stats()
+	c.stats = Stats{Resets: c.stats.Resets + 1}
+	return stats
+}
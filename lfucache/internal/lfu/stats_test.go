@@ -0,0 +1,85 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](2)
+	require.Equal(t, Stats{}, cache.Stats())
+	require.Zero(t, cache.Stats().HitRatio())
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+	_, err = cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = cache.Peek(3)
+	require.NoError(t, err)
+
+	_, err = cache.GetOrCompute(4, func() (int, error) { return 4, nil })
+	require.NoError(t, err)
+
+	require.True(t, cache.Remove(2))
+
+	stats := cache.Stats()
+	require.Equal(t, Stats{
+		Hits:      1,
+		Misses:    2,
+		Puts:      5,
+		Evictions: 2,
+		Size:      1,
+	}, stats)
+	require.InDelta(t, 1./3, stats.HitRatio(), 1e-9)
+}
+
+func TestShardedStats(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	for i := 0; i < 20; i++ {
+		_, _ = cache.Get(i)
+	}
+
+	require.Equal(t, Stats{
+		Hits:   10,
+		Misses: 10,
+		Puts:   10,
+		Size:   10,
+	}, cache.Stats())
+}
+
+func TestStatsDelta(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](2)
+	cache.Put(1, 1)
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(2)
+	previous := cache.Stats()
+
+	_, _ = cache.Get(1)
+	cache.Put(2, 2)
+	require.Equal(t, Stats{Hits: 1, Puts: 1, Size: 2}, cache.Stats().Delta(previous))
+
+	// The counters reset in between are reported as they are, even once they
+	// exceed the previous ones.
+	previous = cache.Stats()
+	cache.ResetStats()
+	for range 3 {
+		_, _ = cache.Get(1)
+	}
+	require.Equal(t, Stats{Hits: 3, Size: 2, Resets: 1}, cache.Stats().Delta(previous))
+}
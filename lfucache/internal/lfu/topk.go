@@ -0,0 +1,52 @@
+package lfu
+
+import (
+	"iter"
+	"slices"
+)
+
+// TopK returns the k most frequent entries in descending order of frequency
+// without changing the frequencies.
+func (l *cacheImpl[K, V]) TopK(k int) []Entry[K, V] {
+	return topK(l.Entries(), k)
+}
+
+// TopK returns the k most frequent entries of a snapshot of the cache in the
+// order of All without changing the frequencies. Only the k most frequent
+// items of every shard are copied.
+func (c *shardedCacheImpl[K, V]) TopK(k int) []Entry[K, V] {
+	if k <= 0 {
+		return nil
+	}
+	cursors := c.snapshot(func(cache *cacheImpl[K, V]) iter.Seq[CacheItem[K, V]] {
+		return func(yield func(CacheItem[K, V]) bool) {
+			taken := 0
+			for item := range cache.items() {
+				if !yield(item) {
+					return
+				}
+				if taken++; taken == k {
+					return
+				}
+			}
+		}
+	})
+	return topK(entries(func(yield func(CacheItem[K, V]) bool) {
+		mergeShardCursors(&cursors, &cursors, yield)
+	}), k)
+}
+
+// topK collects the first k entries, which are sorted by frequency.
+func topK[K comparable, V any](entries iter.Seq[Entry[K, V]], k int) []Entry[K, V] {
+	if k <= 0 {
+		return nil
+	}
+	top := make([]Entry[K, V], 0, min(k, 64))
+	for entry := range entries {
+		top = append(top, entry)
+		if len(top) == k {
+			break
+		}
+	}
+	return slices.Clip(top)
+}
@@ -0,0 +1,55 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](10)
+	require.Empty(t, cache.TopK(3))
+
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	require.Equal(t, []Entry[int, int]{
+		{Key: 4, Value: 4, Frequency: 5},
+		{Key: 3, Value: 3, Frequency: 4},
+	}, cache.TopK(2))
+	require.Len(t, cache.TopK(10), 5)
+	require.Empty(t, cache.TopK(0))
+
+	// Frequencies are not changed.
+	frequency, err := cache.GetKeyFrequency(4)
+	require.NoError(t, err)
+	require.Equal(t, 5, frequency)
+}
+
+func TestShardedTopK(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i; j++ {
+			_, err := cache.Get(i)
+			require.NoError(t, err)
+		}
+	}
+
+	top := cache.TopK(3)
+	require.Len(t, top, 3)
+	for i, entry := range top {
+		require.Equal(t, 19-i, entry.Key)
+		require.Equal(t, 20-i, entry.Frequency)
+	}
+}
@@ -0,0 +1,53 @@
+package lfu
+
+// Touch uses the key as Get does without returning its value: the frequency
+// of the key is incremented and the key becomes the most recently used one of
+// its group. Unlike Get, it is not counted in the statistics and does not
+// restart the sliding TTL. It reports whether the key exists.
+//
+// O(1)
+func (l *cacheImpl[K, V]) Touch(key K) bool {
+	return l.Boost(key, 1)
+}
+
+// Boost increments the frequency of the key by n at once, e.g. to signal
+// that the key is about to be used often. It panics if n is negative and
+// reports whether the key exists. The LRU cache only makes the key the most
+// recently used one.
+//
+// O(1) if n is 1, otherwise, O(number of frequency groups)
+func (l *cacheImpl[K, V]) Boost(key K, n int) bool {
+	if n < 0 {
+		panic("Invalid boost")
+	}
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || l.expireIfDue(cacheItemNode) {
+		return false
+	}
+	if l.sketch != nil {
+		l.sketch.increment(key)
+	}
+	switch {
+	case n == 0:
+	case n == 1 || l.policy == PolicyLRU:
+		l.updateFreqAndMoveCacheItemNode(cacheItemNode)
+	default:
+		l.moveToFrequency(cacheItemNode, cacheItemNode.Value.frequency+n)
+	}
+	return true
+}
+
+// Touch uses the key as Get does without returning its value and reports
+// whether the key exists.
+func (c *shardedCacheImpl[K, V]) Touch(key K) bool {
+	return c.Boost(key, 1)
+}
+
+// Boost increments the frequency of the key by n at once and reports whether
+// the key exists.
+func (c *shardedCacheImpl[K, V]) Boost(key K, n int) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Boost(key, n)
+}
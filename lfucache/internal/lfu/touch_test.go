@@ -0,0 +1,62 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTouchAndBoost(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](3)
+	for i := range 3 {
+		cache.Put(i, i)
+	}
+	require.False(t, cache.Touch(3))
+
+	require.True(t, cache.Touch(0))
+	require.True(t, cache.Boost(1, 5))
+	require.True(t, cache.Boost(2, 0))
+	require.Equal(t, Stats{Puts: 3, Size: 3}, cache.Stats())
+
+	entries := []Entry[int, int]{}
+	for entry := range cache.Entries() {
+		entries = append(entries, entry)
+	}
+	require.Equal(t, []Entry[int, int]{
+		{Key: 1, Value: 1, Frequency: 6},
+		{Key: 0, Value: 0, Frequency: 2},
+		{Key: 2, Value: 2, Frequency: 1},
+	}, entries)
+
+	// Boost places the key between the existing groups.
+	require.True(t, cache.Boost(2, 2))
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{1, 2, 0}, keys)
+
+	cache.Put(3, 3)
+	require.False(t, cache.Contains(0))
+
+	require.Panics(t, func() { cache.Boost(1, -1) })
+}
+
+func TestBoostLRUAndSharded(t *testing.T) {
+	t.Parallel()
+
+	cache := NewWithOptions(2, WithPolicy[int, int](PolicyLRU))
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	require.True(t, cache.Boost(1, 10))
+	cache.Put(3, 3)
+	require.True(t, cache.Contains(1))
+	require.False(t, cache.Contains(2))
+
+	sharded := NewSharded[int, int](4, 2)
+	sharded.Put(1, 1)
+	require.True(t, sharded.Touch(1))
+	require.True(t, sharded.Boost(1, 3))
+	frequency, err := sharded.GetKeyFrequency(1)
+	require.NoError(t, err)
+	require.Equal(t, 5, frequency)
+}
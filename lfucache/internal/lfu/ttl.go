@@ -0,0 +1,177 @@
+package lfu
+
+import (
+	"lfucache/internal/linkedlist"
+	"time"
+)
+
+// WithTTL makes the items put by Put expire once ttl has passed since they
+// were put. Expired items are not returned by lookups and iterators, they are
+// removed with EvictionReasonExpired when they are accessed, until then they
+// count towards the size of the cache. A negative ttl makes NewWithConfig
+// return ErrInvalidTTL.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.ttl = ttl
+		l.expiring = l.expiring || ttl != 0
+	}
+}
+
+// WithSlidingTTL makes Get restart the time to live of the items put by Put,
+// so that only the items which are not used for the TTL set with WithTTL
+// expire.
+func WithSlidingTTL[K comparable, V any]() Option[K, V] {
+	return func(l *cacheImpl[K, V]) {
+		l.sliding = true
+	}
+}
+
+// PutWithTTL puts the value as Put does, the item expires once ttl has passed
+// regardless of the accesses. Zero ttl means that the item never expires.
+func (l *cacheImpl[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	l.putWithTTL(key, value, ttl, false)
+}
+
+// PutWithSlidingTTL puts the value as Put does, the item expires once ttl has
+// passed since it was last put or got by Get.
+func (l *cacheImpl[K, V]) PutWithSlidingTTL(key K, value V, ttl time.Duration) {
+	l.putWithTTL(key, value, ttl, true)
+}
+
+func (l *cacheImpl[K, V]) putWithTTL(key K, value V, ttl time.Duration, sliding bool) {
+	if ttl < 0 {
+		panic("Invalid TTL")
+	}
+	l.expiring = l.expiring || ttl != 0
+	if cacheItemNode := l.put(key, value); cacheItemNode != nil {
+		l.setExpiration(cacheItemNode, ttl, sliding)
+		l.emitPut(cacheItemNode)
+	}
+}
+
+// setExpiration makes the cache item expire once ttl has passed, zero ttl
+// means that it never expires.
+func (l *cacheImpl[K, V]) setExpiration(
+	cacheItemNode *linkedlist.Node[CacheItem[K, V]],
+	ttl time.Duration,
+	sliding bool,
+) {
+	if ttl == 0 {
+		cacheItemNode.Value.expiresAt = 0
+		cacheItemNode.Value.slidingTTL = 0
+		return
+	}
+	cacheItemNode.Value.expiresAt = l.clock.Now().Add(ttl).UnixNano()
+	if sliding {
+		cacheItemNode.Value.slidingTTL = ttl
+	} else {
+		cacheItemNode.Value.slidingTTL = 0
+	}
+}
+
+// expirationTime returns the current time to compare expiration times with,
+// it is less than any expiration time if no item may expire.
+func (l *cacheImpl[K, V]) expirationTime() int64 {
+	if !l.expiring {
+		return 0
+	}
+	return l.clock.Now().UnixNano()
+}
+
+// isExpired reports whether the item with the expiration time has expired.
+func (l *cacheImpl[K, V]) isExpired(expiresAt int64) bool {
+	return expiresAt != 0 && expiresAt <= l.expirationTime()
+}
+
+// expireIfDue removes the cache item if it has expired and reports whether
+// it has been removed.
+func (l *cacheImpl[K, V]) expireIfDue(cacheItemNode *linkedlist.Node[CacheItem[K, V]]) bool {
+	if !l.isExpired(cacheItemNode.Value.expiresAt) {
+		return false
+	}
+	l.removeCacheItemNode(cacheItemNode, EvictionReasonExpired)
+	return true
+}
+
+// PutWithTTL puts the value as Put does, the item expires once ttl has passed
+// regardless of the accesses.
+func (c *shardedCacheImpl[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.PutWithTTL(key, value, ttl)
+}
+
+// PutWithSlidingTTL puts the value as Put does, the item expires once ttl has
+// passed since it was last put or got by Get.
+func (c *shardedCacheImpl[K, V]) PutWithSlidingTTL(key K, value V, ttl time.Duration) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.PutWithSlidingTTL(key, value, ttl)
+}
+
+// GetWithExpiration returns the value as Get does along with the time when
+// the item expires, refreshed if the TTL is sliding, or the zero time if the
+// item never expires.
+func (l *cacheImpl[K, V]) GetWithExpiration(key K) (V, time.Time, error) {
+	value, err := l.Get(key)
+	if err != nil {
+		return value, time.Time{}, err
+	}
+	return value, l.expiresAt(l.keyToCacheItem[key]), nil
+}
+
+// expiresAt returns the expiration time of the cache item, or the zero time
+// if it never expires.
+func (l *cacheImpl[K, V]) expiresAt(cacheItemNode *linkedlist.Node[CacheItem[K, V]]) time.Time {
+	if cacheItemNode.Value.expiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, cacheItemNode.Value.expiresAt)
+}
+
+// GetWithExpiration returns the value as Get does along with the time when
+// the item expires, or the zero time if the item never expires.
+func (c *shardedCacheImpl[K, V]) GetWithExpiration(key K) (V, time.Time, error) {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.GetWithExpiration(key)
+}
+
+// DeleteExpired removes all expired items, reporting them to the callbacks
+// as expired, and returns their number. Expired items are otherwise removed
+// only on access, so it lets the callers free the memory at the points of
+// their choice.
+//
+// O(size)
+func (l *cacheImpl[K, V]) DeleteExpired() int {
+	now := l.expirationTime()
+	if now == 0 {
+		return 0
+	}
+	var expired []*linkedlist.Node[CacheItem[K, V]]
+	for _, cacheItemNode := range l.keyToCacheItem {
+		if expiresAt := cacheItemNode.Value.expiresAt; expiresAt != 0 && expiresAt <= now {
+			expired = append(expired, cacheItemNode)
+		}
+	}
+	for _, cacheItemNode := range expired {
+		l.removeCacheItemNode(cacheItemNode, EvictionReasonExpired)
+	}
+	return len(expired)
+}
+
+// DeleteExpired removes all expired items of every shard and returns their
+// number. The shards are locked one after another.
+func (c *shardedCacheImpl[K, V]) DeleteExpired() int {
+	deleted := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.lock()
+		deleted += s.cache.DeleteExpired()
+		s.mu.Unlock()
+	}
+	return deleted
+}
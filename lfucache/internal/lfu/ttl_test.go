@@ -0,0 +1,235 @@
+package lfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTL(t *testing.T) {
+	t.Parallel()
+
+	var expired []int
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithTTL[int, int](time.Minute),
+		WithOnExpire(func(key int, _ int) { expired = append(expired, key) }),
+	)
+
+	cache.Put(1, 1)
+	clock.Advance(30 * time.Second)
+	cache.Put(2, 2)
+
+	value, err := cache.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	// Get does not restart the absolute expiration.
+	clock.Advance(30 * time.Second)
+	require.False(t, cache.Contains(1))
+	_, err = cache.Peek(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{2}, keys)
+	// The expired key is removed on access.
+	require.Equal(t, 2, cache.Size())
+	_, err = cache.Get(1)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, 1, cache.Size())
+	require.Equal(t, []int{1}, expired)
+
+	// Put restarts the expiration.
+	clock.Advance(20 * time.Second)
+	cache.Put(2, 20)
+	clock.Advance(50 * time.Second)
+	value, err = cache.Get(2)
+	require.NoError(t, err)
+	require.Equal(t, 20, value)
+
+	stats := cache.Stats()
+	require.Equal(t, uint64(1), stats.Evictions)
+	require.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestSlidingTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithTTL[int, int](time.Minute),
+		WithSlidingTTL[int, int](),
+	)
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	for range 5 {
+		clock.Advance(40 * time.Second)
+		_, err := cache.Get(1)
+		require.NoError(t, err)
+	}
+	// Peek does not restart the expiration.
+	_, err := cache.Peek(1)
+	require.NoError(t, err)
+
+	require.True(t, cache.Contains(1))
+	require.False(t, cache.Contains(2))
+
+	clock.Advance(time.Minute)
+	require.False(t, cache.Contains(1))
+}
+
+func TestPerEntryTTL(t *testing.T) {
+	t.Parallel()
+
+	var reasons []EvictionReason
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithOnEvict(func(_ int, _ int, reason EvictionReason) {
+			reasons = append(reasons, reason)
+		}),
+	)
+
+	cache.Put(1, 1)
+	cache.PutWithTTL(2, 2, time.Minute)
+	cache.PutWithSlidingTTL(3, 3, time.Minute)
+
+	clock.Advance(40 * time.Second)
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+	_, err = cache.Get(3)
+	require.NoError(t, err)
+
+	clock.Advance(40 * time.Second)
+	require.True(t, cache.Contains(1))
+	require.False(t, cache.Contains(2))
+	require.True(t, cache.Contains(3))
+
+	// The expired key is put again as a new one.
+	cache.Put(2, 20)
+	frequency, err := cache.GetKeyFrequency(2)
+	require.NoError(t, err)
+	require.Equal(t, 1, frequency)
+	require.Equal(t, []EvictionReason{EvictionReasonExpired}, reasons)
+
+	// Put without TTL makes the key never expire.
+	cache.Put(3, 30)
+	clock.Advance(time.Hour)
+	require.True(t, cache.Contains(3))
+
+	require.Panics(t, func() { cache.PutWithTTL(4, 4, -time.Second) })
+}
+
+func TestTTLUpdateAndPop(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10, WithClock[int, int](clock))
+
+	cache.PutWithTTL(1, 1, time.Minute)
+	cache.Put(2, 2)
+	_, err := cache.Get(2)
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Second)
+	require.True(t, cache.Update(1, func(old int) int { return old + 1 }))
+
+	clock.Advance(30 * time.Second)
+	require.False(t, cache.Update(1, func(old int) int { return old + 1 }))
+	require.False(t, cache.Remove(1))
+
+	cache.PutWithTTL(3, 3, time.Minute)
+	clock.Advance(time.Minute)
+	key, _, ok := cache.PopLFU()
+	require.True(t, ok)
+	require.Equal(t, 2, key)
+	require.Zero(t, cache.Size())
+}
+
+func TestShardedTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewShardedWithOptions(100, 4,
+		WithClock[int, int](clock),
+		WithTTL[int, int](time.Minute),
+	)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	cache.PutWithSlidingTTL(10, 10, time.Minute)
+	cache.PutWithTTL(11, 11, time.Hour)
+	clock.Advance(time.Minute)
+
+	keys, _ := collect(cache.All())
+	require.ElementsMatch(t, []int{11}, keys)
+}
+
+func TestGetWithExpiration(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	clock := NewFakeClock(start)
+	cache := NewShardedWithOptions(10, 2, WithClock[int, int](clock))
+
+	cache.Put(1, 1)
+	cache.PutWithTTL(2, 2, time.Minute)
+	cache.PutWithSlidingTTL(3, 3, time.Minute)
+	clock.Advance(30 * time.Second)
+
+	value, expiresAt, err := cache.GetWithExpiration(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	require.True(t, expiresAt.IsZero())
+
+	value, expiresAt, err = cache.GetWithExpiration(2)
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+	require.True(t, expiresAt.Equal(start.Add(time.Minute)))
+
+	_, expiresAt, err = cache.GetWithExpiration(3)
+	require.NoError(t, err)
+	require.True(t, expiresAt.Equal(start.Add(90*time.Second)))
+
+	clock.Advance(30 * time.Second)
+	_, expiresAt, err = cache.GetWithExpiration(2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.True(t, expiresAt.IsZero())
+}
+
+func TestDeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	var expired []int
+	clock := NewFakeClock(time.Unix(1_000_000, 0))
+	cache := NewWithOptions(10,
+		WithClock[int, int](clock),
+		WithOnExpire(func(key int, _ int) { expired = append(expired, key) }),
+	)
+	require.Zero(t, cache.DeleteExpired())
+
+	cache.Put(1, 1)
+	cache.PutWithTTL(2, 2, time.Minute)
+	cache.PutWithTTL(3, 3, time.Hour)
+	cache.PutWithSlidingTTL(4, 4, time.Minute)
+	require.Zero(t, cache.DeleteExpired())
+
+	clock.Advance(time.Minute)
+	require.Equal(t, 2, cache.DeleteExpired())
+	require.ElementsMatch(t, []int{2, 4}, expired)
+	require.Equal(t, 2, cache.Size())
+	require.Equal(t, uint64(2), cache.Stats().Evictions)
+
+	sharded := NewShardedWithOptions(10, 2, WithClock[int, int](clock), WithTTL[int, int](time.Minute))
+	for i := range 6 {
+		sharded.Put(i, i)
+	}
+	clock.Advance(time.Minute)
+	require.Equal(t, 6, sharded.Size())
+	require.Equal(t, 6, sharded.DeleteExpired())
+	require.Zero(t, sharded.Size())
+}
@@ -0,0 +1,26 @@
+package lfu
+
+// Update replaces the value of the key with the result of update called with
+// the current value and reports whether the key exists in the cache. The
+// frequency of the key is increased once, as by Put, the expiration time of
+// the key is kept.
+func (l *cacheImpl[K, V]) Update(key K, update func(old V) V) bool {
+	cacheItemNode, ok := l.keyToCacheItem[key]
+	if !ok || l.expireIfDue(cacheItemNode) {
+		return false
+	}
+	if cacheItemNode = l.put(key, update(cacheItemNode.Value.value)); cacheItemNode != nil {
+		l.emitPut(cacheItemNode)
+	}
+	return true
+}
+
+// Update replaces the value of the key with the result of update called with
+// the current value and reports whether the key exists in the cache. update is
+// called under the lock of the shard, so it must not use the cache.
+func (c *shardedCacheImpl[K, V]) Update(key K, update func(old V) V) bool {
+	s := c.shardFor(key)
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Update(key, update)
+}
@@ -0,0 +1,60 @@
+package lfu
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func increment(old int) int {
+	return old + 1
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](2)
+
+	require.False(t, cache.Update("a", increment))
+	require.False(t, cache.Contains("a"))
+
+	cache.Put("a", 1)
+	require.True(t, cache.Update("a", increment))
+
+	value, err := cache.Peek("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	frequency, err := cache.GetKeyFrequency("a")
+	require.NoError(t, err)
+	require.Equal(t, 2, frequency)
+}
+
+func TestShardedUpdateConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		goroutines = 8
+		updates    = 1000
+	)
+
+	cache := NewSharded[string, int](10)
+	cache.Put("counter", 0)
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range updates {
+				require.True(t, cache.Update("counter", increment))
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := cache.Peek("counter")
+	require.NoError(t, err)
+	require.Equal(t, goroutines*updates, value)
+}
@@ -0,0 +1,110 @@
+package lfu
+
+import (
+	"iter"
+	"slices"
+
+	"lfucache/internal/linkedlist"
+)
+
+// Warm puts the entries into the cache in one pass, as if Put were called for
+// each of them.
+func (l *cacheImpl[K, V]) Warm(entries iter.Seq2[K, V]) {
+	for key, value := range entries {
+		l.Put(key, value)
+	}
+}
+
+// WarmEntries puts the entries into the cache in one pass and assigns them the
+// initial frequencies, e.g. the ones known from the previous run of the
+// service. A key already in the cache keeps its frequency if it is higher.
+// The LRU cache ignores the frequencies.
+func (l *cacheImpl[K, V]) WarmEntries(entries iter.Seq[Entry[K, V]]) {
+	for entry := range entries {
+		l.Put(entry.Key, entry.Value)
+		cacheItemNode, ok := l.keyToCacheItem[entry.Key]
+		if !ok || l.policy == PolicyLRU || cacheItemNode.Value.frequency >= entry.Frequency {
+			continue
+		}
+		l.moveToFrequency(cacheItemNode, entry.Frequency)
+	}
+}
+
+// moveToFrequency makes the cache item the most recently used one of the group
+// with the given frequency, which is higher than its current frequency.
+//
+// O(number of frequency groups)
+func (l *cacheImpl[K, V]) moveToFrequency(
+	cacheItemNode *linkedlist.Node[CacheItem[K, V]],
+	frequency int,
+) {
+	currentFrequency := cacheItemNode.Value.frequency
+	currentFrequencyGroupNode := l.freqToFreqGroupNode[currentFrequency]
+	// The dummy head of the list of groups stops the search for the group.
+	head := l.freqGroupsList.First().Prev
+	greaterFrequencyGroupNode := currentFrequencyGroupNode.Prev
+
+	linkedlist.RemoveNode(cacheItemNode)
+	if currentFrequencyGroupNode.Value.elementsList.IsEmpty() {
+		delete(l.freqToFreqGroupNode, currentFrequency)
+		linkedlist.RemoveNode(currentFrequencyGroupNode)
+		l.freeNodesOfFreqGroups = append(l.freeNodesOfFreqGroups, currentFrequencyGroupNode)
+	}
+
+	if frequencyGroupNode, ok := l.freqToFreqGroupNode[frequency]; ok {
+		frequencyGroupNode.Value.elementsList.PushFront(cacheItemNode)
+		cacheItemNode.Value.frequency = frequency
+		return
+	}
+	// Find the group with the lowest frequency higher than the given one and
+	// place the new group right after it.
+	for greaterFrequencyGroupNode != head && greaterFrequencyGroupNode.Value.frequency < frequency {
+		greaterFrequencyGroupNode = greaterFrequencyGroupNode.Prev
+	}
+	frequencyGroupNode := l.getNewFrequencyGroupNode(cacheItemNode, frequency)
+	linkedlist.PutNodeBeforeAnotherNode(frequencyGroupNode, greaterFrequencyGroupNode.Next)
+	l.freqToFreqGroupNode[frequency] = frequencyGroupNode
+}
+
+// Warm puts the entries into the cache in one pass, taking the lock of every
+// shard once.
+func (c *shardedCacheImpl[K, V]) Warm(entries iter.Seq2[K, V]) {
+	c.warm(func(yield func(Entry[K, V]) bool) {
+		for key, value := range entries {
+			if !yield(Entry[K, V]{Key: key, Value: value}) {
+				return
+			}
+		}
+	}, false)
+}
+
+// WarmEntries puts the entries into the cache in one pass and assigns them the
+// initial frequencies, taking the lock of every shard once.
+func (c *shardedCacheImpl[K, V]) WarmEntries(entries iter.Seq[Entry[K, V]]) {
+	c.warm(entries, true)
+}
+
+// warm spreads the entries over the shards keeping their order and warms up
+// every shard.
+func (c *shardedCacheImpl[K, V]) warm(entries iter.Seq[Entry[K, V]], withFrequencies bool) {
+	shardEntries := make([][]Entry[K, V], len(c.shards))
+	for entry := range entries {
+		i := c.shardIndex(entry.Key)
+		shardEntries[i] = append(shardEntries[i], entry)
+	}
+	for i := range c.shards {
+		if len(shardEntries[i]) == 0 {
+			continue
+		}
+		s := &c.shards[i]
+		s.lock()
+		if withFrequencies {
+			s.cache.WarmEntries(slices.Values(shardEntries[i]))
+		} else {
+			for _, entry := range shardEntries[i] {
+				s.cache.Put(entry.Key, entry.Value)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
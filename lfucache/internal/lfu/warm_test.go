@@ -0,0 +1,88 @@
+package lfu
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	cache := New[int, int](3)
+	cache.Warm(maps.All(map[int]int{1: 10}))
+	cache.Warm(slices.All([]int{0, 10, 20, 30}))
+
+	require.Equal(t, 3, cache.Size())
+	keys, values := collect(cache.All())
+	require.Equal(t, []int{1, 3, 2}, keys)
+	require.Equal(t, []int{10, 30, 20}, values)
+}
+
+func TestWarmEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](5)
+	cache.Put("hot", 0)
+	for i := 0; i < 9; i++ {
+		_, err := cache.Get("hot")
+		require.NoError(t, err)
+	}
+
+	cache.WarmEntries(slices.Values([]Entry[string, int]{
+		{Key: "a", Value: 1, Frequency: 3},
+		{Key: "b", Value: 2, Frequency: 7},
+		{Key: "c", Value: 3, Frequency: 3},
+		{Key: "d", Value: 4},
+		{Key: "hot", Value: 5, Frequency: 2},
+	}))
+
+	require.Equal(t, []Entry[string, int]{
+		{Key: "hot", Value: 5, Frequency: 11},
+		{Key: "b", Value: 2, Frequency: 7},
+		{Key: "c", Value: 3, Frequency: 3},
+		{Key: "a", Value: 1, Frequency: 3},
+		{Key: "d", Value: 4, Frequency: 1},
+	}, slices.Collect(cache.Entries()))
+
+	// The least frequently used keys are invalidated first.
+	cache.Put("e", 5)
+	require.False(t, cache.Contains("d"))
+	cache.Put("f", 6)
+	require.False(t, cache.Contains("e"))
+	_, err := cache.Get("f")
+	require.NoError(t, err)
+	_, err = cache.Get("f")
+	require.NoError(t, err)
+	frequency, err := cache.GetKeyFrequency("f")
+	require.NoError(t, err)
+	require.Equal(t, 3, frequency)
+
+	keys, _ := collect(cache.All())
+	require.Equal(t, []string{"hot", "b", "f", "c", "a"}, keys)
+}
+
+func TestShardedWarm(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSharded[int, int](100, 4)
+
+	cache.Warm(slices.All([]int{0, 1, 2, 3, 4}))
+	require.Equal(t, 5, cache.Size())
+
+	cache.WarmEntries(slices.Values([]Entry[int, int]{
+		{Key: 10, Value: 10, Frequency: 5},
+		{Key: 11, Value: 11, Frequency: 2},
+	}))
+	require.Equal(t, 7, cache.Size())
+
+	frequency, err := cache.GetKeyFrequency(10)
+	require.NoError(t, err)
+	require.Equal(t, 5, frequency)
+
+	entries := slices.Collect(cache.Entries())
+	require.Equal(t, 10, entries[0].Key)
+	require.Equal(t, 11, entries[1].Key)
+}
@@ -0,0 +1,82 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func byLength(_ int, value string) int {
+	return len(value)
+}
+
+func TestWeigher(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(10,
+		WithWeigher(byLength),
+		WithOnEvict(func(key int, _ string, reason EvictionReason) {
+			require.Equal(t, EvictionReasonCapacity, reason)
+			evicted = append(evicted, key)
+		}),
+	)
+
+	cache.Put(1, "aaaa")
+	cache.Put(2, "bbb")
+	cache.Put(3, "cc")
+	require.Equal(t, 3, cache.Size())
+	_, err := cache.Get(1)
+	require.NoError(t, err)
+
+	// 6 units are needed: both 2 and 3 are invalidated, but not the more
+	// frequently used 1.
+	cache.Put(4, "dddddd")
+	require.Equal(t, []int{2, 3}, evicted)
+	keys, _ := collect(cache.All())
+	require.Equal(t, []int{1, 4}, keys)
+
+	// The oversized item is rejected.
+	cache.Put(5, "eeeeeeeeeee")
+	require.False(t, cache.Contains(5))
+	require.Equal(t, []int{2, 3}, evicted)
+}
+
+func TestWeigherUpdate(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	cache := NewWithOptions(10,
+		WithWeigher(byLength),
+		WithOnEvict(func(key int, _ string, _ EvictionReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+
+	cache.Put(1, "a")
+	cache.Put(2, "bb")
+	cache.Put(3, "ccc")
+
+	// The updated item is the least frequently used one, but it is never
+	// invalidated to make room for itself.
+	cache.Put(3, "ccccccccc")
+	require.Equal(t, []int{1, 2}, evicted)
+	value, err := cache.Peek(3)
+	require.NoError(t, err)
+	require.Equal(t, "ccccccccc", value)
+
+	// The item which no longer fits is invalidated.
+	cache.Put(3, "ccccccccccc")
+	require.Equal(t, []int{1, 2, 3}, evicted)
+	require.Zero(t, cache.Size())
+
+	cache.Put(4, "dddd")
+	cache.Put(5, "eeee")
+	cache.Resize(5)
+	require.Equal(t, []int{1, 2, 3, 4}, evicted)
+	require.Equal(t, 1, cache.Size())
+
+	require.Panics(t, func() {
+		NewWithOptions(10, WithWeigher(func(int, int) int { return -1 })).Put(1, 1)
+	})
+}
@@ -6,6 +6,12 @@ import "iter"
 type LinkedList[V any] interface {
 	// All iterates over LinkedList.
 	All() iter.Seq[V]
+	// Backward iterates over LinkedList from the last element to the first.
+	Backward() iter.Seq[V]
+	// Nodes iterates over the nodes of LinkedList. The yielded node may be
+	// removed or moved elsewhere, the iteration goes on with the node which
+	// followed it. A node moved ahead of the iteration is visited again.
+	Nodes() iter.Seq[*Node[V]]
 	// First element of LinkedList
 	First() *Node[V]
 	// Last element of LinkedList
@@ -14,12 +20,47 @@ type LinkedList[V any] interface {
 	PushBack(node *Node[V])
 	// PushFront makes node the first element in the list.
 	PushFront(node *Node[V])
+	// Len returns the number of elements in the list.
+	Len() int
+	// IsEmpty reports whether the list has no elements.
+	IsEmpty() bool
+	// SpliceBack moves all elements of other to the end of the list in the
+	// same order, other becomes empty.
+	SpliceBack(other LinkedList[V])
+	// SpliceFront moves all elements of other to the beginning of the list
+	// in the same order, other becomes empty.
+	SpliceFront(other LinkedList[V])
 }
 
 // linkedListImpl is a doubly linked list implementation.
 type linkedListImpl[V any] struct {
 	// head is the first element of LinkedList.
 	head *Node[V]
+	// counter counts the elements of LinkedList.
+	counter *counter
+}
+
+// counter is the number of elements of a list shared by its nodes. When the
+// elements of a list are spliced into another list, the counter of the former
+// is merged into the counter of the latter, so that the nodes do not have to
+// be updated one by one.
+type counter struct {
+	length int
+	// parent is the counter this counter has been merged into.
+	parent *counter
+}
+
+// root returns the counter all merged counters have been merged into.
+func (c *counter) root() *counter {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	// Compress the path, so that the next lookups are shorter.
+	for c != root {
+		c, c.parent = c.parent, root
+	}
+	return root
 }
 
 // Node is an element of the doubly linked list.
@@ -30,6 +71,9 @@ type Node[V any] struct {
 	Prev *Node[V]
 	// value of doubly linked list element
 	Value V
+	// counter of the list the element belongs to, it is nil if the element
+	// is not in a list.
+	counter *counter
 }
 
 func (list *linkedListImpl[V]) All() iter.Seq[V] {
@@ -44,6 +88,32 @@ func (list *linkedListImpl[V]) All() iter.Seq[V] {
 	}
 }
 
+func (list *linkedListImpl[V]) Nodes() iter.Seq[*Node[V]] {
+	return func(yield func(*Node[V]) bool) {
+		current := list.head.Next
+		for current != list.head {
+			// Remember the next node before the current one is moved.
+			next := current.Next
+			if !yield(current) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+func (list *linkedListImpl[V]) Backward() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		current := list.head.Prev
+		for current != list.head {
+			if !yield(current.Value) {
+				return
+			}
+			current = current.Prev
+		}
+	}
+}
+
 func (list *linkedListImpl[V]) First() *Node[V] {
 	return list.head.Next
 }
@@ -61,17 +131,65 @@ func NewNode[V any](value V) *Node[V] {
 
 // New creates LinkedList with dummies and a given node.
 func New[V any](node *Node[V]) *linkedListImpl[V] {
-	// Create dummy node to make operations with the list more
-	// convenient.
-	dummyHead := &Node[V]{
-		Next: node,
-		Prev: node,
+	list := NewEmpty[V]()
+	list.PushBack(node)
+	return list
+}
+
+// NewEmpty creates LinkedList without elements.
+func NewEmpty[V any]() *linkedListImpl[V] {
+	list := &linkedListImpl[V]{
+		// Create dummy node to make operations with the list more
+		// convenient.
+		head: &Node[V]{},
+	}
+	list.reset()
+	return list
+}
+
+// reset makes the list empty without touching its former elements.
+func (list *linkedListImpl[V]) reset() {
+	list.counter = &counter{}
+	list.head.Next = list.head
+	list.head.Prev = list.head
+	list.head.counter = list.counter
+}
+
+func (list *linkedListImpl[V]) Len() int {
+	return list.counter.length
+}
+
+func (list *linkedListImpl[V]) IsEmpty() bool {
+	return list.counter.length == 0
+}
+
+func (list *linkedListImpl[V]) SpliceBack(other LinkedList[V]) {
+	list.splice(other, list.head)
+}
+
+func (list *linkedListImpl[V]) SpliceFront(other LinkedList[V]) {
+	list.splice(other, list.head.Next)
+}
+
+// splice moves all elements of other before the given node of the list.
+func (list *linkedListImpl[V]) splice(other LinkedList[V], before *Node[V]) {
+	otherList := other.(*linkedListImpl[V])
+	if otherList == list {
+		panic("Splice of the list into itself")
 	}
-	node.Next = dummyHead
-	node.Prev = dummyHead
-	return &linkedListImpl[V]{
-		head: dummyHead,
+	if otherList.IsEmpty() {
+		return
 	}
+	first, last := otherList.head.Next, otherList.head.Prev
+	first.Prev = before.Prev
+	last.Next = before
+	before.Prev.Next = first
+	before.Prev = last
+
+	list.counter.length += otherList.counter.length
+	otherList.counter.length = 0
+	otherList.counter.parent = list.counter
+	otherList.reset()
 }
 
 func (list *linkedListImpl[V]) PushFront(node *Node[V]) {
@@ -89,6 +207,11 @@ func PutNodeBeforeAnotherNode[V any](node *Node[V], anotherNode *Node[V]) {
 	node.Next = anotherNode
 	anotherNode.Prev.Next = node
 	anotherNode.Prev = node
+	node.counter = nil
+	if anotherNode.counter != nil {
+		node.counter = anotherNode.counter.root()
+		node.counter.length++
+	}
 }
 
 // RemoveNode removes the given node from its current position in doubly linked
@@ -96,4 +219,8 @@ func PutNodeBeforeAnotherNode[V any](node *Node[V], anotherNode *Node[V]) {
 func RemoveNode[V any](node *Node[V]) {
 	node.Prev.Next = node.Next
 	node.Next.Prev = node.Prev
+	if node.counter != nil {
+		node.counter.root().length--
+		node.counter = nil
+	}
 }
@@ -0,0 +1,111 @@
+package linkedlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newList(values ...int) *linkedListImpl[int] {
+	list := NewEmpty[int]()
+	for _, value := range values {
+		list.PushBack(NewNode(value))
+	}
+	return list
+}
+
+func TestLength(t *testing.T) {
+	t.Parallel()
+
+	list := NewEmpty[int]()
+	require.True(t, list.IsEmpty())
+
+	first := NewNode(1)
+	list.PushBack(first)
+	list.PushFront(NewNode(0))
+	list.PushBack(NewNode(2))
+	require.Equal(t, 3, list.Len())
+	require.Equal(t, []int{0, 1, 2}, slices.Collect(list.All()))
+
+	RemoveNode(first)
+	require.Equal(t, 2, list.Len())
+	PutNodeBeforeAnotherNode(first, list.First())
+	require.Equal(t, []int{1, 0, 2}, slices.Collect(list.All()))
+	require.Equal(t, 3, list.Len())
+
+	single := New(NewNode(42))
+	require.Equal(t, 1, single.Len())
+	RemoveNode(single.First())
+	require.True(t, single.IsEmpty())
+}
+
+func TestSplice(t *testing.T) {
+	t.Parallel()
+
+	list := newList(1, 2)
+	back := newList(3, 4)
+	front := newList(-1, 0)
+
+	list.SpliceBack(back)
+	list.SpliceFront(front)
+	require.Equal(t, []int{-1, 0, 1, 2, 3, 4}, slices.Collect(list.All()))
+	require.Equal(t, []int{4, 3, 2, 1, 0, -1}, slices.Collect(list.Backward()))
+	require.Equal(t, 6, list.Len())
+	require.True(t, back.IsEmpty())
+	require.Empty(t, slices.Collect(back.All()))
+
+	// Nodes moved by splice are counted by their new list.
+	RemoveNode(list.Last())
+	RemoveNode(list.First())
+	require.Equal(t, 4, list.Len())
+
+	// The emptied lists can be used again.
+	back.PushBack(NewNode(5))
+	require.Equal(t, 1, back.Len())
+	require.Equal(t, 4, list.Len())
+
+	// Splicing chains of lists keeps the counters right.
+	other := newList(7)
+	back.SpliceBack(other)
+	list.SpliceBack(back)
+	require.Equal(t, []int{0, 1, 2, 3, 5, 7}, slices.Collect(list.All()))
+	RemoveNode(list.Last())
+	require.Equal(t, 5, list.Len())
+	require.True(t, other.IsEmpty())
+
+	list.SpliceBack(NewEmpty[int]())
+	require.Equal(t, 5, list.Len())
+	require.Panics(t, func() { list.SpliceBack(list) })
+}
+
+func TestNodes(t *testing.T) {
+	t.Parallel()
+
+	list := newList(1, 2, 3, 4, 5, 6)
+	evens := NewEmpty[int]()
+
+	visited := make([]int, 0, 6)
+	for node := range list.Nodes() {
+		visited = append(visited, node.Value)
+		if node.Value%2 == 0 {
+			RemoveNode(node)
+			evens.PushBack(node)
+		}
+	}
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6}, visited)
+	require.Equal(t, []int{1, 3, 5}, slices.Collect(list.All()))
+	require.Equal(t, []int{2, 4, 6}, slices.Collect(evens.All()))
+	require.Equal(t, 3, list.Len())
+	require.Equal(t, 3, evens.Len())
+
+	for node := range list.Nodes() {
+		RemoveNode(node)
+	}
+	require.True(t, list.IsEmpty())
+
+	for node := range evens.Nodes() {
+		require.Equal(t, 2, node.Value)
+		break
+	}
+}
@@ -0,0 +1,97 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanCount is the COUNT hint of the SCAN commands issued by Count.
+const scanCount = 1000
+
+// RedisClient is the Client on top of go-redis, it is safe for concurrent
+// use.
+type RedisClient struct {
+	rdb redis.UniversalClient
+}
+
+var _ Client = (*RedisClient)(nil)
+
+// NewClient wraps the go-redis client, which is configured, e.g. with the
+// address, password and database, by the caller. The client is closed by
+// Close.
+func NewClient(rdb redis.UniversalClient) *RedisClient {
+	return &RedisClient{rdb: rdb}
+}
+
+// Close closes the wrapped client.
+func (c *RedisClient) Close() error {
+	return c.rdb.Close()
+}
+
+// Get returns the value of the key and reports whether the key exists.
+func (c *RedisClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set sets the value of the key, which expires after ttl unless ttl is zero.
+// The TTL is rounded down to milliseconds, but not below one millisecond.
+func (c *RedisClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, expiration(ttl)).Err()
+}
+
+// Del deletes the key and reports whether it has existed.
+func (c *RedisClient) Del(ctx context.Context, key string) (bool, error) {
+	n, err := c.rdb.Del(ctx, key).Result()
+	return n > 0, err
+}
+
+// Count returns the number of keys with the prefix. It iterates over the keys
+// with SCAN, so it does not block the server, but a key put or deleted during
+// the iteration may or may not be counted.
+//
+// O(number of keys in Redis)
+func (c *RedisClient) Count(ctx context.Context, prefix string) (int, error) {
+	// SCAN may return a key more than once.
+	seen := make(map[string]struct{})
+	iter := c.rdb.Scan(ctx, 0, escapeGlob(prefix)+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		seen[iter.Val()] = struct{}{}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	return len(seen), nil
+}
+
+// expiration maps the TTL of the adapter to the expiration of go-redis, where
+// zero means no expiration as well, but negative values have special
+// meanings and TTLs below one millisecond are logged as misuse.
+func expiration(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 0
+	}
+	return max(ttl.Truncate(time.Millisecond), time.Millisecond)
+}
+
+// escapeGlob escapes the special characters of the MATCH patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package rediscache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, options *redis.Options) (*miniredis.Miniredis, *RedisClient) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	options.Addr = server.Addr()
+	client := NewClient(redis.NewClient(options))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return server, client
+}
+
+func TestRedisClient(t *testing.T) {
+	t.Parallel()
+
+	server, client := newTestClient(t, &redis.Options{})
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "books:1", []byte("Onegin\r\n"), 0))
+	require.NoError(t, client.Set(ctx, "books:2", []byte(""), time.Minute))
+	require.NoError(t, client.Set(ctx, "books:*", []byte("x"), 0))
+	require.NoError(t, client.Set(ctx, "other:1", []byte("x"), 0))
+
+	value, ok, err := client.Get(ctx, "books:1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("Onegin\r\n"), value)
+
+	value, ok, err = client.Get(ctx, "books:2")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, value)
+
+	_, ok, err = client.Get(ctx, "books:3")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	n, err := client.Count(ctx, "books:")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	n, err = client.Count(ctx, "books:*")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	ok, err = client.Del(ctx, "books:1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = client.Del(ctx, "books:1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.Equal(t, time.Minute, server.TTL("books:2"))
+	server.FastForward(time.Minute)
+	_, ok, err = client.Get(ctx, "books:2")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestRedisClientCache(t *testing.T) {
+	t.Parallel()
+
+	_, client := newTestClient(t, &redis.Options{})
+
+	for _, codec := range []Codec[book]{JSONCodec[book]{}, MsgpackCodec[book]{}} {
+		c := New(client, "books:", WithCodec[int](codec))
+		c.Put(1, book{Name: "Onegin", Authors: []string{"Pushkin"}})
+
+		value, err := c.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, book{Name: "Onegin", Authors: []string{"Pushkin"}}, value)
+		require.Equal(t, 1, c.Len())
+		require.True(t, c.Remove(1))
+	}
+}
+
+func TestRedisClientErrors(t *testing.T) {
+	t.Parallel()
+
+	server, client := newTestClient(t, &redis.Options{Password: "wrong"})
+	server.RequireAuth("secret")
+	ctx := context.Background()
+
+	_, _, err := client.Get(ctx, "a")
+	require.ErrorContains(t, err, "WRONGPASS")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = client.Get(cancelled, "a")
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, client.Close())
+	_, _, err = client.Get(ctx, "a")
+	require.ErrorIs(t, err, redis.ErrClosed)
+}
+
+func TestExpiration(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, expiration(0))
+	require.Zero(t, expiration(-time.Second))
+	require.Equal(t, time.Millisecond, expiration(time.Microsecond))
+	require.Equal(t, 1500*time.Millisecond, expiration(1500*time.Millisecond+time.Microsecond))
+}
+
+func TestEscapeGlob(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `a\*b\?c\[d\]e\\`, escapeGlob(`a*b?c[d]e\`))
+}
@@ -0,0 +1,225 @@
+// Package rediscache implements cache.Cache on top of Redis, so that the
+// services can share the cache instead of keeping one per process.
+//
+// The adapter talks to Redis through Client, which RedisClient implements on
+// top of go-redis with GET, SET with expiration, DEL and SCAN over the key
+// prefix.
+package rediscache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lfucache/internal/cache"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Client is the subset of Redis commands used by the adapter.
+type Client interface {
+	// Get returns the value of the key and reports whether the key exists.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set sets the value of the key, which expires after ttl unless ttl is
+	// zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Del deletes the key and reports whether it has existed.
+	Del(ctx context.Context, key string) (bool, error)
+	// Count returns the number of keys with the prefix.
+	Count(ctx context.Context, prefix string) (int, error)
+}
+
+// Codec encodes the values stored in Redis.
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec encodes the values as JSON.
+type JSONCodec[V any] struct{}
+
+func (JSONCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// MsgpackCodec encodes the values as MessagePack, which is more compact and
+// faster to decode than JSON.
+type MsgpackCodec[V any] struct{}
+
+func (MsgpackCodec[V]) Marshal(value V) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (MsgpackCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := msgpack.Unmarshal(data, &value)
+	return value, err
+}
+
+// Cache is the cache stored in Redis under the keys with its prefix.
+type Cache[K comparable, V any] struct {
+	client  Client
+	prefix  string
+	codec   Codec[V]
+	keyFunc func(key K) string
+	ttl     time.Duration
+	timeout time.Duration
+	onError func(err error)
+}
+
+var _ cache.Cache[string, int] = (*Cache[string, int])(nil)
+
+// Option configures the cache at construction.
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithCodec sets the codec of the values, JSONCodec is used by default.
+func WithCodec[K comparable, V any](codec Codec[V]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.codec = codec
+	}
+}
+
+// WithKeyFunc sets the function converting the keys to the Redis keys, which
+// are prefixed with the prefix of the cache. fmt.Sprint is used by default.
+func WithKeyFunc[K comparable, V any](keyFunc func(key K) string) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.keyFunc = keyFunc
+	}
+}
+
+// WithTTL makes the values put by Put expire after ttl, as lfu.WithTTL does.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.ttl = ttl
+	}
+}
+
+// WithTimeout limits the time of every command sent by the methods of
+// cache.Cache, which have no context. The default timeout is one second.
+func WithTimeout[K comparable, V any](timeout time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.timeout = timeout
+	}
+}
+
+// WithOnError registers the function called with the errors of Put, Remove
+// and Len, which cannot return them. The errors are dropped by default.
+func WithOnError[K comparable, V any](onError func(err error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onError = onError
+	}
+}
+
+// New creates the cache stored under the keys with the given prefix.
+func New[K comparable, V any](client Client, prefix string, opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		client:  client,
+		prefix:  prefix,
+		codec:   JSONCodec[V]{},
+		keyFunc: func(key K) string { return fmt.Sprint(key) },
+		timeout: time.Second,
+		onError: func(error) {},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Cache[K, V]) redisKey(key K) string {
+	return c.prefix + c.keyFunc(key)
+}
+
+func (c *Cache[K, V]) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// Get returns the value of the key, cache.ErrKeyNotFound if it is missing or
+// the error of Redis or the codec.
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.GetContext(ctx, key)
+}
+
+// GetContext is Get with the context.
+func (c *Cache[K, V]) GetContext(ctx context.Context, key K) (V, error) {
+	var value V
+	data, ok, err := c.client.Get(ctx, c.redisKey(key))
+	if err != nil {
+		return value, fmt.Errorf("get %v: %w", key, err)
+	}
+	if !ok {
+		return value, cache.ErrKeyNotFound
+	}
+	value, err = c.codec.Unmarshal(data)
+	if err != nil {
+		return value, fmt.Errorf("decode %v: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put sets the value of the key with the TTL of the cache.
+func (c *Cache[K, V]) Put(key K, value V) {
+	ctx, cancel := c.context()
+	defer cancel()
+	if err := c.PutContext(ctx, key, value, c.ttl); err != nil {
+		c.onError(err)
+	}
+}
+
+// PutContext sets the value of the key, which expires after ttl unless ttl is
+// zero.
+func (c *Cache[K, V]) PutContext(ctx context.Context, key K, value V, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %v: %w", key, err)
+	}
+	if err := c.client.Set(ctx, c.redisKey(key), data, ttl); err != nil {
+		return fmt.Errorf("set %v: %w", key, err)
+	}
+	return nil
+}
+
+// Remove deletes the key and reports whether it has existed, it reports false
+// on errors.
+func (c *Cache[K, V]) Remove(key K) bool {
+	ctx, cancel := c.context()
+	defer cancel()
+	ok, err := c.RemoveContext(ctx, key)
+	if err != nil {
+		c.onError(err)
+	}
+	return ok
+}
+
+// RemoveContext is Remove with the context.
+func (c *Cache[K, V]) RemoveContext(ctx context.Context, key K) (bool, error) {
+	ok, err := c.client.Del(ctx, c.redisKey(key))
+	if err != nil {
+		return false, fmt.Errorf("del %v: %w", key, err)
+	}
+	return ok, nil
+}
+
+// Len returns the number of keys with the prefix of the cache, it returns 0
+// on errors.
+//
+// O(number of keys in Redis)
+func (c *Cache[K, V]) Len() int {
+	ctx, cancel := c.context()
+	defer cancel()
+	n, err := c.client.Count(ctx, c.prefix)
+	if err != nil {
+		c.onError(fmt.Errorf("count: %w", err))
+		return 0
+	}
+	return n
+}
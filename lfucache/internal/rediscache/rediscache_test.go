@@ -0,0 +1,181 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lfucache/internal/cache"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// fakeClient is the in-memory Redis.
+type fakeClient struct {
+	mu      sync.Mutex
+	now     time.Time
+	entries map[string]fakeEntry
+	err     error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{now: time.Unix(1_000_000, 0), entries: make(map[string]fakeEntry)}
+}
+
+func (f *fakeClient) entry(key string) (fakeEntry, bool) {
+	entry, ok := f.entries[key]
+	if ok && !entry.expiresAt.IsZero() && !f.now.Before(entry.expiresAt) {
+		delete(f.entries, key)
+		return fakeEntry{}, false
+	}
+	return entry, ok
+}
+
+func (f *fakeClient) Get(_ context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	entry, ok := f.entry(key)
+	return entry.value, ok, nil
+}
+
+func (f *fakeClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	entry := fakeEntry{value: value}
+	if ttl != 0 {
+		entry.expiresAt = f.now.Add(ttl)
+	}
+	f.entries[key] = entry
+	return nil
+}
+
+func (f *fakeClient) Del(_ context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	_, ok := f.entry(key)
+	delete(f.entries, key)
+	return ok, nil
+}
+
+func (f *fakeClient) Count(_ context.Context, prefix string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return 0, f.err
+	}
+	n := 0
+	for key := range f.entries {
+		if _, ok := f.entry(key); ok && strings.HasPrefix(key, prefix) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type book struct {
+	Name    string   `json:"name"`
+	Authors []string `json:"authors"`
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	var c cache.Cache[int, book] = New[int, book](client, "books:")
+	other := New[int, book](client, "other:")
+
+	c.Put(1, book{Name: "Onegin", Authors: []string{"Pushkin"}})
+	other.Put(1, book{Name: "Other"})
+
+	value, err := c.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, book{Name: "Onegin", Authors: []string{"Pushkin"}}, value)
+	require.Contains(t, client.entries, "books:1")
+
+	_, err = c.Get(2)
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+	require.Equal(t, 1, c.Len())
+	require.True(t, c.Remove(1))
+	require.False(t, c.Remove(1))
+	require.Zero(t, c.Len())
+	require.Equal(t, 1, other.Len())
+}
+
+func TestTTL(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	c := New(client, "ttl:", WithTTL[string, int](time.Minute))
+
+	c.Put("a", 1)
+	require.NoError(t, c.PutContext(context.Background(), "b", 2, time.Hour))
+	require.NoError(t, c.PutContext(context.Background(), "c", 3, 0))
+
+	client.now = client.now.Add(time.Minute)
+	_, err := c.Get("a")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.Equal(t, 2, c.Len())
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Marshal(value string) ([]byte, error) {
+	return []byte(strings.ToUpper(value)), nil
+}
+
+func (upperCodec) Unmarshal(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("empty value")
+	}
+	return string(data), nil
+}
+
+func TestOptionsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	var errs []error
+	c := New(client, "p:",
+		WithCodec[int, string](upperCodec{}),
+		WithKeyFunc[int, string](func(key int) string { return strings.Repeat("k", key) }),
+		WithTimeout[int, string](time.Minute),
+		WithOnError[int, string](func(err error) { errs = append(errs, err) }),
+	)
+
+	c.Put(3, "value")
+	require.Equal(t, []byte("VALUE"), client.entries["p:kkk"].value)
+
+	client.entries["p:k"] = fakeEntry{}
+	_, err := c.Get(1)
+	require.ErrorContains(t, err, "empty value")
+
+	redisErr := errors.New("connection refused")
+	client.err = redisErr
+	_, err = c.Get(3)
+	require.ErrorIs(t, err, redisErr)
+	c.Put(3, "value")
+	require.False(t, c.Remove(3))
+	require.Zero(t, c.Len())
+	require.Len(t, errs, 3)
+	for _, err := range errs {
+		require.ErrorIs(t, err, redisErr)
+	}
+}